@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
 	"SimpleBankProject/token"
 
 	"github.com/gin-gonic/gin"
@@ -95,11 +96,11 @@ func (server *Server) getAccount(ctx *gin.Context) {
 		return
 	}
 
-	// we can only return the account data if the account owner matches the logged in user
+	// we can only return the account data if the account owner matches the logged in user or the user is an admin
 	// middleware passes the payload information to the handler via context using ctx.Next()
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 	// if the account owner doesn't match the logged in user, we do not return the account
-	if account.Owner != authPayload.Username {
+	if !isOwnerOrAdmin(authPayload, account.Owner) {
 		// create error
 		err := errors.New("account doesn't belong to the authenticated user")
 		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
@@ -112,10 +113,19 @@ func (server *Server) getAccount(ctx *gin.Context) {
 
 type listAccountRequest struct {
 	// since we are using query parameters, we use form: "page_id"
-	PageID int32 `form:"page_id" binding:"required,min=1"` // no spaces unless shown here
+	// page_id is optional - leaving it out switches to cursor pagination, where page_token picks the page instead
+	PageID int32 `form:"page_id" binding:"omitempty,min=1"` // no spaces unless shown here
 	// we use min and max to ensure the page size is neither too big or too small
 	// the page size is the number of accounts per page
 	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"` //no spaces unless shown here
+	// the next_page_token of the previous page - empty for the first page, can't be combined with page_id
+	PageToken string `form:"page_token" binding:"excluded_with=PageID"`
+}
+
+// the response of cursor pagination - next_page_token is empty on the last page
+type listAccountResponse struct {
+	Accounts      []db.Account `json:"accounts"`
+	NextPageToken string       `json:"next_page_token"`
 }
 
 func (server *Server) listAccount(ctx *gin.Context) {
@@ -132,6 +142,12 @@ func (server *Server) listAccount(ctx *gin.Context) {
 	// MustGet returns a general interface so we cast it to be an object of type *token.Payload
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 
+	// without page_id the accounts are listed with cursor pagination
+	if req.PageID == 0 {
+		server.listAccountAfter(ctx, authPayload.Username, req)
+		return
+	}
+
 	// Server.Store.ListAccounts requires passing ListAccountsParams
 	arg := db.ListAccountsParams{
 		Owner: authPayload.Username,
@@ -143,68 +159,39 @@ func (server *Server) listAccount(ctx *gin.Context) {
 	accounts, err := server.store.ListAccounts(ctx, arg)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
 	}
 
 	ctx.JSON(http.StatusOK, accounts)
 }
 
-type updateAccountRequest struct {
-	// ID must be provided and have a value no less than 1
-	// remember the capitalization exports the property
-	ID int64 `json:"id" binding:"required,min=1"`
-	// Balance must be provided and have a value no less than 0
-	Balance int64 `json:"balance" binding:"required,min=0"`
-}
-
-func (server *Server) updateAccount(ctx *gin.Context) {
-	var req updateAccountRequest
-
-	// if err is not nil, something with the request is incorrect
-	if err := ctx.ShouldBindJSON(&req); err != nil {
+// listAccountAfter lists the page of accounts after the one in the page token - unlike an offset, the cursor doesn't skip or
+// repeat accounts when accounts are created or deleted between two pages
+func (server *Server) listAccountAfter(ctx *gin.Context, owner string, req listAccountRequest) {
+	afterID, err := util.DecodePageToken(req.PageToken)
+	if err != nil {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	// server.store.UpdateAccount requires passing UpdateAccountParams
-	arg := db.UpdateAccountParams{
-		ID:      req.ID,
-		Balance: req.Balance,
-	}
-
-	account, err := server.store.GetAccount(ctx, arg.ID)
+	// one extra account is fetched to find out if there is another page
+	accounts, err := server.store.ListAccountsAfter(ctx, db.ListAccountsAfterParams{
+		Owner:   owner,
+		AfterID: afterID,
+		Limit:   req.PageSize + 1,
+	})
 	if err != nil {
-		// two possible reasons
-		// the id provided doesn't exist
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-		// something failed internally - perhaps with the GetAccount method
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	// the logged in user is only allowed to update an account they own - the username is in the payload of the access token
-	// the middleware will forward this information to the handler via context using ctx.Next()
-	// MustGet returns a general interface so we cast it to be an object of type *token.Payload
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-	// if the account owner doesn't match the username of the access token, the account cannot be updated by the logged in user
-	if account.Owner != authPayload.Username {
-		// create error
-		err := errors.New("account doesn't belong to the authenticated user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
-		return
-	}
-
-	account, err = server.store.UpdateAccount(ctx, arg)
-	if err != nil {
-		// something failed internally - perhaps with the UpdateAccount method
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
+	rsp := listAccountResponse{Accounts: accounts}
+	if len(accounts) > int(req.PageSize) {
+		rsp.Accounts = accounts[:req.PageSize]
+		rsp.NextPageToken = util.EncodePageToken(rsp.Accounts[req.PageSize-1].ID)
 	}
 
-	ctx.JSON(http.StatusOK, account)
-
+	ctx.JSON(http.StatusOK, rsp)
 }
 
 type deleteAccountRequest struct {
@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
@@ -47,7 +48,7 @@ func TestCreateAccountAPI(t *testing.T) {
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				// addAuthorization defined in middleware_test
 				// addAuthorization creates the token, creates the authentication header, and adds header to request
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				// EXPECT() returns an object of *MockStoreMockRecorder and indicates expected use
@@ -68,7 +69,7 @@ func TestCreateAccountAPI(t *testing.T) {
 			name:               "Internal Error",
 			createAccountInput: validCreateAccount,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				// expect CreateAccount to run one time and fail thus returning an empty account and an internal error
@@ -82,7 +83,7 @@ func TestCreateAccountAPI(t *testing.T) {
 			name:               "Invalid Params",
 			createAccountInput: invalidCreateAccount, // invalid owner and currency
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				// for any invalid parameters, we do not expect CreateAccount to run
@@ -154,7 +155,7 @@ func TestGetAccountAPI(t *testing.T) {
 			name:      "OK",
 			accountID: account.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				// building stub for this MockStore
@@ -173,7 +174,7 @@ func TestGetAccountAPI(t *testing.T) {
 			name:      "Unauthorized User",
 			accountID: account.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				// building stub for this MockStore
@@ -186,6 +187,21 @@ func TestGetAccountAPI(t *testing.T) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
 		},
+		{
+			// admins may read every account
+			name:      "Admin",
+			accountID: account.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "admin_user", util.AdminRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchAccount(t, recorder.Body, account)
+			},
+		},
 		{
 			name:      "No Authorization",
 			accountID: account.ID,
@@ -208,7 +224,7 @@ func TestGetAccountAPI(t *testing.T) {
 			name:      "Not Found",
 			accountID: account.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				// here we do not expect to find the account
@@ -225,7 +241,7 @@ func TestGetAccountAPI(t *testing.T) {
 			name:      "Internal Error",
 			accountID: account.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				// here we expect an internal error
@@ -244,7 +260,7 @@ func TestGetAccountAPI(t *testing.T) {
 			// use an invalid ID to cause a bad request
 			accountID: 0,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				// here we expect a bad request error
@@ -334,7 +350,7 @@ func TestListAccountAPI(t *testing.T) {
 			queryInput:        validQuery,
 			listAccountsInput: validListAccounts,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().ListAccounts(gomock.Any(), gomock.Eq(validListAccounts)).Times(1).Return(accounts[:5], nil)
@@ -349,7 +365,7 @@ func TestListAccountAPI(t *testing.T) {
 			queryInput:        validQuery,
 			listAccountsInput: validListAccounts,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().ListAccounts(gomock.Any(), gomock.Eq(validListAccounts)).Times(1).Return([]db.Account{}, sql.ErrConnDone)
@@ -363,7 +379,7 @@ func TestListAccountAPI(t *testing.T) {
 			queryInput:        invalidQuery,
 			listAccountsInput: invalidListAccounts,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Times(0)
@@ -416,145 +432,110 @@ func TestListAccountAPI(t *testing.T) {
 	}
 }
 
-func TestUpdateAccountAPI(t *testing.T) {
+func TestListAccountCursorAPI(t *testing.T) {
 	user, _ := randomUser(t)
-	account := randomAccount(user.Username)
-	// copy made to update balance without impacting other tests
-	copyAccount := account
-	// valid ID and balance for updating account at ID with the balance
-	validUpdateAccount := db.UpdateAccountParams{ID: account.ID, Balance: 500}
-	// invalid ID as ID cannot be zero
-	invalidUpdateAccount := db.UpdateAccountParams{ID: 0, Balance: 100}
 
-	// create test cases for testing
+	// one more account than the page size - the extra account tells the handler there is another page
+	accounts := make([]db.Account, 6)
+	for i := range accounts {
+		accounts[i] = randomAccount(user.Username)
+		accounts[i].ID = int64(11 + i)
+	}
+
 	testCases := []struct {
-		name               string
-		updateAccountInput db.UpdateAccountParams
-		setupAuth          func(t *testing.T, request *http.Request, tokenMaker token.Maker)
-		buildStubs         func(store *mockdb.MockStore)
-		checkResponse      func(t *testing.T, recorder *httptest.ResponseRecorder)
+		name          string
+		query         url.Values
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		{
-			name:               "OK",
-			updateAccountInput: validUpdateAccount,
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
-			},
+			name:  "OK",
+			query: url.Values{"page_size": {"5"}, "page_token": {util.EncodePageToken(10)}},
 			buildStubs: func(store *mockdb.MockStore) {
-				// update the balance
-				copyAccount.Balance = validUpdateAccount.Balance
-
-				first := store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(validUpdateAccount.ID)).Times(1).Return(account, nil)
-				second := store.EXPECT().UpdateAccount(gomock.Any(), gomock.Eq(validUpdateAccount)).Times(1).Return(copyAccount, nil)
-				// ensure the Get Account is called first and the Delete Account is called second
-				gomock.InOrder(first, second)
-
+				arg := db.ListAccountsAfterParams{Owner: user.Username, AfterID: 10, Limit: 6}
+				store.EXPECT().ListAccountsAfter(gomock.Any(), gomock.Eq(arg)).Times(1).Return(accounts, nil)
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
-				requireBodyMatchAccount(t, recorder.Body, copyAccount)
+
+				var rsp listAccountResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, accounts[:5], rsp.Accounts)
+				// the next page starts after the last account of this page
+				require.Equal(t, util.EncodePageToken(accounts[4].ID), rsp.NextPageToken)
 			},
 		},
 		{
-			name:               "Not Found",
-			updateAccountInput: validUpdateAccount,
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
-			},
+			name:  "OK First Page",
+			query: url.Values{"page_size": {"5"}},
 			buildStubs: func(store *mockdb.MockStore) {
-				first := store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(validUpdateAccount.ID)).Times(1).Return(db.Account{}, sql.ErrNoRows)
-				second := store.EXPECT().UpdateAccount(gomock.Any(), gomock.Eq(validUpdateAccount)).Times(0)
-
-				gomock.InOrder(first, second)
+				// without a page token the first page starts after id 0
+				arg := db.ListAccountsAfterParams{Owner: user.Username, AfterID: 0, Limit: 6}
+				store.EXPECT().ListAccountsAfter(gomock.Any(), gomock.Eq(arg)).Times(1).Return(accounts[:3], nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp listAccountResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, accounts[:3], rsp.Accounts)
+				// there is no next page
+				require.Empty(t, rsp.NextPageToken)
 			},
 		},
 		{
-			name:               "Internal Error Get Account",
-			updateAccountInput: validUpdateAccount,
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
-			},
+			name:  "Invalid Page Token",
+			query: url.Values{"page_size": {"5"}, "page_token": {"not-a-token"}},
 			buildStubs: func(store *mockdb.MockStore) {
-				first := store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(validUpdateAccount.ID)).Times(1).Return(db.Account{}, sql.ErrConnDone)
-				second := store.EXPECT().UpdateAccount(gomock.Any(), gomock.Eq(validUpdateAccount)).Times(0)
-				gomock.InOrder(first, second)
+				store.EXPECT().ListAccountsAfter(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
 		{
-			name:               "Internal Error Update Account",
-			updateAccountInput: validUpdateAccount,
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
-			},
+			name:  "Page Token With Page ID",
+			query: url.Values{"page_id": {"1"}, "page_size": {"5"}, "page_token": {util.EncodePageToken(10)}},
 			buildStubs: func(store *mockdb.MockStore) {
-				first := store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(validUpdateAccount.ID)).Times(1).Return(account, nil)
-				second := store.EXPECT().UpdateAccount(gomock.Any(), gomock.Eq(validUpdateAccount)).Times(1).Return(db.Account{}, sql.ErrConnDone)
-				gomock.InOrder(first, second)
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ListAccountsAfter(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
 		{
-			name:               "Invalid Params",
-			updateAccountInput: invalidUpdateAccount,
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
-			},
+			name:  "Internal Error",
+			query: url.Values{"page_size": {"5"}},
 			buildStubs: func(store *mockdb.MockStore) {
-				// with any invalid update parameters, we expect GetAccount and UpdateAccount not to run
-				first := store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
-				second := store.EXPECT().UpdateAccount(gomock.Any(), gomock.Any()).Times(0)
-				gomock.InOrder(first, second)
+				store.EXPECT().ListAccountsAfter(gomock.Any(), gomock.Any()).Times(1).Return([]db.Account{}, sql.ErrConnDone)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
 		},
 	}
 
 	for i := range testCases {
-		// tc will store the data of the current test case
 		tc := testCases[i]
 
-		// running each case as a separate sub-test of this unit test
 		t.Run(tc.name, func(t *testing.T) {
-			// to call NewMockStore, we need a *gomock.Controller object - here we create one
 			ctrl := gomock.NewController(t)
-			// we defer calling ctrl.Finish() - Finish() checks to see if all the methods that were expected to be called, were called
-			// Finish() should be invoked for each controller - checks store.EXPECT was satisfied
 			defer ctrl.Finish()
 
-			// declare a new store
 			store := mockdb.NewMockStore(ctrl)
-			// build stub relevant to test case
 			tc.buildStubs(store)
 
-			// start test HTTP server and send request - this is not an actual server
-			// will use recorder to record the response of the api request
 			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 
-			url := "/accounts"
-			jsonText := fmt.Sprintf("{\"id\":%d, \"balance\":%d}", tc.updateAccountInput.ID, tc.updateAccountInput.Balance)
-			jsonBody := strings.NewReader(jsonText)
-			// generate a new HTTP request with MethodPatch to the url
-			request, err := http.NewRequest(http.MethodPatch, url, jsonBody)
+			url := fmt.Sprintf("/accounts?%s", tc.query.Encode())
+			request, err := http.NewRequest(http.MethodGet, url, nil)
 			require.NoError(t, err)
 
-			// create token and then the authentication header - update request with header
-			tc.setupAuth(t, request, server.tokenMaker)
-
-			// sends our api request through the server router and records response in the recorder
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
 			server.router.ServeHTTP(recorder, request)
-
-			// check response
 			tc.checkResponse(t, recorder)
 		})
 	}
@@ -583,7 +564,7 @@ func TestDeleteAccountAPI(t *testing.T) {
 			// accountID that we want to delete
 			accountID: account.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
 			},
 			// we expect to first get the account to see if it is there
 			// we then expect DeleteAccount to run once and return nil
@@ -602,7 +583,7 @@ func TestDeleteAccountAPI(t *testing.T) {
 			name:      "Not Found",
 			accountID: account.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				// if the ID is valid but doesn't exist, only GetAccount will run
@@ -619,7 +600,7 @@ func TestDeleteAccountAPI(t *testing.T) {
 			name:      "Internal Get Error",
 			accountID: account.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				// for an error with the GetAccount method, we expect it to return an empty account and an internal sever error
@@ -636,7 +617,7 @@ func TestDeleteAccountAPI(t *testing.T) {
 			name:      "Internal Delete Error",
 			accountID: account.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				// we expect GetAccount to return the account, proving that the account exists
@@ -653,7 +634,7 @@ func TestDeleteAccountAPI(t *testing.T) {
 			name:      "Invalid ID",
 			accountID: 0,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				// for any invalid ID, we do not expect GetAccount or DeleteAccount to run
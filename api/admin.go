@@ -0,0 +1,77 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+
+	db "SimpleBankProject/db/sqlc"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// the account an admin freezes or unfreezes - taken from the URI
+type freezeAccountRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// freezeAccount stops all money from moving in to or out of an account - only admins can reach this handler
+func (server *Server) freezeAccount(ctx *gin.Context) {
+	server.setAccountFrozen(ctx, true)
+}
+
+// unfreezeAccount lets money move in to and out of a frozen account again - only admins can reach this handler
+func (server *Server) unfreezeAccount(ctx *gin.Context) {
+	server.setAccountFrozen(ctx, false)
+}
+
+func (server *Server) setAccountFrozen(ctx *gin.Context, frozen bool) {
+	var req freezeAccountRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, err := server.store.UpdateAccountFrozen(ctx, db.UpdateAccountFrozenParams{
+		ID:       req.ID,
+		IsFrozen: frozen,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, account)
+}
+
+// the session an admin blocks - taken from the URI
+type blockSessionRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// blockSession stops a session from renewing access tokens (e.g. when its refresh token was stolen) - only admins can reach
+// this handler
+func (server *Server) blockSession(ctx *gin.Context) {
+	var req blockSessionRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// the uuid binding tag has already confirmed the id can be parsed
+	session, err := server.blockAndDenySession(ctx, uuid.MustParse(req.ID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newSessionResponse(session))
+}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/token"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// apiKeyResponse is an API key without its hash
+type apiKeyResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	Username  string     `json:"username"`
+	Name      string     `json:"name"`
+	KeyPrefix string     `json:"key_prefix"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"` // null for keys which never expire
+	RevokedAt *time.Time `json:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func newAPIKeyResponse(apiKey db.ApiKey) apiKeyResponse {
+	rsp := apiKeyResponse{
+		ID:        apiKey.ID,
+		Username:  apiKey.Username,
+		Name:      apiKey.Name,
+		KeyPrefix: apiKey.KeyPrefix,
+		Scopes:    apiKey.Scopes,
+		CreatedAt: apiKey.CreatedAt,
+	}
+	if apiKey.ExpiresAt.Valid {
+		rsp.ExpiresAt = &apiKey.ExpiresAt.Time
+	}
+	if apiKey.RevokedAt.Valid {
+		rsp.RevokedAt = &apiKey.RevokedAt.Time
+	}
+	return rsp
+}
+
+type createAPIKeyRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+	// scope is a custom validator - see validator.go
+	Scopes []string `json:"scopes" binding:"required,min=1,unique,dive,scope"`
+	// optional - the key never expires if it isn't set
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// the key is only sent once, when it is created - only its hash is stored
+type createAPIKeyResponse struct {
+	APIKey apiKeyResponse `json:"api_key"`
+	Key    string         `json:"key"`
+}
+
+// createAPIKey creates an API key for the logged in user, limited to the scopes in the request
+func (server *Server) createAPIKey(ctx *gin.Context) {
+	var req createAPIKeyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		err := errors.New("expires_at must be in the future")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	key, err := util.NewAPIKey()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	id, err := uuid.NewRandom()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.CreateAPIKeyParams{
+		ID:        id,
+		Username:  authPayload.Username,
+		Name:      req.Name,
+		KeyPrefix: util.APIKeyDisplayPrefix(key),
+		KeyHash:   util.HashSecretToken(key),
+		Scopes:    req.Scopes,
+	}
+	if req.ExpiresAt != nil {
+		arg.ExpiresAt = sql.NullTime{Time: *req.ExpiresAt, Valid: true}
+	}
+
+	apiKey, err := server.store.CreateAPIKey(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := createAPIKeyResponse{
+		APIKey: newAPIKeyResponse(apiKey),
+		Key:    key,
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// listAPIKeys lists the API keys of the logged in user, including the revoked and expired ones
+func (server *Server) listAPIKeys(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	apiKeys, err := server.store.ListAPIKeysByUser(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]apiKeyResponse, 0, len(apiKeys))
+	for _, apiKey := range apiKeys {
+		rsp = append(rsp, newAPIKeyResponse(apiKey))
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// the API key to revoke - taken from the URI
+type revokeAPIKeyRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// revokeAPIKey revokes one of the API keys of the logged in user - it stops working immediately
+func (server *Server) revokeAPIKey(ctx *gin.Context) {
+	var req revokeAPIKeyRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// the uuid binding tag has already confirmed the id can be parsed
+	apiKey, err := server.store.GetAPIKey(ctx, uuid.MustParse(req.ID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// users may only revoke their own API keys - admins may revoke any API key
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if !isOwnerOrAdmin(authPayload, apiKey.Username) {
+		err := errors.New("API key doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	apiKey, err = server.store.RevokeAPIKey(ctx, apiKey.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAPIKeyResponse(apiKey))
+}
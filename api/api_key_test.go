@@ -0,0 +1,494 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "SimpleBankProject/db/mock"
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/token"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func randomAPIKey(username string, scopes ...string) db.ApiKey {
+	return db.ApiKey{
+		ID:        uuid.New(),
+		Username:  username,
+		Name:      util.RandomString(8),
+		KeyPrefix: util.APIKeyPrefix + util.RandomString(8),
+		KeyHash:   util.HashSecretToken(util.RandomString(32)),
+		Scopes:    scopes,
+		CreatedAt: time.Now(),
+	}
+}
+
+func TestCreateAPIKeyAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"name":       "batch job",
+				"scopes":     []string{util.AccountsReadScope, util.TransfersWriteScope},
+				"expires_at": expiresAt,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAPIKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateAPIKeyParams) (db.ApiKey, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.Equal(t, "batch job", arg.Name)
+						require.Equal(t, []string{util.AccountsReadScope, util.TransfersWriteScope}, arg.Scopes)
+						require.True(t, arg.ExpiresAt.Valid)
+						require.WithinDuration(t, expiresAt, arg.ExpiresAt.Time, time.Second)
+						return db.ApiKey{
+							ID:        arg.ID,
+							Username:  arg.Username,
+							Name:      arg.Name,
+							KeyPrefix: arg.KeyPrefix,
+							KeyHash:   arg.KeyHash,
+							Scopes:    arg.Scopes,
+							ExpiresAt: arg.ExpiresAt,
+							CreatedAt: time.Now(),
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp createAPIKeyResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Len(t, rsp.Key, util.APIKeyLength)
+				require.Equal(t, util.APIKeyDisplayPrefix(rsp.Key), rsp.APIKey.KeyPrefix)
+				require.NotNil(t, rsp.APIKey.ExpiresAt)
+				require.Nil(t, rsp.APIKey.RevokedAt)
+				// the hash of the key is never sent back
+				require.NotContains(t, recorder.Body.String(), util.HashSecretToken(rsp.Key))
+			},
+		},
+		{
+			name: "API Key Authorization",
+			body: gin.H{
+				"name":   "batch job",
+				"scopes": []string{util.AccountsReadScope},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				// an API key can't create more API keys, whatever its scopes
+				request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeAPIKey, "sbk_key"))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetActiveAPIKeyByHash(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GetActiveAPIKeyByHashRow{
+						Username: user.Username,
+						Role:     user.Role,
+						Scopes:   []string{util.AccountsReadScope, util.AccountsWriteScope, util.TransfersWriteScope},
+					}, nil)
+				store.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "Unsupported Scope",
+			body: gin.H{
+				"name":   "batch job",
+				"scopes": []string{"users:write"},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "No Scopes",
+			body: gin.H{
+				"name":   "batch job",
+				"scopes": []string{},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Expired",
+			body: gin.H{
+				"name":       "batch job",
+				"scopes":     []string{util.AccountsReadScope},
+				"expires_at": time.Now().Add(-time.Minute),
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Internal Error",
+			body: gin.H{
+				"name":   "batch job",
+				"scopes": []string{util.AccountsReadScope},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).Times(1).Return(db.ApiKey{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api_keys", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListAPIKeysAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	apiKeys := []db.ApiKey{randomAPIKey(user.Username, util.AccountsReadScope), randomAPIKey(user.Username, util.AccountsWriteScope)}
+	apiKeys[1].RevokedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListAPIKeysByUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(apiKeys, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/api_keys", nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	// the hashes of the keys are never sent back
+	for _, apiKey := range apiKeys {
+		require.NotContains(t, recorder.Body.String(), apiKey.KeyHash)
+	}
+
+	var gotAPIKeys []apiKeyResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &gotAPIKeys))
+	require.Len(t, gotAPIKeys, 2)
+	require.Equal(t, apiKeys[0].ID, gotAPIKeys[0].ID)
+	require.Nil(t, gotAPIKeys[0].RevokedAt)
+	require.NotNil(t, gotAPIKeys[1].RevokedAt)
+}
+
+func TestRevokeAPIKeyAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	apiKey := randomAPIKey(user.Username, util.AccountsReadScope)
+	revokedAPIKey := apiKey
+	revokedAPIKey.RevokedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	testCases := []struct {
+		name          string
+		apiKeyID      string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			apiKeyID: apiKey.ID.String(),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAPIKey(gomock.Any(), gomock.Eq(apiKey.ID)).Times(1).Return(apiKey, nil)
+				store.EXPECT().RevokeAPIKey(gomock.Any(), gomock.Eq(apiKey.ID)).Times(1).Return(revokedAPIKey, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp apiKeyResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.NotNil(t, rsp.RevokedAt)
+			},
+		},
+		{
+			name:     "Admin",
+			apiKeyID: apiKey.ID.String(),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "admin", util.AdminRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAPIKey(gomock.Any(), gomock.Eq(apiKey.ID)).Times(1).Return(apiKey, nil)
+				store.EXPECT().RevokeAPIKey(gomock.Any(), gomock.Eq(apiKey.ID)).Times(1).Return(revokedAPIKey, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "Unauthorized User",
+			apiKeyID: apiKey.ID.String(),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAPIKey(gomock.Any(), gomock.Eq(apiKey.ID)).Times(1).Return(apiKey, nil)
+				store.EXPECT().RevokeAPIKey(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "Not Found",
+			apiKeyID: apiKey.ID.String(),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAPIKey(gomock.Any(), gomock.Eq(apiKey.ID)).Times(1).Return(db.ApiKey{}, sql.ErrNoRows)
+				store.EXPECT().RevokeAPIKey(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "Invalid ID",
+			apiKeyID: "invalid",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAPIKey(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api_keys/%s", tc.apiKeyID)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestAuthMiddlewareAPIKey(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+	apiKey, err := util.NewAPIKey()
+	require.NoError(t, err)
+
+	// activeKey returns the row of an active API key of the user with the scopes
+	activeKey := func(scopes ...string) db.GetActiveAPIKeyByHashRow {
+		return db.GetActiveAPIKeyByHashRow{
+			ID:        uuid.New(),
+			Username:  user.Username,
+			Scopes:    scopes,
+			CreatedAt: time.Now(),
+			Role:      user.Role,
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		method        string
+		url           string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/accounts/%d", account.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				// only the hash of the key is looked up
+				store.EXPECT().
+					GetActiveAPIKeyByHash(gomock.Any(), gomock.Eq(util.HashSecretToken(apiKey))).
+					Times(1).
+					Return(activeKey(util.AccountsReadScope), nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchAccount(t, recorder.Body, account)
+			},
+		},
+		{
+			name:   "Missing Scope",
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/accounts/%d", account.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetActiveAPIKeyByHash(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(activeKey(util.TransfersWriteScope), nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "Route Without Scope",
+			method: http.MethodGet,
+			url:    "/sessions",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetActiveAPIKeyByHash(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(activeKey(util.AccountsReadScope, util.AccountsWriteScope, util.TransfersWriteScope), nil)
+				store.EXPECT().ListSessionsByUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "Admin Route",
+			method: http.MethodPost,
+			url:    fmt.Sprintf("/admin/accounts/%d/freeze", account.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				key := activeKey(util.AccountsReadScope, util.AccountsWriteScope, util.TransfersWriteScope)
+				key.Role = util.AdminRole
+				store.EXPECT().GetActiveAPIKeyByHash(gomock.Any(), gomock.Any()).Times(1).Return(key, nil)
+				store.EXPECT().UpdateAccountFrozen(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "Invalid Key",
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/accounts/%d", account.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				// revoked and expired keys aren't returned either
+				store.EXPECT().
+					GetActiveAPIKeyByHash(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GetActiveAPIKeyByHashRow{}, sql.ErrNoRows)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:   "Internal Error",
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/accounts/%d", account.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetActiveAPIKeyByHash(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GetActiveAPIKeyByHashRow{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(tc.method, tc.url, nil)
+			require.NoError(t, err)
+			request.Header.Set(authorizationHeaderKey, fmt.Sprintf("ApiKey %s", apiKey))
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/token"
+
+	"github.com/gin-gonic/gin"
+)
+
+// deposits and withdrawals move money in to or out of the bank, so they only involve one of our accounts
+type externalEntryRequest struct {
+	AccountID int64  `json:"account_id" binding:"required,min=1"`
+	Amount    int64  `json:"amount" binding:"required,gt=0"`       // always positive - the handler decides the direction
+	Currency  string `json:"currency" binding:"required,currency"` // must match the account's currency
+	// identifies the other side of the deposit or withdrawal (e.g. a wire transfer id) - it can only be booked once
+	ExternalReference string `json:"external_reference" binding:"required,max=255"`
+}
+
+// createDeposit adds money from outside the bank to an account - only the back office (admins) can book deposits, as the
+// money arrives outside the bank and a customer could otherwise credit their own account with any amount
+func (server *Server) createDeposit(ctx *gin.Context) {
+	var req externalEntryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// the roleMiddleware has already checked the role, so the account can belong to any user
+	if _, valid := server.validAccount(ctx, req.AccountID, req.Currency); !valid {
+		return
+	}
+
+	result, err := server.store.DepositTX(ctx, db.DepositTxParams{
+		AccountID:         req.AccountID,
+		Amount:            req.Amount,
+		ExternalReference: req.ExternalReference,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrDuplicateExternalReference) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		// an admin froze the account after validAccount checked it
+		if errors.Is(err, db.ErrAccountFrozen) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// createWithdrawal moves money out of the bank from an account of the logged in user
+func (server *Server) createWithdrawal(ctx *gin.Context) {
+	var req externalEntryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !server.validOwnAccount(ctx, req.AccountID, req.Currency) {
+		return
+	}
+
+	result, err := server.store.WithdrawTX(ctx, db.WithdrawTxParams{
+		AccountID:         req.AccountID,
+		Amount:            req.Amount,
+		ExternalReference: req.ExternalReference,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrDuplicateExternalReference) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		// an admin froze the account after validAccount checked it
+		if errors.Is(err, db.ErrAccountFrozen) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		// the account doesn't have enough money - nothing was withdrawn
+		if errors.Is(err, db.ErrInsufficientFunds) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// validOwnAccount confirms the account exists, uses the input currency, and belongs to the logged in user - the error
+// response has already been sent if it returns false
+func (server *Server) validOwnAccount(ctx *gin.Context, accountID int64, currency string) bool {
+	account, valid := server.validAccount(ctx, accountID, currency)
+	if !valid {
+		return false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if account.Owner != authPayload.Username {
+		err := errors.New("account doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,206 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/token"
+
+	"github.com/gin-gonic/gin"
+)
+
+// the account whose history is listed - taken from the URI
+type accountHistoryURI struct {
+	AccountID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// pagination and the optional filters of the history endpoints - taken from the query parameters
+type accountHistoryQuery struct {
+	// page_id is optional - leaving it out switches to cursor pagination, where page_token picks the page instead
+	PageID   int32 `form:"page_id" binding:"omitempty,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=50"`
+	// the next_page_token of the previous page - empty for the first page, can't be combined with page_id
+	PageToken string `form:"page_token" binding:"excluded_with=PageID"`
+	// the time range is in RFC 3339 format (e.g. 2022-07-20T22:00:00Z) - the start is inclusive and the end is exclusive
+	StartTime *time.Time `form:"start_time" time_format:"2006-01-02T15:04:05Z07:00"`
+	EndTime   *time.Time `form:"end_time" time_format:"2006-01-02T15:04:05Z07:00"`
+	// positive lists money coming in to the account and negative lists money leaving it
+	Sign string `form:"sign" binding:"omitempty,oneof=positive negative"`
+}
+
+// the responses of cursor pagination - next_page_token is empty on the last page
+type listEntriesResponse struct {
+	Entries       []db.Entry `json:"entries"`
+	NextPageToken string     `json:"next_page_token"`
+}
+
+type listTransfersResponse struct {
+	Transfers     []db.Transfer `json:"transfers"`
+	NextPageToken string        `json:"next_page_token"`
+}
+
+// listEntries lists the entries (balance changes) of an account owned by the logged in user
+func (server *Server) listEntries(ctx *gin.Context) {
+	uri, query, ok := server.bindAccountHistory(ctx)
+	if !ok {
+		return
+	}
+
+	startTime, endTime, sign := query.filters()
+	if query.PageID == 0 {
+		afterID, err := util.DecodePageToken(query.PageToken)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+
+		// one extra entry is fetched to find out if there is another page
+		entries, err := server.store.ListEntriesAfter(ctx, db.ListEntriesAfterParams{
+			AccountID: uri.AccountID,
+			AfterID:   afterID,
+			StartTime: startTime,
+			EndTime:   endTime,
+			Sign:      sign,
+			Limit:     query.PageSize + 1,
+		})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		rsp := listEntriesResponse{Entries: entries}
+		if len(entries) > int(query.PageSize) {
+			rsp.Entries = entries[:query.PageSize]
+			rsp.NextPageToken = util.EncodePageToken(rsp.Entries[query.PageSize-1].ID)
+		}
+		ctx.JSON(http.StatusOK, rsp)
+		return
+	}
+
+	entries, err := server.store.ListEntries(ctx, db.ListEntriesParams{
+		AccountID: uri.AccountID,
+		StartTime: startTime,
+		EndTime:   endTime,
+		Sign:      sign,
+		Limit:     query.PageSize,
+		Offset:    (query.PageID - 1) * query.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entries)
+}
+
+// listTransfers lists the transfers going in to and out of an account owned by the logged in user
+func (server *Server) listTransfers(ctx *gin.Context) {
+	uri, query, ok := server.bindAccountHistory(ctx)
+	if !ok {
+		return
+	}
+
+	startTime, endTime, sign := query.filters()
+	if query.PageID == 0 {
+		afterID, err := util.DecodePageToken(query.PageToken)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+
+		// one extra transfer is fetched to find out if there is another page
+		transfers, err := server.store.ListTransfersAfter(ctx, db.ListTransfersAfterParams{
+			AccountID: uri.AccountID,
+			AfterID:   afterID,
+			StartTime: startTime,
+			EndTime:   endTime,
+			Sign:      sign,
+			Limit:     query.PageSize + 1,
+		})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		rsp := listTransfersResponse{Transfers: transfers}
+		if len(transfers) > int(query.PageSize) {
+			rsp.Transfers = transfers[:query.PageSize]
+			rsp.NextPageToken = util.EncodePageToken(rsp.Transfers[query.PageSize-1].ID)
+		}
+		ctx.JSON(http.StatusOK, rsp)
+		return
+	}
+
+	transfers, err := server.store.ListTransfers(ctx, db.ListTransfersParams{
+		AccountID: uri.AccountID,
+		StartTime: startTime,
+		EndTime:   endTime,
+		Sign:      sign,
+		Limit:     query.PageSize,
+		Offset:    (query.PageID - 1) * query.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, transfers)
+}
+
+// bindAccountHistory binds the URI and query parameters of a history request and confirms the account belongs to the
+// logged in user - the error response has already been sent if ok is false
+func (server *Server) bindAccountHistory(ctx *gin.Context) (uri accountHistoryURI, query accountHistoryQuery, ok bool) {
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if query.StartTime != nil && query.EndTime != nil && !query.EndTime.After(*query.StartTime) {
+		err := errors.New("end_time must be after start_time")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, uri.AccountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// only the owner of the account or an admin may see its history
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if !isOwnerOrAdmin(authPayload, account.Owner) {
+		err := errors.New("account doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	return uri, query, true
+}
+
+// filters converts the optional filters to the nullable query arguments - a null argument matches every row
+func (query accountHistoryQuery) filters() (startTime sql.NullTime, endTime sql.NullTime, sign sql.NullString) {
+	if query.StartTime != nil {
+		startTime = sql.NullTime{Time: *query.StartTime, Valid: true}
+	}
+	if query.EndTime != nil {
+		endTime = sql.NullTime{Time: *query.EndTime, Valid: true}
+	}
+	if query.Sign != "" {
+		sign = sql.NullString{String: query.Sign, Valid: true}
+	}
+	return
+}
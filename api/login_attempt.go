@@ -0,0 +1,134 @@
+package api
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/token"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	// errIncorrectLogin is sent to the client for an unknown username as well as a wrong password, so the login can't be
+	// used to find out which usernames exist
+	errIncorrectLogin = errors.New("incorrect username or password")
+	// errTooManyLoginAttempts is sent to the client while logins are locked out
+	errTooManyLoginAttempts = errors.New("too many failed login attempts, try again later")
+)
+
+// loginLockedUntil returns when the lockout of logins as the user or from the client IP ends - the zero time if they aren't
+// locked out - a lockout with MaxFailures 0 is disabled and isn't looked up
+func (server *Server) loginLockedUntil(ctx *gin.Context, username string, clientIP string) (time.Time, error) {
+	var lockedUntil time.Time
+	since := time.Now().Add(-server.config.LoginFailureWindow)
+
+	userLockout := util.LoginLockout{
+		MaxFailures: server.config.LoginMaxFailuresPerUser,
+		Duration:    server.config.LoginLockoutDuration,
+		MaxDuration: server.config.LoginMaxLockoutDuration,
+	}
+	if userLockout.MaxFailures > 0 {
+		failures, err := server.store.GetUserLoginFailures(ctx, db.GetUserLoginFailuresParams{
+			Username: username,
+			Since:    since,
+		})
+		if err != nil {
+			return time.Time{}, err
+		}
+		lockedUntil = userLockout.LockedUntil(failures.Failures, failures.LastFailedAt)
+	}
+
+	// the failures from an IP aren't reset by a successful login, so one account can't be used to keep guessing others
+	ipLockout := util.LoginLockout{
+		MaxFailures: server.config.LoginMaxFailuresPerIP,
+		Duration:    server.config.LoginLockoutDuration,
+		MaxDuration: server.config.LoginMaxLockoutDuration,
+	}
+	if ipLockout.MaxFailures > 0 && clientIP != "" {
+		failures, err := server.store.GetClientIPLoginFailures(ctx, db.GetClientIPLoginFailuresParams{
+			ClientIp: clientIP,
+			Since:    since,
+		})
+		if err != nil {
+			return time.Time{}, err
+		}
+		if ipLockedUntil := ipLockout.LockedUntil(failures.Failures, failures.LastFailedAt); ipLockedUntil.After(lockedUntil) {
+			lockedUntil = ipLockedUntil
+		}
+	}
+
+	return lockedUntil, nil
+}
+
+// recordLoginAttempt adds the login attempt to the audit trail in the login_attempts table - an empty failure reason records
+// a successful attempt
+// the failures of an IP lock it out, so the IP is the address of the connection - the X-Forwarded-For header is set by the
+// client, which could send a new one with every attempt
+func (server *Server) recordLoginAttempt(ctx *gin.Context, username string, failureReason string) error {
+	_, err := server.store.CreateLoginAttempt(ctx, db.CreateLoginAttemptParams{
+		Username:      username,
+		ClientIp:      ctx.RemoteIP(),
+		UserAgent:     ctx.Request.UserAgent(),
+		Succeeded:     failureReason == "",
+		FailureReason: failureReason,
+	})
+	return err
+}
+
+// rejectLockedOutLogin records the attempt and sends errTooManyLoginAttempts with the number of seconds until the lockout
+// ends in the Retry-After header
+func (server *Server) rejectLockedOutLogin(ctx *gin.Context, username string, lockedUntil time.Time) {
+	if err := server.recordLoginAttempt(ctx, username, util.LoginFailureLockedOut); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	retryAfter := math.Ceil(time.Until(lockedUntil).Seconds())
+	ctx.Header("Retry-After", strconv.Itoa(int(retryAfter)))
+	ctx.JSON(http.StatusTooManyRequests, errorResponse(errTooManyLoginAttempts))
+}
+
+// rejectIncorrectLogin records the failed attempt and sends errIncorrectLogin
+func (server *Server) rejectIncorrectLogin(ctx *gin.Context, username string, failureReason string) {
+	if err := server.recordLoginAttempt(ctx, username, failureReason); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusUnauthorized, errorResponse(errIncorrectLogin))
+}
+
+// rejectIncorrectMFACode records the wrong TOTP or recovery code as a failed attempt and sends errInvalidMFACode - once
+// MFAMaxFailures wrong codes were sent since the token was issued, the token is denied and the user has to login with their
+// password again
+// mfaPayload is the challenge token of loginUserMFA, or the access token of disableMFA
+func (server *Server) rejectIncorrectMFACode(ctx *gin.Context, mfaPayload *token.Payload) {
+	if err := server.recordLoginAttempt(ctx, mfaPayload.Username, util.LoginFailureWrongMFACode); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if server.config.MFAMaxFailures > 0 {
+		failures, err := server.store.GetMFAChallengeFailures(ctx, db.GetMFAChallengeFailuresParams{
+			Username: mfaPayload.Username,
+			Since:    mfaPayload.IssuedAt,
+		})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		if failures >= server.config.MFAMaxFailures {
+			if err := server.denylist.Deny(ctx, mfaPayload); err != nil {
+				ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+				return
+			}
+		}
+	}
+
+	ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidMFACode))
+}
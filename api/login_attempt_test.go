@@ -0,0 +1,227 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "SimpleBankProject/db/mock"
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// matchLoginAttempt checks the login attempt recorded by the handler
+func matchLoginAttempt(t *testing.T, username string, clientIP string, failureReason string) func(context.Context, db.CreateLoginAttemptParams) (db.LoginAttempt, error) {
+	return func(_ context.Context, arg db.CreateLoginAttemptParams) (db.LoginAttempt, error) {
+		require.Equal(t, username, arg.Username)
+		require.Equal(t, clientIP, arg.ClientIp)
+		require.Equal(t, failureReason, arg.FailureReason)
+		require.Equal(t, failureReason == "", arg.Succeeded)
+		return db.LoginAttempt{}, nil
+	}
+}
+
+func requireIncorrectLogin(t *testing.T, recorder *httptest.ResponseRecorder) {
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	var rsp gin.H
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Equal(t, gin.H{"error": errIncorrectLogin.Error()}, rsp)
+}
+
+func TestLoginUserLockoutAPI(t *testing.T) {
+	user, password := randomUser(t)
+	clientIP := "192.0.2.1"
+
+	testCases := []struct {
+		name          string
+		password      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			password: password,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserLoginFailures(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.GetUserLoginFailuresParams) (db.GetUserLoginFailuresRow, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.WithinDuration(t, time.Now().Add(-24*time.Hour), arg.Since, time.Second)
+						return db.GetUserLoginFailuresRow{Failures: 2, LastFailedAt: time.Now()}, nil
+					})
+				store.EXPECT().
+					GetClientIPLoginFailures(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.GetClientIPLoginFailuresParams) (db.GetClientIPLoginFailuresRow, error) {
+						require.Equal(t, clientIP, arg.ClientIp)
+						return db.GetClientIPLoginFailuresRow{}, nil
+					})
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().
+					CreateLoginAttempt(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(matchLoginAttempt(t, user.Username, clientIP, ""))
+				store.EXPECT().GetUserMFA(gomock.Any(), gomock.Any()).Times(1).Return(db.UserMfa{}, sql.ErrNoRows)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(1).Return(db.Session{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "Unknown User",
+			password: password,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserLoginFailures(gomock.Any(), gomock.Any()).Times(1).Return(db.GetUserLoginFailuresRow{}, nil)
+				store.EXPECT().GetClientIPLoginFailures(gomock.Any(), gomock.Any()).Times(1).Return(db.GetClientIPLoginFailuresRow{}, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().
+					CreateLoginAttempt(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(matchLoginAttempt(t, user.Username, clientIP, util.LoginFailureUnknownUser))
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: requireIncorrectLogin,
+		},
+		{
+			name:     "Wrong Password",
+			password: "wrong-password",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserLoginFailures(gomock.Any(), gomock.Any()).Times(1).Return(db.GetUserLoginFailuresRow{}, nil)
+				store.EXPECT().GetClientIPLoginFailures(gomock.Any(), gomock.Any()).Times(1).Return(db.GetClientIPLoginFailuresRow{}, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().
+					CreateLoginAttempt(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(matchLoginAttempt(t, user.Username, clientIP, util.LoginFailureWrongPassword))
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: requireIncorrectLogin,
+		},
+		{
+			name:     "User Locked Out",
+			password: password,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserLoginFailures(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GetUserLoginFailuresRow{Failures: 3, LastFailedAt: time.Now()}, nil)
+				store.EXPECT().GetClientIPLoginFailures(gomock.Any(), gomock.Any()).Times(1).Return(db.GetClientIPLoginFailuresRow{}, nil)
+				// the password isn't checked while the user is locked out, even if it is correct
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().
+					CreateLoginAttempt(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(matchLoginAttempt(t, user.Username, clientIP, util.LoginFailureLockedOut))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+				require.Equal(t, "60", recorder.Header().Get("Retry-After"))
+			},
+		},
+		{
+			name:     "Lockout Doubles",
+			password: password,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserLoginFailures(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GetUserLoginFailuresRow{Failures: 5, LastFailedAt: time.Now()}, nil)
+				store.EXPECT().GetClientIPLoginFailures(gomock.Any(), gomock.Any()).Times(1).Return(db.GetClientIPLoginFailuresRow{}, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), gomock.Any()).Times(1).Return(db.LoginAttempt{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+				require.Equal(t, "240", recorder.Header().Get("Retry-After"))
+			},
+		},
+		{
+			name:     "Lockout Expired",
+			password: password,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserLoginFailures(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GetUserLoginFailuresRow{Failures: 3, LastFailedAt: time.Now().Add(-2 * time.Minute)}, nil)
+				store.EXPECT().GetClientIPLoginFailures(gomock.Any(), gomock.Any()).Times(1).Return(db.GetClientIPLoginFailuresRow{}, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), gomock.Any()).Times(1).Return(db.LoginAttempt{}, nil)
+				store.EXPECT().GetUserMFA(gomock.Any(), gomock.Any()).Times(1).Return(db.UserMfa{}, sql.ErrNoRows)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(1).Return(db.Session{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "Client IP Locked Out",
+			password: password,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserLoginFailures(gomock.Any(), gomock.Any()).Times(1).Return(db.GetUserLoginFailuresRow{}, nil)
+				store.EXPECT().
+					GetClientIPLoginFailures(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GetClientIPLoginFailuresRow{Failures: 10, LastFailedAt: time.Now()}, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), gomock.Any()).Times(1).Return(db.LoginAttempt{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+				require.Equal(t, "60", recorder.Header().Get("Retry-After"))
+			},
+		},
+		{
+			name:     "Internal Error",
+			password: password,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserLoginFailures(gomock.Any(), gomock.Any()).Times(1).Return(db.GetUserLoginFailuresRow{}, sql.ErrConnDone)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			server.config.LoginMaxFailuresPerUser = 3
+			server.config.LoginMaxFailuresPerIP = 10
+			server.config.LoginLockoutDuration = time.Minute
+			server.config.LoginMaxLockoutDuration = time.Hour
+			server.config.LoginFailureWindow = 24 * time.Hour
+
+			body := gin.H{
+				"username": user.Username,
+				"password": tc.password,
+			}
+			recorder := postJSON(t, server, "/users/login", body, func(request *http.Request) {
+				request.RemoteAddr = clientIP + ":1234"
+				// the header is set by the client, so it can't be used to escape the lockout of its IP
+				request.Header.Set("X-Forwarded-For", "203.0.113.7")
+			})
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
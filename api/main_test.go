@@ -7,6 +7,8 @@ import (
 
 	db "SimpleBankProject/db/sqlc"
 	"SimpleBankProject/db/util"
+	"SimpleBankProject/mail"
+	"SimpleBankProject/token"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/require"
@@ -16,10 +18,15 @@ func newTestServer(t *testing.T, store db.Store) *Server {
 	config := util.Config{
 		TokenSymmetricKey:   util.RandomString(32),
 		AccessTokenDuration: time.Minute,
+		TOTPEncryptionKey:   util.RandomString(32),
 	}
 
-	server, err := NewServer(config, store)
+	server, err := NewServer(config, store, mail.NewMemoryMailer())
 	require.NoError(t, err)
+	// the mock store doesn't expect the queries of the revoked_tokens table, so the tests keep the denylist in memory
+	// the routes hold the denylist they were set up with, so they are set up again
+	server.denylist = token.NewMemoryDenylist()
+	server.setupRouter()
 
 	return server
 }
@@ -0,0 +1,357 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/token"
+	"SimpleBankProject/totp"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errInvalidMFACode is returned by checkMFACode when the code is wrong or was already used
+var errInvalidMFACode = errors.New("invalid two-factor authentication code")
+
+// mfaChallengeResponse is sent by loginUser instead of a loginUserResponse when the user has enabled two-factor
+// authentication - the MFA token and a TOTP code are sent to loginUserMFA to finish the login
+type mfaChallengeResponse struct {
+	MFARequired       bool      `json:"mfa_required"`
+	MFAToken          string    `json:"mfa_token"`
+	MFATokenExpiresAt time.Time `json:"mfa_token_expires_at"`
+}
+
+// isMFAEnabled returns true if the user has confirmed a TOTP secret
+func (server *Server) isMFAEnabled(ctx *gin.Context, username string) (bool, error) {
+	userMFA, err := server.store.GetUserMFA(ctx, username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	return userMFA.IsEnabled, nil
+}
+
+// sendMFAChallenge sends the short lived challenge token which proves the password of the user was checked
+func (server *Server) sendMFAChallenge(ctx *gin.Context, user db.User) {
+	mfaToken, mfaPayload, err := server.tokenMaker.CreateMFAChallengeToken(user.Username, user.Role, server.config.MFAChallengeDuration)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, mfaChallengeResponse{
+		MFARequired:       true,
+		MFAToken:          mfaToken,
+		MFATokenExpiresAt: mfaPayload.ExpiredAt,
+	})
+}
+
+// checkMFACode checks a TOTP code of the user, or one of their recovery codes - both can only be used once
+func (server *Server) checkMFACode(ctx *gin.Context, userMFA db.UserMfa, code string) error {
+	if len(code) != totp.Digits {
+		_, err := server.store.UseMFARecoveryCode(ctx, db.UseMFARecoveryCodeParams{
+			Username: userMFA.Username,
+			CodeHash: util.HashSecretToken(totp.NormalizeRecoveryCode(code)),
+		})
+		if err == sql.ErrNoRows {
+			return errInvalidMFACode
+		}
+		return err
+	}
+
+	secret, err := server.totpCipher.Decrypt(userMFA.EncryptedSecret)
+	if err != nil {
+		return err
+	}
+	step, ok := totp.Validate(secret, code, time.Now())
+	if !ok {
+		return errInvalidMFACode
+	}
+
+	// the code is correct, but it may have been used already
+	_, err = server.store.UseTOTPStep(ctx, db.UseTOTPStepParams{
+		Username: userMFA.Username,
+		Step:     step,
+	})
+	if err == sql.ErrNoRows {
+		return errInvalidMFACode
+	}
+	return err
+}
+
+type loginUserMFARequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required,min=6,max=20"` // a TOTP code or a recovery code
+}
+
+// loginUserMFA is the second step of the login of a user with two-factor authentication - it exchanges the challenge token
+// sent by loginUser and a TOTP code for the access token, refresh token and session
+func (server *Server) loginUserMFA(ctx *gin.Context) {
+	var req loginUserMFARequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	mfaPayload, err := server.tokenMaker.VerifyMFAChallengeToken(req.MFAToken)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	// every challenge token can only be used once - it is denied below once the login succeeds
+	err = token.CheckDenylist(ctx, server.denylist, mfaPayload)
+	if err != nil {
+		if errors.Is(err, token.ErrRevokedToken) {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// wrong codes count as failed logins of the user, so the lockout stops the guessing of codes as well
+	lockedUntil, err := server.loginLockedUntil(ctx, mfaPayload.Username, ctx.RemoteIP())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if time.Now().Before(lockedUntil) {
+		server.rejectLockedOutLogin(ctx, mfaPayload.Username, lockedUntil)
+		return
+	}
+
+	userMFA, err := server.store.GetUserMFA(ctx, mfaPayload.Username)
+	if err != nil {
+		// two-factor authentication was disabled after the challenge token was created
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if !userMFA.IsEnabled {
+		err := errors.New("two-factor authentication is not enabled")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	err = server.checkMFACode(ctx, userMFA, req.Code)
+	if err != nil {
+		if err == errInvalidMFACode {
+			server.rejectIncorrectMFACode(ctx, mfaPayload)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// the login succeeded, which resets the failed attempts of the user
+	err = server.recordLoginAttempt(ctx, mfaPayload.Username, "")
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = server.denylist.Deny(ctx, mfaPayload)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, mfaPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp, err := server.createLogin(ctx, user)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type enrollMFAResponse struct {
+	Secret string `json:"secret"`  // for authenticator apps which can't scan the key uri
+	KeyURI string `json:"key_uri"` // otpauth:// uri, shown as a QR code
+}
+
+// enrollMFA creates a new TOTP secret for the logged in user - two-factor authentication is only enabled once the user sends
+// a code of the secret to confirmMFA, so a user who never finishes the enrollment isn't locked out
+func (server *Server) enrollMFA(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	encryptedSecret, err := server.totpCipher.Encrypt(secret)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	_, err = server.store.CreateUserMFA(ctx, db.CreateUserMFAParams{
+		Username:        authPayload.Username,
+		EncryptedSecret: encryptedSecret,
+	})
+	if err != nil {
+		// the secret isn't replaced once it is enabled, it must be disabled first
+		if err == sql.ErrNoRows {
+			err := errors.New("two-factor authentication is already enabled")
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, enrollMFAResponse{
+		Secret: secret,
+		KeyURI: totp.KeyURI(server.config.TOTPIssuer, authPayload.Username, secret),
+	})
+}
+
+type confirmMFARequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+type confirmMFAResponse struct {
+	// shown once - only their hashes are stored
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// confirmMFA enables two-factor authentication for the logged in user once they send a code of the secret created by
+// enrollMFA, which proves their authenticator app has the secret - the recovery codes are returned
+func (server *Server) confirmMFA(ctx *gin.Context) {
+	var req confirmMFARequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	userMFA, err := server.store.GetUserMFA(ctx, authPayload.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if userMFA.IsEnabled {
+		err := errors.New("two-factor authentication is already enabled")
+		ctx.JSON(http.StatusConflict, errorResponse(err))
+		return
+	}
+
+	secret, err := server.totpCipher.Decrypt(userMFA.EncryptedSecret)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	step, ok := totp.Validate(secret, req.Code, time.Now())
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidMFACode))
+		return
+	}
+
+	recoveryCodes, err := totp.GenerateRecoveryCodes(totp.RecoveryCodeCount)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	codeHashes := make([]string, len(recoveryCodes))
+	for i, code := range recoveryCodes {
+		codeHashes[i] = util.HashSecretToken(totp.NormalizeRecoveryCode(code))
+	}
+
+	_, err = server.store.EnableMFATX(ctx, db.EnableMFATxParams{
+		Username:           authPayload.Username,
+		Step:               step,
+		RecoveryCodeHashes: codeHashes,
+	})
+	if err != nil {
+		// another request confirmed the secret first
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, confirmMFAResponse{RecoveryCodes: recoveryCodes})
+}
+
+type disableMFARequest struct {
+	Code string `json:"code" binding:"required,min=6,max=20"` // a TOTP code or a recovery code
+}
+
+type disableMFAResponse struct {
+	MFAEnabled bool `json:"mfa_enabled"`
+}
+
+// disableMFA turns two-factor authentication off for the logged in user - a stolen access token alone isn't enough, a TOTP
+// code or a recovery code is needed as well
+func (server *Server) disableMFA(ctx *gin.Context) {
+	var req disableMFARequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	userMFA, err := server.store.GetUserMFA(ctx, authPayload.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// an enrollment which was never confirmed can be dropped without a code
+	if userMFA.IsEnabled {
+		// the codes are guarded like in loginUserMFA, so a stolen access token can't be used to guess them - the lockout is
+		// shared with the login, and the access token is denied after MFAMaxFailures wrong codes
+		lockedUntil, err := server.loginLockedUntil(ctx, authPayload.Username, ctx.RemoteIP())
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		if time.Now().Before(lockedUntil) {
+			server.rejectLockedOutLogin(ctx, authPayload.Username, lockedUntil)
+			return
+		}
+
+		err = server.checkMFACode(ctx, userMFA, req.Code)
+		if err != nil {
+			if err == errInvalidMFACode {
+				server.rejectIncorrectMFACode(ctx, authPayload)
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	err = server.store.DisableMFATX(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, disableMFAResponse{MFAEnabled: false})
+}
@@ -0,0 +1,752 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "SimpleBankProject/db/mock"
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/token"
+	"SimpleBankProject/totp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// randomUserMFA creates the two-factor authentication of the user with a secret encrypted by the server
+func randomUserMFA(t *testing.T, server *Server, username string, enabled bool) (db.UserMfa, string) {
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	encryptedSecret, err := server.totpCipher.Encrypt(secret)
+	require.NoError(t, err)
+
+	return db.UserMfa{
+		Username:        username,
+		EncryptedSecret: encryptedSecret,
+		IsEnabled:       enabled,
+	}, secret
+}
+
+// currentCode returns the TOTP code of the secret right now
+func currentCode(t *testing.T, secret string) string {
+	code, err := totp.GenerateCode(secret, time.Now())
+	require.NoError(t, err)
+	return code
+}
+
+func postJSON(t *testing.T, server *Server, url string, body gin.H, setupAuth func(request *http.Request)) *httptest.ResponseRecorder {
+	data, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	require.NoError(t, err)
+	if setupAuth != nil {
+		setupAuth(request)
+	}
+
+	recorder := httptest.NewRecorder()
+	server.router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestLoginUserMFAChallengeAPI(t *testing.T) {
+	user, password := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore, server *Server)
+		checkResponse func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "No MFA",
+			buildStubs: func(store *mockdb.MockStore, server *Server) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), gomock.Any()).Times(1).Return(db.LoginAttempt{}, nil)
+				store.EXPECT().GetUserMFA(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(db.UserMfa{}, sql.ErrNoRows)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(1).Return(db.Session{}, nil)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp loginUserResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.NotEmpty(t, rsp.AccessToken)
+				require.NotEmpty(t, rsp.RefreshToken)
+			},
+		},
+		{
+			name: "MFA Not Confirmed",
+			buildStubs: func(store *mockdb.MockStore, server *Server) {
+				// an enrollment which was never confirmed doesn't change the login
+				userMFA, _ := randomUserMFA(t, server, user.Username, false)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), gomock.Any()).Times(1).Return(db.LoginAttempt{}, nil)
+				store.EXPECT().GetUserMFA(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(userMFA, nil)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(1).Return(db.Session{}, nil)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "access_token")
+			},
+		},
+		{
+			name: "MFA Enabled",
+			buildStubs: func(store *mockdb.MockStore, server *Server) {
+				userMFA, _ := randomUserMFA(t, server, user.Username, true)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				// the login only succeeds once the TOTP code is checked
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetUserMFA(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(userMFA, nil)
+				// no session until the TOTP code is checked
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp mfaChallengeResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.True(t, rsp.MFARequired)
+				require.NotContains(t, recorder.Body.String(), "access_token")
+
+				// the challenge token can't be used as an access token
+				payload, err := server.tokenMaker.VerifyMFAChallengeToken(rsp.MFAToken)
+				require.NoError(t, err)
+				require.Equal(t, user.Username, payload.Username)
+				_, err = server.tokenMaker.VerifyToken(rsp.MFAToken)
+				require.ErrorIs(t, err, token.ErrInvalidToken)
+			},
+		},
+		{
+			name: "Internal Error",
+			buildStubs: func(store *mockdb.MockStore, server *Server) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetUserMFA(gomock.Any(), gomock.Any()).Times(1).Return(db.UserMfa{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := newTestServer(t, store)
+			server.config.MFAChallengeDuration = time.Minute
+			tc.buildStubs(store, server)
+
+			body := gin.H{
+				"username": user.Username,
+				"password": password,
+			}
+			recorder := postJSON(t, server, "/users/login", body, nil)
+			tc.checkResponse(t, server, recorder)
+		})
+	}
+}
+
+func TestLoginUserMFAAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name string
+		// returns the body of the request
+		buildStubs    func(t *testing.T, store *mockdb.MockStore, server *Server, mfaToken string) gin.H
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK TOTP Code",
+			buildStubs: func(t *testing.T, store *mockdb.MockStore, server *Server, mfaToken string) gin.H {
+				userMFA, secret := randomUserMFA(t, server, user.Username, true)
+				store.EXPECT().GetUserMFA(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(userMFA, nil)
+				store.EXPECT().
+					UseTOTPStep(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UseTOTPStepParams) (db.UserMfa, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.InDelta(t, totp.Step(time.Now()), arg.Step, 1)
+						return userMFA, nil
+					})
+				store.EXPECT().
+					CreateLoginAttempt(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateLoginAttemptParams) (db.LoginAttempt, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.True(t, arg.Succeeded)
+						return db.LoginAttempt{}, nil
+					})
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(1).Return(db.Session{}, nil)
+				return gin.H{"mfa_token": mfaToken, "code": currentCode(t, secret)}
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp loginUserResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.NotEmpty(t, rsp.AccessToken)
+				require.NotEmpty(t, rsp.RefreshToken)
+				require.Equal(t, user.Username, rsp.User.Username)
+			},
+		},
+		{
+			name: "OK Recovery Code",
+			buildStubs: func(t *testing.T, store *mockdb.MockStore, server *Server, mfaToken string) gin.H {
+				userMFA, _ := randomUserMFA(t, server, user.Username, true)
+				recoveryCodes, err := totp.GenerateRecoveryCodes(1)
+				require.NoError(t, err)
+
+				store.EXPECT().GetUserMFA(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(userMFA, nil)
+				// only the hash of the normalized code is looked up
+				arg := db.UseMFARecoveryCodeParams{
+					Username: user.Username,
+					CodeHash: util.HashSecretToken(totp.NormalizeRecoveryCode(recoveryCodes[0])),
+				}
+				store.EXPECT().UseMFARecoveryCode(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.MfaRecoveryCode{}, nil)
+				store.EXPECT().UseTOTPStep(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), gomock.Any()).Times(1).Return(db.LoginAttempt{}, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(1).Return(db.Session{}, nil)
+				return gin.H{"mfa_token": mfaToken, "code": recoveryCodes[0]}
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Wrong Code",
+			buildStubs: func(t *testing.T, store *mockdb.MockStore, server *Server, mfaToken string) gin.H {
+				userMFA, secret := randomUserMFA(t, server, user.Username, true)
+				code, err := totp.GenerateCode(secret, time.Now().Add(-time.Hour))
+				require.NoError(t, err)
+
+				store.EXPECT().GetUserMFA(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(userMFA, nil)
+				store.EXPECT().UseTOTPStep(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().
+					CreateLoginAttempt(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateLoginAttemptParams) (db.LoginAttempt, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.False(t, arg.Succeeded)
+						require.Equal(t, util.LoginFailureWrongMFACode, arg.FailureReason)
+						return db.LoginAttempt{}, nil
+					})
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+				return gin.H{"mfa_token": mfaToken, "code": code}
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Code Already Used",
+			buildStubs: func(t *testing.T, store *mockdb.MockStore, server *Server, mfaToken string) gin.H {
+				userMFA, secret := randomUserMFA(t, server, user.Username, true)
+				store.EXPECT().GetUserMFA(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(userMFA, nil)
+				store.EXPECT().UseTOTPStep(gomock.Any(), gomock.Any()).Times(1).Return(db.UserMfa{}, sql.ErrNoRows)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), gomock.Any()).Times(1).Return(db.LoginAttempt{}, nil)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+				return gin.H{"mfa_token": mfaToken, "code": currentCode(t, secret)}
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Wrong Recovery Code",
+			buildStubs: func(t *testing.T, store *mockdb.MockStore, server *Server, mfaToken string) gin.H {
+				userMFA, _ := randomUserMFA(t, server, user.Username, true)
+				store.EXPECT().GetUserMFA(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(userMFA, nil)
+				store.EXPECT().UseMFARecoveryCode(gomock.Any(), gomock.Any()).Times(1).Return(db.MfaRecoveryCode{}, sql.ErrNoRows)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), gomock.Any()).Times(1).Return(db.LoginAttempt{}, nil)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+				return gin.H{"mfa_token": mfaToken, "code": "abcde-fghij"}
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "MFA Disabled",
+			buildStubs: func(t *testing.T, store *mockdb.MockStore, server *Server, mfaToken string) gin.H {
+				store.EXPECT().GetUserMFA(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(db.UserMfa{}, sql.ErrNoRows)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+				return gin.H{"mfa_token": mfaToken, "code": "123456"}
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Access Token",
+			buildStubs: func(t *testing.T, store *mockdb.MockStore, server *Server, mfaToken string) gin.H {
+				// an access token can't stand in for the challenge token
+				accessToken, _, err := server.tokenMaker.CreateToken(user.Username, user.Role, time.Minute)
+				require.NoError(t, err)
+				store.EXPECT().GetUserMFA(gomock.Any(), gomock.Any()).Times(0)
+				return gin.H{"mfa_token": accessToken, "code": "123456"}
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Invalid Code",
+			buildStubs: func(t *testing.T, store *mockdb.MockStore, server *Server, mfaToken string) gin.H {
+				store.EXPECT().GetUserMFA(gomock.Any(), gomock.Any()).Times(0)
+				return gin.H{"mfa_token": mfaToken, "code": "123"}
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := newTestServer(t, store)
+			mfaToken, _, err := server.tokenMaker.CreateMFAChallengeToken(user.Username, user.Role, time.Minute)
+			require.NoError(t, err)
+
+			body := tc.buildStubs(t, store, server, mfaToken)
+			recorder := postJSON(t, server, "/users/login/mfa", body, nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestLoginUserMFAChallengeUsedOnce(t *testing.T) {
+	user, _ := randomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+	userMFA, _ := randomUserMFA(t, server, user.Username, true)
+	mfaToken, _, err := server.tokenMaker.CreateMFAChallengeToken(user.Username, user.Role, time.Minute)
+	require.NoError(t, err)
+
+	store.EXPECT().GetUserMFA(gomock.Any(), gomock.Any()).Times(1).Return(userMFA, nil)
+	store.EXPECT().UseMFARecoveryCode(gomock.Any(), gomock.Any()).Times(1).Return(db.MfaRecoveryCode{}, nil)
+	store.EXPECT().CreateLoginAttempt(gomock.Any(), gomock.Any()).Times(1).Return(db.LoginAttempt{}, nil)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(user, nil)
+	store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(1).Return(db.Session{}, nil)
+
+	body := gin.H{"mfa_token": mfaToken, "code": "abcde-fghij"}
+	recorder := postJSON(t, server, "/users/login/mfa", body, nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	// the challenge token was denied by the first login
+	recorder = postJSON(t, server, "/users/login/mfa", body, nil)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestLoginUserMFAChallengeMaxFailures(t *testing.T) {
+	user, _ := randomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+	server.config.MFAMaxFailures = 3
+	userMFA, _ := randomUserMFA(t, server, user.Username, true)
+	mfaToken, mfaPayload, err := server.tokenMaker.CreateMFAChallengeToken(user.Username, user.Role, time.Minute)
+	require.NoError(t, err)
+
+	// every wrong code is recorded, and the failures since the challenge token was issued are counted
+	failures := int64(0)
+	store.EXPECT().GetUserMFA(gomock.Any(), gomock.Any()).Times(3).Return(userMFA, nil)
+	store.EXPECT().UseMFARecoveryCode(gomock.Any(), gomock.Any()).Times(3).Return(db.MfaRecoveryCode{}, sql.ErrNoRows)
+	store.EXPECT().
+		CreateLoginAttempt(gomock.Any(), gomock.Any()).
+		Times(3).
+		DoAndReturn(func(_ context.Context, arg db.CreateLoginAttemptParams) (db.LoginAttempt, error) {
+			require.Equal(t, util.LoginFailureWrongMFACode, arg.FailureReason)
+			failures++
+			return db.LoginAttempt{}, nil
+		})
+	store.EXPECT().
+		GetMFAChallengeFailures(gomock.Any(), gomock.Any()).
+		Times(3).
+		DoAndReturn(func(_ context.Context, arg db.GetMFAChallengeFailuresParams) (int64, error) {
+			require.Equal(t, user.Username, arg.Username)
+			require.WithinDuration(t, mfaPayload.IssuedAt, arg.Since, time.Second)
+			return failures, nil
+		})
+	store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+
+	for i := 0; i < 3; i++ {
+		body := gin.H{"mfa_token": mfaToken, "code": "abcde-fghij"}
+		recorder := postJSON(t, server, "/users/login/mfa", body, nil)
+		require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	}
+
+	// the challenge token was denied by the last wrong code, so even a correct code has to wait for a new login
+	denied, err := server.denylist.IsDenied(context.Background(), mfaPayload)
+	require.NoError(t, err)
+	require.True(t, denied)
+
+	body := gin.H{"mfa_token": mfaToken, "code": "abcde-fghij"}
+	recorder := postJSON(t, server, "/users/login/mfa", body, nil)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestEnrollMFAAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateUserMFA(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateUserMFAParams) (db.UserMfa, error) {
+						require.Equal(t, user.Username, arg.Username)
+						return db.UserMfa{Username: arg.Username, EncryptedSecret: arg.EncryptedSecret}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp enrollMFAResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.NotEmpty(t, rsp.Secret)
+				require.Contains(t, rsp.KeyURI, "otpauth://totp/")
+				require.Contains(t, rsp.KeyURI, rsp.Secret)
+			},
+		},
+		{
+			name: "Already Enabled",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateUserMFA(gomock.Any(), gomock.Any()).Times(1).Return(db.UserMfa{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := postJSON(t, server, "/users/mfa/enroll", gin.H{}, func(request *http.Request) {
+				addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			})
+			tc.checkResponse(t, server, recorder)
+		})
+	}
+}
+
+func TestConfirmMFAAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name string
+		// returns the code sent in the request
+		buildStubs    func(t *testing.T, store *mockdb.MockStore, server *Server) string
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(t *testing.T, store *mockdb.MockStore, server *Server) string {
+				userMFA, secret := randomUserMFA(t, server, user.Username, false)
+				store.EXPECT().GetUserMFA(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(userMFA, nil)
+				store.EXPECT().
+					EnableMFATX(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.EnableMFATxParams) (db.EnableMFATxResult, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.InDelta(t, totp.Step(time.Now()), arg.Step, 1)
+						require.Len(t, arg.RecoveryCodeHashes, totp.RecoveryCodeCount)
+						return db.EnableMFATxResult{UserMFA: userMFA}, nil
+					})
+				return currentCode(t, secret)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp confirmMFAResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Len(t, rsp.RecoveryCodes, totp.RecoveryCodeCount)
+			},
+		},
+		{
+			name: "Wrong Code",
+			buildStubs: func(t *testing.T, store *mockdb.MockStore, server *Server) string {
+				userMFA, secret := randomUserMFA(t, server, user.Username, false)
+				code, err := totp.GenerateCode(secret, time.Now().Add(-time.Hour))
+				require.NoError(t, err)
+
+				store.EXPECT().GetUserMFA(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(userMFA, nil)
+				store.EXPECT().EnableMFATX(gomock.Any(), gomock.Any()).Times(0)
+				return code
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Not Enrolled",
+			buildStubs: func(t *testing.T, store *mockdb.MockStore, server *Server) string {
+				store.EXPECT().GetUserMFA(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(db.UserMfa{}, sql.ErrNoRows)
+				store.EXPECT().EnableMFATX(gomock.Any(), gomock.Any()).Times(0)
+				return "123456"
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "Already Enabled",
+			buildStubs: func(t *testing.T, store *mockdb.MockStore, server *Server) string {
+				userMFA, secret := randomUserMFA(t, server, user.Username, true)
+				store.EXPECT().GetUserMFA(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(userMFA, nil)
+				store.EXPECT().EnableMFATX(gomock.Any(), gomock.Any()).Times(0)
+				return currentCode(t, secret)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "Invalid Code",
+			buildStubs: func(t *testing.T, store *mockdb.MockStore, server *Server) string {
+				store.EXPECT().GetUserMFA(gomock.Any(), gomock.Any()).Times(0)
+				return "12345a"
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := newTestServer(t, store)
+			code := tc.buildStubs(t, store, server)
+
+			recorder := postJSON(t, server, "/users/mfa/confirm", gin.H{"code": code}, func(request *http.Request) {
+				addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			})
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDisableMFAAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name string
+		// returns the code sent in the request
+		buildStubs    func(t *testing.T, store *mockdb.MockStore, server *Server) string
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(t *testing.T, store *mockdb.MockStore, server *Server) string {
+				userMFA, secret := randomUserMFA(t, server, user.Username, true)
+				store.EXPECT().GetUserMFA(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(userMFA, nil)
+				store.EXPECT().UseTOTPStep(gomock.Any(), gomock.Any()).Times(1).Return(userMFA, nil)
+				store.EXPECT().DisableMFATX(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(nil)
+				return currentCode(t, secret)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Not Confirmed",
+			buildStubs: func(t *testing.T, store *mockdb.MockStore, server *Server) string {
+				// the code isn't checked for an enrollment which was never confirmed
+				userMFA, _ := randomUserMFA(t, server, user.Username, false)
+				store.EXPECT().GetUserMFA(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(userMFA, nil)
+				store.EXPECT().UseTOTPStep(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().DisableMFATX(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(nil)
+				return "123456"
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Wrong Code",
+			buildStubs: func(t *testing.T, store *mockdb.MockStore, server *Server) string {
+				userMFA, secret := randomUserMFA(t, server, user.Username, true)
+				code, err := totp.GenerateCode(secret, time.Now().Add(-time.Hour))
+				require.NoError(t, err)
+
+				store.EXPECT().GetUserMFA(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(userMFA, nil)
+				// the wrong code counts as a failed login of the user
+				store.EXPECT().
+					CreateLoginAttempt(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateLoginAttemptParams) (db.LoginAttempt, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.Equal(t, util.LoginFailureWrongMFACode, arg.FailureReason)
+						return db.LoginAttempt{}, nil
+					})
+				store.EXPECT().DisableMFATX(gomock.Any(), gomock.Any()).Times(0)
+				return code
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Not Enrolled",
+			buildStubs: func(t *testing.T, store *mockdb.MockStore, server *Server) string {
+				store.EXPECT().GetUserMFA(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(db.UserMfa{}, sql.ErrNoRows)
+				store.EXPECT().DisableMFATX(gomock.Any(), gomock.Any()).Times(0)
+				return "123456"
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := newTestServer(t, store)
+			code := tc.buildStubs(t, store, server)
+
+			recorder := postJSON(t, server, "/users/mfa/disable", gin.H{"code": code}, func(request *http.Request) {
+				addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			})
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDisableMFALockoutAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+	server.config.LoginMaxFailuresPerUser = 3
+	server.config.LoginLockoutDuration = time.Minute
+	server.config.LoginMaxLockoutDuration = time.Hour
+	server.config.LoginFailureWindow = 24 * time.Hour
+	userMFA, secret := randomUserMFA(t, server, user.Username, true)
+
+	// the user is locked out by wrong passwords or codes, so the code isn't checked even if it is correct
+	store.EXPECT().GetUserMFA(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(userMFA, nil)
+	store.EXPECT().
+		GetUserLoginFailures(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.GetUserLoginFailuresRow{Failures: 3, LastFailedAt: time.Now()}, nil)
+	store.EXPECT().UseTOTPStep(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().
+		CreateLoginAttempt(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CreateLoginAttemptParams) (db.LoginAttempt, error) {
+			require.Equal(t, util.LoginFailureLockedOut, arg.FailureReason)
+			return db.LoginAttempt{}, nil
+		})
+	store.EXPECT().DisableMFATX(gomock.Any(), gomock.Any()).Times(0)
+
+	recorder := postJSON(t, server, "/users/mfa/disable", gin.H{"code": currentCode(t, secret)}, func(request *http.Request) {
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+	})
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+}
+
+func TestDisableMFAMaxFailuresAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+	server.config.MFAMaxFailures = 3
+	userMFA, _ := randomUserMFA(t, server, user.Username, true)
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, time.Minute)
+	require.NoError(t, err)
+
+	// every wrong code is recorded, and the failures since the access token was issued are counted
+	failures := int64(0)
+	store.EXPECT().GetUserMFA(gomock.Any(), gomock.Any()).Times(3).Return(userMFA, nil)
+	store.EXPECT().UseMFARecoveryCode(gomock.Any(), gomock.Any()).Times(3).Return(db.MfaRecoveryCode{}, sql.ErrNoRows)
+	store.EXPECT().
+		CreateLoginAttempt(gomock.Any(), gomock.Any()).
+		Times(3).
+		DoAndReturn(func(_ context.Context, arg db.CreateLoginAttemptParams) (db.LoginAttempt, error) {
+			require.Equal(t, util.LoginFailureWrongMFACode, arg.FailureReason)
+			failures++
+			return db.LoginAttempt{}, nil
+		})
+	store.EXPECT().
+		GetMFAChallengeFailures(gomock.Any(), gomock.Any()).
+		Times(3).
+		DoAndReturn(func(_ context.Context, arg db.GetMFAChallengeFailuresParams) (int64, error) {
+			require.Equal(t, user.Username, arg.Username)
+			require.WithinDuration(t, accessPayload.IssuedAt, arg.Since, time.Second)
+			return failures, nil
+		})
+	store.EXPECT().DisableMFATX(gomock.Any(), gomock.Any()).Times(0)
+
+	setupAuth := func(request *http.Request) {
+		request.Header.Set(authorizationHeaderKey, authorizationTypeBearer+" "+accessToken)
+	}
+	for i := 0; i < 3; i++ {
+		recorder := postJSON(t, server, "/users/mfa/disable", gin.H{"code": "abcde-fghij"}, setupAuth)
+		require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	}
+
+	// the access token was denied by the last wrong code, so a stolen token can't be used to keep guessing
+	denied, err := server.denylist.IsDenied(context.Background(), accessPayload)
+	require.NoError(t, err)
+	require.True(t, denied)
+}
@@ -1,11 +1,14 @@
 package api
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
 	"SimpleBankProject/token"
 
 	"github.com/gin-gonic/gin"
@@ -14,12 +17,34 @@ import (
 const (
 	authorizationHeaderKey  = "authorization"
 	authorizationTypeBearer = "bearer"
+	authorizationTypeAPIKey = "apikey"
 	authorizationPayloadKey = "authorization_payload"
 )
 
+// errInvalidAPIKey is sent to the client when the API key doesn't exist, was revoked or has expired
+var errInvalidAPIKey = errors.New("API key is invalid")
+
+// apiKeyRouteScopes maps the routes API keys may call (method and path as registered with the router) to the scope they
+// need - API keys can't call any other route, e.g. they can't create more API keys or change the password of their user
+var apiKeyRouteScopes = map[string]string{
+	"GET /accounts/:id":           util.AccountsReadScope,
+	"GET /accounts":               util.AccountsReadScope,
+	"GET /accounts/:id/entries":   util.AccountsReadScope,
+	"GET /accounts/:id/transfers": util.AccountsReadScope,
+	"POST /accounts":              util.AccountsWriteScope,
+	"DELETE /accounts/:id":        util.AccountsWriteScope,
+	"POST /deposits":              util.AccountsWriteScope,
+	"POST /withdrawals":           util.AccountsWriteScope,
+	"POST /transfers":             util.TransfersWriteScope,
+	"POST /transfers/:id/reverse": util.TransfersWriteScope,
+}
+
 // authMiddleware will return the actual authentication middleware function - it isn't middleware in and of itself
 // it is a higher order function
-func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
+// the denylist is checked after the token is verified, so a token which was revoked before it expired is rejected as well
+// instead of a bearer token, server-to-server clients may send an API key ("ApiKey sbk_...") - it is looked up in the store
+// and only lets the client call the routes of its scopes (see apiKeyRouteScopes)
+func authMiddleware(tokenMaker token.Maker, denylist token.Denylist, store db.Store) gin.HandlerFunc {
 	// anonymous function which takes in the same context input as gin.HandlerFunc
 	// this anonymous function is in fact, the authentication middleware
 	return func(ctx *gin.Context) {
@@ -50,6 +75,16 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 		// authorization type should be the first element of the fields slice
 		// strings.ToLower converts it to lower case - easier to compare if we know the data is all lower case
 		authorizationType := strings.ToLower(fields[0])
+		if authorizationType == authorizationTypeAPIKey {
+			// the API key should be the second element of the fields slice
+			payload, ok := authenticateAPIKey(ctx, store, fields[1])
+			if !ok {
+				return
+			}
+			ctx.Set(authorizationPayloadKey, payload)
+			ctx.Next()
+			return
+		}
 		if authorizationType != authorizationTypeBearer {
 			// create the error
 			err := fmt.Errorf("unsupported authorization type %s", authorizationType)
@@ -65,6 +100,16 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
 			return
 		}
+		// a revoked token is unauthorized, but failing to read the denylist is a server error
+		err = token.CheckDenylist(ctx, denylist, payload)
+		if err != nil {
+			if errors.Is(err, token.ErrRevokedToken) {
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+				return
+			}
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
 		// storing the payload in the gin context using authorizationPayloadKey
 		// allows us to retrieve the payload data from the context using the same key
 		ctx.Set(authorizationPayloadKey, payload)
@@ -72,3 +117,94 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+// authenticateAPIKey looks up the API key and returns the payload of its user if the key has the scope of the route - if it
+// returns false, the request has already been aborted with the error
+func authenticateAPIKey(ctx *gin.Context, store db.Store, apiKey string) (*token.Payload, bool) {
+	// only the hash of the key is stored
+	key, err := store.GetActiveAPIKeyByHash(ctx, util.HashSecretToken(apiKey))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(errInvalidAPIKey))
+			return nil, false
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+		return nil, false
+	}
+
+	// the role is the current role of the user, so demoting a user limits their keys as well
+	payload := token.NewAPIKeyPayload(key.ID, key.Username, key.Role, key.Scopes, key.CreatedAt, key.ExpiresAt.Time)
+
+	scope, ok := apiKeyRouteScopes[ctx.Request.Method+" "+ctx.FullPath()]
+	if !ok {
+		err := errors.New("API keys are not allowed to access this route")
+		ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+		return nil, false
+	}
+	if !payload.HasScope(scope) {
+		err := fmt.Errorf("API key doesn't have the %s scope", scope)
+		ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+		return nil, false
+	}
+
+	return payload, true
+}
+
+// roleMiddleware returns middleware which only lets users with one of the input roles through to the handler - it reads
+// the payload stored by the authMiddleware, so it must be added to the route group after the authMiddleware
+func roleMiddleware(accessibleRoles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+		if !hasRole(authPayload, accessibleRoles...) {
+			err := fmt.Errorf("role %s is not allowed to access this route", authPayload.Role)
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// verifiedEmailMiddleware returns middleware which only lets users who verified their email through to the handler when
+// required is true (see REQUIRE_VERIFIED_EMAIL in app.env) - like the roleMiddleware, it must run after the authMiddleware
+func verifiedEmailMiddleware(store db.Store, required bool) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !required {
+			ctx.Next()
+			return
+		}
+
+		authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+		user, err := store.GetUser(ctx, authPayload.Username)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				ctx.AbortWithStatusJSON(http.StatusNotFound, errorResponse(err))
+				return
+			}
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		if !user.IsEmailVerified {
+			err := errors.New("email must be verified first")
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// hasRole returns true if the access token was created for a user with one of the input roles
+func hasRole(authPayload *token.Payload, roles ...string) bool {
+	for _, role := range roles {
+		if authPayload.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+// isOwnerOrAdmin returns true if the logged in user owns the resource or is an admin - admins may read and manage the
+// resources of every user, depositors only their own
+func isOwnerOrAdmin(authPayload *token.Payload, owner string) bool {
+	return authPayload.Username == owner || hasRole(authPayload, util.AdminRole)
+}
@@ -1,15 +1,21 @@
 package api
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	mockdb "SimpleBankProject/db/mock"
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
 	"SimpleBankProject/token"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -20,10 +26,11 @@ func addAuthorization(
 	tokenMaker token.Maker,
 	authorizationType string,
 	username string,
+	role string,
 	duration time.Duration,
 ) {
 	// create token
-	token, payload, err := tokenMaker.CreateToken(username, duration)
+	token, payload, err := tokenMaker.CreateToken(username, role, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, payload)
 	// create authorization header - remember, it should be two strings separated by a space
@@ -46,7 +53,7 @@ func TestAuthMiddleware(t *testing.T) {
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				// create an access token and add it to the authorization header
 				// we use user as the username and give the token a duration of one minute
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", util.DepositorRole, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
@@ -67,7 +74,7 @@ func TestAuthMiddleware(t *testing.T) {
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				// create an access token and add it to the authorization header
 				// we use user as the username and give the token a duration of one minute
-				addAuthorization(t, request, tokenMaker, "unsupported", "user", time.Minute)
+				addAuthorization(t, request, tokenMaker, "unsupported", "user", util.DepositorRole, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -78,7 +85,7 @@ func TestAuthMiddleware(t *testing.T) {
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				// create an access token and add it to the authorization header
 				// we use user as the username and give the token a duration of one minute
-				addAuthorization(t, request, tokenMaker, "", "user", time.Minute)
+				addAuthorization(t, request, tokenMaker, "", "user", util.DepositorRole, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -89,7 +96,7 @@ func TestAuthMiddleware(t *testing.T) {
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				// create an access token and add it to the authorization header
 				// we use user as the username and give the token a duration of one minute
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", -time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", util.DepositorRole, -time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -109,7 +116,7 @@ func TestAuthMiddleware(t *testing.T) {
 			// adding a simple route for the sake of testing only
 			authPath := "/auth"
 			server.router.GET(authPath,
-				authMiddleware(server.tokenMaker),
+				authMiddleware(server.tokenMaker, server.denylist, server.store),
 				// for testing purposes, we write a simple handler
 				func(ctx *gin.Context) {
 					// for testing purposes, we simply return Status OK 200
@@ -130,3 +137,167 @@ func TestAuthMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthMiddlewareRevokedToken(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	authPath := "/auth"
+	server.router.GET(authPath,
+		authMiddleware(server.tokenMaker, server.denylist, server.store),
+		func(ctx *gin.Context) {
+			ctx.JSON(http.StatusOK, gin.H{})
+		},
+	)
+
+	accessToken, payload, err := server.tokenMaker.CreateToken("user", util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+
+	// sendRequest sends a request with the access token and returns the status code of the response
+	sendRequest := func() int {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, authPath, nil)
+		require.NoError(t, err)
+		request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, accessToken))
+		server.router.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	// the token is accepted until it is revoked, even though it hasn't expired yet
+	require.Equal(t, http.StatusOK, sendRequest())
+	require.NoError(t, server.denylist.Deny(context.Background(), payload))
+	require.Equal(t, http.StatusUnauthorized, sendRequest())
+}
+
+func TestRoleMiddleware(t *testing.T) {
+	testCases := []struct {
+		name          string
+		role          string
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			role: util.AdminRole,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Forbidden Role",
+			role: util.DepositorRole,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestServer(t, nil)
+
+			// a route only admins can reach - the roleMiddleware reads the payload stored by the authMiddleware
+			adminPath := "/admin-only"
+			server.router.GET(adminPath,
+				authMiddleware(server.tokenMaker, server.denylist, server.store),
+				roleMiddleware(util.AdminRole),
+				func(ctx *gin.Context) {
+					ctx.JSON(http.StatusOK, gin.H{})
+				},
+			)
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, adminPath, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "user", tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestVerifiedEmailMiddleware(t *testing.T) {
+	user, _ := randomUser(t)
+	verifiedUser := user
+	verifiedUser.IsEmailVerified = true
+
+	testCases := []struct {
+		name          string
+		required      bool
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "Verified",
+			required: true,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(verifiedUser, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "Not Verified",
+			required: true,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "Not Required",
+			required: false,
+			buildStubs: func(store *mockdb.MockStore) {
+				// the user isn't looked up at all
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "Internal Error",
+			required: true,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+
+			// a route only users with a verified email can reach when it is required
+			verifiedPath := "/verified-only"
+			server.router.GET(verifiedPath,
+				authMiddleware(server.tokenMaker, server.denylist, server.store),
+				verifiedEmailMiddleware(server.store, tc.required),
+				func(ctx *gin.Context) {
+					ctx.JSON(http.StatusOK, gin.H{})
+				},
+			)
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, verifiedPath, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
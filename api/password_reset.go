@@ -0,0 +1,111 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+	"time"
+
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/mail"
+
+	"github.com/gin-gonic/gin"
+)
+
+// the same answer is sent whether the email belongs to a user or not, so the route can't be used to find out who has an
+// account at the bank
+const passwordResetSentMessage = "if the email belongs to a user, a password reset link has been sent to it"
+
+type requestPasswordResetRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type requestPasswordResetResponse struct {
+	Message string `json:"message"`
+}
+
+// requestPasswordReset emails a single use link to reset the password of the user with the email - only the hash of the
+// token in the link is stored
+func (server *Server) requestPasswordReset(ctx *gin.Context) {
+	var req requestPasswordResetRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	resetToken, err := util.NewSecretToken()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.CreatePasswordResetTX(ctx, db.CreatePasswordResetTxParams{
+		Email:     req.Email,
+		TokenHash: util.HashSecretToken(resetToken),
+		ExpiresAt: time.Now().Add(server.config.PasswordResetTokenDuration),
+	})
+	if err != nil && err != sql.ErrNoRows {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if err == nil {
+		server.sendPasswordResetEmail(ctx, result.User, resetToken)
+	}
+
+	ctx.JSON(http.StatusOK, requestPasswordResetResponse{Message: passwordResetSentMessage})
+}
+
+// sendPasswordResetEmail sends the token to the user - it links to the password reset page in the config
+// it is called once CreatePasswordResetTX has committed - the response is the same whether the email could be sent or not, so a
+// failed email is only logged and the user can request another one
+func (server *Server) sendPasswordResetEmail(ctx context.Context, user db.User, resetToken string) {
+	msg := mail.PasswordResetMessage(
+		user.Email,
+		user.FullName,
+		server.config.PasswordResetURL,
+		resetToken,
+		server.config.PasswordResetTokenDuration,
+	)
+	if err := server.mailer.SendEmail(ctx, msg); err != nil {
+		log.Printf("cannot send password reset email to user %s: %s", user.Username, err)
+	}
+}
+
+type confirmPasswordResetRequest struct {
+	Token    string `json:"token" binding:"required,len=43"`   // util.SecretTokenLength
+	Password string `json:"password" binding:"required,min=6"` // the new password
+}
+
+// confirmPasswordReset sets the new password of the user the token was sent to - every session of the user is blocked, so
+// they have to login again with the new password
+func (server *Server) confirmPasswordReset(ctx *gin.Context) {
+	var req confirmPasswordResetRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	hashedPassword, err := server.hasher.Hash(req.Password)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.ResetPasswordTX(ctx, db.ResetPasswordTxParams{
+		TokenHash:      util.HashSecretToken(req.Token),
+		HashedPassword: hashedPassword,
+	})
+	if err != nil {
+		// the token is wrong, was already used or has expired
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(result.User))
+}
@@ -0,0 +1,258 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "SimpleBankProject/db/mock"
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/mail"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestPasswordResetAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, mailer *mail.MemoryMailer)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"email": user.Email,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreatePasswordResetTX(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreatePasswordResetTxParams) (db.CreatePasswordResetTxResult, error) {
+						require.Equal(t, user.Email, arg.Email)
+						require.Len(t, arg.TokenHash, 64)
+						require.WithinDuration(t, time.Now().Add(15*time.Minute), arg.ExpiresAt, time.Second)
+
+						// the email is sent to the user of the result once the transaction has committed
+						passwordReset := db.PasswordReset{ID: 1, Username: user.Username, TokenHash: arg.TokenHash}
+						return db.CreatePasswordResetTxResult{User: user, PasswordReset: passwordReset}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, mailer *mail.MemoryMailer) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				// the email links to the reset page with a token whose hash was stored
+				messages := mailer.Messages()
+				require.Len(t, messages, 1)
+				require.Equal(t, []string{user.Email}, messages[0].To)
+				require.Contains(t, messages[0].Body, "http://localhost:8080/reset_password?token=")
+			},
+		},
+		{
+			name: "Unknown Email",
+			body: gin.H{
+				"email": util.RandomEmail(),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreatePasswordResetTX(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CreatePasswordResetTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, mailer *mail.MemoryMailer) {
+				// the same answer as for a known email, but nothing is sent
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), passwordResetSentMessage)
+				require.Empty(t, mailer.Messages())
+			},
+		},
+		{
+			name: "Internal Error",
+			body: gin.H{
+				"email": user.Email,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreatePasswordResetTX(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CreatePasswordResetTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, mailer *mail.MemoryMailer) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "Invalid Email",
+			body: gin.H{
+				"email": "invalid-email",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreatePasswordResetTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, mailer *mail.MemoryMailer) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			mailer := mail.NewMemoryMailer()
+			server.mailer = mailer
+			server.config.PasswordResetURL = "http://localhost:8080/reset_password"
+			server.config.PasswordResetTokenDuration = 15 * time.Minute
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/password_reset", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder, mailer)
+		})
+	}
+}
+
+func TestConfirmPasswordResetAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	resetToken, err := util.NewSecretToken()
+	require.NoError(t, err)
+	newPassword := util.RandomString(8)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"token":    resetToken,
+				"password": newPassword,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ResetPasswordTX(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.ResetPasswordTxParams) (db.ResetPasswordTxResult, error) {
+						// only the hash of the token is looked up
+						require.Equal(t, util.HashSecretToken(resetToken), arg.TokenHash)
+						require.NoError(t, util.CheckPassword(newPassword, arg.HashedPassword))
+
+						updatedUser := user
+						updatedUser.HashedPassword = arg.HashedPassword
+						return db.ResetPasswordTxResult{User: updatedUser, BlockedSessions: 1}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchUser(t, recorder.Body, user)
+			},
+		},
+		{
+			name: "Invalid Token",
+			body: gin.H{
+				"token":    resetToken,
+				"password": newPassword,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				// the token is wrong, was already used or has expired
+				store.EXPECT().
+					ResetPasswordTX(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ResetPasswordTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "Internal Error",
+			body: gin.H{
+				"token":    resetToken,
+				"password": newPassword,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ResetPasswordTX(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ResetPasswordTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "Malformed Token",
+			body: gin.H{
+				"token":    util.RandomString(10),
+				"password": newPassword,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ResetPasswordTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Password Too Short",
+			body: gin.H{
+				"token":    resetToken,
+				"password": "123",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ResetPasswordTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/password_reset/confirm", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"SimpleBankProject/ratelimit"
+	"SimpleBankProject/token"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errRateLimited is sent to the client when it has sent too many requests
+var errRateLimited = errors.New("too many requests, try again later")
+
+// rateLimitRouteGroups maps the routes (method and path as registered with the router) which don't belong to
+// ratelimit.GroupDefault to their group
+var rateLimitRouteGroups = map[string]string{
+	"POST /users":                        ratelimit.GroupAuth,
+	"POST /users/login":                  ratelimit.GroupAuth,
+	"POST /users/login/mfa":              ratelimit.GroupAuth,
+	"GET /users/verify_email":            ratelimit.GroupAuth,
+	"POST /users/password_reset":         ratelimit.GroupAuth,
+	"POST /users/password_reset/confirm": ratelimit.GroupAuth,
+	"POST /tokens/renew_access":          ratelimit.GroupAuth,
+	"POST /tokens/revoke":                ratelimit.GroupAuth,
+	"POST /transfers":                    ratelimit.GroupTransfers,
+	"POST /transfers/:id/reverse":        ratelimit.GroupTransfers,
+	"POST /deposits":                     ratelimit.GroupTransfers,
+	"POST /withdrawals":                  ratelimit.GroupTransfers,
+}
+
+// rateLimitMiddleware limits the requests of each client with a token bucket per route group - on the routes of the
+// authMiddleware it must run after it, so requests are counted per logged in user instead of per client IP
+// a client which has used up its bucket gets 429 Too Many Requests, with the seconds until it may try again in the
+// Retry-After header
+func rateLimitMiddleware(limiter ratelimit.Limiter, limits ratelimit.Limits) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		group, ok := rateLimitRouteGroups[ctx.Request.Method+" "+ctx.FullPath()]
+		if !ok {
+			group = ratelimit.GroupDefault
+		}
+
+		// the router doesn't trust any proxy, so ClientIP is the address of the connection - the X-Forwarded-For header is
+		// set by the client, which could send a new one with every request to get a new bucket
+		key := ratelimit.ClientIPKey(group, ctx.ClientIP())
+		if payload, ok := ctx.Get(authorizationPayloadKey); ok {
+			key = ratelimit.UserKey(group, payload.(*token.Payload).Username)
+		}
+
+		if allowRequest(ctx, limiter, key, limits[group]) {
+			ctx.Next()
+		}
+	}
+}
+
+// clientIPRateLimitMiddleware limits every request of a client IP with the ratelimit.GroupClientIP bucket - it must run
+// before the authMiddleware, which rejects a bad access token or API key before the rateLimitMiddleware counts it
+func clientIPRateLimitMiddleware(limiter ratelimit.Limiter, limits ratelimit.Limits) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := ratelimit.ClientIPKey(ratelimit.GroupClientIP, ctx.ClientIP())
+		if allowRequest(ctx, limiter, key, limits[ratelimit.GroupClientIP]) {
+			ctx.Next()
+		}
+	}
+}
+
+// allowRequest takes a token from the bucket of the key - if the request isn't allowed it is aborted with 429 Too Many
+// Requests and a Retry-After header, and false is returned
+func allowRequest(ctx *gin.Context, limiter ratelimit.Limiter, key string, limit ratelimit.Limit) bool {
+	if !limit.Enabled() {
+		return true
+	}
+
+	result, err := limiter.Allow(ctx, key, limit)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+	if !result.Allowed {
+		retryAfter := math.Ceil(result.RetryAfter.Seconds())
+		ctx.Header("Retry-After", strconv.Itoa(int(retryAfter)))
+		ctx.AbortWithStatusJSON(http.StatusTooManyRequests, errorResponse(errRateLimited))
+		return false
+	}
+	return true
+}
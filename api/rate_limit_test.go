@@ -0,0 +1,173 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "SimpleBankProject/db/mock"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/ratelimit"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// newRateLimitedTestServer returns a test server with the input limits - the routes hold the limits they were set up with,
+// so they are set up again
+func newRateLimitedTestServer(t *testing.T, store *mockdb.MockStore, limits ratelimit.Limits) *Server {
+	server := newTestServer(t, store)
+	server.limiter = ratelimit.NewMemoryLimiter()
+	server.rateLimits = limits
+	server.setupRouter()
+	return server
+}
+
+func sendRequest(server *Server, method string, url string, clientIP string, setupAuth func(request *http.Request)) *httptest.ResponseRecorder {
+	// the invalid JSON body is rejected by the handlers before they use the store
+	request := httptest.NewRequest(method, url, strings.NewReader("{"))
+	request.RemoteAddr = clientIP + ":1234"
+	if setupAuth != nil {
+		setupAuth(request)
+	}
+
+	recorder := httptest.NewRecorder()
+	server.router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func requireRateLimited(t *testing.T, recorder *httptest.ResponseRecorder, retryAfter string) {
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	require.Equal(t, retryAfter, recorder.Header().Get("Retry-After"))
+	require.Contains(t, recorder.Body.String(), errRateLimited.Error())
+}
+
+func TestRateLimitPerClientIP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newRateLimitedTestServer(t, store, ratelimit.Limits{
+		ratelimit.GroupAuth: {Rate: 1.0 / 60, Burst: 2},
+	})
+
+	for i := 0; i < 2; i++ {
+		recorder := sendRequest(server, http.MethodPost, "/users/login", "192.0.2.1", nil)
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+	}
+	recorder := sendRequest(server, http.MethodPost, "/users/login", "192.0.2.1", nil)
+	requireRateLimited(t, recorder, "60")
+
+	// a client can't get a new bucket by sending an X-Forwarded-For header
+	recorder = sendRequest(server, http.MethodPost, "/users/login", "192.0.2.1", func(request *http.Request) {
+		request.Header.Set("X-Forwarded-For", "203.0.113.7")
+	})
+	requireRateLimited(t, recorder, "60")
+
+	// the routes of a group share the bucket
+	recorder = sendRequest(server, http.MethodPost, "/users", "192.0.2.1", nil)
+	requireRateLimited(t, recorder, "60")
+
+	// other clients have their own bucket
+	recorder = sendRequest(server, http.MethodPost, "/users/login", "192.0.2.2", nil)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	// routes of other groups aren't limited by the group
+	recorder = sendRequest(server, http.MethodGet, "/tokens/public_keys", "192.0.2.1", nil)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestRateLimitPerUser(t *testing.T) {
+	user1, _ := randomUser(t)
+	user2, _ := randomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newRateLimitedTestServer(t, store, ratelimit.Limits{
+		ratelimit.GroupTransfers: {Rate: 1, Burst: 1},
+		ratelimit.GroupDefault:   {Rate: 0.5, Burst: 1},
+	})
+	auth := func(username string) func(request *http.Request) {
+		return func(request *http.Request) {
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, username, util.DepositorRole, time.Minute)
+		}
+	}
+
+	recorder := sendRequest(server, http.MethodPost, "/transfers", "192.0.2.1", auth(user1.Username))
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	recorder = sendRequest(server, http.MethodPost, "/transfers", "192.0.2.1", auth(user1.Username))
+	requireRateLimited(t, recorder, "1")
+
+	// the requests of logged in users are counted per user, whichever IP they come from
+	recorder = sendRequest(server, http.MethodPost, "/transfers", "192.0.2.2", auth(user1.Username))
+	requireRateLimited(t, recorder, "1")
+	recorder = sendRequest(server, http.MethodPost, "/transfers", "192.0.2.1", auth(user2.Username))
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	// the other routes have their own limit
+	recorder = sendRequest(server, http.MethodPost, "/accounts", "192.0.2.1", auth(user1.Username))
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	recorder = sendRequest(server, http.MethodPost, "/accounts", "192.0.2.1", auth(user1.Username))
+	requireRateLimited(t, recorder, "2")
+	recorder = sendRequest(server, http.MethodPost, "/admin/sessions/1/block", "192.0.2.1", auth(user1.Username))
+	requireRateLimited(t, recorder, "2")
+
+	// requests without a valid access token are rejected before the per user limits count them
+	recorder = sendRequest(server, http.MethodPost, "/accounts", "192.0.2.1", nil)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestRateLimitBeforeAuthorization(t *testing.T) {
+	user, _ := randomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newRateLimitedTestServer(t, store, ratelimit.Limits{
+		ratelimit.GroupClientIP: {Rate: 1.0 / 60, Burst: 2},
+	})
+	badToken := func(request *http.Request) {
+		request.Header.Set(authorizationHeaderKey, authorizationTypeBearer+" invalid-token")
+	}
+
+	// requests with a bad access token are counted per client IP before they are rejected
+	for i := 0; i < 2; i++ {
+		recorder := sendRequest(server, http.MethodPost, "/accounts", "192.0.2.1", badToken)
+		require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	}
+	recorder := sendRequest(server, http.MethodPost, "/accounts", "192.0.2.1", badToken)
+	requireRateLimited(t, recorder, "60")
+	recorder = sendRequest(server, http.MethodPost, "/admin/sessions/1/block", "192.0.2.1", badToken)
+	requireRateLimited(t, recorder, "60")
+
+	// the limit counts every request of the client IP, logged in or not
+	recorder = sendRequest(server, http.MethodPost, "/accounts", "192.0.2.1", func(request *http.Request) {
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+	})
+	requireRateLimited(t, recorder, "60")
+	recorder = sendRequest(server, http.MethodPost, "/users/login", "192.0.2.1", nil)
+	requireRateLimited(t, recorder, "60")
+
+	// other clients have their own bucket
+	recorder = sendRequest(server, http.MethodPost, "/accounts", "192.0.2.2", badToken)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestRateLimitDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newRateLimitedTestServer(t, store, ratelimit.Limits{})
+
+	for i := 0; i < 10; i++ {
+		recorder := sendRequest(server, http.MethodPost, "/users/login", fmt.Sprintf("192.0.2.%d", i%2), nil)
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+	}
+}
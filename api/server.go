@@ -5,7 +5,10 @@ import (
 
 	db "SimpleBankProject/db/sqlc"
 	"SimpleBankProject/db/util"
+	"SimpleBankProject/mail"
+	"SimpleBankProject/ratelimit"
 	"SimpleBankProject/token"
+	"SimpleBankProject/totp"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
@@ -19,21 +22,45 @@ type Server struct {
 	config     util.Config
 	store      db.Store // Package db, Store interface - defined in store.go - for interacting with the db while processing api requests
 	tokenMaker token.Maker
-	router     *gin.Engine // Router helps send each api request to the correct handler
+	denylist   token.Denylist       // access tokens revoked before they expired
+	mailer     mail.Mailer          // sends the verification and password reset emails
+	totpCipher *totp.SecretCipher   // encrypts the TOTP secrets of two-factor authentication
+	hasher     *util.PasswordHasher // hashes passwords with the algorithm of the config
+	limiter    ratelimit.Limiter    // counts the requests of each client against the rate limits
+	rateLimits ratelimit.Limits     // the rate limit of each route group
+	router     *gin.Engine          // Router helps send each api request to the correct handler
 }
 
 // NewServer creates a new HTTP server and sets up routing
-func NewServer(config util.Config, store db.Store) (*Server, error) {
-	// initialize tokenMaker, symmetric key will come from the environment variable
-	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricKey)
+func NewServer(config util.Config, store db.Store, mailer mail.Mailer) (*Server, error) {
+	// initialize tokenMaker, the config selects the maker and its keys (see token.NewMaker)
+	tokenMaker, err := token.NewMaker(config)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create token maker: %w", err)
 	}
+	totpCipher, err := totp.NewSecretCipher(config.TOTPEncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create TOTP secret cipher: %w", err)
+	}
+	hasher, err := util.NewPasswordHasher(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create password hasher: %w", err)
+	}
+	rateLimits, err := ratelimit.NewLimits(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse rate limits: %w", err)
+	}
 	// Server struct, store property, initialized to store which we pass in
 	server := &Server{
 		config:     config,
 		store:      store,
 		tokenMaker: tokenMaker,
+		denylist:   db.NewDenylist(store, config.TokenDenylistCacheDuration),
+		mailer:     mailer,
+		totpCipher: totpCipher,
+		hasher:     hasher,
+		limiter:    ratelimit.NewMemoryLimiter(),
+		rateLimits: rateLimits,
 	}
 
 	// registering custom validator with gin
@@ -45,6 +72,8 @@ func NewServer(config util.Config, store db.Store) (*Server, error) {
 		// currency is the name of the validation tag
 		// validCurrency is the method in validator.go
 		v.RegisterValidation("currency", validCurrency)
+		// scope validates the scopes of API keys
+		v.RegisterValidation("scope", validScope)
 	}
 	//setup routes
 	server.setupRouter()
@@ -64,15 +93,27 @@ func (server *Server) Start(address string) error {
 
 func (server *Server) setupRouter() {
 	router := gin.Default()
+	// gin trusts the X-Forwarded-For header of every client by default, so ctx.ClientIP() would return whatever the client
+	// sends - without trusted proxies it is the address of the connection, which the login lockout and the rate limits
+	// can count on (SetTrustedProxies only fails on an invalid proxy, nil has none)
+	_ = router.SetTrustedProxies(nil)
+	// every route is rate limited - the rateLimit middleware runs after the authMiddleware, so the requests of logged in
+	// users are counted per user, and the requests of the routes without authorization per client IP
+	rateLimit := rateLimitMiddleware(server.limiter, server.rateLimits)
+	// the clientIPRateLimit middleware runs first and counts every request per client IP, so requests with a bad access
+	// token or API key, which the authMiddleware rejects before the rateLimit middleware, are limited too
+	clientIPRateLimit := clientIPRateLimitMiddleware(server.limiter, server.rateLimits)
 	// adding routes to router
 	// grouping routes that require the authMiddleware for authorization
 	// the "/" is the path prefix for all routes in this group
-	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker))
+	authRoutes := router.Group("/").Use(clientIPRateLimit, authMiddleware(server.tokenMaker, server.denylist, server.store), rateLimit)
 
 	// creating account
 	// "/accounts" is the path, can pass 1+ handler functions
 	// if you do, last function should be "real handler" and all other functions are middleware
-	authRoutes.POST("/accounts", server.createAccount) // createAccount - method of the Server struct - handler
+	// verifiedEmail only lets users who verified their email create accounts and transfer money if the config requires it
+	verifiedEmail := verifiedEmailMiddleware(server.store, server.config.RequireVerifiedEmail)
+	authRoutes.POST("/accounts", verifiedEmail, server.createAccount) // createAccount - method of the Server struct - handler
 	// get account by id
 	// "/accounts/:id" path to account with ID - the colon tells Gin that the ID is a URI parameter
 	// URI (Unique Resource Identifier) is a resource identifier passed as a parameter in the URL
@@ -80,28 +121,77 @@ func (server *Server) setupRouter() {
 	// get a list of accounts with pagination
 	// the path is left as /accounts since the query parameters will be obtained from the query itself
 	authRoutes.GET("/accounts", server.listAccount) // listAccount - method of the Server struct - handler
-	// update an account's balance
-	// "/accounts" path to accounts
-	authRoutes.PATCH("/accounts", server.updateAccount) // updateAccount - method of the Server struct - handler
 	// delete an account
 	// "/accounts/:id" path to account with ID - the colon tells Gin that the ID is a URI parameter
 	authRoutes.DELETE("/accounts/:id", server.deleteAccount) //deleteAccount - method of the Server struct - handler
+	// an account's history - the entries (balance changes) and the transfers going in to and out of it
+	authRoutes.GET("/accounts/:id/entries", server.listEntries)     // listEntries - method of the Server struct - handler
+	authRoutes.GET("/accounts/:id/transfers", server.listTransfers) // listTransfers - method of the Server struct - handler
 	// transfer money from FromAccountID to ToAccountID
 	// "/transfers" path to the transfers table
-	authRoutes.POST("/transfers", server.createTransfer) // createTransfer - method of the Server struct - handler
+	authRoutes.POST("/transfers", verifiedEmail, server.createTransfer) // createTransfer - method of the Server struct - handler
+	// reverse a transfer received by one of the logged in user's accounts
+	// "/transfers/:id/reverse" path to the transfer with ID - the colon tells Gin that the ID is a URI parameter
+	authRoutes.POST("/transfers/:id/reverse", server.reverseTransfer) // reverseTransfer - method of the Server struct - handler
+	// money entering and leaving the bank - balances are never overwritten directly, every change is recorded as an entry
+	// deposits are booked by the back office when the money arrives, so only admins can create them
+	authRoutes.POST("/deposits", roleMiddleware(util.AdminRole), server.createDeposit) // createDeposit - method of the Server struct - handler
+	authRoutes.POST("/withdrawals", server.createWithdrawal)                           // createWithdrawal - method of the Server struct - handler
+
+	// the active sessions of the logged in user and revoking one of them (e.g. a lost device)
+	authRoutes.GET("/sessions", server.listSessions)         // listSessions - method of the Server struct - handler
+	authRoutes.DELETE("/sessions/:id", server.revokeSession) // revokeSession - method of the Server struct - handler
+	// change the full name, email or password of the logged in user
+	authRoutes.PATCH("/users/:username", server.updateUser) // updateUser - method of the Server struct - handler
+	// two-factor authentication of the logged in user - enroll creates a TOTP secret, confirm enables it with a code from
+	// the authenticator app and returns the recovery codes, disable turns it off again
+	authRoutes.POST("/users/mfa/enroll", server.enrollMFA)   // enrollMFA - method of the Server struct - handler
+	authRoutes.POST("/users/mfa/confirm", server.confirmMFA) // confirmMFA - method of the Server struct - handler
+	authRoutes.POST("/users/mfa/disable", server.disableMFA) // disableMFA - method of the Server struct - handler
+	// API keys of the logged in user for server-to-server clients - the key is only sent back when it is created
+	// API keys themselves can't call these routes, so a leaked key can't create more keys
+	authRoutes.POST("/api_keys", server.createAPIKey)       // createAPIKey - method of the Server struct - handler
+	authRoutes.GET("/api_keys", server.listAPIKeys)         // listAPIKeys - method of the Server struct - handler
+	authRoutes.DELETE("/api_keys/:id", server.revokeAPIKey) // revokeAPIKey - method of the Server struct - handler
+
+	// routes only admins can use - the roleMiddleware runs after the authMiddleware, which stores the access token payload
+	adminRoutes := router.Group("/admin").Use(clientIPRateLimit, authMiddleware(server.tokenMaker, server.denylist, server.store), rateLimit, roleMiddleware(util.AdminRole))
+	// freeze and unfreeze an account - no money can move in to or out of a frozen account
+	adminRoutes.POST("/accounts/:id/freeze", server.freezeAccount)     // freezeAccount - method of the Server struct - handler
+	adminRoutes.POST("/accounts/:id/unfreeze", server.unfreezeAccount) // unfreezeAccount - method of the Server struct - handler
+	// block a session so its refresh token can no longer renew access tokens
+	adminRoutes.POST("/sessions/:id/block", server.blockSession) // blockSession - method of the Server struct - handler
 
 	// no authorization required:
+	// the requests of these routes are counted per client IP by the rateLimit middleware
+	publicRoutes := router.Group("/").Use(clientIPRateLimit, rateLimit)
 	// create user account
 	// "/users" path to the users table
 	// no authorization needed as everyone should be able to create a user
-	router.POST("/users", server.createUser) // createUser - method of the Server struct - handler
+	publicRoutes.POST("/users", server.createUser) // createUser - method of the Server struct - handler
 	// user login
 	// "/users/login" path for loginUser handler
 	// no authorization needed as everyone should be able to login
-	router.POST("/users/login", server.loginUser) // loginUser - method of the Server struct - handler
+	publicRoutes.POST("/users/login", server.loginUser) // loginUser - method of the Server struct - handler
+	// second step of the login of users with two-factor authentication
+	// no authorization needed as the MFA token sent by loginUser proves the password was checked
+	publicRoutes.POST("/users/login/mfa", server.loginUserMFA) // loginUserMFA - method of the Server struct - handler
+	// verify email - the link in the verification email points here
+	// no authorization needed as the secret code proves the user received the email
+	publicRoutes.GET("/users/verify_email", server.verifyEmail) // verifyEmail - method of the Server struct - handler
+	// forgotten password - emails a single use link, then the token from the link sets the new password
+	// no authorization needed as the user can't login, the token proves they received the email
+	publicRoutes.POST("/users/password_reset", server.requestPasswordReset)         // requestPasswordReset - handler
+	publicRoutes.POST("/users/password_reset/confirm", server.confirmPasswordReset) // confirmPasswordReset - handler
 	// renew access token
 	// "/tokens/renew_access" path for renewAccess handler
-	router.POST("/tokens/renew_access", server.renewAccessToken) // renewAccessToken - method of the Server struct - handler
+	publicRoutes.POST("/tokens/renew_access", server.renewAccessToken) // renewAccessToken - method of the Server struct - handler
+	// logout - blocks the session of the refresh token
+	// no authorization needed as the refresh token itself proves the session belongs to the caller
+	publicRoutes.POST("/tokens/revoke", server.revokeToken) // revokeToken - method of the Server struct - handler
+	// the public keys which verify the tokens, so other services can check them without calling this server
+	// no authorization needed as public keys can't create tokens
+	publicRoutes.GET("/tokens/public_keys", server.getTokenPublicKeys) // getTokenPublicKeys - method of the Server struct - handler
 
 	// update server.router with router object
 	server.router = router
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/token"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// sessionResponse is a session without its refresh token, which must never be sent back to a client
+type sessionResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Username  string    `json:"username"`
+	UserAgent string    `json:"user_agent"`
+	ClientIp  string    `json:"client_ip"`
+	IsBlocked bool      `json:"is_blocked"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newSessionResponse(session db.Session) sessionResponse {
+	return sessionResponse{
+		ID:        session.ID,
+		Username:  session.Username,
+		UserAgent: session.UserAgent,
+		ClientIp:  session.ClientIp,
+		IsBlocked: session.IsBlocked,
+		ExpiresAt: session.ExpiresAt,
+		CreatedAt: session.CreatedAt,
+	}
+}
+
+// listSessions lists the active sessions of the logged in user - one for every device or client the user has logged in
+// from, with its user agent and client IP so the user can spot sessions they don't recognize
+func (server *Server) listSessions(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	sessions, err := server.store.ListSessionsByUser(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]sessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		rsp = append(rsp, newSessionResponse(session))
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// the session to revoke - taken from the URI
+type revokeSessionRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// revokeSession blocks one of the sessions of the logged in user, e.g. a session on a lost device - its refresh token can no
+// longer renew access tokens
+func (server *Server) revokeSession(ctx *gin.Context) {
+	var req revokeSessionRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// the uuid binding tag has already confirmed the id can be parsed
+	session, err := server.store.GetSession(ctx, uuid.MustParse(req.ID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// users may only revoke their own sessions - admins may revoke any session
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if !isOwnerOrAdmin(authPayload, session.Username) {
+		err := errors.New("session doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	session, err = server.blockAndDenySession(ctx, session.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newSessionResponse(session))
+}
+
+// blockAndDenySession blocks the session, so its refresh token can no longer renew access tokens, and adds the refresh
+// token to the denylist - the session ID is the ID of its refresh token - refresh tokens created before they had a purpose
+// pass VerifyToken, and would otherwise keep working as access tokens until they expire
+// sql.ErrNoRows is returned as is if the session doesn't exist
+func (server *Server) blockAndDenySession(ctx *gin.Context, sessionID uuid.UUID) (db.Session, error) {
+	session, err := server.store.BlockSession(ctx, sessionID)
+	if err != nil {
+		return session, err
+	}
+
+	err = server.denylist.Deny(ctx, &token.Payload{
+		ID:        session.ID,
+		Username:  session.Username,
+		ExpiredAt: session.ExpiresAt,
+	})
+	return session, err
+}
@@ -2,22 +2,36 @@ package api
 
 import (
 	"database/sql"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
 
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/token"
+
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type renewAccessTokenRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
+// every renewal rotates the refresh token - the refresh token in the request can't be used again and the client must keep
+// the one in the response
 type renewAccessTokenResponse struct {
-	AccessToken          string    `json:"access_token"`
-	AccessTokenExpiresAt time.Time `json:"access_token_expires_at"`
+	SessionID             uuid.UUID `json:"session_id"`
+	AccessToken           string    `json:"access_token"`
+	AccessTokenExpiresAt  time.Time `json:"access_token_expires_at"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
 }
 
+// errRefreshTokenReused is sent to the client when a refresh token which was already rotated is used again
+var errRefreshTokenReused = errors.New("refresh token has already been used, all sessions of this login have been blocked")
+
 // renewAccessToken api handler
 func (server *Server) renewAccessToken(ctx *gin.Context) {
 	var req renewAccessTokenRequest
@@ -28,7 +42,7 @@ func (server *Server) renewAccessToken(ctx *gin.Context) {
 	}
 
 	// verify the refresh token is still valid
-	refreshPayload, err := server.tokenMaker.VerifyToken(req.RefreshToken)
+	refreshPayload, err := server.tokenMaker.VerifyRefreshToken(req.RefreshToken)
 	if err != nil {
 		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
@@ -71,6 +85,13 @@ func (server *Server) renewAccessToken(ctx *gin.Context) {
 		return
 	}
 
+	// a rotated refresh token is only ever presented again if someone else got hold of it - either the client or the thief
+	// is using an old copy, and since we can't tell which, every session created from the same login is blocked
+	if session.RotatedAt.Valid {
+		server.blockSessionFamily(ctx, session)
+		return
+	}
+
 	// reconfirming the session isn't expired - in rare cases, we may want to force the session to expire early
 	// checking if the current time is after the session.ExpiresAt value
 	if time.Now().After(session.ExpiresAt) {
@@ -79,18 +100,198 @@ func (server *Server) renewAccessToken(ctx *gin.Context) {
 		return
 	}
 
+	// the role may have changed since login, so the new tokens carry the current role of the user rather than the one
+	// in the refresh token
+	user, err := server.store.GetUser(ctx, refreshPayload.Username)
+	if err != nil {
+		// the user was deleted after logging in
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
 	// create new access token
-	accessToken, accessPayload, err := server.tokenMaker.CreateToken(refreshPayload.Username, server.config.AccessTokenDuration)
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(
+		user.Username,
+		user.Role,
+		server.config.AccessTokenDuration,
+	)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
+	// create the new refresh token - it expires with the session it replaces, so rotating doesn't extend the login
+	refreshToken, newRefreshPayload, err := server.tokenMaker.CreateRefreshToken(
+		user.Username,
+		user.Role,
+		time.Until(session.ExpiresAt),
+	)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.RotateSessionTX(ctx, db.RotateSessionTxParams{
+		SessionID: session.ID,
+		NewSession: db.CreateSessionParams{
+			ID:           newRefreshPayload.ID,
+			Username:     session.Username,
+			RefreshToken: refreshToken,
+			UserAgent:    ctx.Request.UserAgent(),
+			ClientIp:     ctx.ClientIP(),
+			IsBlocked:    false,
+			ExpiresAt:    newRefreshPayload.ExpiredAt,
+		},
+	})
+	if err != nil {
+		// another request rotated the session since we read it - the refresh token was used twice
+		if errors.Is(err, db.ErrSessionAlreadyRotated) {
+			server.blockSessionFamily(ctx, session)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
 	// create renewAccessTokenResponse
 	rsp := renewAccessTokenResponse{
-		AccessToken:          accessToken,
-		AccessTokenExpiresAt: accessPayload.ExpiredAt,
+		SessionID:             result.NewSession.ID,
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessPayload.ExpiredAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: newRefreshPayload.ExpiredAt,
 	}
 	// send renewAccessTokenResponse to the client with 200 Status OK code
 	ctx.JSON(http.StatusOK, rsp)
 }
+
+// blockSessionFamily blocks every session created from the same login as the input session, which revokes their refresh
+// tokens as well, and sends the client errRefreshTokenReused
+func (server *Server) blockSessionFamily(ctx *gin.Context, session db.Session) {
+	_, err := server.store.BlockSessionFamily(ctx, session.FamilyID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusUnauthorized, errorResponse(errRefreshTokenReused))
+}
+
+type revokeTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+	// optional - the access token the client is holding, it is added to the denylist so it stops working right away
+	// instead of when it expires
+	AccessToken string `json:"access_token"`
+}
+
+// revokeToken logs the user out by blocking the session of the refresh token - holding the refresh token is proof enough,
+// so no access token is needed
+func (server *Server) revokeToken(ctx *gin.Context) {
+	var req revokeTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	refreshPayload, err := server.tokenMaker.VerifyRefreshToken(req.RefreshToken)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	session, err := server.store.GetSession(ctx, refreshPayload.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// the same checks as renewAccessToken - the refresh token must be the one the session was created with
+	if session.Username != refreshPayload.Username {
+		err := fmt.Errorf("incorrect session user")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	if session.RefreshToken != req.RefreshToken {
+		err := fmt.Errorf("mismatched session token")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	// a rotated refresh token is treated like it is in renewAccessToken
+	if session.RotatedAt.Valid {
+		server.blockSessionFamily(ctx, session)
+		return
+	}
+
+	// an access token which has already expired doesn't need to be denied, so only its verification errors are returned
+	var accessPayload *token.Payload
+	if req.AccessToken != "" {
+		accessPayload, err = server.tokenMaker.VerifyToken(req.AccessToken)
+		if err != nil && !errors.Is(err, token.ErrExpiredToken) {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+		// a user may only revoke their own access token
+		if accessPayload != nil && accessPayload.Username != refreshPayload.Username {
+			err := fmt.Errorf("access token doesn't belong to the session user")
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+	}
+
+	// revoking a session twice is not an error - the user is logged out either way
+	session, err = server.blockAndDenySession(ctx, session.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if accessPayload != nil {
+		err = server.denylist.Deny(ctx, accessPayload)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, newSessionResponse(session))
+}
+
+type tokenPublicKeyResponse struct {
+	KeyID     string `json:"key_id"`     // the kid in the footer of the tokens signed by the matching private key
+	PublicKey string `json:"public_key"` // hex encoded Ed25519 public key
+}
+
+type getTokenPublicKeysResponse struct {
+	Version string                   `json:"version"` // the PASETO version and purpose of the tokens - v2.public
+	Keys    []tokenPublicKeyResponse `json:"keys"`    // the key of the current private key first
+}
+
+// getTokenPublicKeys api handler - only the "paseto_public" token maker has public keys, the symmetric makers can't share
+// their key
+func (server *Server) getTokenPublicKeys(ctx *gin.Context) {
+	maker, ok := server.tokenMaker.(token.PublicKeyMaker)
+	if !ok {
+		err := errors.New("token maker has no public keys")
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+		return
+	}
+
+	rsp := getTokenPublicKeysResponse{
+		Version: token.PublicKeyVersion,
+	}
+	for _, key := range maker.PublicKeys() {
+		rsp.Keys = append(rsp.Keys, tokenPublicKeyResponse{
+			KeyID:     key.KeyID,
+			PublicKey: hex.EncodeToString(key.PublicKey),
+		})
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
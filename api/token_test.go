@@ -0,0 +1,652 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "SimpleBankProject/db/mock"
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/mail"
+	"SimpleBankProject/token"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/o1egl/paseto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenewAccessTokenAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name string
+		// body builds the request body from the refresh token created for the session
+		body          func(refreshToken string) gin.H
+		buildStubs    func(store *mockdb.MockStore, session db.Session)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, refreshToken string)
+	}{
+		{
+			name: "OK",
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(session.Username)).Times(1).Return(user, nil)
+				store.EXPECT().
+					RotateSessionTX(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.RotateSessionTxParams) (db.RotateSessionTxResult, error) {
+						require.Equal(t, session.ID, arg.SessionID)
+						require.Equal(t, session.Username, arg.NewSession.Username)
+						// the new refresh token expires with the session it replaces
+						require.WithinDuration(t, session.ExpiresAt, arg.NewSession.ExpiresAt, time.Second)
+
+						rotatedSession := session
+						rotatedSession.RotatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+						newSession := db.Session{
+							ID:                arg.NewSession.ID,
+							Username:          arg.NewSession.Username,
+							RefreshToken:      arg.NewSession.RefreshToken,
+							ExpiresAt:         arg.NewSession.ExpiresAt,
+							FamilyID:          session.FamilyID,
+							PreviousSessionID: uuid.NullUUID{UUID: session.ID, Valid: true},
+						}
+						return db.RotateSessionTxResult{RotatedSession: rotatedSession, NewSession: newSession}, nil
+					})
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, refreshToken string) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp renewAccessTokenResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.NotEmpty(t, rsp.AccessToken)
+				// the refresh token is rotated
+				require.NotEmpty(t, rsp.RefreshToken)
+				require.NotEqual(t, refreshToken, rsp.RefreshToken)
+			},
+		},
+		{
+			// the user was deleted after logging in
+			name: "User Not Found",
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(session.Username)).Times(1).Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().RotateSessionTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, refreshToken string) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Reused Refresh Token",
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				// the refresh token was already rotated by an earlier renewal
+				session.RotatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().RotateSessionTX(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Eq(session.FamilyID)).Times(1).Return(int64(2), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, refreshToken string) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Concurrent Reuse",
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				// another request rotated the session after it was read
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(session.Username)).Times(1).Return(user, nil)
+				store.EXPECT().
+					RotateSessionTX(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RotateSessionTxResult{}, db.ErrSessionAlreadyRotated)
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Eq(session.FamilyID)).Times(1).Return(int64(2), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, refreshToken string) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Blocked Session",
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				session.IsBlocked = true
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().RotateSessionTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, refreshToken string) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Mismatched Session Token",
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				session.RefreshToken = util.RandomString(32)
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().RotateSessionTX(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, refreshToken string) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Internal Error",
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(session.Username)).Times(1).Return(user, nil)
+				store.EXPECT().
+					RotateSessionTX(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RotateSessionTxResult{}, sql.ErrConnDone)
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, refreshToken string) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "Invalid Token",
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": "invalid"}
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().RotateSessionTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, refreshToken string) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := newTestServer(t, store)
+
+			// the refresh token must be created by the token maker of the test server
+			refreshToken, refreshPayload, err := server.tokenMaker.CreateRefreshToken(user.Username, user.Role, time.Hour)
+			require.NoError(t, err)
+
+			session := randomSession(user.Username)
+			session.ID = refreshPayload.ID
+			session.FamilyID = refreshPayload.ID
+			session.RefreshToken = refreshToken
+			session.ExpiresAt = refreshPayload.ExpiredAt
+			tc.buildStubs(store, session)
+
+			data, err := json.Marshal(tc.body(refreshToken))
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, "/tokens/renew_access", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder, refreshToken)
+		})
+	}
+}
+
+// TestRenewAccessTokenCurrentRole - tests that the renewed tokens carry the current role of the user, not the role the
+// user had when logging in
+func TestRenewAccessTokenCurrentRole(t *testing.T) {
+	user, _ := randomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+
+	// the user was a depositor at login and has been made an admin since
+	refreshToken, refreshPayload, err := server.tokenMaker.CreateRefreshToken(user.Username, util.DepositorRole, time.Hour)
+	require.NoError(t, err)
+	user.Role = util.AdminRole
+
+	session := randomSession(user.Username)
+	session.ID = refreshPayload.ID
+	session.FamilyID = refreshPayload.ID
+	session.RefreshToken = refreshToken
+	session.ExpiresAt = refreshPayload.ExpiredAt
+
+	store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+	store.EXPECT().
+		RotateSessionTX(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.RotateSessionTxParams) (db.RotateSessionTxResult, error) {
+			newSession := db.Session{
+				ID:           arg.NewSession.ID,
+				Username:     arg.NewSession.Username,
+				RefreshToken: arg.NewSession.RefreshToken,
+				ExpiresAt:    arg.NewSession.ExpiresAt,
+			}
+			return db.RotateSessionTxResult{RotatedSession: session, NewSession: newSession}, nil
+		})
+
+	recorder := postJSON(t, server, "/tokens/renew_access", gin.H{"refresh_token": refreshToken}, nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp renewAccessTokenResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+
+	accessPayload, err := server.tokenMaker.VerifyToken(rsp.AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, util.AdminRole, accessPayload.Role)
+
+	newRefreshPayload, err := server.tokenMaker.VerifyRefreshToken(rsp.RefreshToken)
+	require.NoError(t, err)
+	require.Equal(t, util.AdminRole, newRefreshPayload.Role)
+}
+
+func TestRevokeTokenAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name string
+		// body builds the request body from the refresh token created for the session
+		body          func(refreshToken string) gin.H
+		buildStubs    func(store *mockdb.MockStore, session db.Session)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				blockedSession := session
+				blockedSession.IsBlocked = true
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().BlockSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(blockedSession, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var gotSession sessionResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &gotSession))
+				require.True(t, gotSession.IsBlocked)
+			},
+		},
+		{
+			name: "Invalid Token",
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": "invalid"}
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().BlockSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Missing Token",
+			body: func(refreshToken string) gin.H {
+				return gin.H{}
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Session Not Found",
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(1).Return(db.Session{}, sql.ErrNoRows)
+				store.EXPECT().BlockSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "Rotated Refresh Token",
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				// an old refresh token blocks the whole family instead of the single session
+				session.RotatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().BlockSession(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Eq(session.FamilyID)).Times(1).Return(int64(2), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Mismatched Session Token",
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				// the session was created with a different refresh token
+				session.RefreshToken = util.RandomString(32)
+				store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(1).Return(session, nil)
+				store.EXPECT().BlockSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := newTestServer(t, store)
+
+			// the refresh token must be created by the token maker of the test server
+			refreshToken, refreshPayload, err := server.tokenMaker.CreateRefreshToken(user.Username, user.Role, time.Hour)
+			require.NoError(t, err)
+
+			session := randomSession(user.Username)
+			session.ID = refreshPayload.ID
+			session.RefreshToken = refreshToken
+			tc.buildStubs(store, session)
+
+			data, err := json.Marshal(tc.body(refreshToken))
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, "/tokens/revoke", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestRevokeTokenWithAccessTokenAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name string
+		// accessTokenUser is the user the access token sent along with the refresh token is created for
+		accessTokenUser string
+		buildStubs      func(store *mockdb.MockStore, session db.Session)
+		checkResponse   func(t *testing.T, recorder *httptest.ResponseRecorder, denied bool)
+	}{
+		{
+			name:            "OK",
+			accessTokenUser: user.Username,
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				blockedSession := session
+				blockedSession.IsBlocked = true
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().BlockSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(blockedSession, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, denied bool) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				// the access token stops working right away
+				require.True(t, denied)
+			},
+		},
+		{
+			name:            "Access Token Of Another User",
+			accessTokenUser: "unauthorized_user",
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().BlockSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, denied bool) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.False(t, denied)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := newTestServer(t, store)
+
+			refreshToken, refreshPayload, err := server.tokenMaker.CreateRefreshToken(user.Username, user.Role, time.Hour)
+			require.NoError(t, err)
+			accessToken, accessPayload, err := server.tokenMaker.CreateToken(tc.accessTokenUser, user.Role, time.Minute)
+			require.NoError(t, err)
+
+			session := randomSession(user.Username)
+			session.ID = refreshPayload.ID
+			session.RefreshToken = refreshToken
+			tc.buildStubs(store, session)
+
+			data, err := json.Marshal(gin.H{
+				"refresh_token": refreshToken,
+				"access_token":  accessToken,
+			})
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, "/tokens/revoke", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+
+			denied, err := server.denylist.IsDenied(context.Background(), accessPayload)
+			require.NoError(t, err)
+			tc.checkResponse(t, recorder, denied)
+		})
+	}
+}
+
+// TestRevokedRefreshTokenAsBearerAPI - tests that a refresh token can't be used as an access token once its session was
+// revoked or blocked - refresh tokens created before they had a purpose pass VerifyToken, so those are rejected by the
+// denylist
+func TestRevokedRefreshTokenAsBearerAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name string
+		// createRefreshToken creates the refresh token of the session
+		createRefreshToken func(maker token.Maker) (string, *token.Payload, error)
+		// revoke revokes or blocks the session and returns the request
+		revoke func(t *testing.T, server *Server, refreshToken string, session db.Session) *http.Request
+	}{
+		{
+			name: "Revoke Token",
+			createRefreshToken: func(maker token.Maker) (string, *token.Payload, error) {
+				return maker.CreateRefreshToken(user.Username, user.Role, time.Hour)
+			},
+			revoke: func(t *testing.T, server *Server, refreshToken string, session db.Session) *http.Request {
+				data, err := json.Marshal(gin.H{"refresh_token": refreshToken})
+				require.NoError(t, err)
+				request, err := http.NewRequest(http.MethodPost, "/tokens/revoke", bytes.NewReader(data))
+				require.NoError(t, err)
+				return request
+			},
+		},
+		{
+			// a refresh token created before refresh tokens had a purpose
+			name: "Revoke Session Without Purpose",
+			createRefreshToken: func(maker token.Maker) (string, *token.Payload, error) {
+				return maker.CreateToken(user.Username, user.Role, time.Hour)
+			},
+			revoke: func(t *testing.T, server *Server, refreshToken string, session db.Session) *http.Request {
+				request, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("/sessions/%s", session.ID), nil)
+				require.NoError(t, err)
+				addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+				return request
+			},
+		},
+		{
+			name: "Block Session Without Purpose",
+			createRefreshToken: func(maker token.Maker) (string, *token.Payload, error) {
+				return maker.CreateToken(user.Username, user.Role, time.Hour)
+			},
+			revoke: func(t *testing.T, server *Server, refreshToken string, session db.Session) *http.Request {
+				request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/admin/sessions/%s/block", session.ID), nil)
+				require.NoError(t, err)
+				addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "admin_user", util.AdminRole, time.Minute)
+				return request
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := newTestServer(t, store)
+
+			refreshToken, refreshPayload, err := tc.createRefreshToken(server.tokenMaker)
+			require.NoError(t, err)
+
+			session := randomSession(user.Username)
+			session.ID = refreshPayload.ID
+			session.RefreshToken = refreshToken
+			blockedSession := session
+			blockedSession.IsBlocked = true
+
+			store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).AnyTimes().Return(session, nil)
+			store.EXPECT().BlockSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(blockedSession, nil)
+			// the refresh token never gets past the authMiddleware
+			store.EXPECT().ListSessionsByUser(gomock.Any(), gomock.Any()).Times(0)
+
+			recorder := httptest.NewRecorder()
+			server.router.ServeHTTP(recorder, tc.revoke(t, server, refreshToken, session))
+			require.Equal(t, http.StatusOK, recorder.Code)
+
+			recorder = httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/sessions", nil)
+			require.NoError(t, err)
+			request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, refreshToken))
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusUnauthorized, recorder.Code)
+		})
+	}
+}
+
+func TestGetTokenPublicKeysAPI(t *testing.T) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	oldPublicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		tokenMaker    string
+		checkResponse func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "OK",
+			tokenMaker: token.PasetoPublicMakerType,
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp getTokenPublicKeysResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, token.PublicKeyVersion, rsp.Version)
+				require.Len(t, rsp.Keys, 2)
+				require.Equal(t, hex.EncodeToString(oldPublicKey), rsp.Keys[1].PublicKey)
+
+				// the tokens of the server can be verified with the first key alone
+				accessToken, _, err := server.tokenMaker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute)
+				require.NoError(t, err)
+				publicKey, err := hex.DecodeString(rsp.Keys[0].PublicKey)
+				require.NoError(t, err)
+				require.Equal(t, token.KeyID(publicKey), rsp.Keys[0].KeyID)
+
+				var payload token.Payload
+				err = paseto.NewV2().Verify(accessToken, ed25519.PublicKey(publicKey), &payload, nil)
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:       "Symmetric Token Maker",
+			tokenMaker: token.PasetoMakerType,
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.NotContains(t, recorder.Body.String(), server.config.TokenSymmetricKey)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			config := util.Config{
+				TokenMaker:            tc.tokenMaker,
+				TokenSymmetricKey:     util.RandomString(32),
+				TokenPrivateKey:       hex.EncodeToString(privateKey.Seed()),
+				TokenVerificationKeys: hex.EncodeToString(oldPublicKey),
+				TOTPEncryptionKey:     util.RandomString(32),
+			}
+			server, err := NewServer(config, mockdb.NewMockStore(ctrl), mail.NewMemoryMailer())
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodGet, "/tokens/public_keys", nil)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, server, recorder)
+		})
+	}
+}
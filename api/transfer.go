@@ -21,6 +21,12 @@ type transferRequest struct {
 	Currency string `json:"currency" binding:"required,currency"` // we will need to validate both accounts use the same currency
 }
 
+// the idempotency key is optional and is sent as a header rather than in the body - a client that retries a transfer (e.g.
+// after a timeout) sends the same key again so the money is only moved once
+type transferHeader struct {
+	IdempotencyKey string `header:"Idempotency-Key" binding:"max=255"`
+}
+
 // createAccount takes in gin.Context because it is a handler - the handler function is defined to take gin.Context
 func (server *Server) createTransfer(ctx *gin.Context) {
 	var req transferRequest
@@ -36,6 +42,12 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 		return
 	}
 
+	var header transferHeader
+	if err := ctx.ShouldBindHeader(&header); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
 	// check if FromAccountID has the correct currency for the transfer
 	// we also want fromAccount to ensure that the logged in user is really the owner of fromAccount
 	// only the owner can transfer money from their account
@@ -60,13 +72,25 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 
 	// if no err, create account
 	arg := db.TransferTxParams{
-		FromAccountID: req.FromAccountID,
-		ToAccountID:   req.ToAccountID,
-		Amount:        req.Amount,
+		FromAccountID:  req.FromAccountID,
+		ToAccountID:    req.ToAccountID,
+		Amount:         req.Amount,
+		IdempotencyKey: header.IdempotencyKey,
 	}
 
 	result, err := server.store.TransferTX(ctx, arg)
 	if err != nil {
+		// the from account doesn't have enough money to cover the transfer - nothing was transferred
+		// or the idempotency key was already used for a different transfer
+		if errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrIdempotencyKeyReused) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
+		// an admin froze one of the accounts after validAccount checked it
+		if errors.Is(err, db.ErrAccountFrozen) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
@@ -75,7 +99,69 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, result)
 }
 
-// validAccount confirms the account exists and that its currency matches the input currency
+type reverseTransferRequest struct {
+	// uri:"id" informs Gin that the ID is a URI parameter
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// reverseTransfer sends the money of a transfer back to the account it came from - the original transfer is kept and a
+// compensating transfer is created instead
+func (server *Server) reverseTransfer(ctx *gin.Context) {
+	var req reverseTransferRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	transfer, err := server.store.GetTransfer(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// only the owner of the account that received the money may send it back - admins may reverse any transfer
+	toAccount, err := server.store.GetAccount(ctx, transfer.ToAccountID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if !isOwnerOrAdmin(authPayload, toAccount.Owner) {
+		err := errors.New("transfer wasn't received by an account of the authenticated user")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.ReverseTransferTX(ctx, db.ReverseTransferTxParams{
+		TransferID: transfer.ID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrTransferAlreadyReversed):
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		// the transfer is a reversal or the receiving account no longer holds the money
+		case errors.Is(err, db.ErrCannotReverseReversal), errors.Is(err, db.ErrInsufficientFunds):
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		// no money can move in to or out of an account frozen by an admin
+		case errors.Is(err, db.ErrAccountFrozen):
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// validAccount confirms the account exists, isn't frozen and that its currency matches the input currency
 func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency string) (db.Account, bool) {
 	// get account to confirm the account exists
 	account, err := server.store.GetAccount(ctx, accountID)
@@ -95,5 +181,12 @@ func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency s
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return account, false
 	}
+
+	// no money can move in to or out of an account frozen by an admin
+	if account.IsFrozen {
+		err = fmt.Errorf("account [%d] is frozen", accountID)
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return account, false
+	}
 	return account, true
 }
@@ -2,11 +2,16 @@ package api
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
 	"time"
 
 	db "SimpleBankProject/db/sqlc"
 	"SimpleBankProject/db/util"
+	"SimpleBankProject/token"
+	"SimpleBankProject/val"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -26,6 +31,8 @@ type userResponse struct {
 	Username         string    `json:"username"`
 	FullName         string    `json:"full_name"`
 	Email            string    `json:"email"`
+	Role             string    `json:"role"`
+	IsEmailVerified  bool      `json:"is_email_verified"`
 	PasswordChangeAt time.Time `json:"password_change_at"`
 	CreatedAt        time.Time `json:"created_at"`
 }
@@ -37,6 +44,8 @@ func newUserResponse(user db.User) userResponse {
 		Username:         user.Username,
 		FullName:         user.FullName,
 		Email:            user.Email,
+		Role:             user.Role,
+		IsEmailVerified:  user.IsEmailVerified,
 		PasswordChangeAt: user.PasswordChangeAt,
 		CreatedAt:        user.CreatedAt,
 	}
@@ -57,21 +66,31 @@ func (server *Server) createUser(ctx *gin.Context) {
 		return
 	}
 
-	hashedPassword, err := util.HashPassword(req.Password)
+	hashedPassword, err := server.hasher.Hash(req.Password)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	// if no err, begin user creation
-	arg := db.CreateUserParams{
-		Username:       req.Username,
-		HashedPassword: hashedPassword,
-		FullName:       req.FullName,
-		Email:          req.Email,
+	// the code is sent in the verification email - only its hash is stored
+	secretCode, err := util.NewSecretToken()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
 	}
 
-	user, err := server.store.CreateUser(ctx, arg)
+	// if no err, begin user creation - the verification email is sent once the user has been created
+	arg := db.CreateUserTxParams{
+		CreateUserParams: db.CreateUserParams{
+			Username:       req.Username,
+			HashedPassword: hashedPassword,
+			FullName:       req.FullName,
+			Email:          req.Email,
+		},
+		SecretCodeHash: util.HashSecretToken(secretCode),
+	}
+
+	result, err := server.store.CreateUserTX(ctx, arg)
 	if err != nil {
 		// try to convert err to type pq.Error
 		// this is to provide a better error in the event someone attempts to create a user with a username or email that
@@ -87,13 +106,130 @@ func (server *Server) createUser(ctx *gin.Context) {
 		return
 	}
 
+	server.sendVerifyEmail(ctx, result.User, result.VerifyEmail, secretCode)
+
 	// create a response to return instead of the user which contains the hashed password
-	rsp := newUserResponse(user)
+	rsp := newUserResponse(result.User)
 
 	// if no error, send a 200 OK status code
 	ctx.JSON(http.StatusOK, rsp)
 }
 
+type updateUserURI struct {
+	Username string `uri:"username" binding:"required"`
+}
+
+// every field is optional, a field which is left out keeps its current value - pointers tell a missing field apart from an
+// empty one, which is rejected by the validation
+type updateUserRequest struct {
+	FullName *string `json:"full_name"`
+	Email    *string `json:"email"`
+	Password *string `json:"password"`
+}
+
+// validateUpdateUserRequest validates the fields which are set with the same functions as the gRPC server
+func validateUpdateUserRequest(req updateUserRequest) error {
+	if req.FullName == nil && req.Email == nil && req.Password == nil {
+		return errors.New("at least one of full_name, email or password must be provided")
+	}
+	if req.FullName != nil {
+		if err := val.ValidateFullName(*req.FullName); err != nil {
+			return fmt.Errorf("full_name: %w", err)
+		}
+	}
+	if req.Email != nil {
+		if err := val.ValidateEmail(*req.Email); err != nil {
+			return fmt.Errorf("email: %w", err)
+		}
+	}
+	if req.Password != nil {
+		if err := val.ValidatePassword(*req.Password); err != nil {
+			return fmt.Errorf("password: %w", err)
+		}
+	}
+	return nil
+}
+
+// updateUser changes the full name, email and/or password of a user - users may only update themselves, admins may update
+// anyone - a password change blocks every session of the user, so they have to login again with the new password
+// access tokens which were already issued keep working until they expire - a new email must be verified again
+func (server *Server) updateUser(ctx *gin.Context) {
+	var uri updateUserURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req updateUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if err := validateUpdateUserRequest(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if !isOwnerOrAdmin(authPayload, uri.Username) {
+		err := errors.New("cannot update other user's info")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	arg := db.UpdateUserTxParams{
+		UpdateUserParams: db.UpdateUserParams{
+			Username: uri.Username,
+		},
+	}
+	if req.FullName != nil {
+		arg.FullName = sql.NullString{String: *req.FullName, Valid: true}
+	}
+	// the code is only sent if the email changes - only its hash is stored
+	var secretCode string
+	if req.Email != nil {
+		var err error
+		secretCode, err = util.NewSecretToken()
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		arg.Email = sql.NullString{String: *req.Email, Valid: true}
+		arg.SecretCodeHash = util.HashSecretToken(secretCode)
+	}
+	if req.Password != nil {
+		hashedPassword, err := server.hasher.Hash(*req.Password)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		arg.HashedPassword = sql.NullString{String: hashedPassword, Valid: true}
+		arg.PasswordChangeAt = sql.NullTime{Time: time.Now(), Valid: true}
+	}
+
+	result, err := server.store.UpdateUserTX(ctx, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		// the email is already used by another user
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// the new email must be verified again
+	if result.VerifyEmail.ID != 0 {
+		server.sendVerifyEmail(ctx, result.User, result.VerifyEmail, secretCode)
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(result.User))
+}
+
 type loginUserRequest struct {
 	Username string `json:"username" binding:"required,alphanum"` // alphanum means it must be alphanumeric chars only
 	Password string `json:"password" binding:"required,min=6"`    // min=6 means the password must be at least 6 chars
@@ -117,13 +253,26 @@ func (server *Server) loginUser(ctx *gin.Context) {
 		return
 	}
 
+	// too many failed attempts to login as the user or from the client IP lock out the login for a while
+	lockedUntil, err := server.loginLockedUntil(ctx, req.Username, ctx.RemoteIP())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if time.Now().Before(lockedUntil) {
+		server.rejectLockedOutLogin(ctx, req.Username, lockedUntil)
+		return
+	}
+
 	// get user requested if it exists
 	user, err := server.store.GetUser(ctx, req.Username)
 	if err != nil {
 		// two reasons err may not be nil
-		// first, the user doesn't exist
+		// first, the user doesn't exist - the password is still checked, so the response takes as long and looks the same
+		// as for a wrong password
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			server.hasher.CheckPasswordOfUnknownUser(req.Password)
+			server.rejectIncorrectLogin(ctx, req.Username, util.LoginFailureUnknownUser)
 			return
 		}
 		// second, internal issue with the GetUser api call
@@ -132,27 +281,83 @@ func (server *Server) loginUser(ctx *gin.Context) {
 	}
 
 	// check if the password provided is correct
-	err = util.CheckPassword(req.Password, user.HashedPassword)
+	err = server.hasher.CheckPassword(req.Password, user.HashedPassword)
 	if err != nil {
 		// if err isn't nil, the password provided was incorrect
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		server.rejectIncorrectLogin(ctx, req.Username, util.LoginFailureWrongPassword)
 		return
 	}
 
-	// user exists and password provided is correct, create access token
-	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, server.config.AccessTokenDuration)
+	// a hash created with an outdated algorithm or cost is replaced while the password is at hand
+	server.upgradePasswordHash(ctx, user, req.Password)
+
+	// users with two-factor authentication get a challenge token instead, which loginUserMFA exchanges for the tokens
+	// together with a TOTP code - their login only succeeds once the code is checked, so the correct password doesn't reset
+	// the failed attempts, wrong codes included
+	mfaEnabled, err := server.isMFAEnabled(ctx, user.Username)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
+	if mfaEnabled {
+		server.sendMFAChallenge(ctx, user)
+		return
+	}
 
-	// create refresh token with a longer valid duration than the access token - will use to create session
-	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.Username, server.config.RefreshTokenDuration)
+	// a successful login resets the failed attempts of the user
+	err = server.recordLoginAttempt(ctx, user.Username, "")
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
+	rsp, err := server.createLogin(ctx, user)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	// send loginUserResponse to the client with 200 Status OK code
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// upgradePasswordHash replaces the hash of the user's password if it wasn't created with the algorithm and cost of the
+// config - a failure doesn't fail the login, the old hash still works and is replaced at the next login
+func (server *Server) upgradePasswordHash(ctx *gin.Context, user db.User, password string) {
+	if !server.hasher.NeedsRehash(user.HashedPassword) {
+		return
+	}
+
+	hashedPassword, err := server.hasher.Hash(password)
+	if err != nil {
+		log.Printf("cannot rehash password of user %s: %s", user.Username, err)
+		return
+	}
+	// no row is updated if the password was changed since the user was read - the new password is already hashed
+	_, err = server.store.UpdateUserPasswordHash(ctx, db.UpdateUserPasswordHashParams{
+		Username:          user.Username,
+		OldHashedPassword: user.HashedPassword,
+		NewHashedPassword: hashedPassword,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		log.Printf("cannot store rehashed password of user %s: %s", user.Username, err)
+	}
+}
+
+// createLogin creates the access token, refresh token and session of a user who has proven who they are - with their
+// password, and with a TOTP code if they enabled two-factor authentication
+func (server *Server) createLogin(ctx *gin.Context, user db.User) (loginUserResponse, error) {
+	// create access token
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, server.config.AccessTokenDuration)
+	if err != nil {
+		return loginUserResponse{}, err
+	}
+
+	// create refresh token with a longer valid duration than the access token - will use to create session
+	refreshToken, refreshPayload, err := server.tokenMaker.CreateRefreshToken(user.Username, user.Role, server.config.RefreshTokenDuration)
+	if err != nil {
+		return loginUserResponse{}, err
+	}
+
 	// create session
 	session, err := server.store.CreateSession(ctx, db.CreateSessionParams{
 		ID:           refreshPayload.ID,
@@ -162,11 +367,10 @@ func (server *Server) loginUser(ctx *gin.Context) {
 		ClientIp:     ctx.ClientIP(),
 		IsBlocked:    false,
 		ExpiresAt:    refreshPayload.ExpiredAt,
+		FamilyID:     refreshPayload.ID, // a login starts a new session family, every refresh adds a session to it
 	})
-
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
+		return loginUserResponse{}, err
 	}
 
 	// create loginUserResponse
@@ -178,6 +382,5 @@ func (server *Server) loginUser(ctx *gin.Context) {
 		RefreshTokenExpiresAt: refreshPayload.ExpiredAt,
 		User:                  newUserResponse(user),
 	}
-	// send loginUserResponse to the client with 200 Status OK code
-	ctx.JSON(http.StatusOK, rsp)
+	return rsp, nil
 }
@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -9,28 +10,33 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	mockdb "SimpleBankProject/db/mock"
 	db "SimpleBankProject/db/sqlc"
 	"SimpleBankProject/db/util"
+	"SimpleBankProject/mail"
+	"SimpleBankProject/token"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/lib/pq"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // implementing a custom matcher for gomock
-type eqCreateUserParamsMatcher struct {
-	arg      db.CreateUserParams
+type eqCreateUserTxParamsMatcher struct {
+	arg      db.CreateUserTxParams
 	password string
 }
 
-// method Matches for custom matcher for gomock - a variable of type eqCreateUserParamsMatcher can call Matches with an input
-// arg of type db.CreateUserParams to compare its arg with the input arg
-func (e eqCreateUserParamsMatcher) Matches(x interface{}) bool {
-	arg, ok := x.(db.CreateUserParams)
+// method Matches for custom matcher for gomock - a variable of type eqCreateUserTxParamsMatcher can call Matches with an
+// input arg of type db.CreateUserTxParams to compare its arg with the input arg
+func (e eqCreateUserTxParamsMatcher) Matches(x interface{}) bool {
+	arg, ok := x.(db.CreateUserTxParams)
 	if !ok {
 		return false
 	}
@@ -42,16 +48,98 @@ func (e eqCreateUserParamsMatcher) Matches(x interface{}) bool {
 	// if the expected password, when hashed, matches the hashed password, the expected arg's hash password field is set
 	// to the input hashed password
 	e.arg.HashedPassword = arg.HashedPassword
-	return reflect.DeepEqual(e.arg, arg)
+	if !reflect.DeepEqual(e.arg.CreateUserParams, arg.CreateUserParams) {
+		return false
+	}
+
+	// the secret code is random - only its hash (hex encoded SHA-256) is passed to the store
+	return len(arg.SecretCodeHash) == 64
 }
 
 // String() function to identify what Matches does
-func (e eqCreateUserParamsMatcher) String() string {
+func (e eqCreateUserTxParamsMatcher) String() string {
 	return fmt.Sprintf("matches arg %v and password %v", e.arg, e.password)
 }
 
-func EqCreateUserParams(arg db.CreateUserParams, password string) gomock.Matcher {
-	return eqCreateUserParamsMatcher{arg, password}
+func EqCreateUserTxParams(arg db.CreateUserTxParams, password string) gomock.Matcher {
+	return eqCreateUserTxParamsMatcher{arg, password}
+}
+
+// failingMailer fails to send every email, like an SMTP server which can't be reached
+type failingMailer struct{}
+
+func (failingMailer) SendEmail(ctx context.Context, msg mail.Message) error {
+	return fmt.Errorf("cannot reach SMTP server")
+}
+
+func TestCreateUserAPIVerifyEmail(t *testing.T) {
+	user, password := randomUser(t)
+	verifyEmail := db.VerifyEmail{ID: 1, Username: user.Username, Email: user.Email}
+
+	// the hash of the code passed to the store
+	var secretCodeHash string
+
+	testCases := []struct {
+		name          string
+		mailer        mail.Mailer
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, mailer mail.Mailer)
+	}{
+		{
+			// the email is sent once the transaction has committed
+			name:   "Email Sent",
+			mailer: mail.NewMemoryMailer(),
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, mailer mail.Mailer) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				messages := mailer.(*mail.MemoryMailer).Messages()
+				require.Len(t, messages, 1)
+				require.Equal(t, []string{user.Email}, messages[0].To)
+				// the email holds the code whose hash was stored
+				secretCode := secretCodeFromEmail(t, messages[0])
+				require.Equal(t, secretCodeHash, util.HashSecretToken(secretCode))
+			},
+		},
+		{
+			// the user was created, so the request succeeds even though the email couldn't be sent
+			name:   "Email Not Sent",
+			mailer: failingMailer{},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, mailer mail.Mailer) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchUser(t, recorder.Body, user)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				CreateUserTX(gomock.Any(), gomock.Any()).
+				Times(1).
+				DoAndReturn(func(_ context.Context, arg db.CreateUserTxParams) (db.CreateUserTxResult, error) {
+					secretCodeHash = arg.SecretCodeHash
+					verifyEmail.SecretCodeHash = arg.SecretCodeHash
+					return db.CreateUserTxResult{User: user, VerifyEmail: verifyEmail}, nil
+				})
+
+			server := newTestServer(t, store)
+			server.mailer = tc.mailer
+
+			body := gin.H{
+				"username":  user.Username,
+				"password":  password,
+				"full_name": user.FullName,
+				"email":     user.Email,
+			}
+			recorder := postJSON(t, server, "/users", body, nil)
+			tc.checkResponse(t, recorder, tc.mailer)
+		})
+	}
 }
 
 func TestCreateUserAPI(t *testing.T) {
@@ -75,12 +163,17 @@ func TestCreateUserAPI(t *testing.T) {
 				"email":     user.Email,
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				arg := db.CreateUserParams{
-					Username: user.Username,
-					FullName: user.FullName,
-					Email:    user.Email,
+				arg := db.CreateUserTxParams{
+					CreateUserParams: db.CreateUserParams{
+						Username: user.Username,
+						FullName: user.FullName,
+						Email:    user.Email,
+					},
 				}
-				store.EXPECT().CreateUser(gomock.Any(), EqCreateUserParams(arg, password)).Times(1).Return(user, nil)
+				store.EXPECT().
+					CreateUserTX(gomock.Any(), EqCreateUserTxParams(arg, password)).
+					Times(1).
+					Return(db.CreateUserTxResult{User: user}, nil)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
@@ -96,7 +189,7 @@ func TestCreateUserAPI(t *testing.T) {
 				"email":     user.Email,
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, sql.ErrConnDone)
+				store.EXPECT().CreateUserTX(gomock.Any(), gomock.Any()).Times(1).Return(db.CreateUserTxResult{}, sql.ErrConnDone)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
@@ -113,7 +206,7 @@ func TestCreateUserAPI(t *testing.T) {
 			buildStubs: func(store *mockdb.MockStore) {
 				// pq.Error{Code: "23505"} - per lib/pq's GitHub page, error code 23505 means unique violation
 				// this means the requirement for the username to be unique has been violated
-				store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, &pq.Error{Code: "23505"})
+				store.EXPECT().CreateUserTX(gomock.Any(), gomock.Any()).Times(1).Return(db.CreateUserTxResult{}, &pq.Error{Code: "23505"})
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusForbidden, recorder.Code)
@@ -129,7 +222,7 @@ func TestCreateUserAPI(t *testing.T) {
 				"email":     user.Email,
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateUserTX(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
@@ -145,7 +238,7 @@ func TestCreateUserAPI(t *testing.T) {
 				"email": "gtemail.com",
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateUserTX(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
@@ -161,7 +254,7 @@ func TestCreateUserAPI(t *testing.T) {
 				"email":     user.Email,
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateUserTX(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
@@ -176,7 +269,7 @@ func TestCreateUserAPI(t *testing.T) {
 				"email":     user.Email,
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateUserTX(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
@@ -192,7 +285,7 @@ func TestCreateUserAPI(t *testing.T) {
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				// again, pq.Error{Code: "23505"} - indicates a violation of the requirement for a unique email
-				store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, &pq.Error{Code: "23505"})
+				store.EXPECT().CreateUserTX(gomock.Any(), gomock.Any()).Times(1).Return(db.CreateUserTxResult{}, &pq.Error{Code: "23505"})
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusForbidden, recorder.Code)
@@ -237,6 +330,366 @@ func TestCreateUserAPI(t *testing.T) {
 	}
 }
 
+func TestUpdateUserAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	newFullName := util.RandomOwner()
+	newEmail := util.RandomEmail()
+	newPassword := util.RandomString(8)
+
+	testCases := []struct {
+		name          string
+		username      string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: user.Username,
+			body: gin.H{
+				"full_name": newFullName,
+				"email":     newEmail,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.UpdateUserParams{
+					Username: user.Username,
+					FullName: sql.NullString{String: newFullName, Valid: true},
+					Email:    sql.NullString{String: newEmail, Valid: true},
+				}
+				updatedUser := user
+				updatedUser.FullName = newFullName
+				updatedUser.Email = newEmail
+				store.EXPECT().
+					UpdateUserTX(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, txArg db.UpdateUserTxParams) (db.UpdateUserTxResult, error) {
+						require.Equal(t, arg, txArg.UpdateUserParams)
+						require.Len(t, txArg.SecretCodeHash, 64)
+						return db.UpdateUserTxResult{User: updatedUser}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var gotUser userResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &gotUser))
+				require.Equal(t, user.Username, gotUser.Username)
+				require.Equal(t, newFullName, gotUser.FullName)
+				require.Equal(t, newEmail, gotUser.Email)
+			},
+		},
+		{
+			name:     "OK Password",
+			username: user.Username,
+			body: gin.H{
+				"password": newPassword,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUserTX(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpdateUserTxParams) (db.UpdateUserTxResult, error) {
+						// only the password changes, and password_change_at is bumped with it
+						require.False(t, arg.FullName.Valid)
+						require.False(t, arg.Email.Valid)
+						require.True(t, arg.HashedPassword.Valid)
+						require.NoError(t, util.CheckPassword(newPassword, arg.HashedPassword.String))
+						require.True(t, arg.PasswordChangeAt.Valid)
+						require.WithinDuration(t, time.Now(), arg.PasswordChangeAt.Time, time.Second)
+
+						updatedUser := user
+						updatedUser.HashedPassword = arg.HashedPassword.String
+						updatedUser.PasswordChangeAt = arg.PasswordChangeAt.Time
+						return db.UpdateUserTxResult{User: updatedUser, BlockedSessions: 2}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.NotContains(t, recorder.Body.String(), "hashed_password")
+			},
+		},
+		{
+			name:     "Admin",
+			username: user.Username,
+			body: gin.H{
+				"full_name": newFullName,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "admin", util.AdminRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUserTX(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UpdateUserTxResult{User: user}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "Unauthorized User",
+			username: user.Username,
+			body: gin.H{
+				"full_name": newFullName,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "No Authorization",
+			username: user.Username,
+			body: gin.H{
+				"full_name": newFullName,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "No Fields",
+			username: user.Username,
+			body:     gin.H{},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "Invalid Email",
+			username: user.Username,
+			body: gin.H{
+				"email": "invalid-email",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "Password Too Short",
+			username: user.Username,
+			body: gin.H{
+				"password": "123",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "User Not Found",
+			username: user.Username,
+			body: gin.H{
+				"full_name": newFullName,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUserTX(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UpdateUserTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "Duplicate Email",
+			username: user.Username,
+			body: gin.H{
+				"email": newEmail,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUserTX(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UpdateUserTxResult{}, &pq.Error{Code: "23505"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "Internal Error",
+			username: user.Username,
+			body: gin.H{
+				"full_name": newFullName,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUserTX(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UpdateUserTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/users/%s", tc.username)
+			request, err := http.NewRequest(http.MethodPatch, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestLoginUserRehashAPI(t *testing.T) {
+	user, password := randomUser(t)
+
+	testCases := []struct {
+		name       string
+		config     util.Config
+		buildStubs func(store *mockdb.MockStore)
+	}{
+		{
+			name:   "Outdated Algorithm",
+			config: util.Config{PasswordHashAlgorithm: util.PasswordHashArgon2id, PasswordArgon2Time: 1, PasswordArgon2Memory: 1024},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUserPasswordHash(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpdateUserPasswordHashParams) (db.User, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.Equal(t, user.HashedPassword, arg.OldHashedPassword)
+						require.True(t, strings.HasPrefix(arg.NewHashedPassword, "$argon2id$"))
+						require.NoError(t, util.CheckPassword(password, arg.NewHashedPassword))
+						return user, nil
+					})
+			},
+		},
+		{
+			name:   "Outdated Cost",
+			config: util.Config{PasswordBcryptCost: bcrypt.MinCost},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUserPasswordHash(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpdateUserPasswordHashParams) (db.User, error) {
+						cost, err := bcrypt.Cost([]byte(arg.NewHashedPassword))
+						require.NoError(t, err)
+						require.Equal(t, bcrypt.MinCost, cost)
+						require.NoError(t, util.CheckPassword(password, arg.NewHashedPassword))
+						return user, nil
+					})
+			},
+		},
+		{
+			name:   "Current Hash",
+			config: util.Config{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserPasswordHash(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:   "Password Changed Meanwhile",
+			config: util.Config{PasswordBcryptCost: bcrypt.MinCost},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserPasswordHash(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, sql.ErrNoRows)
+			},
+		},
+		{
+			// the old hash still works, so the login doesn't fail
+			name:   "Internal Error",
+			config: util.Config{PasswordBcryptCost: bcrypt.MinCost},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserPasswordHash(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, sql.ErrConnDone)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+			store.EXPECT().CreateLoginAttempt(gomock.Any(), gomock.Any()).Times(1).Return(db.LoginAttempt{}, nil)
+			store.EXPECT().GetUserMFA(gomock.Any(), gomock.Any()).Times(1).Return(db.UserMfa{}, sql.ErrNoRows)
+			store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(1).Return(db.Session{}, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			hasher, err := util.NewPasswordHasher(tc.config)
+			require.NoError(t, err)
+			server.hasher = hasher
+
+			body := gin.H{
+				"username": user.Username,
+				"password": password,
+			}
+			recorder := postJSON(t, server, "/users/login", body, nil)
+			require.Equal(t, http.StatusOK, recorder.Code)
+		})
+	}
+}
+
 func randomUser(t *testing.T) (db.User, string) {
 	password := util.RandomString(6)
 	hashedPassword, err := util.HashPassword(password)
@@ -247,6 +700,7 @@ func randomUser(t *testing.T) (db.User, string) {
 		HashedPassword: hashedPassword,
 		FullName:       util.RandomOwner(),
 		Email:          util.RandomEmail(),
+		Role:           util.DepositorRole,
 	}
 
 	return user, password
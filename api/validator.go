@@ -19,3 +19,11 @@ var validCurrency validator.Func = func(fieldLevel validator.FieldLevel) bool {
 	// else field is not a string
 	return false
 }
+
+// validScope returns true if the field is a supported API key scope
+var validScope validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	if scope, ok := fieldLevel.Field().Interface().(string); ok {
+		return util.IsSupportedScope(scope)
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/mail"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sendVerifyEmail sends the verification code to the user - it links to the verify email route in the config
+// verifyEmail only holds the hash of the code, so the code itself is passed in as well
+// it is called once the user transaction has committed, so the email is sent once however often the transaction was retried -
+// the user exists either way, so a failed email is only logged
+func (server *Server) sendVerifyEmail(ctx context.Context, user db.User, verifyEmail db.VerifyEmail, secretCode string) {
+	msg := mail.VerifyEmailMessage(
+		verifyEmail.Email,
+		user.FullName,
+		server.config.VerifyEmailURL,
+		verifyEmail.ID,
+		secretCode,
+	)
+	if err := server.mailer.SendEmail(ctx, msg); err != nil {
+		log.Printf("cannot send verification email to user %s: %s", user.Username, err)
+	}
+}
+
+// the query parameters of the link in the verification email
+type verifyEmailRequest struct {
+	EmailID    int64  `form:"email_id" binding:"required,min=1"`
+	SecretCode string `form:"secret_code" binding:"required,len=43"` // util.SecretTokenLength
+}
+
+// verifyEmail marks the email of the user as verified - no access token is needed, the secret code sent to the email is proof
+// enough
+func (server *Server) verifyEmail(ctx *gin.Context) {
+	var req verifyEmailRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.VerifyEmailTX(ctx, db.VerifyEmailTxParams{
+		EmailID:        req.EmailID,
+		SecretCodeHash: util.HashSecretToken(req.SecretCode),
+	})
+	if err != nil {
+		// the code is wrong, was already used or has expired
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(result.User))
+}
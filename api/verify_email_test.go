@@ -0,0 +1,167 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	mockdb "SimpleBankProject/db/mock"
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/mail"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyEmailAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	verifiedUser := user
+	verifiedUser.IsEmailVerified = true
+	emailID := util.RandomInt(1, 1000)
+	secretCode := util.RandomString(util.SecretTokenLength)
+
+	testCases := []struct {
+		name          string
+		emailID       int64
+		secretCode    string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "OK",
+			emailID:    emailID,
+			secretCode: secretCode,
+			buildStubs: func(store *mockdb.MockStore) {
+				// only the hash of the code is looked up
+				arg := db.VerifyEmailTxParams{
+					EmailID:        emailID,
+					SecretCodeHash: util.HashSecretToken(secretCode),
+				}
+				store.EXPECT().
+					VerifyEmailTX(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.VerifyEmailTxResult{User: verifiedUser}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var gotUser userResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &gotUser))
+				require.Equal(t, user.Username, gotUser.Username)
+				require.True(t, gotUser.IsEmailVerified)
+			},
+		},
+		{
+			name:       "Invalid Code",
+			emailID:    emailID,
+			secretCode: secretCode,
+			buildStubs: func(store *mockdb.MockStore) {
+				// the code is wrong, was already used or has expired
+				store.EXPECT().
+					VerifyEmailTX(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.VerifyEmailTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:       "Internal Error",
+			emailID:    emailID,
+			secretCode: secretCode,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					VerifyEmailTX(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.VerifyEmailTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:       "Invalid Email ID",
+			emailID:    0,
+			secretCode: secretCode,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().VerifyEmailTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:       "Invalid Secret Code",
+			emailID:    emailID,
+			secretCode: util.RandomString(10),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().VerifyEmailTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/users/verify_email?email_id=%d&secret_code=%s", tc.emailID, tc.secretCode)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestSendVerifyEmail(t *testing.T) {
+	user, _ := randomUser(t)
+	mailer := mail.NewMemoryMailer()
+
+	server := newTestServer(t, nil)
+	server.mailer = mailer
+	server.config.VerifyEmailURL = "http://localhost:8080/users/verify_email"
+
+	secretCode, err := util.NewSecretToken()
+	require.NoError(t, err)
+	verifyEmail := db.VerifyEmail{
+		ID:             util.RandomInt(1, 1000),
+		Username:       user.Username,
+		Email:          user.Email,
+		SecretCodeHash: util.HashSecretToken(secretCode),
+	}
+	server.sendVerifyEmail(context.Background(), user, verifyEmail, secretCode)
+
+	// the email goes to the address being verified and links back to the verify email route
+	messages := mailer.Messages()
+	require.Len(t, messages, 1)
+	require.Equal(t, []string{user.Email}, messages[0].To)
+	require.Contains(t, messages[0].Body, server.config.VerifyEmailURL)
+	require.Equal(t, secretCode, secretCodeFromEmail(t, messages[0]))
+}
+
+// secretCodeFromEmail returns the secret code of the link in a verification email
+func secretCodeFromEmail(t *testing.T, msg mail.Message) string {
+	match := regexp.MustCompile(`secret_code=([A-Za-z0-9_-]+)`).FindStringSubmatch(msg.Body)
+	require.Len(t, match, 2)
+	return match[1]
+}
@@ -0,0 +1,5 @@
+-- the overdraft limit is how far below zero an account's balance is allowed to go - most accounts cannot be overdrawn
+ALTER TABLE "accounts" ADD COLUMN "overdraft_limit" bigint NOT NULL DEFAULT 0;
+
+-- the limit is stored as a positive number, i.e. a limit of 100 allows the balance to go down to -100
+ALTER TABLE "accounts" ADD CONSTRAINT "overdraft_limit_non_negative" CHECK ("overdraft_limit" >= 0);
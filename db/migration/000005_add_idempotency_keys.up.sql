@@ -0,0 +1,13 @@
+-- an idempotency key lets a client safely retry a transfer - the key is stored in the same transaction as the transfer so
+-- a retry with the same key finds the original result instead of moving the money a second time
+CREATE TABLE "idempotency_keys" (
+  "key" varchar PRIMARY KEY, -- chosen by the client
+  "from_account_id" bigint NOT NULL, -- the payload of the original transfer - a retry must send the same values
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "transfer_id" bigint NOT NULL,
+  "result" jsonb NOT NULL, -- the TransferTxResult returned by the original transfer
+  "created_at" timestamptz NOT NULL DEFAULT now()
+);
+
+ALTER TABLE "idempotency_keys" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
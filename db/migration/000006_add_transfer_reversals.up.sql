@@ -0,0 +1,5 @@
+-- a reversal is a compensating transfer that moves the money of a mistaken transfer back - it points at the transfer it
+-- reverses and the unique constraint guarantees a transfer can only ever be reversed once
+ALTER TABLE "transfers" ADD COLUMN "reversal_of" bigint UNIQUE;
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("reversal_of") REFERENCES "transfers" ("id");
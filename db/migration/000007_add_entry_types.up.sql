@@ -0,0 +1,12 @@
+-- entries created by transfers move money between two internal accounts, deposits and withdrawals move money in to or out
+-- of the bank - the external reference identifies the other side of a deposit or withdrawal (e.g. a wire or card payment id)
+ALTER TABLE "entries" ADD COLUMN "type" varchar NOT NULL DEFAULT 'transfer';
+
+ALTER TABLE "entries" ADD COLUMN "external_reference" varchar;
+
+ALTER TABLE "entries" ADD CONSTRAINT "entry_type_check" CHECK ("type" IN ('transfer', 'deposit', 'withdrawal'));
+
+-- the same external payment can only be booked once - transfer entries have no external reference and are not affected
+CREATE UNIQUE INDEX ON "entries" ("type", "external_reference");
+
+COMMENT ON COLUMN "entries"."type" IS 'transfer, deposit, or withdrawal';
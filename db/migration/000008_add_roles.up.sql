@@ -0,0 +1,9 @@
+-- depositors can only use their own accounts, admins (bank staff) can read any account, block sessions and freeze accounts
+ALTER TABLE "users" ADD COLUMN "role" varchar NOT NULL DEFAULT 'depositor';
+
+ALTER TABLE "users" ADD CONSTRAINT "user_role_check" CHECK ("role" IN ('depositor', 'admin'));
+
+COMMENT ON COLUMN "users"."role" IS 'depositor or admin';
+
+-- a frozen account can't send, receive, deposit or withdraw money until an admin unfreezes it
+ALTER TABLE "accounts" ADD COLUMN "is_frozen" boolean NOT NULL DEFAULT false;
@@ -0,0 +1,9 @@
+-- access tokens are stateless, so a token that must stop working before it expires (after a logout, a password change or
+-- a security incident) is denied by its payload id - a row is only needed until the token would have expired anyway
+CREATE TABLE "revoked_tokens" (
+  "id" uuid PRIMARY KEY, -- the payload id of the token
+  "expires_at" timestamptz NOT NULL, -- when the token expires - the row can be deleted after this time
+  "created_at" timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ON "revoked_tokens" ("expires_at");
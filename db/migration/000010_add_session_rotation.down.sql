@@ -0,0 +1,5 @@
+ALTER TABLE IF EXISTS "sessions" DROP COLUMN IF EXISTS "rotated_at";
+
+ALTER TABLE IF EXISTS "sessions" DROP COLUMN IF EXISTS "previous_session_id";
+
+ALTER TABLE IF EXISTS "sessions" DROP COLUMN IF EXISTS "family_id";
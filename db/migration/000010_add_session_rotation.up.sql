@@ -0,0 +1,16 @@
+-- every refresh rotates the refresh token - the session of the old token is marked as rotated and a new session is created
+-- in the same family, linked to the session it replaced
+-- the sessions created before rotation existed each start their own family
+ALTER TABLE "sessions" ADD COLUMN "family_id" uuid;
+UPDATE "sessions" SET "family_id" = "id";
+ALTER TABLE "sessions" ALTER COLUMN "family_id" SET NOT NULL;
+
+ALTER TABLE "sessions" ADD COLUMN "previous_session_id" uuid;
+ALTER TABLE "sessions" ADD FOREIGN KEY ("previous_session_id") REFERENCES "sessions" ("id");
+
+ALTER TABLE "sessions" ADD COLUMN "rotated_at" timestamptz;
+
+CREATE INDEX ON "sessions" ("family_id");
+
+COMMENT ON COLUMN "sessions"."family_id" IS 'id of the first session of the login - shared by every rotated session';
+COMMENT ON COLUMN "sessions"."rotated_at" IS 'when the refresh token was replaced - using it again blocks the whole family';
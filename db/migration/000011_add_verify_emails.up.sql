@@ -0,0 +1,15 @@
+-- new users must confirm their email address by following a link with a secret code - the flag is reset when the email
+-- changes
+ALTER TABLE "users" ADD COLUMN "is_email_verified" boolean NOT NULL DEFAULT false;
+
+CREATE TABLE "verify_emails" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "email" varchar NOT NULL, -- the address the code was sent to - it must still be the email of the user when it is used
+  "secret_code" varchar NOT NULL,
+  "is_used" boolean NOT NULL DEFAULT false, -- every code can only be used once
+  "created_at" timestamptz NOT NULL DEFAULT now(),
+  "expired_at" timestamptz NOT NULL DEFAULT (now() + interval '15 minutes')
+);
+
+ALTER TABLE "verify_emails" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
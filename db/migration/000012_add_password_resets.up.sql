@@ -0,0 +1,11 @@
+-- a password reset link is sent to the email of a user who forgot their password - only the hash of its token is stored
+CREATE TABLE "password_resets" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "token_hash" varchar UNIQUE NOT NULL, -- SHA-256 of the token in the link
+  "is_used" boolean NOT NULL DEFAULT false, -- every token can only be used once
+  "created_at" timestamptz NOT NULL DEFAULT now(),
+  "expires_at" timestamptz NOT NULL
+);
+
+ALTER TABLE "password_resets" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
@@ -0,0 +1,25 @@
+-- a user who enrolls in two-factor authentication gets a TOTP secret, encrypted with TOTP_ENCRYPTION_KEY - it is only used
+-- at login once the user has confirmed it with a code from their authenticator app
+CREATE TABLE "user_mfa" (
+  "username" varchar PRIMARY KEY,
+  "encrypted_secret" varchar NOT NULL,
+  "is_enabled" boolean NOT NULL DEFAULT false,
+  "last_used_step" bigint NOT NULL DEFAULT 0, -- the time step of the last code used - a code can't be used twice
+  "created_at" timestamptz NOT NULL DEFAULT now(),
+  "enabled_at" timestamptz
+);
+
+-- single use codes which replace a TOTP code when the user has lost their authenticator app - only their hashes are stored
+CREATE TABLE "mfa_recovery_codes" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "code_hash" varchar NOT NULL,
+  "used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ON "mfa_recovery_codes" ("username");
+
+ALTER TABLE "user_mfa" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "mfa_recovery_codes" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
@@ -0,0 +1,17 @@
+-- long-lived keys which let server-to-server clients call the API as a user without logging in - only their hashes are
+-- stored, and each key may only call the routes of its scopes
+CREATE TABLE "api_keys" (
+  "id" uuid PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "name" varchar NOT NULL,
+  "key_prefix" varchar NOT NULL, -- the start of the key, so the user can tell their keys apart
+  "key_hash" varchar UNIQUE NOT NULL,
+  "scopes" varchar[] NOT NULL,
+  "expires_at" timestamptz, -- null for keys which never expire
+  "revoked_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ON "api_keys" ("username");
+
+ALTER TABLE "api_keys" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
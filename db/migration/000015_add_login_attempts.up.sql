@@ -0,0 +1,15 @@
+-- every login attempt, successful or not - an audit trail of who tried to login from where, which the login lockout counts
+-- the failed attempts of - the username isn't a foreign key, attempts with unknown usernames are recorded as well
+CREATE TABLE "login_attempts" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "client_ip" varchar NOT NULL,
+  "user_agent" varchar NOT NULL,
+  "succeeded" boolean NOT NULL,
+  "failure_reason" varchar NOT NULL DEFAULT '', -- unknown_user, wrong_password or locked_out - empty when it succeeded
+  "created_at" timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ON "login_attempts" ("username", "created_at");
+
+CREATE INDEX ON "login_attempts" ("client_ip", "created_at");
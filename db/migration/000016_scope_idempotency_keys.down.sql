@@ -0,0 +1,7 @@
+-- keys used by several accounts can't be kept once the key is global again - only the oldest one is kept
+DELETE FROM "idempotency_keys" AS newer
+USING "idempotency_keys" AS older
+WHERE newer.key = older.key AND newer.created_at > older.created_at;
+
+ALTER TABLE "idempotency_keys" DROP CONSTRAINT "idempotency_keys_pkey";
+ALTER TABLE "idempotency_keys" ADD PRIMARY KEY ("key");
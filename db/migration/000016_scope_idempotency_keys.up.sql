@@ -0,0 +1,4 @@
+-- idempotency keys are chosen by the clients, so two users could pick the same key - scoping the key to the from account
+-- (which only its owner can transfer out of) keeps the transfers of one user from being replayed or rejected for another
+ALTER TABLE "idempotency_keys" DROP CONSTRAINT "idempotency_keys_pkey";
+ALTER TABLE "idempotency_keys" ADD PRIMARY KEY ("from_account_id", "key");
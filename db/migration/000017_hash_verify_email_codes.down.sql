@@ -0,0 +1,4 @@
+-- the hashes can't be turned back into codes - the codes which weren't used yet can't be used any more and have to be sent
+-- again
+UPDATE "verify_emails" SET "is_used" = true WHERE "is_used" = false;
+ALTER TABLE "verify_emails" RENAME COLUMN "secret_code_hash" TO "secret_code";
@@ -0,0 +1,4 @@
+-- only the SHA-256 of the verification codes is stored (like the password reset tokens), so a leaked database doesn't leak
+-- codes which can still be used - the codes which weren't used yet are hashed the same way as util.HashSecretToken does
+ALTER TABLE "verify_emails" RENAME COLUMN "secret_code" TO "secret_code_hash";
+UPDATE "verify_emails" SET "secret_code_hash" = encode(sha256(convert_to("secret_code_hash", 'UTF8')), 'hex');
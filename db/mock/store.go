@@ -1,16 +1,16 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/techschool/simplebank/db/sqlc (interfaces: Store)
+// Source: SimpleBankProject/db/sqlc (interfaces: Store)
 
 // Package mockdb is a generated GoMock package.
 package mockdb
 
 import (
+	db "SimpleBankProject/db/sqlc"
 	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
-	db "SimpleBankProject/db/sqlc"
 )
 
 // MockStore is a mock of Store interface.
@@ -51,6 +51,81 @@ func (mr *MockStoreMockRecorder) AddAccountBalance(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccountBalance", reflect.TypeOf((*MockStore)(nil).AddAccountBalance), arg0, arg1)
 }
 
+// AddAccountBalanceIfSufficient mocks base method.
+func (m *MockStore) AddAccountBalanceIfSufficient(arg0 context.Context, arg1 db.AddAccountBalanceIfSufficientParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAccountBalanceIfSufficient", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddAccountBalanceIfSufficient indicates an expected call of AddAccountBalanceIfSufficient.
+func (mr *MockStoreMockRecorder) AddAccountBalanceIfSufficient(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccountBalanceIfSufficient", reflect.TypeOf((*MockStore)(nil).AddAccountBalanceIfSufficient), arg0, arg1)
+}
+
+// BlockSession mocks base method.
+func (m *MockStore) BlockSession(arg0 context.Context, arg1 uuid.UUID) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockSession", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockSession indicates an expected call of BlockSession.
+func (mr *MockStoreMockRecorder) BlockSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockSession", reflect.TypeOf((*MockStore)(nil).BlockSession), arg0, arg1)
+}
+
+// BlockSessionFamily mocks base method.
+func (m *MockStore) BlockSessionFamily(arg0 context.Context, arg1 uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockSessionFamily", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockSessionFamily indicates an expected call of BlockSessionFamily.
+func (mr *MockStoreMockRecorder) BlockSessionFamily(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockSessionFamily", reflect.TypeOf((*MockStore)(nil).BlockSessionFamily), arg0, arg1)
+}
+
+// BlockUserSessions mocks base method.
+func (m *MockStore) BlockUserSessions(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockUserSessions", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockUserSessions indicates an expected call of BlockUserSessions.
+func (mr *MockStoreMockRecorder) BlockUserSessions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockUserSessions", reflect.TypeOf((*MockStore)(nil).BlockUserSessions), arg0, arg1)
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockStore) CreateAPIKey(arg0 context.Context, arg1 db.CreateAPIKeyParams) (db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", arg0, arg1)
+	ret0, _ := ret[0].(db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockStoreMockRecorder) CreateAPIKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockStore)(nil).CreateAPIKey), arg0, arg1)
+}
+
 // CreateAccount mocks base method.
 func (m *MockStore) CreateAccount(arg0 context.Context, arg1 db.CreateAccountParams) (db.Account, error) {
 	m.ctrl.T.Helper()
@@ -81,6 +156,110 @@ func (mr *MockStoreMockRecorder) CreateEntry(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntry", reflect.TypeOf((*MockStore)(nil).CreateEntry), arg0, arg1)
 }
 
+// CreateExternalEntry mocks base method.
+func (m *MockStore) CreateExternalEntry(arg0 context.Context, arg1 db.CreateExternalEntryParams) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExternalEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateExternalEntry indicates an expected call of CreateExternalEntry.
+func (mr *MockStoreMockRecorder) CreateExternalEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExternalEntry", reflect.TypeOf((*MockStore)(nil).CreateExternalEntry), arg0, arg1)
+}
+
+// CreateIdempotencyKey mocks base method.
+func (m *MockStore) CreateIdempotencyKey(arg0 context.Context, arg1 db.CreateIdempotencyKeyParams) (db.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIdempotencyKey", arg0, arg1)
+	ret0, _ := ret[0].(db.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIdempotencyKey indicates an expected call of CreateIdempotencyKey.
+func (mr *MockStoreMockRecorder) CreateIdempotencyKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIdempotencyKey", reflect.TypeOf((*MockStore)(nil).CreateIdempotencyKey), arg0, arg1)
+}
+
+// CreateLoginAttempt mocks base method.
+func (m *MockStore) CreateLoginAttempt(arg0 context.Context, arg1 db.CreateLoginAttemptParams) (db.LoginAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLoginAttempt", arg0, arg1)
+	ret0, _ := ret[0].(db.LoginAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLoginAttempt indicates an expected call of CreateLoginAttempt.
+func (mr *MockStoreMockRecorder) CreateLoginAttempt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoginAttempt", reflect.TypeOf((*MockStore)(nil).CreateLoginAttempt), arg0, arg1)
+}
+
+// CreateMFARecoveryCode mocks base method.
+func (m *MockStore) CreateMFARecoveryCode(arg0 context.Context, arg1 db.CreateMFARecoveryCodeParams) (db.MfaRecoveryCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMFARecoveryCode", arg0, arg1)
+	ret0, _ := ret[0].(db.MfaRecoveryCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMFARecoveryCode indicates an expected call of CreateMFARecoveryCode.
+func (mr *MockStoreMockRecorder) CreateMFARecoveryCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMFARecoveryCode", reflect.TypeOf((*MockStore)(nil).CreateMFARecoveryCode), arg0, arg1)
+}
+
+// CreatePasswordReset mocks base method.
+func (m *MockStore) CreatePasswordReset(arg0 context.Context, arg1 db.CreatePasswordResetParams) (db.PasswordReset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePasswordReset", arg0, arg1)
+	ret0, _ := ret[0].(db.PasswordReset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePasswordReset indicates an expected call of CreatePasswordReset.
+func (mr *MockStoreMockRecorder) CreatePasswordReset(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePasswordReset", reflect.TypeOf((*MockStore)(nil).CreatePasswordReset), arg0, arg1)
+}
+
+// CreatePasswordResetTX mocks base method.
+func (m *MockStore) CreatePasswordResetTX(arg0 context.Context, arg1 db.CreatePasswordResetTxParams) (db.CreatePasswordResetTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePasswordResetTX", arg0, arg1)
+	ret0, _ := ret[0].(db.CreatePasswordResetTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePasswordResetTX indicates an expected call of CreatePasswordResetTX.
+func (mr *MockStoreMockRecorder) CreatePasswordResetTX(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePasswordResetTX", reflect.TypeOf((*MockStore)(nil).CreatePasswordResetTX), arg0, arg1)
+}
+
+// CreateRevokedToken mocks base method.
+func (m *MockStore) CreateRevokedToken(arg0 context.Context, arg1 db.CreateRevokedTokenParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRevokedToken", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRevokedToken indicates an expected call of CreateRevokedToken.
+func (mr *MockStoreMockRecorder) CreateRevokedToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRevokedToken", reflect.TypeOf((*MockStore)(nil).CreateRevokedToken), arg0, arg1)
+}
+
 // CreateSession mocks base method.
 func (m *MockStore) CreateSession(arg0 context.Context, arg1 db.CreateSessionParams) (db.Session, error) {
 	m.ctrl.T.Helper()
@@ -111,6 +290,21 @@ func (mr *MockStoreMockRecorder) CreateTransfer(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransfer", reflect.TypeOf((*MockStore)(nil).CreateTransfer), arg0, arg1)
 }
 
+// CreateTransferReversal mocks base method.
+func (m *MockStore) CreateTransferReversal(arg0 context.Context, arg1 db.CreateTransferReversalParams) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransferReversal", arg0, arg1)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransferReversal indicates an expected call of CreateTransferReversal.
+func (mr *MockStoreMockRecorder) CreateTransferReversal(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransferReversal", reflect.TypeOf((*MockStore)(nil).CreateTransferReversal), arg0, arg1)
+}
+
 // CreateUser mocks base method.
 func (m *MockStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
 	m.ctrl.T.Helper()
@@ -126,6 +320,51 @@ func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
 }
 
+// CreateUserMFA mocks base method.
+func (m *MockStore) CreateUserMFA(arg0 context.Context, arg1 db.CreateUserMFAParams) (db.UserMfa, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserMFA", arg0, arg1)
+	ret0, _ := ret[0].(db.UserMfa)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserMFA indicates an expected call of CreateUserMFA.
+func (mr *MockStoreMockRecorder) CreateUserMFA(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserMFA", reflect.TypeOf((*MockStore)(nil).CreateUserMFA), arg0, arg1)
+}
+
+// CreateUserTX mocks base method.
+func (m *MockStore) CreateUserTX(arg0 context.Context, arg1 db.CreateUserTxParams) (db.CreateUserTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserTX", arg0, arg1)
+	ret0, _ := ret[0].(db.CreateUserTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserTX indicates an expected call of CreateUserTX.
+func (mr *MockStoreMockRecorder) CreateUserTX(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserTX", reflect.TypeOf((*MockStore)(nil).CreateUserTX), arg0, arg1)
+}
+
+// CreateVerifyEmail mocks base method.
+func (m *MockStore) CreateVerifyEmail(arg0 context.Context, arg1 db.CreateVerifyEmailParams) (db.VerifyEmail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVerifyEmail", arg0, arg1)
+	ret0, _ := ret[0].(db.VerifyEmail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVerifyEmail indicates an expected call of CreateVerifyEmail.
+func (mr *MockStoreMockRecorder) CreateVerifyEmail(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVerifyEmail", reflect.TypeOf((*MockStore)(nil).CreateVerifyEmail), arg0, arg1)
+}
+
 // DeleteAccount mocks base method.
 func (m *MockStore) DeleteAccount(arg0 context.Context, arg1 int64) error {
 	m.ctrl.T.Helper()
@@ -154,6 +393,35 @@ func (mr *MockStoreMockRecorder) DeleteEntry(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEntry", reflect.TypeOf((*MockStore)(nil).DeleteEntry), arg0, arg1)
 }
 
+// DeleteExpiredRevokedTokens mocks base method.
+func (m *MockStore) DeleteExpiredRevokedTokens(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredRevokedTokens", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredRevokedTokens indicates an expected call of DeleteExpiredRevokedTokens.
+func (mr *MockStoreMockRecorder) DeleteExpiredRevokedTokens(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredRevokedTokens", reflect.TypeOf((*MockStore)(nil).DeleteExpiredRevokedTokens), arg0)
+}
+
+// DeleteMFARecoveryCodes mocks base method.
+func (m *MockStore) DeleteMFARecoveryCodes(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMFARecoveryCodes", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMFARecoveryCodes indicates an expected call of DeleteMFARecoveryCodes.
+func (mr *MockStoreMockRecorder) DeleteMFARecoveryCodes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMFARecoveryCodes", reflect.TypeOf((*MockStore)(nil).DeleteMFARecoveryCodes), arg0, arg1)
+}
+
 // DeleteTransfer mocks base method.
 func (m *MockStore) DeleteTransfer(arg0 context.Context, arg1 int64) error {
 	m.ctrl.T.Helper()
@@ -168,6 +436,94 @@ func (mr *MockStoreMockRecorder) DeleteTransfer(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTransfer", reflect.TypeOf((*MockStore)(nil).DeleteTransfer), arg0, arg1)
 }
 
+// DeleteUserMFA mocks base method.
+func (m *MockStore) DeleteUserMFA(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserMFA", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserMFA indicates an expected call of DeleteUserMFA.
+func (mr *MockStoreMockRecorder) DeleteUserMFA(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserMFA", reflect.TypeOf((*MockStore)(nil).DeleteUserMFA), arg0, arg1)
+}
+
+// DepositTX mocks base method.
+func (m *MockStore) DepositTX(arg0 context.Context, arg1 db.DepositTxParams) (db.DepositTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DepositTX", arg0, arg1)
+	ret0, _ := ret[0].(db.DepositTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DepositTX indicates an expected call of DepositTX.
+func (mr *MockStoreMockRecorder) DepositTX(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DepositTX", reflect.TypeOf((*MockStore)(nil).DepositTX), arg0, arg1)
+}
+
+// DisableMFATX mocks base method.
+func (m *MockStore) DisableMFATX(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableMFATX", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableMFATX indicates an expected call of DisableMFATX.
+func (mr *MockStoreMockRecorder) DisableMFATX(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableMFATX", reflect.TypeOf((*MockStore)(nil).DisableMFATX), arg0, arg1)
+}
+
+// EnableMFATX mocks base method.
+func (m *MockStore) EnableMFATX(arg0 context.Context, arg1 db.EnableMFATxParams) (db.EnableMFATxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableMFATX", arg0, arg1)
+	ret0, _ := ret[0].(db.EnableMFATxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableMFATX indicates an expected call of EnableMFATX.
+func (mr *MockStoreMockRecorder) EnableMFATX(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableMFATX", reflect.TypeOf((*MockStore)(nil).EnableMFATX), arg0, arg1)
+}
+
+// EnableUserMFA mocks base method.
+func (m *MockStore) EnableUserMFA(arg0 context.Context, arg1 string) (db.UserMfa, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableUserMFA", arg0, arg1)
+	ret0, _ := ret[0].(db.UserMfa)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableUserMFA indicates an expected call of EnableUserMFA.
+func (mr *MockStoreMockRecorder) EnableUserMFA(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableUserMFA", reflect.TypeOf((*MockStore)(nil).EnableUserMFA), arg0, arg1)
+}
+
+// GetAPIKey mocks base method.
+func (m *MockStore) GetAPIKey(arg0 context.Context, arg1 uuid.UUID) (db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKey", arg0, arg1)
+	ret0, _ := ret[0].(db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKey indicates an expected call of GetAPIKey.
+func (mr *MockStoreMockRecorder) GetAPIKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKey", reflect.TypeOf((*MockStore)(nil).GetAPIKey), arg0, arg1)
+}
+
 // GetAccount mocks base method.
 func (m *MockStore) GetAccount(arg0 context.Context, arg1 int64) (db.Account, error) {
 	m.ctrl.T.Helper()
@@ -198,6 +554,36 @@ func (mr *MockStoreMockRecorder) GetAccountForUpdate(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountForUpdate", reflect.TypeOf((*MockStore)(nil).GetAccountForUpdate), arg0, arg1)
 }
 
+// GetActiveAPIKeyByHash mocks base method.
+func (m *MockStore) GetActiveAPIKeyByHash(arg0 context.Context, arg1 string) (db.GetActiveAPIKeyByHashRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveAPIKeyByHash", arg0, arg1)
+	ret0, _ := ret[0].(db.GetActiveAPIKeyByHashRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveAPIKeyByHash indicates an expected call of GetActiveAPIKeyByHash.
+func (mr *MockStoreMockRecorder) GetActiveAPIKeyByHash(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveAPIKeyByHash", reflect.TypeOf((*MockStore)(nil).GetActiveAPIKeyByHash), arg0, arg1)
+}
+
+// GetClientIPLoginFailures mocks base method.
+func (m *MockStore) GetClientIPLoginFailures(arg0 context.Context, arg1 db.GetClientIPLoginFailuresParams) (db.GetClientIPLoginFailuresRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClientIPLoginFailures", arg0, arg1)
+	ret0, _ := ret[0].(db.GetClientIPLoginFailuresRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClientIPLoginFailures indicates an expected call of GetClientIPLoginFailures.
+func (mr *MockStoreMockRecorder) GetClientIPLoginFailures(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClientIPLoginFailures", reflect.TypeOf((*MockStore)(nil).GetClientIPLoginFailures), arg0, arg1)
+}
+
 // GetEntry mocks base method.
 func (m *MockStore) GetEntry(arg0 context.Context, arg1 int64) (db.Entry, error) {
 	m.ctrl.T.Helper()
@@ -213,6 +599,36 @@ func (mr *MockStoreMockRecorder) GetEntry(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockStore)(nil).GetEntry), arg0, arg1)
 }
 
+// GetIdempotencyKey mocks base method.
+func (m *MockStore) GetIdempotencyKey(arg0 context.Context, arg1 db.GetIdempotencyKeyParams) (db.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdempotencyKey", arg0, arg1)
+	ret0, _ := ret[0].(db.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdempotencyKey indicates an expected call of GetIdempotencyKey.
+func (mr *MockStoreMockRecorder) GetIdempotencyKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdempotencyKey", reflect.TypeOf((*MockStore)(nil).GetIdempotencyKey), arg0, arg1)
+}
+
+// GetMFAChallengeFailures mocks base method.
+func (m *MockStore) GetMFAChallengeFailures(arg0 context.Context, arg1 db.GetMFAChallengeFailuresParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMFAChallengeFailures", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMFAChallengeFailures indicates an expected call of GetMFAChallengeFailures.
+func (mr *MockStoreMockRecorder) GetMFAChallengeFailures(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMFAChallengeFailures", reflect.TypeOf((*MockStore)(nil).GetMFAChallengeFailures), arg0, arg1)
+}
+
 // GetSession mocks base method.
 func (m *MockStore) GetSession(arg0 context.Context, arg1 uuid.UUID) (db.Session, error) {
 	m.ctrl.T.Helper()
@@ -243,6 +659,36 @@ func (mr *MockStoreMockRecorder) GetTransfer(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockStore)(nil).GetTransfer), arg0, arg1)
 }
 
+// GetTransferForUpdate mocks base method.
+func (m *MockStore) GetTransferForUpdate(arg0 context.Context, arg1 int64) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferForUpdate indicates an expected call of GetTransferForUpdate.
+func (mr *MockStoreMockRecorder) GetTransferForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferForUpdate", reflect.TypeOf((*MockStore)(nil).GetTransferForUpdate), arg0, arg1)
+}
+
+// GetTransferReversal mocks base method.
+func (m *MockStore) GetTransferReversal(arg0 context.Context, arg1 int64) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferReversal", arg0, arg1)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferReversal indicates an expected call of GetTransferReversal.
+func (mr *MockStoreMockRecorder) GetTransferReversal(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferReversal", reflect.TypeOf((*MockStore)(nil).GetTransferReversal), arg0, arg1)
+}
+
 // GetUser mocks base method.
 func (m *MockStore) GetUser(arg0 context.Context, arg1 string) (db.User, error) {
 	m.ctrl.T.Helper()
@@ -258,6 +704,96 @@ func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
 }
 
+// GetUserByEmail mocks base method.
+func (m *MockStore) GetUserByEmail(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByEmail", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByEmail indicates an expected call of GetUserByEmail.
+func (mr *MockStoreMockRecorder) GetUserByEmail(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockStore)(nil).GetUserByEmail), arg0, arg1)
+}
+
+// GetUserLoginFailures mocks base method.
+func (m *MockStore) GetUserLoginFailures(arg0 context.Context, arg1 db.GetUserLoginFailuresParams) (db.GetUserLoginFailuresRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserLoginFailures", arg0, arg1)
+	ret0, _ := ret[0].(db.GetUserLoginFailuresRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserLoginFailures indicates an expected call of GetUserLoginFailures.
+func (mr *MockStoreMockRecorder) GetUserLoginFailures(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserLoginFailures", reflect.TypeOf((*MockStore)(nil).GetUserLoginFailures), arg0, arg1)
+}
+
+// GetUserMFA mocks base method.
+func (m *MockStore) GetUserMFA(arg0 context.Context, arg1 string) (db.UserMfa, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserMFA", arg0, arg1)
+	ret0, _ := ret[0].(db.UserMfa)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserMFA indicates an expected call of GetUserMFA.
+func (mr *MockStoreMockRecorder) GetUserMFA(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserMFA", reflect.TypeOf((*MockStore)(nil).GetUserMFA), arg0, arg1)
+}
+
+// IsTokenDenied mocks base method.
+func (m *MockStore) IsTokenDenied(arg0 context.Context, arg1 db.IsTokenDeniedParams) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTokenDenied", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTokenDenied indicates an expected call of IsTokenDenied.
+func (mr *MockStoreMockRecorder) IsTokenDenied(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTokenDenied", reflect.TypeOf((*MockStore)(nil).IsTokenDenied), arg0, arg1)
+}
+
+// IsTokenRevoked mocks base method.
+func (m *MockStore) IsTokenRevoked(arg0 context.Context, arg1 uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTokenRevoked", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTokenRevoked indicates an expected call of IsTokenRevoked.
+func (mr *MockStoreMockRecorder) IsTokenRevoked(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTokenRevoked", reflect.TypeOf((*MockStore)(nil).IsTokenRevoked), arg0, arg1)
+}
+
+// ListAPIKeysByUser mocks base method.
+func (m *MockStore) ListAPIKeysByUser(arg0 context.Context, arg1 string) ([]db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAPIKeysByUser", arg0, arg1)
+	ret0, _ := ret[0].([]db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPIKeysByUser indicates an expected call of ListAPIKeysByUser.
+func (mr *MockStoreMockRecorder) ListAPIKeysByUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKeysByUser", reflect.TypeOf((*MockStore)(nil).ListAPIKeysByUser), arg0, arg1)
+}
+
 // ListAccounts mocks base method.
 func (m *MockStore) ListAccounts(arg0 context.Context, arg1 db.ListAccountsParams) ([]db.Account, error) {
 	m.ctrl.T.Helper()
@@ -273,6 +809,21 @@ func (mr *MockStoreMockRecorder) ListAccounts(arg0, arg1 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockStore)(nil).ListAccounts), arg0, arg1)
 }
 
+// ListAccountsAfter mocks base method.
+func (m *MockStore) ListAccountsAfter(arg0 context.Context, arg1 db.ListAccountsAfterParams) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountsAfter", arg0, arg1)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountsAfter indicates an expected call of ListAccountsAfter.
+func (mr *MockStoreMockRecorder) ListAccountsAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountsAfter", reflect.TypeOf((*MockStore)(nil).ListAccountsAfter), arg0, arg1)
+}
+
 // ListEntries mocks base method.
 func (m *MockStore) ListEntries(arg0 context.Context, arg1 db.ListEntriesParams) ([]db.Entry, error) {
 	m.ctrl.T.Helper()
@@ -288,6 +839,36 @@ func (mr *MockStoreMockRecorder) ListEntries(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntries", reflect.TypeOf((*MockStore)(nil).ListEntries), arg0, arg1)
 }
 
+// ListEntriesAfter mocks base method.
+func (m *MockStore) ListEntriesAfter(arg0 context.Context, arg1 db.ListEntriesAfterParams) ([]db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntriesAfter", arg0, arg1)
+	ret0, _ := ret[0].([]db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntriesAfter indicates an expected call of ListEntriesAfter.
+func (mr *MockStoreMockRecorder) ListEntriesAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntriesAfter", reflect.TypeOf((*MockStore)(nil).ListEntriesAfter), arg0, arg1)
+}
+
+// ListSessionsByUser mocks base method.
+func (m *MockStore) ListSessionsByUser(arg0 context.Context, arg1 string) ([]db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSessionsByUser", arg0, arg1)
+	ret0, _ := ret[0].([]db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSessionsByUser indicates an expected call of ListSessionsByUser.
+func (mr *MockStoreMockRecorder) ListSessionsByUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessionsByUser", reflect.TypeOf((*MockStore)(nil).ListSessionsByUser), arg0, arg1)
+}
+
 // ListTransfers mocks base method.
 func (m *MockStore) ListTransfers(arg0 context.Context, arg1 db.ListTransfersParams) ([]db.Transfer, error) {
 	m.ctrl.T.Helper()
@@ -303,6 +884,111 @@ func (mr *MockStoreMockRecorder) ListTransfers(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfers", reflect.TypeOf((*MockStore)(nil).ListTransfers), arg0, arg1)
 }
 
+// ListTransfersAfter mocks base method.
+func (m *MockStore) ListTransfersAfter(arg0 context.Context, arg1 db.ListTransfersAfterParams) ([]db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransfersAfter", arg0, arg1)
+	ret0, _ := ret[0].([]db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransfersAfter indicates an expected call of ListTransfersAfter.
+func (mr *MockStoreMockRecorder) ListTransfersAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfersAfter", reflect.TypeOf((*MockStore)(nil).ListTransfersAfter), arg0, arg1)
+}
+
+// MarkEmailVerified mocks base method.
+func (m *MockStore) MarkEmailVerified(arg0 context.Context, arg1 db.MarkEmailVerifiedParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkEmailVerified", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkEmailVerified indicates an expected call of MarkEmailVerified.
+func (mr *MockStoreMockRecorder) MarkEmailVerified(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEmailVerified", reflect.TypeOf((*MockStore)(nil).MarkEmailVerified), arg0, arg1)
+}
+
+// ResetPasswordTX mocks base method.
+func (m *MockStore) ResetPasswordTX(arg0 context.Context, arg1 db.ResetPasswordTxParams) (db.ResetPasswordTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPasswordTX", arg0, arg1)
+	ret0, _ := ret[0].(db.ResetPasswordTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetPasswordTX indicates an expected call of ResetPasswordTX.
+func (mr *MockStoreMockRecorder) ResetPasswordTX(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPasswordTX", reflect.TypeOf((*MockStore)(nil).ResetPasswordTX), arg0, arg1)
+}
+
+// ReverseTransferTX mocks base method.
+func (m *MockStore) ReverseTransferTX(arg0 context.Context, arg1 db.ReverseTransferTxParams) (db.ReverseTransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReverseTransferTX", arg0, arg1)
+	ret0, _ := ret[0].(db.ReverseTransferTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReverseTransferTX indicates an expected call of ReverseTransferTX.
+func (mr *MockStoreMockRecorder) ReverseTransferTX(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReverseTransferTX", reflect.TypeOf((*MockStore)(nil).ReverseTransferTX), arg0, arg1)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockStore) RevokeAPIKey(arg0 context.Context, arg1 uuid.UUID) (db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", arg0, arg1)
+	ret0, _ := ret[0].(db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockStoreMockRecorder) RevokeAPIKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockStore)(nil).RevokeAPIKey), arg0, arg1)
+}
+
+// RotateSession mocks base method.
+func (m *MockStore) RotateSession(arg0 context.Context, arg1 uuid.UUID) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateSession", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateSession indicates an expected call of RotateSession.
+func (mr *MockStoreMockRecorder) RotateSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateSession", reflect.TypeOf((*MockStore)(nil).RotateSession), arg0, arg1)
+}
+
+// RotateSessionTX mocks base method.
+func (m *MockStore) RotateSessionTX(arg0 context.Context, arg1 db.RotateSessionTxParams) (db.RotateSessionTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateSessionTX", arg0, arg1)
+	ret0, _ := ret[0].(db.RotateSessionTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateSessionTX indicates an expected call of RotateSessionTX.
+func (mr *MockStoreMockRecorder) RotateSessionTX(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateSessionTX", reflect.TypeOf((*MockStore)(nil).RotateSessionTX), arg0, arg1)
+}
+
 // TransferTX mocks base method.
 func (m *MockStore) TransferTX(arg0 context.Context, arg1 db.TransferTxParams) (db.TransferTxResult, error) {
 	m.ctrl.T.Helper()
@@ -333,6 +1019,36 @@ func (mr *MockStoreMockRecorder) UpdateAccount(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccount", reflect.TypeOf((*MockStore)(nil).UpdateAccount), arg0, arg1)
 }
 
+// UpdateAccountFrozen mocks base method.
+func (m *MockStore) UpdateAccountFrozen(arg0 context.Context, arg1 db.UpdateAccountFrozenParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountFrozen", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccountFrozen indicates an expected call of UpdateAccountFrozen.
+func (mr *MockStoreMockRecorder) UpdateAccountFrozen(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountFrozen", reflect.TypeOf((*MockStore)(nil).UpdateAccountFrozen), arg0, arg1)
+}
+
+// UpdateAccountOverdraftLimit mocks base method.
+func (m *MockStore) UpdateAccountOverdraftLimit(arg0 context.Context, arg1 db.UpdateAccountOverdraftLimitParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountOverdraftLimit", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccountOverdraftLimit indicates an expected call of UpdateAccountOverdraftLimit.
+func (mr *MockStoreMockRecorder) UpdateAccountOverdraftLimit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountOverdraftLimit", reflect.TypeOf((*MockStore)(nil).UpdateAccountOverdraftLimit), arg0, arg1)
+}
+
 // UpdateEntry mocks base method.
 func (m *MockStore) UpdateEntry(arg0 context.Context, arg1 db.UpdateEntryParams) (db.Entry, error) {
 	m.ctrl.T.Helper()
@@ -362,3 +1078,138 @@ func (mr *MockStoreMockRecorder) UpdateTransfer(arg0, arg1 interface{}) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransfer", reflect.TypeOf((*MockStore)(nil).UpdateTransfer), arg0, arg1)
 }
+
+// UpdateUser mocks base method.
+func (m *MockStore) UpdateUser(arg0 context.Context, arg1 db.UpdateUserParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUser indicates an expected call of UpdateUser.
+func (mr *MockStoreMockRecorder) UpdateUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockStore)(nil).UpdateUser), arg0, arg1)
+}
+
+// UpdateUserPasswordHash mocks base method.
+func (m *MockStore) UpdateUserPasswordHash(arg0 context.Context, arg1 db.UpdateUserPasswordHashParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserPasswordHash", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserPasswordHash indicates an expected call of UpdateUserPasswordHash.
+func (mr *MockStoreMockRecorder) UpdateUserPasswordHash(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserPasswordHash", reflect.TypeOf((*MockStore)(nil).UpdateUserPasswordHash), arg0, arg1)
+}
+
+// UpdateUserTX mocks base method.
+func (m *MockStore) UpdateUserTX(arg0 context.Context, arg1 db.UpdateUserTxParams) (db.UpdateUserTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserTX", arg0, arg1)
+	ret0, _ := ret[0].(db.UpdateUserTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserTX indicates an expected call of UpdateUserTX.
+func (mr *MockStoreMockRecorder) UpdateUserTX(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserTX", reflect.TypeOf((*MockStore)(nil).UpdateUserTX), arg0, arg1)
+}
+
+// UpdateVerifyEmail mocks base method.
+func (m *MockStore) UpdateVerifyEmail(arg0 context.Context, arg1 db.UpdateVerifyEmailParams) (db.VerifyEmail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateVerifyEmail", arg0, arg1)
+	ret0, _ := ret[0].(db.VerifyEmail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateVerifyEmail indicates an expected call of UpdateVerifyEmail.
+func (mr *MockStoreMockRecorder) UpdateVerifyEmail(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVerifyEmail", reflect.TypeOf((*MockStore)(nil).UpdateVerifyEmail), arg0, arg1)
+}
+
+// UseMFARecoveryCode mocks base method.
+func (m *MockStore) UseMFARecoveryCode(arg0 context.Context, arg1 db.UseMFARecoveryCodeParams) (db.MfaRecoveryCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseMFARecoveryCode", arg0, arg1)
+	ret0, _ := ret[0].(db.MfaRecoveryCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseMFARecoveryCode indicates an expected call of UseMFARecoveryCode.
+func (mr *MockStoreMockRecorder) UseMFARecoveryCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseMFARecoveryCode", reflect.TypeOf((*MockStore)(nil).UseMFARecoveryCode), arg0, arg1)
+}
+
+// UsePasswordReset mocks base method.
+func (m *MockStore) UsePasswordReset(arg0 context.Context, arg1 string) (db.PasswordReset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UsePasswordReset", arg0, arg1)
+	ret0, _ := ret[0].(db.PasswordReset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UsePasswordReset indicates an expected call of UsePasswordReset.
+func (mr *MockStoreMockRecorder) UsePasswordReset(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UsePasswordReset", reflect.TypeOf((*MockStore)(nil).UsePasswordReset), arg0, arg1)
+}
+
+// UseTOTPStep mocks base method.
+func (m *MockStore) UseTOTPStep(arg0 context.Context, arg1 db.UseTOTPStepParams) (db.UserMfa, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseTOTPStep", arg0, arg1)
+	ret0, _ := ret[0].(db.UserMfa)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseTOTPStep indicates an expected call of UseTOTPStep.
+func (mr *MockStoreMockRecorder) UseTOTPStep(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseTOTPStep", reflect.TypeOf((*MockStore)(nil).UseTOTPStep), arg0, arg1)
+}
+
+// VerifyEmailTX mocks base method.
+func (m *MockStore) VerifyEmailTX(arg0 context.Context, arg1 db.VerifyEmailTxParams) (db.VerifyEmailTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmailTX", arg0, arg1)
+	ret0, _ := ret[0].(db.VerifyEmailTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyEmailTX indicates an expected call of VerifyEmailTX.
+func (mr *MockStoreMockRecorder) VerifyEmailTX(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmailTX", reflect.TypeOf((*MockStore)(nil).VerifyEmailTX), arg0, arg1)
+}
+
+// WithdrawTX mocks base method.
+func (m *MockStore) WithdrawTX(arg0 context.Context, arg1 db.WithdrawTxParams) (db.WithdrawTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithdrawTX", arg0, arg1)
+	ret0, _ := ret[0].(db.WithdrawTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WithdrawTX indicates an expected call of WithdrawTX.
+func (mr *MockStoreMockRecorder) WithdrawTX(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithdrawTX", reflect.TypeOf((*MockStore)(nil).WithdrawTX), arg0, arg1)
+}
@@ -24,6 +24,13 @@ ORDER BY id
 LIMIT $2
 OFFSET $3;
 
+-- name: ListAccountsAfter :many
+-- keyset (cursor) pagination - lists the accounts with an id greater than after_id, the last id of the previous page
+SELECT * FROM accounts
+WHERE owner = sqlc.arg(owner) AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg('limit');
+
 -- name: UpdateAccount :one
 UPDATE accounts
 set balance = $2
@@ -36,6 +43,25 @@ SET balance = balance + sqlc.arg(amount)
 WHERE id = sqlc.arg(id)
 RETURNING *;
 
+-- name: AddAccountBalanceIfSufficient :one
+-- the balance is only updated if it doesn't go below the account's overdraft limit - no rows are returned otherwise
+UPDATE accounts
+SET balance = balance + sqlc.arg(amount)
+WHERE id = sqlc.arg(id) AND balance + sqlc.arg(amount) >= -overdraft_limit
+RETURNING *;
+
+-- name: UpdateAccountOverdraftLimit :one
+UPDATE accounts
+SET overdraft_limit = sqlc.arg(overdraft_limit)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: UpdateAccountFrozen :one
+UPDATE accounts
+SET is_frozen = sqlc.arg(is_frozen)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
 -- name: DeleteAccount :exec
 DELETE FROM accounts
 WHERE id = $1;
\ No newline at end of file
@@ -0,0 +1,45 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (
+  id,
+  username,
+  name,
+  key_prefix,
+  key_hash,
+  scopes,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: GetAPIKey :one
+SELECT * FROM api_keys
+WHERE id = $1 LIMIT 1;
+
+-- name: GetActiveAPIKeyByHash :one
+-- the key a request was authenticated with and the current role of its user - revoked and expired keys are left out
+SELECT
+  api_keys.id,
+  api_keys.username,
+  api_keys.scopes,
+  api_keys.expires_at,
+  api_keys.created_at,
+  users.role
+FROM api_keys
+JOIN users ON users.username = api_keys.username
+WHERE api_keys.key_hash = $1
+  AND api_keys.revoked_at IS NULL
+  AND (api_keys.expires_at IS NULL OR api_keys.expires_at > now())
+LIMIT 1;
+
+-- name: ListAPIKeysByUser :many
+SELECT * FROM api_keys
+WHERE username = $1
+ORDER BY created_at DESC;
+
+-- name: RevokeAPIKey :one
+-- revoking a key twice keeps the time it was first revoked
+UPDATE api_keys
+SET revoked_at = COALESCE(revoked_at, now())
+WHERE id = $1
+RETURNING *;
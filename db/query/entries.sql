@@ -7,16 +7,50 @@ INSERT INTO entries (
 )
 RETURNING *;
 
+-- name: CreateExternalEntry :one
+-- records money entering (deposit) or leaving (withdrawal) the bank
+INSERT INTO entries (
+  account_id,
+  amount,
+  type,
+  external_reference
+) VALUES (
+  $1, $2, $3, sqlc.arg(external_reference)::varchar
+)
+RETURNING *;
+
 -- name: GetEntry :one
 SELECT * FROM entries
 WHERE id = $1 LIMIT 1;
 
 -- name: ListEntries :many
+-- the time range and sign filters are optional - a null argument matches every entry
+SELECT * FROM entries
+WHERE
+    account_id = sqlc.arg(account_id) AND
+    (sqlc.narg(start_time)::timestamptz IS NULL OR created_at >= sqlc.narg(start_time)) AND
+    (sqlc.narg(end_time)::timestamptz IS NULL OR created_at < sqlc.narg(end_time)) AND
+    (sqlc.narg(sign)::varchar IS NULL OR
+        (sqlc.narg(sign) = 'positive' AND amount > 0) OR
+        (sqlc.narg(sign) = 'negative' AND amount < 0))
+ORDER BY id
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: ListEntriesAfter :many
+-- keyset (cursor) pagination - same filters as ListEntries but lists the entries with an id greater than after_id, the last
+-- id of the previous page
 SELECT * FROM entries
-WHERE account_id = $1
+WHERE
+    account_id = sqlc.arg(account_id) AND
+    id > sqlc.arg(after_id) AND
+    (sqlc.narg(start_time)::timestamptz IS NULL OR created_at >= sqlc.narg(start_time)) AND
+    (sqlc.narg(end_time)::timestamptz IS NULL OR created_at < sqlc.narg(end_time)) AND
+    (sqlc.narg(sign)::varchar IS NULL OR
+        (sqlc.narg(sign) = 'positive' AND amount > 0) OR
+        (sqlc.narg(sign) = 'negative' AND amount < 0))
 ORDER BY id
-LIMIT $2
-OFFSET $3;
+LIMIT sqlc.arg('limit');
 
 -- name: UpdateEntry :one
 UPDATE entries
@@ -0,0 +1,17 @@
+-- name: CreateIdempotencyKey :one
+INSERT INTO idempotency_keys (
+  key,
+  from_account_id,
+  to_account_id,
+  amount,
+  transfer_id,
+  result
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: GetIdempotencyKey :one
+-- keys are scoped to the from account, so the same key chosen by another user is a different key
+SELECT * FROM idempotency_keys
+WHERE from_account_id = sqlc.arg(from_account_id) AND key = sqlc.arg(key) LIMIT 1;
@@ -0,0 +1,47 @@
+-- name: CreateLoginAttempt :one
+INSERT INTO login_attempts (
+  username,
+  client_ip,
+  user_agent,
+  succeeded,
+  failure_reason
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetUserLoginFailures :one
+-- the failed attempts to login as the user since the time and since their last successful login - attempts rejected by the
+-- lockout itself aren't counted, so retrying while locked out doesn't extend the lockout
+SELECT
+  count(*) AS failures,
+  COALESCE(max(attempts.created_at), 'epoch')::timestamptz AS last_failed_at
+FROM login_attempts AS attempts
+WHERE attempts.username = sqlc.arg(username)
+  AND attempts.succeeded = false
+  AND attempts.failure_reason <> 'locked_out'
+  AND attempts.created_at > sqlc.arg(since)
+  AND attempts.created_at > COALESCE((
+    SELECT max(successes.created_at) FROM login_attempts AS successes
+    WHERE successes.username = sqlc.arg(username) AND successes.succeeded = true
+  ), 'epoch');
+
+-- name: GetClientIPLoginFailures :one
+-- the failed login attempts from the client IP since the time, whichever username they tried - unlike the failures of a
+-- user, a successful login doesn't reset them, an attacker could otherwise login to their own account in between guesses
+SELECT
+  count(*) AS failures,
+  COALESCE(max(created_at), 'epoch')::timestamptz AS last_failed_at
+FROM login_attempts
+WHERE client_ip = sqlc.arg(client_ip)
+  AND succeeded = false
+  AND failure_reason <> 'locked_out'
+  AND created_at > sqlc.arg(since);
+
+-- name: GetMFAChallengeFailures :one
+-- the wrong TOTP and recovery codes sent for the user since the time the MFA challenge token was issued
+SELECT count(*) AS failures
+FROM login_attempts
+WHERE username = sqlc.arg(username)
+  AND failure_reason = 'wrong_mfa_code'
+  AND created_at >= sqlc.arg(since);
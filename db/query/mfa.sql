@@ -0,0 +1,59 @@
+-- name: CreateUserMFA :one
+-- enrolling again before the secret was confirmed replaces it - no row is returned if two-factor authentication is already
+-- enabled, it must be disabled first
+INSERT INTO user_mfa (
+  username,
+  encrypted_secret
+) VALUES (
+  $1, $2
+)
+ON CONFLICT (username) DO UPDATE
+SET
+  encrypted_secret = EXCLUDED.encrypted_secret,
+  last_used_step = 0,
+  created_at = now()
+WHERE user_mfa.is_enabled = false
+RETURNING *;
+
+-- name: GetUserMFA :one
+SELECT * FROM user_mfa
+WHERE username = $1 LIMIT 1;
+
+-- name: EnableUserMFA :one
+UPDATE user_mfa
+SET
+  is_enabled = true,
+  enabled_at = now()
+WHERE username = $1 AND is_enabled = false
+RETURNING *;
+
+-- name: UseTOTPStep :one
+-- no row is returned if a code of the same or a later time step was already used, so every code can only be used once
+UPDATE user_mfa
+SET last_used_step = sqlc.arg(step)
+WHERE username = sqlc.arg(username) AND last_used_step < sqlc.arg(step)
+RETURNING *;
+
+-- name: DeleteUserMFA :exec
+DELETE FROM user_mfa
+WHERE username = $1;
+
+-- name: CreateMFARecoveryCode :one
+INSERT INTO mfa_recovery_codes (
+  username,
+  code_hash
+) VALUES (
+  $1, $2
+)
+RETURNING *;
+
+-- name: UseMFARecoveryCode :one
+-- no row is returned if the code is wrong or was already used
+UPDATE mfa_recovery_codes
+SET used_at = now()
+WHERE username = $1 AND code_hash = $2 AND used_at IS NULL
+RETURNING *;
+
+-- name: DeleteMFARecoveryCodes :exec
+DELETE FROM mfa_recovery_codes
+WHERE username = $1;
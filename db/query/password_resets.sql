@@ -0,0 +1,19 @@
+-- name: CreatePasswordReset :one
+INSERT INTO password_resets (
+  username,
+  token_hash,
+  expires_at
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: UsePasswordReset :one
+-- marks the token as used - no row is returned if the token is wrong, was already used, or has expired
+UPDATE password_resets
+SET is_used = true
+WHERE
+  token_hash = $1
+  AND is_used = false
+  AND expires_at > now()
+RETURNING *;
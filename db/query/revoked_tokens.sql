@@ -0,0 +1,33 @@
+-- name: CreateRevokedToken :exec
+-- revoking a token twice is not an error
+INSERT INTO revoked_tokens (
+  id,
+  expires_at
+) VALUES (
+  $1, $2
+)
+ON CONFLICT (id) DO NOTHING;
+
+-- name: IsTokenRevoked :one
+SELECT EXISTS (
+  SELECT 1 FROM revoked_tokens
+  WHERE id = $1
+) AS revoked;
+
+-- name: IsTokenDenied :one
+-- a token is denied if it was revoked, or if it was issued before the password of its user last changed - a password change
+-- cuts off every token issued with the old password at once, without revoking each of them
+SELECT (
+  EXISTS (
+    SELECT 1 FROM revoked_tokens
+    WHERE revoked_tokens.id = sqlc.arg(id)
+  ) OR EXISTS (
+    SELECT 1 FROM users
+    WHERE users.username = sqlc.arg(username) AND users.password_change_at > sqlc.arg(issued_at)
+  )
+)::bool AS denied;
+
+-- name: DeleteExpiredRevokedTokens :execrows
+-- expired tokens are rejected by the token maker, so their rows are no longer needed
+DELETE FROM revoked_tokens
+WHERE expires_at < now();
@@ -6,12 +6,65 @@ INSERT INTO sessions (
   user_agent,
   client_ip,
   is_blocked,
-  expires_at
+  expires_at,
+  family_id,
+  previous_session_id
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
 )
 RETURNING *;
 
 -- name: GetSession :one
 SELECT * FROM sessions
-WHERE id = $1 LIMIT 1;
\ No newline at end of file
+WHERE id = $1 LIMIT 1;
+
+-- name: BlockSession :one
+-- a blocked session can no longer be used to renew access tokens
+UPDATE sessions
+SET is_blocked = true
+WHERE id = $1
+RETURNING *;
+
+-- name: ListSessionsByUser :many
+-- the active sessions of a user - blocked, rotated and expired sessions can no longer renew access tokens and are left out
+SELECT * FROM sessions
+WHERE username = $1 AND is_blocked = false AND rotated_at IS NULL AND expires_at > now()
+ORDER BY created_at DESC;
+
+-- name: RotateSession :one
+-- marks the session as rotated - no row is returned if it was already rotated, so only one of two concurrent refreshes
+-- with the same refresh token succeeds
+UPDATE sessions
+SET rotated_at = now()
+WHERE id = $1 AND rotated_at IS NULL
+RETURNING *;
+
+-- name: BlockSessionFamily :one
+-- blocks every session created from the same login and revokes their refresh tokens, used when a rotated refresh token is
+-- presented again - returns the number of sessions blocked
+WITH blocked AS (
+  UPDATE sessions
+  SET is_blocked = true
+  WHERE sessions.family_id = $1 AND sessions.is_blocked = false
+  RETURNING id, expires_at
+), revoked AS (
+  INSERT INTO revoked_tokens (id, expires_at)
+  SELECT blocked.id, blocked.expires_at FROM blocked
+  ON CONFLICT (id) DO NOTHING
+)
+SELECT count(*) FROM blocked;
+
+-- name: BlockUserSessions :one
+-- blocks every session of the user and revokes their refresh tokens, e.g. after a password change - returns the number of
+-- sessions blocked
+WITH blocked AS (
+  UPDATE sessions
+  SET is_blocked = true
+  WHERE sessions.username = $1 AND sessions.is_blocked = false
+  RETURNING id, expires_at
+), revoked AS (
+  INSERT INTO revoked_tokens (id, expires_at)
+  SELECT blocked.id, blocked.expires_at FROM blocked
+  ON CONFLICT (id) DO NOTHING
+)
+SELECT count(*) FROM blocked;
@@ -8,18 +8,60 @@ INSERT INTO transfers (
 )
 RETURNING *;
 
+-- name: CreateTransferReversal :one
+INSERT INTO transfers (
+  from_account_id,
+  to_account_id,
+  amount,
+  reversal_of
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
 -- name: GetTransfer :one
 SELECT * FROM transfers
 WHERE id = $1 LIMIT 1;
 
+-- name: GetTransferForUpdate :one
+-- locks the transfer so concurrent reversals of it run one after the other
+SELECT * FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: GetTransferReversal :one
+SELECT * FROM transfers
+WHERE reversal_of = sqlc.arg(transfer_id)::bigint LIMIT 1;
+
 -- name: ListTransfers :many
+-- lists the transfers going in to and out of the account - the time range and sign filters are optional, a null argument
+-- matches every transfer - incoming transfers are positive for the account and outgoing transfers are negative
+SELECT * FROM transfers
+WHERE
+    (from_account_id = sqlc.arg(account_id) OR to_account_id = sqlc.arg(account_id)) AND
+    (sqlc.narg(start_time)::timestamptz IS NULL OR created_at >= sqlc.narg(start_time)) AND
+    (sqlc.narg(end_time)::timestamptz IS NULL OR created_at < sqlc.narg(end_time)) AND
+    (sqlc.narg(sign)::varchar IS NULL OR
+        (sqlc.narg(sign) = 'positive' AND to_account_id = sqlc.arg(account_id)) OR
+        (sqlc.narg(sign) = 'negative' AND from_account_id = sqlc.arg(account_id)))
+ORDER BY id
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: ListTransfersAfter :many
+-- keyset (cursor) pagination - same filters as ListTransfers but lists the transfers with an id greater than after_id, the
+-- last id of the previous page
 SELECT * FROM transfers
 WHERE
-    from_account_id = $1 OR
-    to_account_id = $2 
+    (from_account_id = sqlc.arg(account_id) OR to_account_id = sqlc.arg(account_id)) AND
+    id > sqlc.arg(after_id) AND
+    (sqlc.narg(start_time)::timestamptz IS NULL OR created_at >= sqlc.narg(start_time)) AND
+    (sqlc.narg(end_time)::timestamptz IS NULL OR created_at < sqlc.narg(end_time)) AND
+    (sqlc.narg(sign)::varchar IS NULL OR
+        (sqlc.narg(sign) = 'positive' AND to_account_id = sqlc.arg(account_id)) OR
+        (sqlc.narg(sign) = 'negative' AND from_account_id = sqlc.arg(account_id)))
 ORDER BY id
-LIMIT $3
-OFFSET $4;
+LIMIT sqlc.arg('limit');
 
 -- name: UpdateTransfer :one
 UPDATE transfers
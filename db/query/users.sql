@@ -11,4 +11,35 @@ RETURNING *;
 
 -- name: GetUser :one
 SELECT * FROM users
-WHERE username = $1 LIMIT 1;
\ No newline at end of file
+WHERE username = $1 LIMIT 1;
+
+-- name: GetUserByEmail :one
+SELECT * FROM users
+WHERE email = $1 LIMIT 1;
+
+-- name: UpdateUser :one
+-- every field is optional - a NULL argument keeps the current value
+UPDATE users
+SET
+  hashed_password = COALESCE(sqlc.narg(hashed_password), hashed_password),
+  password_change_at = COALESCE(sqlc.narg(password_change_at), password_change_at),
+  full_name = COALESCE(sqlc.narg(full_name), full_name),
+  email = COALESCE(sqlc.narg(email), email),
+  is_email_verified = COALESCE(sqlc.narg(is_email_verified), is_email_verified)
+WHERE username = sqlc.arg(username)
+RETURNING *;
+
+-- name: MarkEmailVerified :one
+-- no row is returned if the email of the user has changed since the verification email was sent
+UPDATE users
+SET is_email_verified = true
+WHERE username = $1 AND email = $2
+RETURNING *;
+
+-- name: UpdateUserPasswordHash :one
+-- replaces the hash of the password with a new hash of the same password, e.g. with a stronger algorithm - password_change_at
+-- is kept as the password didn't change - no row is returned if the password was changed since the old hash was read
+UPDATE users
+SET hashed_password = sqlc.arg(new_hashed_password)
+WHERE username = sqlc.arg(username) AND hashed_password = sqlc.arg(old_hashed_password)
+RETURNING *;
\ No newline at end of file
@@ -0,0 +1,20 @@
+-- name: CreateVerifyEmail :one
+INSERT INTO verify_emails (
+  username,
+  email,
+  secret_code_hash
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: UpdateVerifyEmail :one
+-- marks the code as used - no row is returned if the code is wrong, was already used, or has expired
+UPDATE verify_emails
+SET is_used = true
+WHERE
+  id = sqlc.arg(id)
+  AND secret_code_hash = sqlc.arg(secret_code_hash)
+  AND is_used = false
+  AND expired_at > now()
+RETURNING *;
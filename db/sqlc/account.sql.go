@@ -13,7 +13,7 @@ const addAccountBalance = `-- name: AddAccountBalance :one
 UPDATE accounts
 SET balance = balance + $1
 WHERE id = $2
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, is_frozen
 `
 
 type AddAccountBalanceParams struct {
@@ -30,6 +30,36 @@ func (q *Queries) AddAccountBalance(ctx context.Context, arg AddAccountBalancePa
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.IsFrozen,
+	)
+	return i, err
+}
+
+const addAccountBalanceIfSufficient = `-- name: AddAccountBalanceIfSufficient :one
+UPDATE accounts
+SET balance = balance + $1
+WHERE id = $2 AND balance + $1 >= -overdraft_limit
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, is_frozen
+`
+
+type AddAccountBalanceIfSufficientParams struct {
+	Amount int64 `json:"amount"`
+	ID     int64 `json:"id"`
+}
+
+// the balance is only updated if it doesn't go below the account's overdraft limit - no rows are returned otherwise
+func (q *Queries) AddAccountBalanceIfSufficient(ctx context.Context, arg AddAccountBalanceIfSufficientParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, addAccountBalanceIfSufficient, arg.Amount, arg.ID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.IsFrozen,
 	)
 	return i, err
 }
@@ -42,7 +72,7 @@ INSERT INTO accounts (
 ) VALUES (
   $1, $2, $3
 )
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, is_frozen
 `
 
 type CreateAccountParams struct {
@@ -60,6 +90,8 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.IsFrozen,
 	)
 	return i, err
 }
@@ -75,7 +107,7 @@ func (q *Queries) DeleteAccount(ctx context.Context, id int64) error {
 }
 
 const getAccount = `-- name: GetAccount :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, overdraft_limit, is_frozen FROM accounts
 WHERE id = $1 LIMIT 1
 `
 
@@ -88,12 +120,14 @@ func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.IsFrozen,
 	)
 	return i, err
 }
 
 const getAccountForUpdate = `-- name: GetAccountForUpdate :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, overdraft_limit, is_frozen FROM accounts
 WHERE id = $1 LIMIT 1
 FOR NO KEY UPDATE
 `
@@ -107,12 +141,14 @@ func (q *Queries) GetAccountForUpdate(ctx context.Context, id int64) (Account, e
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.IsFrozen,
 	)
 	return i, err
 }
 
 const listAccounts = `-- name: ListAccounts :many
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, overdraft_limit, is_frozen FROM accounts
 WHERE owner = $1
 ORDER BY id
 LIMIT $2
@@ -140,6 +176,53 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 			&i.Balance,
 			&i.Currency,
 			&i.CreatedAt,
+			&i.OverdraftLimit,
+			&i.IsFrozen,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAccountsAfter = `-- name: ListAccountsAfter :many
+SELECT id, owner, balance, currency, created_at, overdraft_limit, is_frozen FROM accounts
+WHERE owner = $1 AND id > $2
+ORDER BY id
+LIMIT $3
+`
+
+type ListAccountsAfterParams struct {
+	Owner   string `json:"owner"`
+	AfterID int64  `json:"after_id"`
+	Limit   int32  `json:"limit"`
+}
+
+// keyset (cursor) pagination - lists the accounts with an id greater than after_id, the last id of the previous page
+func (q *Queries) ListAccountsAfter(ctx context.Context, arg ListAccountsAfterParams) ([]Account, error) {
+	rows, err := q.db.QueryContext(ctx, listAccountsAfter, arg.Owner, arg.AfterID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.OverdraftLimit,
+			&i.IsFrozen,
 		); err != nil {
 			return nil, err
 		}
@@ -158,7 +241,7 @@ const updateAccount = `-- name: UpdateAccount :one
 UPDATE accounts
 set balance = $2
 WHERE id = $1
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, is_frozen
 `
 
 type UpdateAccountParams struct {
@@ -175,6 +258,62 @@ func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.IsFrozen,
+	)
+	return i, err
+}
+
+const updateAccountFrozen = `-- name: UpdateAccountFrozen :one
+UPDATE accounts
+SET is_frozen = $1
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, is_frozen
+`
+
+type UpdateAccountFrozenParams struct {
+	IsFrozen bool  `json:"is_frozen"`
+	ID       int64 `json:"id"`
+}
+
+func (q *Queries) UpdateAccountFrozen(ctx context.Context, arg UpdateAccountFrozenParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountFrozen, arg.IsFrozen, arg.ID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.IsFrozen,
+	)
+	return i, err
+}
+
+const updateAccountOverdraftLimit = `-- name: UpdateAccountOverdraftLimit :one
+UPDATE accounts
+SET overdraft_limit = $1
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, is_frozen
+`
+
+type UpdateAccountOverdraftLimitParams struct {
+	OverdraftLimit int64 `json:"overdraft_limit"`
+	ID             int64 `json:"id"`
+}
+
+func (q *Queries) UpdateAccountOverdraftLimit(ctx context.Context, arg UpdateAccountOverdraftLimitParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountOverdraftLimit, arg.OverdraftLimit, arg.ID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.IsFrozen,
 	)
 	return i, err
 }
@@ -81,6 +81,58 @@ func TestUpdateAccount(t *testing.T) {
 	require.WithinDuration(t, account1.CreatedAt, account2.CreatedAt, time.Second)
 }
 
+func TestUpdateAccountFrozen(t *testing.T) {
+	account1 := createRandomAccount(t)
+	// accounts are not frozen when they are created
+	require.False(t, account1.IsFrozen)
+
+	account2, err := testQueries.UpdateAccountFrozen(context.Background(), UpdateAccountFrozenParams{
+		ID:       account1.ID,
+		IsFrozen: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account1.ID, account2.ID)
+	require.Equal(t, account1.Balance, account2.Balance)
+	require.True(t, account2.IsFrozen)
+
+	account3, err := testQueries.UpdateAccountFrozen(context.Background(), UpdateAccountFrozenParams{
+		ID:       account1.ID,
+		IsFrozen: false,
+	})
+	require.NoError(t, err)
+	require.False(t, account3.IsFrozen)
+}
+
+func TestUpdateAccountOverdraftLimit(t *testing.T) {
+	account1 := createRandomAccount(t)
+	// accounts cannot be overdrawn unless a limit is set
+	require.Zero(t, account1.OverdraftLimit)
+
+	arg := UpdateAccountOverdraftLimitParams{
+		ID:             account1.ID,
+		OverdraftLimit: util.RandomMoney(),
+	}
+
+	account2, err := testQueries.UpdateAccountOverdraftLimit(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, account2)
+
+	// only the overdraft limit changes
+	require.Equal(t, account1.ID, account2.ID)
+	require.Equal(t, account1.Owner, account2.Owner)
+	require.Equal(t, account1.Balance, account2.Balance)
+	require.Equal(t, account1.Currency, account2.Currency)
+	require.Equal(t, arg.OverdraftLimit, account2.OverdraftLimit)
+	require.WithinDuration(t, account1.CreatedAt, account2.CreatedAt, time.Second)
+
+	// the overdraft_limit_non_negative check constraint rejects negative limits
+	_, err = testQueries.UpdateAccountOverdraftLimit(context.Background(), UpdateAccountOverdraftLimitParams{
+		ID:             account1.ID,
+		OverdraftLimit: -1,
+	})
+	require.Error(t, err)
+}
+
 func TestDeleteAccount(t *testing.T) {
 	account1 := createRandomAccount(t)
 	err := testQueries.DeleteAccount(context.Background(), account1.ID)
@@ -118,3 +170,42 @@ func TestListAccounts(t *testing.T) {
 		require.Equal(t, lastAccount.Owner, account.Owner) // each account must have an owner that matches the lastAccount.Username
 	}
 }
+
+func TestListAccountsAfter(t *testing.T) {
+	// a user can hold one account per currency, so the owner gets three accounts
+	user := createRandomUser(t)
+	var created []Account
+	for _, currency := range []string{util.USD, util.EUR, util.CAD} {
+		account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+			Owner:    user.Username,
+			Balance:  util.RandomMoney(),
+			Currency: currency,
+		})
+		require.NoError(t, err)
+		created = append(created, account)
+	}
+
+	// the first page starts after id 0
+	arg := ListAccountsAfterParams{
+		Owner:   user.Username,
+		AfterID: 0,
+		Limit:   2,
+	}
+	page1, err := testQueries.ListAccountsAfter(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, page1, 2)
+	require.Equal(t, created[0].ID, page1[0].ID)
+	require.Equal(t, created[1].ID, page1[1].ID)
+
+	// the next page starts after the last id of the previous page
+	arg.AfterID = page1[1].ID
+	page2, err := testQueries.ListAccountsAfter(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, page2, 1)
+	require.Equal(t, created[2].ID, page2[0].ID)
+
+	arg.AfterID = page2[0].ID
+	page3, err := testQueries.ListAccountsAfter(context.Background(), arg)
+	require.NoError(t, err)
+	require.Empty(t, page3)
+}
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"SimpleBankProject/db/util"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomAPIKey(t *testing.T, user User, expiresAt sql.NullTime) ApiKey {
+	key, err := util.NewAPIKey()
+	require.NoError(t, err)
+
+	arg := CreateAPIKeyParams{
+		ID:        uuid.New(),
+		Username:  user.Username,
+		Name:      util.RandomString(8),
+		KeyPrefix: util.APIKeyDisplayPrefix(key),
+		KeyHash:   util.HashSecretToken(key),
+		Scopes:    []string{util.AccountsReadScope, util.TransfersWriteScope},
+		ExpiresAt: expiresAt,
+	}
+
+	apiKey, err := testQueries.CreateAPIKey(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.ID, apiKey.ID)
+	require.Equal(t, arg.Username, apiKey.Username)
+	require.Equal(t, arg.Name, apiKey.Name)
+	require.Equal(t, arg.KeyPrefix, apiKey.KeyPrefix)
+	require.Equal(t, arg.KeyHash, apiKey.KeyHash)
+	require.Equal(t, arg.Scopes, apiKey.Scopes)
+	require.Equal(t, arg.ExpiresAt.Valid, apiKey.ExpiresAt.Valid)
+	require.False(t, apiKey.RevokedAt.Valid)
+	require.NotZero(t, apiKey.CreatedAt)
+
+	return apiKey
+}
+
+func TestCreateAPIKey(t *testing.T) {
+	createRandomAPIKey(t, createRandomUser(t), sql.NullTime{})
+}
+
+func TestGetAPIKey(t *testing.T) {
+	apiKey1 := createRandomAPIKey(t, createRandomUser(t), sql.NullTime{})
+
+	apiKey2, err := testQueries.GetAPIKey(context.Background(), apiKey1.ID)
+	require.NoError(t, err)
+	require.Equal(t, apiKey1.ID, apiKey2.ID)
+	require.Equal(t, apiKey1.KeyHash, apiKey2.KeyHash)
+	require.Equal(t, apiKey1.Scopes, apiKey2.Scopes)
+}
+
+func TestGetActiveAPIKeyByHash(t *testing.T) {
+	user := createRandomUser(t)
+	apiKey := createRandomAPIKey(t, user, sql.NullTime{Time: time.Now().Add(time.Minute), Valid: true})
+
+	key, err := testQueries.GetActiveAPIKeyByHash(context.Background(), apiKey.KeyHash)
+	require.NoError(t, err)
+	require.Equal(t, apiKey.ID, key.ID)
+	require.Equal(t, user.Username, key.Username)
+	require.Equal(t, user.Role, key.Role)
+	require.Equal(t, apiKey.Scopes, key.Scopes)
+
+	// a revoked key is left out
+	_, err = testQueries.RevokeAPIKey(context.Background(), apiKey.ID)
+	require.NoError(t, err)
+	_, err = testQueries.GetActiveAPIKeyByHash(context.Background(), apiKey.KeyHash)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// and so is an expired key
+	expiredAPIKey := createRandomAPIKey(t, user, sql.NullTime{Time: time.Now().Add(-time.Minute), Valid: true})
+	_, err = testQueries.GetActiveAPIKeyByHash(context.Background(), expiredAPIKey.KeyHash)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestListAPIKeysByUser(t *testing.T) {
+	user := createRandomUser(t)
+	createRandomAPIKey(t, createRandomUser(t), sql.NullTime{})
+	for i := 0; i < 3; i++ {
+		createRandomAPIKey(t, user, sql.NullTime{})
+	}
+
+	apiKeys, err := testQueries.ListAPIKeysByUser(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, apiKeys, 3)
+	for _, apiKey := range apiKeys {
+		require.Equal(t, user.Username, apiKey.Username)
+	}
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	apiKey1 := createRandomAPIKey(t, createRandomUser(t), sql.NullTime{})
+
+	apiKey2, err := testQueries.RevokeAPIKey(context.Background(), apiKey1.ID)
+	require.NoError(t, err)
+	require.True(t, apiKey2.RevokedAt.Valid)
+	require.WithinDuration(t, time.Now(), apiKey2.RevokedAt.Time, time.Second)
+
+	// revoking it again keeps the time it was first revoked
+	apiKey3, err := testQueries.RevokeAPIKey(context.Background(), apiKey1.ID)
+	require.NoError(t, err)
+	require.Equal(t, apiKey2.RevokedAt.Time, apiKey3.RevokedAt.Time)
+}
@@ -0,0 +1,53 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"SimpleBankProject/token"
+)
+
+// NewDenylist returns the token.Denylist used by the servers - the revoked_tokens table, behind an in-process cache when
+// cacheDuration is positive - the cache remembers tokens which weren't denied, so a token revoked by another process or cut
+// off by a password change keeps working for up to cacheDuration
+func NewDenylist(querier Querier, cacheDuration time.Duration) token.Denylist {
+	denylist := NewTokenDenylist(querier)
+	if cacheDuration <= 0 {
+		return denylist
+	}
+	return token.NewCachedDenylist(denylist, cacheDuration)
+}
+
+// TokenDenylist is the token.Denylist kept in the revoked_tokens table - every server process shares it
+type TokenDenylist struct {
+	querier Querier
+}
+
+// NewTokenDenylist creates a TokenDenylist which reads and writes the revoked_tokens table through querier
+func NewTokenDenylist(querier Querier) *TokenDenylist {
+	return &TokenDenylist{querier: querier}
+}
+
+// Deny revokes the token of the payload until it expires
+func (denylist *TokenDenylist) Deny(ctx context.Context, payload *token.Payload) error {
+	return denylist.querier.CreateRevokedToken(ctx, CreateRevokedTokenParams{
+		ID:        payload.ID,
+		ExpiresAt: payload.ExpiredAt,
+	})
+}
+
+// IsDenied returns true if the token of the payload has been revoked, or if it was issued before the password of its user
+// last changed - changing the password or resetting it cuts off every access token issued before, not only the sessions
+func (denylist *TokenDenylist) IsDenied(ctx context.Context, payload *token.Payload) (bool, error) {
+	return denylist.querier.IsTokenDenied(ctx, IsTokenDeniedParams{
+		ID:       payload.ID,
+		Username: payload.Username,
+		IssuedAt: payload.IssuedAt,
+	})
+}
+
+// DeleteExpired removes the tokens which have expired and returns how many were removed - the token maker already rejects
+// expired tokens, so their rows are no longer needed
+func (denylist *TokenDenylist) DeleteExpired(ctx context.Context) (int64, error) {
+	return denylist.querier.DeleteExpiredRevokedTokens(ctx)
+}
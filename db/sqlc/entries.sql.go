@@ -7,6 +7,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 )
 
 const createEntry = `-- name: CreateEntry :one
@@ -16,7 +17,7 @@ INSERT INTO entries (
 ) VALUES (
   $1, $2
 )
-RETURNING id, account_id, amount, created_at
+RETURNING id, account_id, amount, created_at, type, external_reference
 `
 
 type CreateEntryParams struct {
@@ -32,6 +33,47 @@ func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Type,
+		&i.ExternalReference,
+	)
+	return i, err
+}
+
+const createExternalEntry = `-- name: CreateExternalEntry :one
+INSERT INTO entries (
+  account_id,
+  amount,
+  type,
+  external_reference
+) VALUES (
+  $1, $2, $3, $4::varchar
+)
+RETURNING id, account_id, amount, created_at, type, external_reference
+`
+
+type CreateExternalEntryParams struct {
+	AccountID         int64  `json:"account_id"`
+	Amount            int64  `json:"amount"`
+	Type              string `json:"type"`
+	ExternalReference string `json:"external_reference"`
+}
+
+// records money entering (deposit) or leaving (withdrawal) the bank
+func (q *Queries) CreateExternalEntry(ctx context.Context, arg CreateExternalEntryParams) (Entry, error) {
+	row := q.db.QueryRowContext(ctx, createExternalEntry,
+		arg.AccountID,
+		arg.Amount,
+		arg.Type,
+		arg.ExternalReference,
+	)
+	var i Entry
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.Type,
+		&i.ExternalReference,
 	)
 	return i, err
 }
@@ -47,7 +89,7 @@ func (q *Queries) DeleteEntry(ctx context.Context, id int64) error {
 }
 
 const getEntry = `-- name: GetEntry :one
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, type, external_reference FROM entries
 WHERE id = $1 LIMIT 1
 `
 
@@ -59,26 +101,107 @@ func (q *Queries) GetEntry(ctx context.Context, id int64) (Entry, error) {
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Type,
+		&i.ExternalReference,
 	)
 	return i, err
 }
 
 const listEntries = `-- name: ListEntries :many
-SELECT id, account_id, amount, created_at FROM entries
-WHERE account_id = $1
+SELECT id, account_id, amount, created_at, type, external_reference FROM entries
+WHERE
+    account_id = $1 AND
+    ($2::timestamptz IS NULL OR created_at >= $2) AND
+    ($3::timestamptz IS NULL OR created_at < $3) AND
+    ($4::varchar IS NULL OR
+        ($4 = 'positive' AND amount > 0) OR
+        ($4 = 'negative' AND amount < 0))
 ORDER BY id
-LIMIT $2
-OFFSET $3
+LIMIT $6
+OFFSET $5
 `
 
 type ListEntriesParams struct {
-	AccountID int64 `json:"account_id"`
-	Limit     int32 `json:"limit"`
-	Offset    int32 `json:"offset"`
+	AccountID int64          `json:"account_id"`
+	StartTime sql.NullTime   `json:"start_time"`
+	EndTime   sql.NullTime   `json:"end_time"`
+	Sign      sql.NullString `json:"sign"`
+	Offset    int32          `json:"offset"`
+	Limit     int32          `json:"limit"`
 }
 
+// the time range and sign filters are optional - a null argument matches every entry
 func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error) {
-	rows, err := q.db.QueryContext(ctx, listEntries, arg.AccountID, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listEntries,
+		arg.AccountID,
+		arg.StartTime,
+		arg.EndTime,
+		arg.Sign,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Entry{}
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.Type,
+			&i.ExternalReference,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEntriesAfter = `-- name: ListEntriesAfter :many
+SELECT id, account_id, amount, created_at, type, external_reference FROM entries
+WHERE
+    account_id = $1 AND
+    id > $2 AND
+    ($3::timestamptz IS NULL OR created_at >= $3) AND
+    ($4::timestamptz IS NULL OR created_at < $4) AND
+    ($5::varchar IS NULL OR
+        ($5 = 'positive' AND amount > 0) OR
+        ($5 = 'negative' AND amount < 0))
+ORDER BY id
+LIMIT $6
+`
+
+type ListEntriesAfterParams struct {
+	AccountID int64          `json:"account_id"`
+	AfterID   int64          `json:"after_id"`
+	StartTime sql.NullTime   `json:"start_time"`
+	EndTime   sql.NullTime   `json:"end_time"`
+	Sign      sql.NullString `json:"sign"`
+	Limit     int32          `json:"limit"`
+}
+
+// keyset (cursor) pagination - same filters as ListEntries but lists the entries with an id greater than after_id, the last
+// id of the previous page
+func (q *Queries) ListEntriesAfter(ctx context.Context, arg ListEntriesAfterParams) ([]Entry, error) {
+	rows, err := q.db.QueryContext(ctx, listEntriesAfter,
+		arg.AccountID,
+		arg.AfterID,
+		arg.StartTime,
+		arg.EndTime,
+		arg.Sign,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -91,6 +214,8 @@ func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Ent
 			&i.AccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.Type,
+			&i.ExternalReference,
 		); err != nil {
 			return nil, err
 		}
@@ -109,7 +234,7 @@ const updateEntry = `-- name: UpdateEntry :one
 UPDATE entries
 set amount = $2
 WHERE id = $1
-RETURNING id, account_id, amount, created_at
+RETURNING id, account_id, amount, created_at, type, external_reference
 `
 
 type UpdateEntryParams struct {
@@ -125,6 +250,8 @@ func (q *Queries) UpdateEntry(ctx context.Context, arg UpdateEntryParams) (Entry
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Type,
+		&i.ExternalReference,
 	)
 	return i, err
 }
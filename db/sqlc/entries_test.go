@@ -36,6 +36,37 @@ func TestCreateEntry(t *testing.T) {
 	createRandomEntry(t, account)
 }
 
+func TestCreateExternalEntry(t *testing.T) {
+	account := createRandomAccount(t)
+
+	arg := CreateExternalEntryParams{
+		AccountID:         account.ID,
+		Amount:            util.RandomMoney(),
+		Type:              EntryTypeDeposit,
+		ExternalReference: util.RandomString(16),
+	}
+
+	entry, err := testQueries.CreateExternalEntry(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, entry)
+
+	require.Equal(t, arg.AccountID, entry.AccountID)
+	require.Equal(t, arg.Amount, entry.Amount)
+	require.Equal(t, arg.Type, entry.Type)
+	require.Equal(t, sql.NullString{String: arg.ExternalReference, Valid: true}, entry.ExternalReference)
+	require.NotZero(t, entry.ID)
+	require.NotZero(t, entry.CreatedAt)
+
+	// the same external reference cannot be booked twice for the same type
+	_, err = testQueries.CreateExternalEntry(context.Background(), arg)
+	require.Error(t, err)
+
+	// entries created by transfers use the default type and have no external reference
+	transferEntry := createRandomEntry(t, account)
+	require.Equal(t, EntryTypeTransfer, transferEntry.Type)
+	require.False(t, transferEntry.ExternalReference.Valid)
+}
+
 func TestGetEntry(t *testing.T) {
 	account1 := createRandomAccount(t)       // create a random account to pass to CreateRandomEntry
 	entry1 := createRandomEntry(t, account1) // create a random entry using account1.ID and a random amount (account1.amount)
@@ -108,3 +139,71 @@ func TestListEntries(t *testing.T) {
 		require.Equal(t, arg.AccountID, entry.AccountID) // the list is of entries from a specific account ID and so they should match across all entries in the slice of entries
 	}
 }
+
+func TestListEntriesFilters(t *testing.T) {
+	account1 := createRandomAccount(t)
+	for i := 0; i < 3; i++ {
+		createRandomEntry(t, account1)
+	}
+
+	// money leaving the account is recorded as a negative entry
+	negative, err := testQueries.CreateEntry(context.Background(), CreateEntryParams{
+		AccountID: account1.ID,
+		Amount:    -util.RandomMoney(),
+	})
+	require.NoError(t, err)
+
+	arg := ListEntriesParams{
+		AccountID: account1.ID,
+		Sign:      sql.NullString{String: AmountSignNegative, Valid: true},
+		Limit:     10,
+	}
+
+	entries, err := testQueries.ListEntries(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, negative.ID, entries[0].ID)
+
+	arg.Sign = sql.NullString{String: AmountSignPositive, Valid: true}
+	entries, err = testQueries.ListEntries(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	for _, entry := range entries {
+		require.Positive(t, entry.Amount)
+	}
+
+	// no entries are created in the future
+	arg.Sign = sql.NullString{}
+	arg.StartTime = sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true}
+	entries, err = testQueries.ListEntries(context.Background(), arg)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestListEntriesAfter(t *testing.T) {
+	account1 := createRandomAccount(t)
+	var created []Entry
+	for i := 0; i < 5; i++ {
+		created = append(created, createRandomEntry(t, account1))
+	}
+
+	arg := ListEntriesAfterParams{
+		AccountID: account1.ID,
+		AfterID:   created[1].ID,
+		Limit:     10,
+	}
+
+	// only the entries after the cursor are listed, in id order
+	entries, err := testQueries.ListEntriesAfter(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	for i, entry := range entries {
+		require.Equal(t, created[i+2].ID, entry.ID)
+	}
+
+	// the filters still apply - every random entry is positive
+	arg.Sign = sql.NullString{String: AmountSignNegative, Valid: true}
+	entries, err = testQueries.ListEntriesAfter(context.Background(), arg)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
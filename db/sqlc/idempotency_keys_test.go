@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"SimpleBankProject/db/util"
+
+	"github.com/stretchr/testify/require"
+)
+
+// createRandomIdempotencyKey stores a random key for a new transfer
+func createRandomIdempotencyKey(t *testing.T) IdempotencyKey {
+	transfer := createRandomTransfer(t)
+
+	// any valid JSON will do here - TransferTX stores the marshalled TransferTxResult
+	result, err := json.Marshal(TransferTxResult{Transfer: transfer})
+	require.NoError(t, err)
+
+	arg := CreateIdempotencyKeyParams{
+		Key:           util.RandomString(32),
+		FromAccountID: transfer.FromAccountID,
+		ToAccountID:   transfer.ToAccountID,
+		Amount:        transfer.Amount,
+		TransferID:    transfer.ID,
+		Result:        result,
+	}
+
+	idempotencyKey, err := testQueries.CreateIdempotencyKey(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, idempotencyKey)
+
+	require.Equal(t, arg.Key, idempotencyKey.Key)
+	require.Equal(t, arg.FromAccountID, idempotencyKey.FromAccountID)
+	require.Equal(t, arg.ToAccountID, idempotencyKey.ToAccountID)
+	require.Equal(t, arg.Amount, idempotencyKey.Amount)
+	require.Equal(t, arg.TransferID, idempotencyKey.TransferID)
+	require.JSONEq(t, string(arg.Result), string(idempotencyKey.Result))
+	require.NotZero(t, idempotencyKey.CreatedAt)
+
+	return idempotencyKey
+}
+
+func TestCreateIdempotencyKey(t *testing.T) {
+	createRandomIdempotencyKey(t)
+}
+
+func TestGetIdempotencyKey(t *testing.T) {
+	idempotencyKey1 := createRandomIdempotencyKey(t)
+
+	idempotencyKey2, err := testQueries.GetIdempotencyKey(context.Background(), GetIdempotencyKeyParams{
+		FromAccountID: idempotencyKey1.FromAccountID,
+		Key:           idempotencyKey1.Key,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, idempotencyKey2)
+
+	require.Equal(t, idempotencyKey1.Key, idempotencyKey2.Key)
+	require.Equal(t, idempotencyKey1.FromAccountID, idempotencyKey2.FromAccountID)
+	require.Equal(t, idempotencyKey1.ToAccountID, idempotencyKey2.ToAccountID)
+	require.Equal(t, idempotencyKey1.Amount, idempotencyKey2.Amount)
+	require.Equal(t, idempotencyKey1.TransferID, idempotencyKey2.TransferID)
+	require.JSONEq(t, string(idempotencyKey1.Result), string(idempotencyKey2.Result))
+	require.WithinDuration(t, idempotencyKey1.CreatedAt, idempotencyKey2.CreatedAt, time.Second)
+}
+
+func TestGetIdempotencyKeyNotFound(t *testing.T) {
+	idempotencyKey, err := testQueries.GetIdempotencyKey(context.Background(), GetIdempotencyKeyParams{
+		FromAccountID: createRandomAccount(t).ID,
+		Key:           util.RandomString(32),
+	})
+	require.Error(t, err)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+	require.Empty(t, idempotencyKey)
+}
+
+// TestGetIdempotencyKeyOtherAccount - tests that a key is only found for the from account it was used with
+func TestGetIdempotencyKeyOtherAccount(t *testing.T) {
+	idempotencyKey1 := createRandomIdempotencyKey(t)
+
+	idempotencyKey2, err := testQueries.GetIdempotencyKey(context.Background(), GetIdempotencyKeyParams{
+		FromAccountID: idempotencyKey1.ToAccountID,
+		Key:           idempotencyKey1.Key,
+	})
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+	require.Empty(t, idempotencyKey2)
+}
@@ -0,0 +1,106 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"SimpleBankProject/db/util"
+
+	"github.com/stretchr/testify/require"
+)
+
+// randomClientIP returns a random private IP, so the failures counted by one test aren't left over from another
+func randomClientIP() string {
+	return fmt.Sprintf("10.%d.%d.%d", util.RandomInt(0, 255), util.RandomInt(0, 255), util.RandomInt(1, 254))
+}
+
+func createRandomLoginAttempt(t *testing.T, username string, clientIP string, failureReason string) LoginAttempt {
+	arg := CreateLoginAttemptParams{
+		Username:      username,
+		ClientIp:      clientIP,
+		UserAgent:     util.RandomString(10),
+		Succeeded:     failureReason == "",
+		FailureReason: failureReason,
+	}
+
+	attempt, err := testQueries.CreateLoginAttempt(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, attempt.ID)
+	require.Equal(t, arg.Username, attempt.Username)
+	require.Equal(t, arg.ClientIp, attempt.ClientIp)
+	require.Equal(t, arg.UserAgent, attempt.UserAgent)
+	require.Equal(t, arg.Succeeded, attempt.Succeeded)
+	require.Equal(t, arg.FailureReason, attempt.FailureReason)
+	require.NotZero(t, attempt.CreatedAt)
+
+	return attempt
+}
+
+func TestCreateLoginAttempt(t *testing.T) {
+	createRandomLoginAttempt(t, util.RandomOwner(), randomClientIP(), util.LoginFailureUnknownUser)
+}
+
+func TestGetUserLoginFailures(t *testing.T) {
+	username := util.RandomOwner()
+	arg := GetUserLoginFailuresParams{
+		Username: username,
+		Since:    time.Now().Add(-time.Hour),
+	}
+
+	failures, err := testQueries.GetUserLoginFailures(context.Background(), arg)
+	require.NoError(t, err)
+	require.Zero(t, failures.Failures)
+
+	createRandomLoginAttempt(t, username, randomClientIP(), util.LoginFailureWrongPassword)
+	last := createRandomLoginAttempt(t, username, randomClientIP(), util.LoginFailureWrongPassword)
+	// attempts rejected by the lockout aren't counted
+	createRandomLoginAttempt(t, username, randomClientIP(), util.LoginFailureLockedOut)
+
+	failures, err = testQueries.GetUserLoginFailures(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), failures.Failures)
+	require.WithinDuration(t, last.CreatedAt, failures.LastFailedAt, time.Millisecond)
+
+	// failures before the window aren't counted
+	arg.Since = time.Now()
+	failures, err = testQueries.GetUserLoginFailures(context.Background(), arg)
+	require.NoError(t, err)
+	require.Zero(t, failures.Failures)
+
+	// a successful login resets the failures
+	arg.Since = time.Now().Add(-time.Hour)
+	createRandomLoginAttempt(t, username, randomClientIP(), "")
+	failures, err = testQueries.GetUserLoginFailures(context.Background(), arg)
+	require.NoError(t, err)
+	require.Zero(t, failures.Failures)
+
+	createRandomLoginAttempt(t, username, randomClientIP(), util.LoginFailureWrongPassword)
+	failures, err = testQueries.GetUserLoginFailures(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), failures.Failures)
+}
+
+func TestGetClientIPLoginFailures(t *testing.T) {
+	clientIP := randomClientIP()
+	arg := GetClientIPLoginFailuresParams{
+		ClientIp: clientIP,
+		Since:    time.Now().Add(-time.Hour),
+	}
+
+	createRandomLoginAttempt(t, util.RandomOwner(), clientIP, util.LoginFailureUnknownUser)
+	createRandomLoginAttempt(t, util.RandomOwner(), clientIP, util.LoginFailureWrongPassword)
+	createRandomLoginAttempt(t, util.RandomOwner(), clientIP, util.LoginFailureLockedOut)
+	// a successful login from the IP doesn't reset its failures
+	createRandomLoginAttempt(t, util.RandomOwner(), clientIP, "")
+
+	failures, err := testQueries.GetClientIPLoginFailures(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), failures.Failures)
+
+	arg.Since = time.Now()
+	failures, err = testQueries.GetClientIPLoginFailures(context.Background(), arg)
+	require.NoError(t, err)
+	require.Zero(t, failures.Failures)
+}
@@ -0,0 +1,157 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"SimpleBankProject/db/util"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomUserMFA(t *testing.T, user User) UserMfa {
+	arg := CreateUserMFAParams{
+		Username:        user.Username,
+		EncryptedSecret: util.RandomString(32),
+	}
+
+	userMFA, err := testQueries.CreateUserMFA(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Username, userMFA.Username)
+	require.Equal(t, arg.EncryptedSecret, userMFA.EncryptedSecret)
+	require.False(t, userMFA.IsEnabled)
+	require.Zero(t, userMFA.LastUsedStep)
+	require.NotZero(t, userMFA.CreatedAt)
+	require.False(t, userMFA.EnabledAt.Valid)
+
+	return userMFA
+}
+
+func createRandomMFARecoveryCode(t *testing.T, user User) MfaRecoveryCode {
+	arg := CreateMFARecoveryCodeParams{
+		Username: user.Username,
+		CodeHash: util.HashSecretToken(util.RandomString(10)),
+	}
+
+	recoveryCode, err := testQueries.CreateMFARecoveryCode(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, recoveryCode.ID)
+	require.Equal(t, arg.Username, recoveryCode.Username)
+	require.Equal(t, arg.CodeHash, recoveryCode.CodeHash)
+	require.False(t, recoveryCode.UsedAt.Valid)
+
+	return recoveryCode
+}
+
+func TestCreateUserMFA(t *testing.T) {
+	createRandomUserMFA(t, createRandomUser(t))
+}
+
+func TestCreateUserMFAReplacesSecret(t *testing.T) {
+	user := createRandomUser(t)
+	createRandomUserMFA(t, user)
+
+	// enrolling again before confirming replaces the secret
+	userMFA := createRandomUserMFA(t, user)
+
+	gotUserMFA, err := testQueries.GetUserMFA(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Equal(t, userMFA.EncryptedSecret, gotUserMFA.EncryptedSecret)
+
+	// once enabled, the secret can't be replaced
+	_, err = testQueries.EnableUserMFA(context.Background(), user.Username)
+	require.NoError(t, err)
+
+	_, err = testQueries.CreateUserMFA(context.Background(), CreateUserMFAParams{
+		Username:        user.Username,
+		EncryptedSecret: util.RandomString(32),
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestEnableUserMFA(t *testing.T) {
+	user := createRandomUser(t)
+	createRandomUserMFA(t, user)
+
+	userMFA, err := testQueries.EnableUserMFA(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.True(t, userMFA.IsEnabled)
+	require.True(t, userMFA.EnabledAt.Valid)
+
+	// it can only be enabled once
+	_, err = testQueries.EnableUserMFA(context.Background(), user.Username)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestUseTOTPStep(t *testing.T) {
+	user := createRandomUser(t)
+	createRandomUserMFA(t, user)
+
+	arg := UseTOTPStepParams{
+		Step:     util.RandomInt(1000, 2000),
+		Username: user.Username,
+	}
+	userMFA, err := testQueries.UseTOTPStep(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Step, userMFA.LastUsedStep)
+
+	// the same step and earlier steps can't be used again
+	_, err = testQueries.UseTOTPStep(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	arg.Step--
+	_, err = testQueries.UseTOTPStep(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestDeleteUserMFA(t *testing.T) {
+	user := createRandomUser(t)
+	createRandomUserMFA(t, user)
+
+	err := testQueries.DeleteUserMFA(context.Background(), user.Username)
+	require.NoError(t, err)
+
+	_, err = testQueries.GetUserMFA(context.Background(), user.Username)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestUseMFARecoveryCode(t *testing.T) {
+	user := createRandomUser(t)
+	recoveryCode1 := createRandomMFARecoveryCode(t, user)
+
+	arg := UseMFARecoveryCodeParams{
+		Username: user.Username,
+		CodeHash: recoveryCode1.CodeHash,
+	}
+	recoveryCode2, err := testQueries.UseMFARecoveryCode(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, recoveryCode1.ID, recoveryCode2.ID)
+	require.True(t, recoveryCode2.UsedAt.Valid)
+
+	// a recovery code can only be used once
+	_, err = testQueries.UseMFARecoveryCode(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// nor by another user
+	recoveryCode3 := createRandomMFARecoveryCode(t, user)
+	_, err = testQueries.UseMFARecoveryCode(context.Background(), UseMFARecoveryCodeParams{
+		Username: createRandomUser(t).Username,
+		CodeHash: recoveryCode3.CodeHash,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestDeleteMFARecoveryCodes(t *testing.T) {
+	user := createRandomUser(t)
+	recoveryCode := createRandomMFARecoveryCode(t, user)
+
+	err := testQueries.DeleteMFARecoveryCodes(context.Background(), user.Username)
+	require.NoError(t, err)
+
+	_, err = testQueries.UseMFARecoveryCode(context.Background(), UseMFARecoveryCodeParams{
+		Username: user.Username,
+		CodeHash: recoveryCode.CodeHash,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -5,17 +5,33 @@
 package db
 
 import (
+	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type Account struct {
-	ID        int64     `json:"id"`
-	Owner     string    `json:"owner"`
-	Balance   int64     `json:"balance"`
-	Currency  string    `json:"currency"`
-	CreatedAt time.Time `json:"created_at"`
+	ID             int64     `json:"id"`
+	Owner          string    `json:"owner"`
+	Balance        int64     `json:"balance"`
+	Currency       string    `json:"currency"`
+	CreatedAt      time.Time `json:"created_at"`
+	OverdraftLimit int64     `json:"overdraft_limit"`
+	IsFrozen       bool      `json:"is_frozen"`
+}
+
+type ApiKey struct {
+	ID        uuid.UUID    `json:"id"`
+	Username  string       `json:"username"`
+	Name      string       `json:"name"`
+	KeyPrefix string       `json:"key_prefix"`
+	KeyHash   string       `json:"key_hash"`
+	Scopes    []string     `json:"scopes"`
+	ExpiresAt sql.NullTime `json:"expires_at"`
+	RevokedAt sql.NullTime `json:"revoked_at"`
+	CreatedAt time.Time    `json:"created_at"`
 }
 
 type Entry struct {
@@ -24,6 +40,52 @@ type Entry struct {
 	// can be negative or positive
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"created_at"`
+	// transfer, deposit, or withdrawal
+	Type              string         `json:"type"`
+	ExternalReference sql.NullString `json:"external_reference"`
+}
+
+type IdempotencyKey struct {
+	Key           string          `json:"key"`
+	FromAccountID int64           `json:"from_account_id"`
+	ToAccountID   int64           `json:"to_account_id"`
+	Amount        int64           `json:"amount"`
+	TransferID    int64           `json:"transfer_id"`
+	Result        json.RawMessage `json:"result"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+type LoginAttempt struct {
+	ID            int64     `json:"id"`
+	Username      string    `json:"username"`
+	ClientIp      string    `json:"client_ip"`
+	UserAgent     string    `json:"user_agent"`
+	Succeeded     bool      `json:"succeeded"`
+	FailureReason string    `json:"failure_reason"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type MfaRecoveryCode struct {
+	ID        int64        `json:"id"`
+	Username  string       `json:"username"`
+	CodeHash  string       `json:"code_hash"`
+	UsedAt    sql.NullTime `json:"used_at"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type PasswordReset struct {
+	ID        int64     `json:"id"`
+	Username  string    `json:"username"`
+	TokenHash string    `json:"token_hash"`
+	IsUsed    bool      `json:"is_used"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type RevokedToken struct {
+	ID        uuid.UUID `json:"id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type Session struct {
@@ -35,6 +97,11 @@ type Session struct {
 	IsBlocked    bool      `json:"is_blocked"`
 	ExpiresAt    time.Time `json:"expires_at"`
 	CreatedAt    time.Time `json:"created_at"`
+	// id of the first session of the login - shared by every rotated session
+	FamilyID          uuid.UUID     `json:"family_id"`
+	PreviousSessionID uuid.NullUUID `json:"previous_session_id"`
+	// when the refresh token was replaced - using it again blocks the whole family
+	RotatedAt sql.NullTime `json:"rotated_at"`
 }
 
 type Transfer struct {
@@ -42,8 +109,9 @@ type Transfer struct {
 	FromAccountID int64 `json:"from_account_id"`
 	ToAccountID   int64 `json:"to_account_id"`
 	// must be positive
-	Amount    int64     `json:"amount"`
-	CreatedAt time.Time `json:"created_at"`
+	Amount     int64         `json:"amount"`
+	CreatedAt  time.Time     `json:"created_at"`
+	ReversalOf sql.NullInt64 `json:"reversal_of"`
 }
 
 type User struct {
@@ -53,4 +121,26 @@ type User struct {
 	Email            string    `json:"email"`
 	PasswordChangeAt time.Time `json:"password_change_at"`
 	CreatedAt        time.Time `json:"created_at"`
+	// depositor or admin
+	Role            string `json:"role"`
+	IsEmailVerified bool   `json:"is_email_verified"`
+}
+
+type UserMfa struct {
+	Username        string       `json:"username"`
+	EncryptedSecret string       `json:"encrypted_secret"`
+	IsEnabled       bool         `json:"is_enabled"`
+	LastUsedStep    int64        `json:"last_used_step"`
+	CreatedAt       time.Time    `json:"created_at"`
+	EnabledAt       sql.NullTime `json:"enabled_at"`
+}
+
+type VerifyEmail struct {
+	ID             int64     `json:"id"`
+	Username       string    `json:"username"`
+	Email          string    `json:"email"`
+	SecretCodeHash string    `json:"secret_code_hash"`
+	IsUsed         bool      `json:"is_used"`
+	CreatedAt      time.Time `json:"created_at"`
+	ExpiredAt      time.Time `json:"expired_at"`
 }
@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"SimpleBankProject/db/util"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomPasswordReset(t *testing.T, user User, expiresAt time.Time) PasswordReset {
+	arg := CreatePasswordResetParams{
+		Username:  user.Username,
+		TokenHash: util.HashSecretToken(util.RandomString(32)),
+		ExpiresAt: expiresAt,
+	}
+
+	passwordReset, err := testQueries.CreatePasswordReset(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, passwordReset.ID)
+	require.Equal(t, arg.Username, passwordReset.Username)
+	require.Equal(t, arg.TokenHash, passwordReset.TokenHash)
+	require.False(t, passwordReset.IsUsed)
+	require.NotZero(t, passwordReset.CreatedAt)
+	require.WithinDuration(t, arg.ExpiresAt, passwordReset.ExpiresAt, time.Second)
+
+	return passwordReset
+}
+
+func TestCreatePasswordReset(t *testing.T) {
+	createRandomPasswordReset(t, createRandomUser(t), time.Now().Add(time.Minute))
+}
+
+func TestUsePasswordReset(t *testing.T) {
+	passwordReset1 := createRandomPasswordReset(t, createRandomUser(t), time.Now().Add(time.Minute))
+
+	passwordReset2, err := testQueries.UsePasswordReset(context.Background(), passwordReset1.TokenHash)
+	require.NoError(t, err)
+	require.Equal(t, passwordReset1.ID, passwordReset2.ID)
+	require.True(t, passwordReset2.IsUsed)
+
+	// a used token can't be used again
+	_, err = testQueries.UsePasswordReset(context.Background(), passwordReset1.TokenHash)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestUsePasswordResetExpired(t *testing.T) {
+	passwordReset := createRandomPasswordReset(t, createRandomUser(t), time.Now().Add(-time.Minute))
+
+	_, err := testQueries.UsePasswordReset(context.Background(), passwordReset.TokenHash)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
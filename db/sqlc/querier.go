@@ -12,26 +12,113 @@ import (
 
 type Querier interface {
 	AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error)
+	// the balance is only updated if it doesn't go below the account's overdraft limit - no rows are returned otherwise
+	AddAccountBalanceIfSufficient(ctx context.Context, arg AddAccountBalanceIfSufficientParams) (Account, error)
+	// a blocked session can no longer be used to renew access tokens
+	BlockSession(ctx context.Context, id uuid.UUID) (Session, error)
+	// blocks every session created from the same login and revokes their refresh tokens, used when a rotated refresh token is
+	// presented again - returns the number of sessions blocked
+	BlockSessionFamily(ctx context.Context, familyID uuid.UUID) (int64, error)
+	// blocks every session of the user and revokes their refresh tokens, e.g. after a password change - returns the number of
+	// sessions blocked
+	BlockUserSessions(ctx context.Context, username string) (int64, error)
+	CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	// records money entering (deposit) or leaving (withdrawal) the bank
+	CreateExternalEntry(ctx context.Context, arg CreateExternalEntryParams) (Entry, error)
+	CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (IdempotencyKey, error)
+	CreateLoginAttempt(ctx context.Context, arg CreateLoginAttemptParams) (LoginAttempt, error)
+	CreateMFARecoveryCode(ctx context.Context, arg CreateMFARecoveryCodeParams) (MfaRecoveryCode, error)
+	CreatePasswordReset(ctx context.Context, arg CreatePasswordResetParams) (PasswordReset, error)
+	// revoking a token twice is not an error
+	CreateRevokedToken(ctx context.Context, arg CreateRevokedTokenParams) error
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
+	CreateTransferReversal(ctx context.Context, arg CreateTransferReversalParams) (Transfer, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	// enrolling again before the secret was confirmed replaces it - no row is returned if two-factor authentication is already
+	// enabled, it must be disabled first
+	CreateUserMFA(ctx context.Context, arg CreateUserMFAParams) (UserMfa, error)
+	CreateVerifyEmail(ctx context.Context, arg CreateVerifyEmailParams) (VerifyEmail, error)
 	DeleteAccount(ctx context.Context, id int64) error
 	DeleteEntry(ctx context.Context, id int64) error
+	// expired tokens are rejected by the token maker, so their rows are no longer needed
+	DeleteExpiredRevokedTokens(ctx context.Context) (int64, error)
+	DeleteMFARecoveryCodes(ctx context.Context, username string) error
 	DeleteTransfer(ctx context.Context, id int64) error
+	DeleteUserMFA(ctx context.Context, username string) error
+	EnableUserMFA(ctx context.Context, username string) (UserMfa, error)
+	GetAPIKey(ctx context.Context, id uuid.UUID) (ApiKey, error)
 	GetAccount(ctx context.Context, id int64) (Account, error)
 	GetAccountForUpdate(ctx context.Context, id int64) (Account, error)
+	// the key a request was authenticated with and the current role of its user - revoked and expired keys are left out
+	GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (GetActiveAPIKeyByHashRow, error)
+	// the failed login attempts from the client IP since the time, whichever username they tried - unlike the failures of a
+	// user, a successful login doesn't reset them, an attacker could otherwise login to their own account in between guesses
+	GetClientIPLoginFailures(ctx context.Context, arg GetClientIPLoginFailuresParams) (GetClientIPLoginFailuresRow, error)
 	GetEntry(ctx context.Context, id int64) (Entry, error)
+	// keys are scoped to the from account, so the same key chosen by another user is a different key
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error)
+	// the wrong TOTP and recovery codes sent for the user since the time the MFA challenge token was issued
+	GetMFAChallengeFailures(ctx context.Context, arg GetMFAChallengeFailuresParams) (int64, error)
 	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
 	GetTransfer(ctx context.Context, id int64) (Transfer, error)
+	// locks the transfer so concurrent reversals of it run one after the other
+	GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error)
+	GetTransferReversal(ctx context.Context, transferID int64) (Transfer, error)
 	GetUser(ctx context.Context, username string) (User, error)
+	GetUserByEmail(ctx context.Context, email string) (User, error)
+	// the failed attempts to login as the user since the time and since their last successful login - attempts rejected by the
+	// lockout itself aren't counted, so retrying while locked out doesn't extend the lockout
+	GetUserLoginFailures(ctx context.Context, arg GetUserLoginFailuresParams) (GetUserLoginFailuresRow, error)
+	GetUserMFA(ctx context.Context, username string) (UserMfa, error)
+	// a token is denied if it was revoked, or if it was issued before the password of its user last changed - a password change
+	// cuts off every token issued with the old password at once, without revoking each of them
+	IsTokenDenied(ctx context.Context, arg IsTokenDeniedParams) (bool, error)
+	IsTokenRevoked(ctx context.Context, id uuid.UUID) (bool, error)
+	ListAPIKeysByUser(ctx context.Context, username string) ([]ApiKey, error)
 	ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error)
+	// keyset (cursor) pagination - lists the accounts with an id greater than after_id, the last id of the previous page
+	ListAccountsAfter(ctx context.Context, arg ListAccountsAfterParams) ([]Account, error)
+	// the time range and sign filters are optional - a null argument matches every entry
 	ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error)
+	// keyset (cursor) pagination - same filters as ListEntries but lists the entries with an id greater than after_id, the last
+	// id of the previous page
+	ListEntriesAfter(ctx context.Context, arg ListEntriesAfterParams) ([]Entry, error)
+	// the active sessions of a user - blocked, rotated and expired sessions can no longer renew access tokens and are left out
+	ListSessionsByUser(ctx context.Context, username string) ([]Session, error)
+	// lists the transfers going in to and out of the account - the time range and sign filters are optional, a null argument
+	// matches every transfer - incoming transfers are positive for the account and outgoing transfers are negative
 	ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error)
+	// keyset (cursor) pagination - same filters as ListTransfers but lists the transfers with an id greater than after_id, the
+	// last id of the previous page
+	ListTransfersAfter(ctx context.Context, arg ListTransfersAfterParams) ([]Transfer, error)
+	// no row is returned if the email of the user has changed since the verification email was sent
+	MarkEmailVerified(ctx context.Context, arg MarkEmailVerifiedParams) (User, error)
+	// revoking a key twice keeps the time it was first revoked
+	RevokeAPIKey(ctx context.Context, id uuid.UUID) (ApiKey, error)
+	// marks the session as rotated - no row is returned if it was already rotated, so only one of two concurrent refreshes
+	// with the same refresh token succeeds
+	RotateSession(ctx context.Context, id uuid.UUID) (Session, error)
 	UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error)
+	UpdateAccountFrozen(ctx context.Context, arg UpdateAccountFrozenParams) (Account, error)
+	UpdateAccountOverdraftLimit(ctx context.Context, arg UpdateAccountOverdraftLimitParams) (Account, error)
 	UpdateEntry(ctx context.Context, arg UpdateEntryParams) (Entry, error)
 	UpdateTransfer(ctx context.Context, arg UpdateTransferParams) (Transfer, error)
+	// every field is optional - a NULL argument keeps the current value
+	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	// replaces the hash of the password with a new hash of the same password, e.g. with a stronger algorithm - password_change_at
+	// is kept as the password didn't change - no row is returned if the password was changed since the old hash was read
+	UpdateUserPasswordHash(ctx context.Context, arg UpdateUserPasswordHashParams) (User, error)
+	// marks the code as used - no row is returned if the code is wrong, was already used, or has expired
+	UpdateVerifyEmail(ctx context.Context, arg UpdateVerifyEmailParams) (VerifyEmail, error)
+	// no row is returned if the code is wrong or was already used
+	UseMFARecoveryCode(ctx context.Context, arg UseMFARecoveryCodeParams) (MfaRecoveryCode, error)
+	// marks the token as used - no row is returned if the token is wrong, was already used, or has expired
+	UsePasswordReset(ctx context.Context, tokenHash string) (PasswordReset, error)
+	// no row is returned if a code of the same or a later time step was already used, so every code can only be used once
+	UseTOTPStep(ctx context.Context, arg UseTOTPStepParams) (UserMfa, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,103 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomRevokedToken(t *testing.T, expiresAt time.Time) uuid.UUID {
+	arg := CreateRevokedTokenParams{
+		ID:        uuid.New(),
+		ExpiresAt: expiresAt,
+	}
+
+	err := testQueries.CreateRevokedToken(context.Background(), arg)
+	require.NoError(t, err)
+
+	return arg.ID
+}
+
+func TestCreateRevokedToken(t *testing.T) {
+	id := createRandomRevokedToken(t, time.Now().Add(time.Minute))
+
+	revoked, err := testQueries.IsTokenRevoked(context.Background(), id)
+	require.NoError(t, err)
+	require.True(t, revoked)
+
+	// revoking a token twice is not an error
+	err = testQueries.CreateRevokedToken(context.Background(), CreateRevokedTokenParams{
+		ID:        id,
+		ExpiresAt: time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+
+	revoked, err = testQueries.IsTokenRevoked(context.Background(), uuid.New())
+	require.NoError(t, err)
+	require.False(t, revoked)
+}
+
+func TestDeleteExpiredRevokedTokens(t *testing.T) {
+	expiredID := createRandomRevokedToken(t, time.Now().Add(-time.Minute))
+	validID := createRandomRevokedToken(t, time.Now().Add(time.Minute))
+
+	deleted, err := testQueries.DeleteExpiredRevokedTokens(context.Background())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, deleted, int64(1))
+
+	revoked, err := testQueries.IsTokenRevoked(context.Background(), expiredID)
+	require.NoError(t, err)
+	require.False(t, revoked)
+
+	revoked, err = testQueries.IsTokenRevoked(context.Background(), validID)
+	require.NoError(t, err)
+	require.True(t, revoked)
+}
+
+func TestIsTokenDenied(t *testing.T) {
+	user := createRandomUser(t)
+	issuedAt := time.Now().Add(-time.Minute)
+
+	arg := IsTokenDeniedParams{
+		ID:       uuid.New(),
+		Username: user.Username,
+		IssuedAt: issuedAt,
+	}
+	denied, err := testQueries.IsTokenDenied(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, denied)
+
+	// a revoked token is denied
+	revokedArg := arg
+	revokedArg.ID = createRandomRevokedToken(t, time.Now().Add(time.Minute))
+	denied, err = testQueries.IsTokenDenied(context.Background(), revokedArg)
+	require.NoError(t, err)
+	require.True(t, denied)
+
+	// after a password change, the tokens issued before it are denied and the ones issued after aren't
+	_, err = testQueries.UpdateUser(context.Background(), UpdateUserParams{
+		Username:         user.Username,
+		PasswordChangeAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	require.NoError(t, err)
+
+	denied, err = testQueries.IsTokenDenied(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, denied)
+
+	arg.IssuedAt = time.Now().Add(time.Second)
+	denied, err = testQueries.IsTokenDenied(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, denied)
+
+	// the password change of another user doesn't matter
+	arg.Username = createRandomUser(t).Username
+	arg.IssuedAt = issuedAt
+	denied, err = testQueries.IsTokenDenied(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, denied)
+}
@@ -12,6 +12,79 @@ import (
 	"github.com/google/uuid"
 )
 
+const blockSession = `-- name: BlockSession :one
+UPDATE sessions
+SET is_blocked = true
+WHERE id = $1
+RETURNING id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at, family_id, previous_session_id, rotated_at
+`
+
+// a blocked session can no longer be used to renew access tokens
+func (q *Queries) BlockSession(ctx context.Context, id uuid.UUID) (Session, error) {
+	row := q.db.QueryRowContext(ctx, blockSession, id)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.RefreshToken,
+		&i.UserAgent,
+		&i.ClientIp,
+		&i.IsBlocked,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.FamilyID,
+		&i.PreviousSessionID,
+		&i.RotatedAt,
+	)
+	return i, err
+}
+
+const blockSessionFamily = `-- name: BlockSessionFamily :one
+WITH blocked AS (
+  UPDATE sessions
+  SET is_blocked = true
+  WHERE sessions.family_id = $1 AND sessions.is_blocked = false
+  RETURNING id, expires_at
+), revoked AS (
+  INSERT INTO revoked_tokens (id, expires_at)
+  SELECT blocked.id, blocked.expires_at FROM blocked
+  ON CONFLICT (id) DO NOTHING
+)
+SELECT count(*) FROM blocked
+`
+
+// blocks every session created from the same login and revokes their refresh tokens, used when a rotated refresh token is
+// presented again - returns the number of sessions blocked
+func (q *Queries) BlockSessionFamily(ctx context.Context, familyID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, blockSessionFamily, familyID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const blockUserSessions = `-- name: BlockUserSessions :one
+WITH blocked AS (
+  UPDATE sessions
+  SET is_blocked = true
+  WHERE sessions.username = $1 AND sessions.is_blocked = false
+  RETURNING id, expires_at
+), revoked AS (
+  INSERT INTO revoked_tokens (id, expires_at)
+  SELECT blocked.id, blocked.expires_at FROM blocked
+  ON CONFLICT (id) DO NOTHING
+)
+SELECT count(*) FROM blocked
+`
+
+// blocks every session of the user and revokes their refresh tokens, e.g. after a password change - returns the number of
+// sessions blocked
+func (q *Queries) BlockUserSessions(ctx context.Context, username string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, blockUserSessions, username)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createSession = `-- name: CreateSession :one
 INSERT INTO sessions (
   id,
@@ -20,21 +93,25 @@ INSERT INTO sessions (
   user_agent,
   client_ip,
   is_blocked,
-  expires_at
+  expires_at,
+  family_id,
+  previous_session_id
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
 )
-RETURNING id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at
+RETURNING id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at, family_id, previous_session_id, rotated_at
 `
 
 type CreateSessionParams struct {
-	ID           uuid.UUID `json:"id"`
-	Username     string    `json:"username"`
-	RefreshToken string    `json:"refresh_token"`
-	UserAgent    string    `json:"user_agent"`
-	ClientIp     string    `json:"client_ip"`
-	IsBlocked    bool      `json:"is_blocked"`
-	ExpiresAt    time.Time `json:"expires_at"`
+	ID                uuid.UUID     `json:"id"`
+	Username          string        `json:"username"`
+	RefreshToken      string        `json:"refresh_token"`
+	UserAgent         string        `json:"user_agent"`
+	ClientIp          string        `json:"client_ip"`
+	IsBlocked         bool          `json:"is_blocked"`
+	ExpiresAt         time.Time     `json:"expires_at"`
+	FamilyID          uuid.UUID     `json:"family_id"`
+	PreviousSessionID uuid.NullUUID `json:"previous_session_id"`
 }
 
 func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error) {
@@ -46,6 +123,8 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		arg.ClientIp,
 		arg.IsBlocked,
 		arg.ExpiresAt,
+		arg.FamilyID,
+		arg.PreviousSessionID,
 	)
 	var i Session
 	err := row.Scan(
@@ -57,12 +136,15 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		&i.IsBlocked,
 		&i.ExpiresAt,
 		&i.CreatedAt,
+		&i.FamilyID,
+		&i.PreviousSessionID,
+		&i.RotatedAt,
 	)
 	return i, err
 }
 
 const getSession = `-- name: GetSession :one
-SELECT id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at FROM sessions
+SELECT id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at, family_id, previous_session_id, rotated_at FROM sessions
 WHERE id = $1 LIMIT 1
 `
 
@@ -78,6 +160,79 @@ func (q *Queries) GetSession(ctx context.Context, id uuid.UUID) (Session, error)
 		&i.IsBlocked,
 		&i.ExpiresAt,
 		&i.CreatedAt,
+		&i.FamilyID,
+		&i.PreviousSessionID,
+		&i.RotatedAt,
+	)
+	return i, err
+}
+
+const listSessionsByUser = `-- name: ListSessionsByUser :many
+SELECT id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at, family_id, previous_session_id, rotated_at FROM sessions
+WHERE username = $1 AND is_blocked = false AND rotated_at IS NULL AND expires_at > now()
+ORDER BY created_at DESC
+`
+
+// the active sessions of a user - blocked, rotated and expired sessions can no longer renew access tokens and are left out
+func (q *Queries) ListSessionsByUser(ctx context.Context, username string) ([]Session, error) {
+	rows, err := q.db.QueryContext(ctx, listSessionsByUser, username)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Session{}
+	for rows.Next() {
+		var i Session
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.RefreshToken,
+			&i.UserAgent,
+			&i.ClientIp,
+			&i.IsBlocked,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.FamilyID,
+			&i.PreviousSessionID,
+			&i.RotatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const rotateSession = `-- name: RotateSession :one
+UPDATE sessions
+SET rotated_at = now()
+WHERE id = $1 AND rotated_at IS NULL
+RETURNING id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at, family_id, previous_session_id, rotated_at
+`
+
+// marks the session as rotated - no row is returned if it was already rotated, so only one of two concurrent refreshes
+// with the same refresh token succeeds
+func (q *Queries) RotateSession(ctx context.Context, id uuid.UUID) (Session, error) {
+	row := q.db.QueryRowContext(ctx, rotateSession, id)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.RefreshToken,
+		&i.UserAgent,
+		&i.ClientIp,
+		&i.IsBlocked,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.FamilyID,
+		&i.PreviousSessionID,
+		&i.RotatedAt,
 	)
 	return i, err
 }
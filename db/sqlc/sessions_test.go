@@ -0,0 +1,138 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"SimpleBankProject/db/util"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomSession(t *testing.T, user User) Session {
+	id := uuid.New()
+	arg := CreateSessionParams{
+		ID:           id,
+		FamilyID:     id,
+		Username:     user.Username,
+		RefreshToken: util.RandomString(32),
+		UserAgent:    "test-agent",
+		ClientIp:     "127.0.0.1",
+		IsBlocked:    false,
+		ExpiresAt:    time.Now().Add(time.Hour),
+	}
+
+	session, err := testQueries.CreateSession(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, session)
+
+	require.Equal(t, arg.ID, session.ID)
+	require.Equal(t, arg.Username, session.Username)
+	require.Equal(t, arg.RefreshToken, session.RefreshToken)
+	require.False(t, session.IsBlocked)
+	require.WithinDuration(t, arg.ExpiresAt, session.ExpiresAt, time.Second)
+	require.NotZero(t, session.CreatedAt)
+	require.Equal(t, arg.FamilyID, session.FamilyID)
+	require.False(t, session.PreviousSessionID.Valid)
+	require.False(t, session.RotatedAt.Valid)
+
+	return session
+}
+
+func TestBlockSession(t *testing.T) {
+	session1 := createRandomSession(t, createRandomUser(t))
+
+	session2, err := testQueries.BlockSession(context.Background(), session1.ID)
+	require.NoError(t, err)
+	require.Equal(t, session1.ID, session2.ID)
+	require.Equal(t, session1.RefreshToken, session2.RefreshToken)
+	require.True(t, session2.IsBlocked)
+
+	// the block is stored
+	session3, err := testQueries.GetSession(context.Background(), session1.ID)
+	require.NoError(t, err)
+	require.True(t, session3.IsBlocked)
+}
+
+func TestListSessionsByUser(t *testing.T) {
+	user := createRandomUser(t)
+	session1 := createRandomSession(t, user)
+	session2 := createRandomSession(t, user)
+	blocked := createRandomSession(t, user)
+	rotated := createRandomSession(t, user)
+
+	_, err := testQueries.BlockSession(context.Background(), blocked.ID)
+	require.NoError(t, err)
+	_, err = testQueries.RotateSession(context.Background(), rotated.ID)
+	require.NoError(t, err)
+
+	// the sessions of other users are never listed
+	createRandomSession(t, createRandomUser(t))
+
+	// only the active sessions are listed, newest first
+	sessions, err := testQueries.ListSessionsByUser(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	require.Equal(t, session2.ID, sessions[0].ID)
+	require.Equal(t, session1.ID, sessions[1].ID)
+}
+
+func TestRotateSession(t *testing.T) {
+	session1 := createRandomSession(t, createRandomUser(t))
+
+	session2, err := testQueries.RotateSession(context.Background(), session1.ID)
+	require.NoError(t, err)
+	require.Equal(t, session1.ID, session2.ID)
+	require.True(t, session2.RotatedAt.Valid)
+	require.WithinDuration(t, time.Now(), session2.RotatedAt.Time, time.Second)
+
+	// a session can only be rotated once
+	_, err = testQueries.RotateSession(context.Background(), session1.ID)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+}
+
+func TestBlockSessionFamily(t *testing.T) {
+	user := createRandomUser(t)
+	session1 := createRandomSession(t, user)
+
+	// a second session in the same family, the way RotateSessionTX creates it
+	session2, err := testQueries.CreateSession(context.Background(), CreateSessionParams{
+		ID:                uuid.New(),
+		Username:          user.Username,
+		RefreshToken:      util.RandomString(32),
+		UserAgent:         "test-agent",
+		ClientIp:          "127.0.0.1",
+		ExpiresAt:         session1.ExpiresAt,
+		FamilyID:          session1.FamilyID,
+		PreviousSessionID: uuid.NullUUID{UUID: session1.ID, Valid: true},
+	})
+	require.NoError(t, err)
+	// a session of another login of the same user
+	other := createRandomSession(t, user)
+
+	blocked, err := testQueries.BlockSessionFamily(context.Background(), session1.FamilyID)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), blocked)
+
+	// the refresh tokens of the blocked sessions are revoked as well - the session ID is the ID of its refresh token
+	for _, id := range []uuid.UUID{session1.ID, session2.ID} {
+		session, err := testQueries.GetSession(context.Background(), id)
+		require.NoError(t, err)
+		require.True(t, session.IsBlocked)
+
+		revoked, err := testQueries.IsTokenRevoked(context.Background(), id)
+		require.NoError(t, err)
+		require.True(t, revoked)
+	}
+
+	session, err := testQueries.GetSession(context.Background(), other.ID)
+	require.NoError(t, err)
+	require.False(t, session.IsBlocked)
+
+	revoked, err := testQueries.IsTokenRevoked(context.Background(), other.ID)
+	require.NoError(t, err)
+	require.False(t, revoked)
+}
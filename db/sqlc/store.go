@@ -3,7 +3,65 @@ package db
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"math/rand"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ErrInsufficientFunds is returned by TransferTX when the from account doesn't have enough money to cover the transfer, i.e.
+// the balance would drop below the negative of the account's overdraft limit
+// the api and gapi packages check for it using errors.Is to send the client a clear response
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// ErrIdempotencyKeyReused is returned by TransferTX when the idempotency key was already used for a transfer with a different
+// from account, to account, or amount - a key may only ever be replayed with the exact same payload
+var ErrIdempotencyKeyReused = errors.New("idempotency key was already used for a different transfer")
+
+// ErrTransferAlreadyReversed is returned by ReverseTransferTX when the transfer already has a reversal
+var ErrTransferAlreadyReversed = errors.New("transfer has already been reversed")
+
+// ErrCannotReverseReversal is returned by ReverseTransferTX when the transfer is itself a reversal - the money can simply be
+// transferred again instead
+var ErrCannotReverseReversal = errors.New("a reversal cannot be reversed")
+
+// ErrAccountFrozen is returned by TransferTX, ReverseTransferTX, DepositTX and WithdrawTX when an account was frozen by an
+// admin - no money can move in to or out of a frozen account
+var ErrAccountFrozen = errors.New("account is frozen")
+
+// ErrDuplicateExternalReference is returned by DepositTX and WithdrawTX when an entry of the same type was already booked
+// with the external reference - the same external payment must never change a balance twice
+var ErrDuplicateExternalReference = errors.New("external reference has already been booked")
+
+// ErrSessionAlreadyRotated is returned by RotateSessionTX when the refresh token of the session was already replaced - the
+// token has been used twice, so callers should treat it as stolen and block the whole session family
+var ErrSessionAlreadyRotated = errors.New("session has already been rotated")
+
+// the values of entries.type - entries created by transfers (and reversals) use the column's default
+const (
+	EntryTypeTransfer   = "transfer"
+	EntryTypeDeposit    = "deposit"
+	EntryTypeWithdrawal = "withdrawal"
+)
+
+// the values of the sign filter of ListEntries and ListTransfers - for transfers, money coming in to the account is positive
+// and money leaving it is negative
+const (
+	AmountSignPositive = "positive"
+	AmountSignNegative = "negative"
+)
+
+const (
+	// maxTxAttempts is how many times execTx runs a transaction before returning a retryable error to the caller
+	maxTxAttempts = 5
+	// txRetryBaseDelay is the backoff before the first retry and maxTxRetryDelay is the most execTx ever waits
+	txRetryBaseDelay = 10 * time.Millisecond
+	maxTxRetryDelay  = 200 * time.Millisecond
 )
 
 // Store provides all functions to execute db queries individually and as transactions
@@ -12,6 +70,17 @@ type Store interface {
 	// it will implement all *Queries methods plus the TransferTX method defined below
 	Querier // interface in querier.go which contains all *Queries methods
 	TransferTX(ctx context.Context, arg TransferTxParams) (TransferTxResult, error)
+	ReverseTransferTX(ctx context.Context, arg ReverseTransferTxParams) (ReverseTransferTxResult, error)
+	DepositTX(ctx context.Context, arg DepositTxParams) (DepositTxResult, error)
+	WithdrawTX(ctx context.Context, arg WithdrawTxParams) (WithdrawTxResult, error)
+	RotateSessionTX(ctx context.Context, arg RotateSessionTxParams) (RotateSessionTxResult, error)
+	CreateUserTX(ctx context.Context, arg CreateUserTxParams) (CreateUserTxResult, error)
+	UpdateUserTX(ctx context.Context, arg UpdateUserTxParams) (UpdateUserTxResult, error)
+	VerifyEmailTX(ctx context.Context, arg VerifyEmailTxParams) (VerifyEmailTxResult, error)
+	CreatePasswordResetTX(ctx context.Context, arg CreatePasswordResetTxParams) (CreatePasswordResetTxResult, error)
+	ResetPasswordTX(ctx context.Context, arg ResetPasswordTxParams) (ResetPasswordTxResult, error)
+	EnableMFATX(ctx context.Context, arg EnableMFATxParams) (EnableMFATxResult, error)
+	DisableMFATX(ctx context.Context, username string) error
 }
 
 // SQLStore provides all functions to execute SQL queries individually and as transactions
@@ -30,10 +99,37 @@ func NewStore(db *sql.DB) Store {
 	}
 }
 
-// execTx - executes a generic transaction - takes in a context and a callback function - starts a new db tx, creates a queries object with that tx, calls the callback function with the created queries, and commits or rolls back the changes
-func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) error { // unexported function as it starts with a lowercase letter - will create exported functions for each transaction
+// execTx - executes a generic transaction - takes in a context, the transaction options, and a callback function - it runs
+// the transaction with runTx and, if it failed with a retryable error (deadlock or serialization failure), runs the whole
+// transaction again with a jittered backoff - fn must therefore be safe to call more than once
+func (store *SQLStore) execTx(ctx context.Context, opts *sql.TxOptions, fn func(*Queries) error) error { // unexported function as it starts with a lowercase letter - will create exported functions for each transaction
+	for attempt := 1; ; attempt++ {
+		err := store.runTx(ctx, opts, fn)
+		if err == nil || !isRetryableTxError(err) || attempt == maxTxAttempts {
+			// only log when a retry actually happened, otherwise every transaction would be logged
+			if attempt > 1 {
+				log.Printf("transaction finished after %d attempts: %v", attempt, err)
+			}
+			return err
+		}
+
+		delay := txRetryDelay(attempt)
+		log.Printf("transaction attempt %d of %d failed, retrying in %s: %v", attempt, maxTxAttempts, delay, err)
+
+		// stop waiting if the caller gives up (e.g. the client disconnected) - the last error is more useful than ctx.Err()
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// runTx - starts a new db tx, creates a queries object with that tx, calls the callback function with the created queries,
+// and commits or rolls back the changes
+func (store *SQLStore) runTx(ctx context.Context, opts *sql.TxOptions, fn func(*Queries) error) error {
 	// start transaction - BeginTx returns a transaction object or an error
-	tx, err := store.db.BeginTx(ctx, nil) // nil - use the default isolation level
+	tx, err := store.db.BeginTx(ctx, opts) // nil opts - use the default isolation level
 
 	// means something went wrong
 	if err != nil {
@@ -54,14 +150,44 @@ func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) erro
 	}
 
 	// if all operations in the transaction are successful
+	// a serializable transaction can also fail here, which is why the commit error is checked for retries as well
 	return tx.Commit() // tx.Commit returns an error - nil or otherwise
 }
 
+// isRetryableTxError reports whether the transaction failed only because it ran concurrently with another one - postgres
+// rolls back one of the transactions on a deadlock (40P01) or serialization failure (40001), and running it again is
+// expected to succeed
+func isRetryableTxError(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	switch pqErr.Code.Name() {
+	case "serialization_failure", "deadlock_detected":
+		return true
+	}
+	return false
+}
+
+// txRetryDelay returns how long to wait before the next attempt - the backoff doubles with every attempt (capped at
+// maxTxRetryDelay) and a random jitter is used so concurrent transactions that failed together don't retry together
+func txRetryDelay(attempt int) time.Duration {
+	backoff := txRetryBaseDelay << (attempt - 1)
+	if backoff > maxTxRetryDelay {
+		backoff = maxTxRetryDelay
+	}
+	// wait between half and all of the backoff
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
 // TransferTxParams contains the input parameters for the transfer transaction
 type TransferTxParams struct {
 	FromAccountID int64 `json:"from_account_id"` // notice these are not single quotes, they are accents which are below the tilde
 	ToAccountID   int64 `json:"to_account_id"`
 	Amount        int64 `json:"amount"`
+	// optional - when set, a retry with the same key returns the result of the original transfer instead of moving the
+	// money a second time
+	IdempotencyKey string `json:"idempotency_key"`
 }
 
 // TransferTxResult contains the result of the transfer transaction
@@ -82,14 +208,35 @@ func (store *SQLStore) TransferTX(ctx context.Context, arg TransferTxParams) (Tr
 	var result TransferTxResult // empty result at this point
 
 	// creates and runs a new db transaction
-	err := store.execTx(ctx, func(q *Queries) error {
+	// nil options use the default isolation level - execTx still retries if the transaction deadlocks
+	err := store.execTx(ctx, nil, func(q *Queries) error {
 		// we can use the Queries CRUD functions
 
+		// the closure runs again if execTx retries the transaction, so start from an empty result each time
+		result = TransferTxResult{}
+
 		var err error // declared for CreateTransfer command
 
 		// grab the transaction name from the context ctx
 		txName := ctx.Value(txKey)
 
+		// if the key has been used before, the transfer already happened - return its result without touching any balances
+		if arg.IdempotencyKey != "" {
+			var found bool
+			result, found, err = replayTransfer(ctx, q, arg)
+			if err != nil || found {
+				return err
+			}
+		}
+
+		// the handlers check for frozen accounts before calling TransferTX, but an admin may freeze an account in the meantime -
+		// the accounts are locked (in the same order as the balance updates below) so they can't be frozen between the check
+		// and the update
+		err = lockUnfrozenAccounts(ctx, q, arg.FromAccountID, arg.ToAccountID)
+		if err != nil {
+			return err
+		}
+
 		// this Queries object is created from one database transaction - the methods we call will run within that one transaction
 		// result (TransferTxResult object) has its Transfer proptery set to the CreateTransfer record which q (a *Queries object) calls
 		// the CreateTransferParams struct is initialized using arg (TransferTxParams object)
@@ -215,12 +362,71 @@ func (store *SQLStore) TransferTX(ctx context.Context, arg TransferTxParams) (Tr
 			result.ToAccount, result.FromAccount, err = addMoney(ctx, q, arg.ToAccountID, arg.Amount, arg.FromAccountID, -arg.Amount)
 		}
 
+		// returning the error (e.g. ErrInsufficientFunds) rolls back the transfer record and entries created above
+		if err != nil {
+			return err
+		}
+
+		// the key is stored in the same transaction as the transfer so either both are saved or neither is
+		if arg.IdempotencyKey != "" {
+			return saveIdempotencyKey(ctx, q, arg, result)
+		}
 		return nil
 	})
 
+	// a concurrent request with the same key saved it first, so our transaction was rolled back - the money only moved once
+	// and we return the result of the request that won
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" && pqErr.Constraint == "idempotency_keys_pkey" {
+		result, _, err = replayTransfer(ctx, store.Queries, arg)
+	}
+
 	return result, err // TransferTxResult and error
 }
 
+// replayTransfer looks up the transfer previously made with arg.IdempotencyKey - found is false if the key is unused
+// the keys are scoped to the from account, so a key another user happened to pick for their own transfers isn't found
+func replayTransfer(ctx context.Context, q *Queries, arg TransferTxParams) (result TransferTxResult, found bool, err error) {
+	idempotencyKey, err := q.GetIdempotencyKey(ctx, GetIdempotencyKeyParams{
+		FromAccountID: arg.FromAccountID,
+		Key:           arg.IdempotencyKey,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return result, false, nil
+		}
+		return result, false, err
+	}
+
+	// the same key with a different payload is most likely a client bug - replaying it would hide that the new transfer
+	// never happened
+	if idempotencyKey.FromAccountID != arg.FromAccountID ||
+		idempotencyKey.ToAccountID != arg.ToAccountID ||
+		idempotencyKey.Amount != arg.Amount {
+		return result, true, ErrIdempotencyKeyReused
+	}
+
+	err = json.Unmarshal(idempotencyKey.Result, &result)
+	return result, true, err
+}
+
+// saveIdempotencyKey stores the key along with the payload and result of the transfer so it can be replayed later
+func saveIdempotencyKey(ctx context.Context, q *Queries, arg TransferTxParams, result TransferTxResult) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.CreateIdempotencyKey(ctx, CreateIdempotencyKeyParams{
+		Key:           arg.IdempotencyKey,
+		FromAccountID: arg.FromAccountID,
+		ToAccountID:   arg.ToAccountID,
+		Amount:        arg.Amount,
+		TransferID:    result.Transfer.ID,
+		Result:        data,
+	})
+	return err
+}
+
 // addMoney - will be used to add money to two accounts - this is to refactor the code a bit as we have duplicate code in the if
 // else statement
 func addMoney(
@@ -231,20 +437,612 @@ func addMoney(
 	accountID2 int64,
 	amount2 int64,
 ) (account1 Account, account2 Account, err error) {
-	// call AddAccountBalance to add amount1 to account1
-	account1, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
-		Amount: amount1,
-		ID:     accountID1,
-	})
+	// call updateBalance to add amount1 to account1
+	account1, err = updateBalance(ctx, q, accountID1, amount1)
 	if err != nil {
 		// since we are using named returns we needn't specify the return variables
 		return // this is the same as return account1, account2, err
 	}
 
-	account2, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
-		ID:     accountID2,
-		Amount: amount2,
-	})
+	account2, err = updateBalance(ctx, q, accountID2, amount2)
 
 	return //if err != nil, it will be returned here anyway and doesn't need to be handled specifically
 }
+
+// updateBalance adds amount to the balance of the account - when money is leaving the account (negative amount), the
+// balance is only updated if it stays at or above -overdraft_limit, otherwise ErrInsufficientFunds is returned
+func updateBalance(ctx context.Context, q *Queries, accountID int64, amount int64) (Account, error) {
+	if amount >= 0 {
+		return q.AddAccountBalance(ctx, AddAccountBalanceParams{
+			ID:     accountID,
+			Amount: amount,
+		})
+	}
+
+	// the check and the update happen in a single statement which locks the row, so concurrent transfers cannot both
+	// spend the same money
+	account, err := q.AddAccountBalanceIfSufficient(ctx, AddAccountBalanceIfSufficientParams{
+		ID:     accountID,
+		Amount: amount,
+	})
+	// the transfer or entry record created earlier in the transaction references the account (foreign key), so the account
+	// exists and no rows means the balance (plus the overdraft limit) was too low
+	if err == sql.ErrNoRows {
+		return account, ErrInsufficientFunds
+	}
+	return account, err
+}
+
+// ReverseTransferTxParams contains the input parameters for the reverse transfer transaction
+type ReverseTransferTxParams struct {
+	TransferID int64 `json:"transfer_id"` // the transfer to reverse
+}
+
+// ReverseTransferTxResult contains the result of the reverse transfer transaction
+type ReverseTransferTxResult struct {
+	OriginalTransfer Transfer `json:"original_transfer"` // the transfer that was reversed
+	// the compensating transfer, its entries, and the accounts after the balances have been updated - the money moves from
+	// the original to account back to the original from account
+	Reversal TransferTxResult `json:"reversal"`
+}
+
+// ReverseTransferTX - undoes a transfer by creating a compensating transfer (linked to the original through reversal_of),
+// adding its entries, and updating each account's balance all within a single db tx - the original transfer and its entries
+// are never modified so the history stays intact
+func (store *SQLStore) ReverseTransferTX(ctx context.Context, arg ReverseTransferTxParams) (ReverseTransferTxResult, error) {
+	var result ReverseTransferTxResult
+
+	err := store.execTx(ctx, nil, func(q *Queries) error {
+		// the closure runs again if execTx retries the transaction
+		result = ReverseTransferTxResult{}
+
+		var err error
+
+		// locking the original transfer makes concurrent reversals of the same transfer wait for each other, so only the
+		// first one passes the checks below - the unique constraint on reversal_of backs this up
+		// sql.ErrNoRows is returned as is so callers can tell that the transfer doesn't exist
+		result.OriginalTransfer, err = q.GetTransferForUpdate(ctx, arg.TransferID)
+		if err != nil {
+			return err
+		}
+		original := result.OriginalTransfer
+
+		if original.ReversalOf.Valid {
+			return ErrCannotReverseReversal
+		}
+
+		_, err = q.GetTransferReversal(ctx, original.ID)
+		if err == nil {
+			return ErrTransferAlreadyReversed
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		// an admin may have frozen one of the accounts since the original transfer - the accounts are locked (in the same
+		// order as the balance updates below) so they can't be frozen between the check and the update
+		err = lockUnfrozenAccounts(ctx, q, original.FromAccountID, original.ToAccountID)
+		if err != nil {
+			return err
+		}
+
+		// the reversal moves the same amount in the opposite direction
+		result.Reversal.Transfer, err = q.CreateTransferReversal(ctx, CreateTransferReversalParams{
+			FromAccountID: original.ToAccountID,
+			ToAccountID:   original.FromAccountID,
+			Amount:        original.Amount,
+			ReversalOf:    sql.NullInt64{Int64: original.ID, Valid: true},
+		})
+		if err != nil {
+			if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+				return ErrTransferAlreadyReversed
+			}
+			return err
+		}
+
+		result.Reversal.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID: original.ToAccountID,
+			Amount:    -original.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Reversal.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID: original.FromAccountID,
+			Amount:    original.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		// same ordering as TransferTX to avoid deadlocks - the account with the smaller ID is always updated first
+		// the original to account must still hold the money, otherwise ErrInsufficientFunds rolls the reversal back
+		if original.ToAccountID < original.FromAccountID {
+			result.Reversal.FromAccount, result.Reversal.ToAccount, err = addMoney(ctx, q, original.ToAccountID, -original.Amount, original.FromAccountID, original.Amount)
+		} else {
+			result.Reversal.ToAccount, result.Reversal.FromAccount, err = addMoney(ctx, q, original.FromAccountID, original.Amount, original.ToAccountID, -original.Amount)
+		}
+		return err
+	})
+
+	return result, err
+}
+
+// lockUnfrozenAccounts locks both accounts, the one with the smaller ID first, and returns ErrAccountFrozen if either of them
+// is frozen
+func lockUnfrozenAccounts(ctx context.Context, q *Queries, accountID1 int64, accountID2 int64) error {
+	if accountID2 < accountID1 {
+		accountID1, accountID2 = accountID2, accountID1
+	}
+
+	for _, accountID := range []int64{accountID1, accountID2} {
+		if err := lockUnfrozenAccount(ctx, q, accountID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lockUnfrozenAccount locks the account and returns ErrAccountFrozen if it is frozen
+func lockUnfrozenAccount(ctx context.Context, q *Queries, accountID int64) error {
+	account, err := q.GetAccountForUpdate(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	if account.IsFrozen {
+		return fmt.Errorf("account [%d]: %w", accountID, ErrAccountFrozen)
+	}
+	return nil
+}
+
+// DepositTxParams contains the input parameters for the deposit transaction
+type DepositTxParams struct {
+	AccountID         int64  `json:"account_id"`
+	Amount            int64  `json:"amount"`             // must be positive
+	ExternalReference string `json:"external_reference"` // identifies where the money came from, e.g. a wire transfer id
+}
+
+// DepositTxResult contains the result of the deposit transaction
+type DepositTxResult struct {
+	Account Account `json:"account"` // account record after the balance has been updated
+	Entry   Entry   `json:"entry"`   // entry record showing money coming in to the account
+}
+
+// DepositTX - adds money from outside the bank to an account - it creates a deposit entry and updates the account's balance
+// within a single db tx
+func (store *SQLStore) DepositTX(ctx context.Context, arg DepositTxParams) (DepositTxResult, error) {
+	var result DepositTxResult
+
+	err := store.execTx(ctx, nil, func(q *Queries) error {
+		var err error
+		result.Account, result.Entry, err = addExternalEntry(ctx, q, arg.AccountID, arg.Amount, EntryTypeDeposit, arg.ExternalReference)
+		return err
+	})
+
+	return result, err
+}
+
+// WithdrawTxParams contains the input parameters for the withdraw transaction
+type WithdrawTxParams struct {
+	AccountID         int64  `json:"account_id"`
+	Amount            int64  `json:"amount"`             // must be positive - the amount leaving the account
+	ExternalReference string `json:"external_reference"` // identifies where the money went, e.g. a card payment id
+}
+
+// WithdrawTxResult contains the result of the withdraw transaction
+type WithdrawTxResult struct {
+	Account Account `json:"account"` // account record after the balance has been updated
+	Entry   Entry   `json:"entry"`   // entry record showing money leaving the account
+}
+
+// WithdrawTX - moves money out of the bank - it creates a withdrawal entry and updates the account's balance within a single
+// db tx - like a transfer, the balance cannot go below the account's overdraft limit (ErrInsufficientFunds)
+func (store *SQLStore) WithdrawTX(ctx context.Context, arg WithdrawTxParams) (WithdrawTxResult, error) {
+	var result WithdrawTxResult
+
+	err := store.execTx(ctx, nil, func(q *Queries) error {
+		var err error
+		result.Account, result.Entry, err = addExternalEntry(ctx, q, arg.AccountID, -arg.Amount, EntryTypeWithdrawal, arg.ExternalReference)
+		return err
+	})
+
+	return result, err
+}
+
+// addExternalEntry books money entering (positive amount) or leaving (negative amount) the bank - it records the entry and
+// then updates the balance with updateBalance, so a withdrawal is checked against the overdraft limit
+// a frozen account returns ErrAccountFrozen - the account stays locked until the transaction ends, so it can't be frozen
+// between the check and the update
+func addExternalEntry(
+	ctx context.Context,
+	q *Queries,
+	accountID int64,
+	amount int64,
+	entryType string,
+	externalReference string,
+) (account Account, entry Entry, err error) {
+	err = lockUnfrozenAccount(ctx, q, accountID)
+	if err != nil {
+		return
+	}
+
+	entry, err = q.CreateExternalEntry(ctx, CreateExternalEntryParams{
+		AccountID:         accountID,
+		Amount:            amount,
+		Type:              entryType,
+		ExternalReference: externalReference,
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+			err = ErrDuplicateExternalReference
+		}
+		return
+	}
+
+	account, err = updateBalance(ctx, q, accountID, amount)
+	return
+}
+
+// RotateSessionTxParams contains the input parameters for the rotate session transaction
+type RotateSessionTxParams struct {
+	SessionID uuid.UUID `json:"session_id"` // the session of the refresh token being replaced
+	// the session of the new refresh token - FamilyID and PreviousSessionID are taken from the rotated session
+	NewSession CreateSessionParams `json:"new_session"`
+}
+
+// RotateSessionTxResult contains the result of the rotate session transaction
+type RotateSessionTxResult struct {
+	RotatedSession Session `json:"rotated_session"` // the old session, its refresh token can no longer be used
+	NewSession     Session `json:"new_session"`
+}
+
+// RotateSessionTX - replaces the refresh token of a session by marking the session as rotated and creating the session of the
+// new refresh token in the same family, all within a single db tx - a session which was already rotated returns
+// ErrSessionAlreadyRotated and nothing is changed
+func (store *SQLStore) RotateSessionTX(ctx context.Context, arg RotateSessionTxParams) (RotateSessionTxResult, error) {
+	var result RotateSessionTxResult
+
+	err := store.execTx(ctx, nil, func(q *Queries) error {
+		// the closure runs again if execTx retries the transaction
+		result = RotateSessionTxResult{}
+
+		var err error
+
+		// the update only matches a session which hasn't been rotated yet, so when the same refresh token is used by two
+		// requests at once, the second one waits for the first and then finds nothing to rotate
+		result.RotatedSession, err = q.RotateSession(ctx, arg.SessionID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return ErrSessionAlreadyRotated
+			}
+			return err
+		}
+
+		newSession := arg.NewSession
+		newSession.FamilyID = result.RotatedSession.FamilyID
+		newSession.PreviousSessionID = uuid.NullUUID{UUID: result.RotatedSession.ID, Valid: true}
+
+		result.NewSession, err = q.CreateSession(ctx, newSession)
+		return err
+	})
+
+	return result, err
+}
+
+// CreateUserTxParams contains the input parameters for the create user transaction
+type CreateUserTxParams struct {
+	CreateUserParams
+	// util.HashSecretToken of the code the user must send back to verify their email - only the hash is stored
+	SecretCodeHash string `json:"secret_code_hash"`
+}
+
+// CreateUserTxResult contains the result of the create user transaction
+type CreateUserTxResult struct {
+	User        User        `json:"user"`
+	VerifyEmail VerifyEmail `json:"verify_email"`
+}
+
+// CreateUserTX - creates the user and the code to verify their email within a single db tx
+// the caller sends the code once the transaction has committed - execTx may run the transaction more than once, and an email
+// can't be rolled back
+func (store *SQLStore) CreateUserTX(ctx context.Context, arg CreateUserTxParams) (CreateUserTxResult, error) {
+	var result CreateUserTxResult
+
+	err := store.execTx(ctx, nil, func(q *Queries) error {
+		// the closure runs again if execTx retries the transaction
+		result = CreateUserTxResult{}
+
+		var err error
+
+		result.User, err = q.CreateUser(ctx, arg.CreateUserParams)
+		if err != nil {
+			return err
+		}
+
+		result.VerifyEmail, err = q.CreateVerifyEmail(ctx, CreateVerifyEmailParams{
+			Username:       result.User.Username,
+			Email:          result.User.Email,
+			SecretCodeHash: arg.SecretCodeHash,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// UpdateUserTxParams contains the input parameters for the update user transaction
+type UpdateUserTxParams struct {
+	UpdateUserParams
+	// util.HashSecretToken of the code sent to the new email - only used when the email changes, the new email has to be
+	// verified again
+	SecretCodeHash string `json:"secret_code_hash"`
+}
+
+// UpdateUserTxResult contains the result of the update user transaction
+type UpdateUserTxResult struct {
+	User            User        `json:"user"`             // the user after the update
+	BlockedSessions int64       `json:"blocked_sessions"` // how many sessions were blocked because the password changed
+	VerifyEmail     VerifyEmail `json:"verify_email"`     // the code created for the new email - empty if the email didn't change
+}
+
+// UpdateUserTX - updates the fields of the user which are set in arg within a single db tx
+//   - if the password changes, every session of the user is blocked, so the refresh tokens issued for the old password can't
+//     be used any more - the access tokens issued before are denied by TokenDenylist, which compares their issued at time
+//     with password_change_at, so arg should set PasswordChangeAt along with HashedPassword
+//   - if the email changes, the user is no longer verified and a code for the new email is created - the caller sends it once
+//     the transaction has committed, result.VerifyEmail is empty if the email didn't change
+//
+// sql.ErrNoRows is returned as is if the user doesn't exist
+func (store *SQLStore) UpdateUserTX(ctx context.Context, arg UpdateUserTxParams) (UpdateUserTxResult, error) {
+	var result UpdateUserTxResult
+
+	err := store.execTx(ctx, nil, func(q *Queries) error {
+		// the closure runs again if execTx retries the transaction
+		result = UpdateUserTxResult{}
+
+		params := arg.UpdateUserParams
+		emailChanged := false
+		if params.Email.Valid {
+			user, err := q.GetUser(ctx, params.Username)
+			if err != nil {
+				return err
+			}
+			emailChanged = user.Email != params.Email.String
+			if emailChanged {
+				params.IsEmailVerified = sql.NullBool{Bool: false, Valid: true}
+			}
+		}
+
+		var err error
+
+		result.User, err = q.UpdateUser(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		if params.HashedPassword.Valid {
+			result.BlockedSessions, err = q.BlockUserSessions(ctx, params.Username)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !emailChanged {
+			return nil
+		}
+
+		result.VerifyEmail, err = q.CreateVerifyEmail(ctx, CreateVerifyEmailParams{
+			Username:       result.User.Username,
+			Email:          result.User.Email,
+			SecretCodeHash: arg.SecretCodeHash,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// VerifyEmailTxParams contains the input parameters for the verify email transaction
+type VerifyEmailTxParams struct {
+	EmailID        int64  `json:"email_id"`
+	SecretCodeHash string `json:"secret_code_hash"` // util.HashSecretToken of the code from the link
+}
+
+// VerifyEmailTxResult contains the result of the verify email transaction
+type VerifyEmailTxResult struct {
+	User        User        `json:"user"`
+	VerifyEmail VerifyEmail `json:"verify_email"`
+}
+
+// VerifyEmailTX - uses up the verification code and marks the email of the user as verified within a single db tx
+// sql.ErrNoRows is returned as is if the code is wrong, was already used or has expired, or if the email of the user changed
+// after the code was sent
+func (store *SQLStore) VerifyEmailTX(ctx context.Context, arg VerifyEmailTxParams) (VerifyEmailTxResult, error) {
+	var result VerifyEmailTxResult
+
+	err := store.execTx(ctx, nil, func(q *Queries) error {
+		// the closure runs again if execTx retries the transaction
+		result = VerifyEmailTxResult{}
+
+		var err error
+
+		result.VerifyEmail, err = q.UpdateVerifyEmail(ctx, UpdateVerifyEmailParams{
+			ID:             arg.EmailID,
+			SecretCodeHash: arg.SecretCodeHash,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.User, err = q.MarkEmailVerified(ctx, MarkEmailVerifiedParams{
+			Username: result.VerifyEmail.Username,
+			Email:    result.VerifyEmail.Email,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// CreatePasswordResetTxParams contains the input parameters for the create password reset transaction
+type CreatePasswordResetTxParams struct {
+	Email     string    `json:"email"`
+	TokenHash string    `json:"token_hash"` // util.HashSecretToken of the token sent to the user
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// CreatePasswordResetTxResult contains the result of the create password reset transaction
+type CreatePasswordResetTxResult struct {
+	User          User          `json:"user"`
+	PasswordReset PasswordReset `json:"password_reset"`
+}
+
+// CreatePasswordResetTX - creates a password reset for the user with the email within a single db tx - the caller sends the
+// token to result.User once the transaction has committed
+// sql.ErrNoRows is returned as is if no user has the email
+func (store *SQLStore) CreatePasswordResetTX(ctx context.Context, arg CreatePasswordResetTxParams) (CreatePasswordResetTxResult, error) {
+	var result CreatePasswordResetTxResult
+
+	err := store.execTx(ctx, nil, func(q *Queries) error {
+		// the closure runs again if execTx retries the transaction
+		result = CreatePasswordResetTxResult{}
+
+		var err error
+
+		result.User, err = q.GetUserByEmail(ctx, arg.Email)
+		if err != nil {
+			return err
+		}
+
+		result.PasswordReset, err = q.CreatePasswordReset(ctx, CreatePasswordResetParams{
+			Username:  result.User.Username,
+			TokenHash: arg.TokenHash,
+			ExpiresAt: arg.ExpiresAt,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// ResetPasswordTxParams contains the input parameters for the reset password transaction
+type ResetPasswordTxParams struct {
+	TokenHash      string `json:"token_hash"` // util.HashSecretToken of the token the user sent back
+	HashedPassword string `json:"hashed_password"`
+}
+
+// ResetPasswordTxResult contains the result of the reset password transaction
+type ResetPasswordTxResult struct {
+	User            User          `json:"user"` // the user with the new password
+	PasswordReset   PasswordReset `json:"password_reset"`
+	BlockedSessions int64         `json:"blocked_sessions"` // how many sessions were blocked because the password changed
+}
+
+// ResetPasswordTX - uses up the password reset token, sets the new password and blocks every session of the user within a
+// single db tx - whoever knew the old password can't keep using a session they created with it, and the access tokens
+// issued before the reset are denied by TokenDenylist as password_change_at is updated
+// sql.ErrNoRows is returned as is if the token is wrong, was already used or has expired
+func (store *SQLStore) ResetPasswordTX(ctx context.Context, arg ResetPasswordTxParams) (ResetPasswordTxResult, error) {
+	var result ResetPasswordTxResult
+
+	err := store.execTx(ctx, nil, func(q *Queries) error {
+		// the closure runs again if execTx retries the transaction
+		result = ResetPasswordTxResult{}
+
+		var err error
+
+		result.PasswordReset, err = q.UsePasswordReset(ctx, arg.TokenHash)
+		if err != nil {
+			return err
+		}
+
+		result.User, err = q.UpdateUser(ctx, UpdateUserParams{
+			Username:         result.PasswordReset.Username,
+			HashedPassword:   sql.NullString{String: arg.HashedPassword, Valid: true},
+			PasswordChangeAt: sql.NullTime{Time: time.Now(), Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		result.BlockedSessions, err = q.BlockUserSessions(ctx, result.User.Username)
+		return err
+	})
+
+	return result, err
+}
+
+// EnableMFATxParams contains the input parameters for the enable MFA transaction
+type EnableMFATxParams struct {
+	Username string `json:"username"`
+	// the time step of the code which confirmed the secret - it is used up, like a code used at login
+	Step int64 `json:"step"`
+	// util.HashSecretToken of the normalized recovery codes shown to the user
+	RecoveryCodeHashes []string `json:"recovery_code_hashes"`
+}
+
+// EnableMFATxResult contains the result of the enable MFA transaction
+type EnableMFATxResult struct {
+	UserMFA       UserMfa           `json:"user_mfa"`
+	RecoveryCodes []MfaRecoveryCode `json:"recovery_codes"`
+}
+
+// EnableMFATX - enables two-factor authentication for the user and replaces their recovery codes within a single db tx
+// sql.ErrNoRows is returned as is if the user hasn't enrolled or two-factor authentication is already enabled
+func (store *SQLStore) EnableMFATX(ctx context.Context, arg EnableMFATxParams) (EnableMFATxResult, error) {
+	var result EnableMFATxResult
+
+	err := store.execTx(ctx, nil, func(q *Queries) error {
+		// the closure runs again if execTx retries the transaction
+		result = EnableMFATxResult{}
+
+		var err error
+
+		_, err = q.EnableUserMFA(ctx, arg.Username)
+		if err != nil {
+			return err
+		}
+
+		result.UserMFA, err = q.UseTOTPStep(ctx, UseTOTPStepParams{
+			Username: arg.Username,
+			Step:     arg.Step,
+		})
+		if err != nil {
+			return err
+		}
+
+		// codes left over from an earlier enrollment must not work any more
+		err = q.DeleteMFARecoveryCodes(ctx, arg.Username)
+		if err != nil {
+			return err
+		}
+
+		for _, codeHash := range arg.RecoveryCodeHashes {
+			recoveryCode, err := q.CreateMFARecoveryCode(ctx, CreateMFARecoveryCodeParams{
+				Username: arg.Username,
+				CodeHash: codeHash,
+			})
+			if err != nil {
+				return err
+			}
+			result.RecoveryCodes = append(result.RecoveryCodes, recoveryCode)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// DisableMFATX - removes the TOTP secret and the recovery codes of the user within a single db tx, so they login with only
+// their password again
+func (store *SQLStore) DisableMFATX(ctx context.Context, username string) error {
+	return store.execTx(ctx, nil, func(q *Queries) error {
+		err := q.DeleteMFARecoveryCodes(ctx, username)
+		if err != nil {
+			return err
+		}
+		return q.DeleteUserMFA(ctx, username)
+	})
+}
@@ -2,9 +2,16 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"testing"
+	"time"
 
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/token"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/require"
 )
 
@@ -224,3 +231,742 @@ func TestTransferTxDeadlock(t *testing.T) {
 	// account 2 remains untouched since it was declared
 	require.Equal(t, account2.Balance, updatedAccount2.Balance)
 }
+
+// TestTransferTxInsufficientFunds - tests that a transfer of more money than the from account holds is rejected and that
+// nothing is written to the database
+func TestTransferTxInsufficientFunds(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	// transferring one more than the balance of account1 would make its balance negative
+	_, err := store.TransferTX(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        account1.Balance + 1,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// the transaction was rolled back so both balances remain untouched
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updatedAccount1.Balance)
+
+	updatedAccount2, err := testQueries.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+	require.Equal(t, account2.Balance, updatedAccount2.Balance)
+
+	// the transfer record was rolled back as well
+	transfers, err := testQueries.ListTransfers(context.Background(), ListTransfersParams{
+		AccountID: account1.ID,
+		Limit:     5,
+		Offset:    0,
+	})
+	require.NoError(t, err)
+	require.Empty(t, transfers)
+}
+
+// TestTransferTxFrozenAccount - tests that no money moves in to or out of a frozen account, even when the caller didn't
+// check the account first
+func TestTransferTxFrozenAccount(t *testing.T) {
+	store := NewStore(testDB)
+
+	for _, freezeReceiver := range []bool{true, false} {
+		account1 := createRandomAccount(t)
+		account2 := createRandomAccount(t)
+
+		frozenAccountID := account1.ID
+		if freezeReceiver {
+			frozenAccountID = account2.ID
+		}
+		_, err := store.UpdateAccountFrozen(context.Background(), UpdateAccountFrozenParams{
+			ID:       frozenAccountID,
+			IsFrozen: true,
+		})
+		require.NoError(t, err)
+
+		_, err = store.TransferTX(context.Background(), TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        10,
+		})
+		require.ErrorIs(t, err, ErrAccountFrozen)
+
+		// nothing was written
+		fromAccount, err := store.GetAccount(context.Background(), account1.ID)
+		require.NoError(t, err)
+		require.Equal(t, account1.Balance, fromAccount.Balance)
+
+		toAccount, err := store.GetAccount(context.Background(), account2.ID)
+		require.NoError(t, err)
+		require.Equal(t, account2.Balance, toAccount.Balance)
+
+		transfers, err := store.ListTransfers(context.Background(), ListTransfersParams{
+			AccountID: account1.ID,
+			Limit:     5,
+			Offset:    0,
+		})
+		require.NoError(t, err)
+		require.Empty(t, transfers)
+	}
+}
+
+// TestTransferTxOverdraftLimit - tests that an account with an overdraft limit can go below zero, but no further than the limit
+func TestTransferTxOverdraftLimit(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	overdraftLimit := int64(50)
+	account1, err := testQueries.UpdateAccountOverdraftLimit(context.Background(), UpdateAccountOverdraftLimitParams{
+		ID:             account1.ID,
+		OverdraftLimit: overdraftLimit,
+	})
+	require.NoError(t, err)
+
+	// going past the limit by one is rejected
+	_, err = store.TransferTX(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        account1.Balance + overdraftLimit + 1,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// using the whole limit leaves the balance at exactly -overdraftLimit
+	result, err := store.TransferTX(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        account1.Balance + overdraftLimit,
+	})
+	require.NoError(t, err)
+	require.Equal(t, -overdraftLimit, result.FromAccount.Balance)
+	require.Equal(t, account2.Balance+account1.Balance+overdraftLimit, result.ToAccount.Balance)
+
+	// the account is now at its limit so not even one more unit can leave it
+	_, err = store.TransferTX(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        1,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+}
+
+// TestTransferTxIdempotencyKey - tests that retrying a transfer with the same idempotency key only moves the money once
+func TestTransferTxIdempotencyKey(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	arg := TransferTxParams{
+		FromAccountID:  account1.ID,
+		ToAccountID:    account2.ID,
+		Amount:         10,
+		IdempotencyKey: util.RandomString(32),
+	}
+
+	result1, err := store.TransferTX(context.Background(), arg)
+	require.NoError(t, err)
+
+	// the retry returns the original result, including the balances as they were right after the original transfer
+	result2, err := store.TransferTX(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, result1.Transfer.ID, result2.Transfer.ID)
+	require.Equal(t, result1.FromEntry.ID, result2.FromEntry.ID)
+	require.Equal(t, result1.ToEntry.ID, result2.ToEntry.ID)
+	require.Equal(t, result1.FromAccount.Balance, result2.FromAccount.Balance)
+	require.Equal(t, result1.ToAccount.Balance, result2.ToAccount.Balance)
+
+	// the money only moved once
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance-arg.Amount, updatedAccount1.Balance)
+
+	updatedAccount2, err := testQueries.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+	require.Equal(t, account2.Balance+arg.Amount, updatedAccount2.Balance)
+
+	// reusing the key for a different amount is rejected
+	arg.Amount++
+	_, err = store.TransferTX(context.Background(), arg)
+	require.ErrorIs(t, err, ErrIdempotencyKeyReused)
+
+	// the key is scoped to the from account, so another account can use it for a transfer of its own
+	otherArg := TransferTxParams{
+		FromAccountID:  account2.ID,
+		ToAccountID:    account1.ID,
+		Amount:         5,
+		IdempotencyKey: arg.IdempotencyKey,
+	}
+	result3, err := store.TransferTX(context.Background(), otherArg)
+	require.NoError(t, err)
+	require.NotEqual(t, result1.Transfer.ID, result3.Transfer.ID)
+	require.Equal(t, account2.ID, result3.Transfer.FromAccountID)
+}
+
+// TestExecTxRetry - runs concurrent serializable read-modify-write transactions on the same account - postgres aborts all
+// but one of them with a serialization failure, so every update only lands if execTx retries the failed transactions
+func TestExecTxRetry(t *testing.T) {
+	store := NewStore(testDB).(*SQLStore)
+	account := createRandomAccount(t)
+
+	n := 3
+	errs := make(chan error)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			errs <- store.execTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelSerializable}, func(q *Queries) error {
+				current, err := q.GetAccount(context.Background(), account.ID)
+				if err != nil {
+					return err
+				}
+
+				_, err = q.UpdateAccount(context.Background(), UpdateAccountParams{
+					ID:      account.ID,
+					Balance: current.Balance + 1,
+				})
+				return err
+			})
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		require.NoError(t, <-errs)
+	}
+
+	// no update was lost
+	updatedAccount, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance+int64(n), updatedAccount.Balance)
+}
+
+func TestIsRetryableTxError(t *testing.T) {
+	require.True(t, isRetryableTxError(&pq.Error{Code: "40001"}))                            // serialization_failure
+	require.True(t, isRetryableTxError(fmt.Errorf("wrapped: %w", &pq.Error{Code: "40P01"}))) // deadlock_detected
+	require.False(t, isRetryableTxError(&pq.Error{Code: "23505"}))                           // unique_violation
+	require.False(t, isRetryableTxError(ErrInsufficientFunds))
+	require.False(t, isRetryableTxError(sql.ErrNoRows))
+}
+
+func TestTxRetryDelay(t *testing.T) {
+	for attempt := 1; attempt < 10; attempt++ {
+		backoff := txRetryBaseDelay << (attempt - 1)
+		if backoff > maxTxRetryDelay {
+			backoff = maxTxRetryDelay
+		}
+
+		// the jitter keeps the delay between half and all of the backoff
+		delay := txRetryDelay(attempt)
+		require.GreaterOrEqual(t, delay, backoff/2)
+		require.LessOrEqual(t, delay, backoff)
+	}
+}
+
+// TestReverseTransferTx - tests that a reversal sends the money back without touching the original transfer and that a
+// transfer can only be reversed once
+func TestReverseTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	amount := int64(10)
+	original, err := store.TransferTX(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        amount,
+	})
+	require.NoError(t, err)
+
+	result, err := store.ReverseTransferTX(context.Background(), ReverseTransferTxParams{
+		TransferID: original.Transfer.ID,
+	})
+	require.NoError(t, err)
+
+	// the original transfer is unchanged
+	require.Equal(t, original.Transfer, result.OriginalTransfer)
+
+	// the reversal moves the same amount from account2 back to account1
+	reversal := result.Reversal
+	require.NotZero(t, reversal.Transfer.ID)
+	require.Equal(t, account2.ID, reversal.Transfer.FromAccountID)
+	require.Equal(t, account1.ID, reversal.Transfer.ToAccountID)
+	require.Equal(t, amount, reversal.Transfer.Amount)
+	require.Equal(t, sql.NullInt64{Int64: original.Transfer.ID, Valid: true}, reversal.Transfer.ReversalOf)
+
+	require.Equal(t, account2.ID, reversal.FromEntry.AccountID)
+	require.Equal(t, -amount, reversal.FromEntry.Amount)
+	require.Equal(t, account1.ID, reversal.ToEntry.AccountID)
+	require.Equal(t, amount, reversal.ToEntry.Amount)
+
+	// both balances are back where they started
+	require.Equal(t, account2.Balance, reversal.FromAccount.Balance)
+	require.Equal(t, account1.Balance, reversal.ToAccount.Balance)
+
+	// a transfer can only be reversed once
+	_, err = store.ReverseTransferTX(context.Background(), ReverseTransferTxParams{
+		TransferID: original.Transfer.ID,
+	})
+	require.ErrorIs(t, err, ErrTransferAlreadyReversed)
+
+	// and a reversal cannot be reversed
+	_, err = store.ReverseTransferTX(context.Background(), ReverseTransferTxParams{
+		TransferID: reversal.Transfer.ID,
+	})
+	require.ErrorIs(t, err, ErrCannotReverseReversal)
+
+	// a transfer that doesn't exist cannot be reversed
+	_, err = store.ReverseTransferTX(context.Background(), ReverseTransferTxParams{
+		TransferID: reversal.Transfer.ID + 1000,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+// TestReverseTransferTxFrozenAccount - tests that a transfer can't be reversed while either of its accounts is frozen, and
+// that nothing is written when the reversal is refused
+func TestReverseTransferTxFrozenAccount(t *testing.T) {
+	store := NewStore(testDB)
+
+	for _, freezeReceiver := range []bool{true, false} {
+		account1 := createRandomAccount(t)
+		account2 := createRandomAccount(t)
+
+		original, err := store.TransferTX(context.Background(), TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        10,
+		})
+		require.NoError(t, err)
+
+		frozenAccountID := account1.ID
+		if freezeReceiver {
+			frozenAccountID = account2.ID
+		}
+		_, err = store.UpdateAccountFrozen(context.Background(), UpdateAccountFrozenParams{
+			ID:       frozenAccountID,
+			IsFrozen: true,
+		})
+		require.NoError(t, err)
+
+		_, err = store.ReverseTransferTX(context.Background(), ReverseTransferTxParams{
+			TransferID: original.Transfer.ID,
+		})
+		require.ErrorIs(t, err, ErrAccountFrozen)
+
+		// the transaction was rolled back - there is no reversal and the balances didn't change
+		_, err = store.GetTransferReversal(context.Background(), original.Transfer.ID)
+		require.ErrorIs(t, err, sql.ErrNoRows)
+
+		fromAccount, err := store.GetAccount(context.Background(), account1.ID)
+		require.NoError(t, err)
+		require.Equal(t, original.FromAccount.Balance, fromAccount.Balance)
+
+		toAccount, err := store.GetAccount(context.Background(), account2.ID)
+		require.NoError(t, err)
+		require.Equal(t, original.ToAccount.Balance, toAccount.Balance)
+	}
+}
+
+// TestDepositAndWithdrawTx - tests that deposits and withdrawals record a typed entry and update the balance, that a
+// withdrawal cannot overdraw the account, and that an external reference can only be booked once
+func TestDepositAndWithdrawTx(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t)
+
+	deposit, err := store.DepositTX(context.Background(), DepositTxParams{
+		AccountID:         account.ID,
+		Amount:            10,
+		ExternalReference: util.RandomString(16),
+	})
+	require.NoError(t, err)
+	require.Equal(t, account.Balance+10, deposit.Account.Balance)
+	require.Equal(t, account.ID, deposit.Entry.AccountID)
+	require.Equal(t, int64(10), deposit.Entry.Amount)
+	require.Equal(t, EntryTypeDeposit, deposit.Entry.Type)
+
+	// booking the same deposit again is rejected and doesn't change the balance
+	_, err = store.DepositTX(context.Background(), DepositTxParams{
+		AccountID:         account.ID,
+		Amount:            10,
+		ExternalReference: deposit.Entry.ExternalReference.String,
+	})
+	require.ErrorIs(t, err, ErrDuplicateExternalReference)
+
+	withdrawal, err := store.WithdrawTX(context.Background(), WithdrawTxParams{
+		AccountID:         account.ID,
+		Amount:            deposit.Account.Balance,
+		ExternalReference: util.RandomString(16),
+	})
+	require.NoError(t, err)
+	require.Zero(t, withdrawal.Account.Balance)
+	require.Equal(t, -deposit.Account.Balance, withdrawal.Entry.Amount)
+	require.Equal(t, EntryTypeWithdrawal, withdrawal.Entry.Type)
+
+	// the account is empty now
+	_, err = store.WithdrawTX(context.Background(), WithdrawTxParams{
+		AccountID:         account.ID,
+		Amount:            1,
+		ExternalReference: util.RandomString(16),
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+}
+
+// TestDepositAndWithdrawTxFrozenAccount - tests that no money can be deposited to or withdrawn from a frozen account
+func TestDepositAndWithdrawTxFrozenAccount(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t)
+
+	_, err := store.UpdateAccountFrozen(context.Background(), UpdateAccountFrozenParams{
+		ID:       account.ID,
+		IsFrozen: true,
+	})
+	require.NoError(t, err)
+
+	_, err = store.DepositTX(context.Background(), DepositTxParams{
+		AccountID:         account.ID,
+		Amount:            10,
+		ExternalReference: util.RandomString(16),
+	})
+	require.ErrorIs(t, err, ErrAccountFrozen)
+
+	_, err = store.WithdrawTX(context.Background(), WithdrawTxParams{
+		AccountID:         account.ID,
+		Amount:            1,
+		ExternalReference: util.RandomString(16),
+	})
+	require.ErrorIs(t, err, ErrAccountFrozen)
+
+	// the balance didn't change
+	gotAccount, err := store.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance, gotAccount.Balance)
+}
+
+func TestRotateSessionTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+	session := createRandomSession(t, user)
+
+	arg := RotateSessionTxParams{
+		SessionID: session.ID,
+		NewSession: CreateSessionParams{
+			ID:           uuid.New(),
+			Username:     user.Username,
+			RefreshToken: util.RandomString(32),
+			UserAgent:    "test-agent",
+			ClientIp:     "127.0.0.1",
+			ExpiresAt:    session.ExpiresAt,
+		},
+	}
+
+	result, err := store.RotateSessionTX(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, session.ID, result.RotatedSession.ID)
+	require.True(t, result.RotatedSession.RotatedAt.Valid)
+
+	// the new session is linked to the one it replaced
+	newSession := result.NewSession
+	require.Equal(t, arg.NewSession.ID, newSession.ID)
+	require.Equal(t, arg.NewSession.RefreshToken, newSession.RefreshToken)
+	require.Equal(t, session.FamilyID, newSession.FamilyID)
+	require.True(t, newSession.PreviousSessionID.Valid)
+	require.Equal(t, session.ID, newSession.PreviousSessionID.UUID)
+	require.False(t, newSession.RotatedAt.Valid)
+
+	// rotating the same session again fails and creates nothing
+	arg.NewSession.ID = uuid.New()
+	_, err = store.RotateSessionTX(context.Background(), arg)
+	require.ErrorIs(t, err, ErrSessionAlreadyRotated)
+
+	_, err = store.GetSession(context.Background(), arg.NewSession.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestUpdateUserTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+	session1 := createRandomSession(t, user)
+	session2 := createRandomSession(t, user)
+	// the sessions of other users are not blocked
+	other := createRandomSession(t, createRandomUser(t))
+
+	// changing only the full name keeps the sessions
+	result, err := store.UpdateUserTX(context.Background(), UpdateUserTxParams{
+		UpdateUserParams: UpdateUserParams{
+			Username: user.Username,
+			FullName: sql.NullString{String: util.RandomOwner(), Valid: true},
+		},
+	})
+	require.NoError(t, err)
+	require.Zero(t, result.BlockedSessions)
+
+	// a password change blocks every session of the user
+	hashedPassword, err := util.HashPassword(util.RandomString(6))
+	require.NoError(t, err)
+	result, err = store.UpdateUserTX(context.Background(), UpdateUserTxParams{
+		UpdateUserParams: UpdateUserParams{
+			Username:         user.Username,
+			HashedPassword:   sql.NullString{String: hashedPassword, Valid: true},
+			PasswordChangeAt: sql.NullTime{Time: time.Now(), Valid: true},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, hashedPassword, result.User.HashedPassword)
+	require.Equal(t, int64(2), result.BlockedSessions)
+
+	// the refresh tokens of the blocked sessions are revoked too, so they can't be used as access tokens either
+	for _, id := range []uuid.UUID{session1.ID, session2.ID} {
+		session, err := store.GetSession(context.Background(), id)
+		require.NoError(t, err)
+		require.True(t, session.IsBlocked)
+
+		revoked, err := store.IsTokenRevoked(context.Background(), id)
+		require.NoError(t, err)
+		require.True(t, revoked)
+	}
+
+	session, err := store.GetSession(context.Background(), other.ID)
+	require.NoError(t, err)
+	require.False(t, session.IsBlocked)
+}
+
+func TestCreateUserTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	hashedPassword, err := util.HashPassword(util.RandomString(6))
+	require.NoError(t, err)
+	arg := CreateUserTxParams{
+		CreateUserParams: CreateUserParams{
+			Username:       util.RandomOwner(),
+			HashedPassword: hashedPassword,
+			FullName:       util.RandomOwner(),
+			Email:          util.RandomEmail(),
+		},
+		SecretCodeHash: util.HashSecretToken(util.RandomString(32)),
+	}
+
+	// the verification code to send is returned with the user
+	result, err := store.CreateUserTX(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Username, result.User.Username)
+	require.False(t, result.User.IsEmailVerified)
+	require.Equal(t, arg.Email, result.VerifyEmail.Email)
+	require.Equal(t, arg.SecretCodeHash, result.VerifyEmail.SecretCodeHash)
+	require.False(t, result.VerifyEmail.IsUsed)
+	require.True(t, result.VerifyEmail.ExpiredAt.After(time.Now()))
+}
+
+func TestUpdateUserTxEmail(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+	verifyEmail := createRandomVerifyEmail(t, user)
+	_, err := store.VerifyEmailTX(context.Background(), VerifyEmailTxParams{
+		EmailID:        verifyEmail.ID,
+		SecretCodeHash: verifyEmail.SecretCodeHash,
+	})
+	require.NoError(t, err)
+
+	// the same email keeps the user verified and no email is sent
+	arg := UpdateUserTxParams{
+		UpdateUserParams: UpdateUserParams{
+			Username: user.Username,
+			Email:    sql.NullString{String: user.Email, Valid: true},
+		},
+		SecretCodeHash: util.HashSecretToken(util.RandomString(32)),
+	}
+	result, err := store.UpdateUserTX(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, result.User.IsEmailVerified)
+	require.Zero(t, result.VerifyEmail.ID)
+
+	// a new email must be verified again
+	arg.Email = sql.NullString{String: util.RandomEmail(), Valid: true}
+	result, err = store.UpdateUserTX(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, result.VerifyEmail.ID)
+	require.Equal(t, arg.Email.String, result.User.Email)
+	require.False(t, result.User.IsEmailVerified)
+	require.Equal(t, arg.Email.String, result.VerifyEmail.Email)
+}
+
+func TestVerifyEmailTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+	verifyEmail := createRandomVerifyEmail(t, user)
+
+	// a wrong code is rejected
+	_, err := store.VerifyEmailTX(context.Background(), VerifyEmailTxParams{
+		EmailID:        verifyEmail.ID,
+		SecretCodeHash: util.HashSecretToken(util.RandomString(32)),
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	result, err := store.VerifyEmailTX(context.Background(), VerifyEmailTxParams{
+		EmailID:        verifyEmail.ID,
+		SecretCodeHash: verifyEmail.SecretCodeHash,
+	})
+	require.NoError(t, err)
+	require.True(t, result.User.IsEmailVerified)
+	require.True(t, result.VerifyEmail.IsUsed)
+
+	// every code can only be used once
+	_, err = store.VerifyEmailTX(context.Background(), VerifyEmailTxParams{
+		EmailID:        verifyEmail.ID,
+		SecretCodeHash: verifyEmail.SecretCodeHash,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestVerifyEmailTxChangedEmail(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+	verifyEmail := createRandomVerifyEmail(t, user)
+
+	// the user changes their email before using the code sent to the old one
+	_, err := store.UpdateUser(context.Background(), UpdateUserParams{
+		Username: user.Username,
+		Email:    sql.NullString{String: util.RandomEmail(), Valid: true},
+	})
+	require.NoError(t, err)
+
+	_, err = store.VerifyEmailTX(context.Background(), VerifyEmailTxParams{
+		EmailID:        verifyEmail.ID,
+		SecretCodeHash: verifyEmail.SecretCodeHash,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// the transaction was rolled back, so the user is still not verified
+	gotUser, err := store.GetUser(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.False(t, gotUser.IsEmailVerified)
+}
+
+func TestCreatePasswordResetTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+
+	arg := CreatePasswordResetTxParams{
+		Email:     user.Email,
+		TokenHash: util.HashSecretToken(util.RandomString(32)),
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+
+	// the user to send the email to is returned with the password reset
+	result, err := store.CreatePasswordResetTX(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, user.Username, result.User.Username)
+	require.Equal(t, user.Username, result.PasswordReset.Username)
+	require.Equal(t, arg.TokenHash, result.PasswordReset.TokenHash)
+
+	// no user has the email
+	arg.Email = util.RandomEmail()
+	_, err = store.CreatePasswordResetTX(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestResetPasswordTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+	session1 := createRandomSession(t, user)
+	session2 := createRandomSession(t, user)
+	passwordReset := createRandomPasswordReset(t, user, time.Now().Add(time.Minute))
+
+	// an access token issued with the old password
+	denylist := NewTokenDenylist(store)
+	accessPayload, err := token.NewPayload(user.Username, user.Role, time.Minute)
+	require.NoError(t, err)
+	denied, err := denylist.IsDenied(context.Background(), accessPayload)
+	require.NoError(t, err)
+	require.False(t, denied)
+
+	hashedPassword, err := util.HashPassword(util.RandomString(6))
+	require.NoError(t, err)
+	arg := ResetPasswordTxParams{
+		TokenHash:      passwordReset.TokenHash,
+		HashedPassword: hashedPassword,
+	}
+
+	result, err := store.ResetPasswordTX(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, user.Username, result.User.Username)
+	require.Equal(t, hashedPassword, result.User.HashedPassword)
+	require.WithinDuration(t, time.Now(), result.User.PasswordChangeAt, time.Second)
+	require.True(t, result.PasswordReset.IsUsed)
+	// every session of the user is blocked
+	require.Equal(t, int64(2), result.BlockedSessions)
+
+	// whoever knew the old password can't keep using the tokens issued before the reset - the access token is cut off by the
+	// password change, and the refresh tokens of the blocked sessions are revoked
+	denied, err = denylist.IsDenied(context.Background(), accessPayload)
+	require.NoError(t, err)
+	require.True(t, denied)
+
+	for _, id := range []uuid.UUID{session1.ID, session2.ID} {
+		revoked, err := store.IsTokenRevoked(context.Background(), id)
+		require.NoError(t, err)
+		require.True(t, revoked)
+	}
+
+	// the tokens issued after the reset work
+	newPayload, err := token.NewPayload(user.Username, user.Role, time.Minute)
+	require.NoError(t, err)
+	denied, err = denylist.IsDenied(context.Background(), newPayload)
+	require.NoError(t, err)
+	require.False(t, denied)
+
+	// the token can only be used once
+	_, err = store.ResetPasswordTX(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestEnableMFATx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+	createRandomUserMFA(t, user)
+	oldRecoveryCode := createRandomMFARecoveryCode(t, user)
+
+	arg := EnableMFATxParams{
+		Username: user.Username,
+		Step:     util.RandomInt(1000, 2000),
+	}
+	for i := 0; i < 3; i++ {
+		arg.RecoveryCodeHashes = append(arg.RecoveryCodeHashes, util.HashSecretToken(util.RandomString(10)))
+	}
+
+	result, err := store.EnableMFATX(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, result.UserMFA.IsEnabled)
+	require.Equal(t, arg.Step, result.UserMFA.LastUsedStep)
+	require.Len(t, result.RecoveryCodes, len(arg.RecoveryCodeHashes))
+	for i, recoveryCode := range result.RecoveryCodes {
+		require.Equal(t, arg.RecoveryCodeHashes[i], recoveryCode.CodeHash)
+	}
+
+	// the recovery codes of an earlier enrollment are replaced
+	_, err = store.UseMFARecoveryCode(context.Background(), UseMFARecoveryCodeParams{
+		Username: user.Username,
+		CodeHash: oldRecoveryCode.CodeHash,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// it can only be enabled once
+	_, err = store.EnableMFATX(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestDisableMFATx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+	createRandomUserMFA(t, user)
+	recoveryCode := createRandomMFARecoveryCode(t, user)
+
+	err := store.DisableMFATX(context.Background(), user.Username)
+	require.NoError(t, err)
+
+	_, err = store.GetUserMFA(context.Background(), user.Username)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = store.UseMFARecoveryCode(context.Background(), UseMFARecoveryCodeParams{
+		Username: user.Username,
+		CodeHash: recoveryCode.CodeHash,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
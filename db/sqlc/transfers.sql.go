@@ -7,6 +7,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 )
 
 const createTransfer = `-- name: CreateTransfer :one
@@ -17,7 +18,7 @@ INSERT INTO transfers (
 ) VALUES (
   $1, $2, $3
 )
-RETURNING id, from_account_id, to_account_id, amount, created_at
+RETURNING id, from_account_id, to_account_id, amount, created_at, reversal_of
 `
 
 type CreateTransferParams struct {
@@ -35,6 +36,45 @@ func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ReversalOf,
+	)
+	return i, err
+}
+
+const createTransferReversal = `-- name: CreateTransferReversal :one
+INSERT INTO transfers (
+  from_account_id,
+  to_account_id,
+  amount,
+  reversal_of
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING id, from_account_id, to_account_id, amount, created_at, reversal_of
+`
+
+type CreateTransferReversalParams struct {
+	FromAccountID int64         `json:"from_account_id"`
+	ToAccountID   int64         `json:"to_account_id"`
+	Amount        int64         `json:"amount"`
+	ReversalOf    sql.NullInt64 `json:"reversal_of"`
+}
+
+func (q *Queries) CreateTransferReversal(ctx context.Context, arg CreateTransferReversalParams) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, createTransferReversal,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.ReversalOf,
+	)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.ReversalOf,
 	)
 	return i, err
 }
@@ -50,7 +90,7 @@ func (q *Queries) DeleteTransfer(ctx context.Context, id int64) error {
 }
 
 const getTransfer = `-- name: GetTransfer :one
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, reversal_of FROM transfers
 WHERE id = $1 LIMIT 1
 `
 
@@ -63,33 +103,146 @@ func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ReversalOf,
+	)
+	return i, err
+}
+
+const getTransferForUpdate = `-- name: GetTransferForUpdate :one
+SELECT id, from_account_id, to_account_id, amount, created_at, reversal_of FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+// locks the transfer so concurrent reversals of it run one after the other
+func (q *Queries) GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, getTransferForUpdate, id)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.ReversalOf,
+	)
+	return i, err
+}
+
+const getTransferReversal = `-- name: GetTransferReversal :one
+SELECT id, from_account_id, to_account_id, amount, created_at, reversal_of FROM transfers
+WHERE reversal_of = $1::bigint LIMIT 1
+`
+
+func (q *Queries) GetTransferReversal(ctx context.Context, transferID int64) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, getTransferReversal, transferID)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.ReversalOf,
 	)
 	return i, err
 }
 
 const listTransfers = `-- name: ListTransfers :many
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, reversal_of FROM transfers
 WHERE
-    from_account_id = $1 OR
-    to_account_id = $2 
+    (from_account_id = $1 OR to_account_id = $1) AND
+    ($2::timestamptz IS NULL OR created_at >= $2) AND
+    ($3::timestamptz IS NULL OR created_at < $3) AND
+    ($4::varchar IS NULL OR
+        ($4 = 'positive' AND to_account_id = $1) OR
+        ($4 = 'negative' AND from_account_id = $1))
 ORDER BY id
-LIMIT $3
-OFFSET $4
+LIMIT $6
+OFFSET $5
 `
 
 type ListTransfersParams struct {
-	FromAccountID int64 `json:"from_account_id"`
-	ToAccountID   int64 `json:"to_account_id"`
-	Limit         int32 `json:"limit"`
-	Offset        int32 `json:"offset"`
+	AccountID int64          `json:"account_id"`
+	StartTime sql.NullTime   `json:"start_time"`
+	EndTime   sql.NullTime   `json:"end_time"`
+	Sign      sql.NullString `json:"sign"`
+	Offset    int32          `json:"offset"`
+	Limit     int32          `json:"limit"`
 }
 
+// lists the transfers going in to and out of the account - the time range and sign filters are optional, a null argument
+// matches every transfer - incoming transfers are positive for the account and outgoing transfers are negative
 func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error) {
 	rows, err := q.db.QueryContext(ctx, listTransfers,
-		arg.FromAccountID,
-		arg.ToAccountID,
-		arg.Limit,
+		arg.AccountID,
+		arg.StartTime,
+		arg.EndTime,
+		arg.Sign,
 		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Transfer{}
+	for rows.Next() {
+		var i Transfer
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.ReversalOf,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTransfersAfter = `-- name: ListTransfersAfter :many
+SELECT id, from_account_id, to_account_id, amount, created_at, reversal_of FROM transfers
+WHERE
+    (from_account_id = $1 OR to_account_id = $1) AND
+    id > $2 AND
+    ($3::timestamptz IS NULL OR created_at >= $3) AND
+    ($4::timestamptz IS NULL OR created_at < $4) AND
+    ($5::varchar IS NULL OR
+        ($5 = 'positive' AND to_account_id = $1) OR
+        ($5 = 'negative' AND from_account_id = $1))
+ORDER BY id
+LIMIT $6
+`
+
+type ListTransfersAfterParams struct {
+	AccountID int64          `json:"account_id"`
+	AfterID   int64          `json:"after_id"`
+	StartTime sql.NullTime   `json:"start_time"`
+	EndTime   sql.NullTime   `json:"end_time"`
+	Sign      sql.NullString `json:"sign"`
+	Limit     int32          `json:"limit"`
+}
+
+// keyset (cursor) pagination - same filters as ListTransfers but lists the transfers with an id greater than after_id, the
+// last id of the previous page
+func (q *Queries) ListTransfersAfter(ctx context.Context, arg ListTransfersAfterParams) ([]Transfer, error) {
+	rows, err := q.db.QueryContext(ctx, listTransfersAfter,
+		arg.AccountID,
+		arg.AfterID,
+		arg.StartTime,
+		arg.EndTime,
+		arg.Sign,
+		arg.Limit,
 	)
 	if err != nil {
 		return nil, err
@@ -104,6 +257,7 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 			&i.ToAccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.ReversalOf,
 		); err != nil {
 			return nil, err
 		}
@@ -122,7 +276,7 @@ const updateTransfer = `-- name: UpdateTransfer :one
 UPDATE transfers
 set amount = $2
 WHERE id = $1
-RETURNING id, from_account_id, to_account_id, amount, created_at
+RETURNING id, from_account_id, to_account_id, amount, created_at, reversal_of
 `
 
 type UpdateTransferParams struct {
@@ -139,6 +293,7 @@ func (q *Queries) UpdateTransfer(ctx context.Context, arg UpdateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ReversalOf,
 	)
 	return i, err
 }
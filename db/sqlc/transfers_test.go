@@ -75,6 +75,47 @@ func TestGetTransfer(t *testing.T) {
 	require.WithinDuration(t, transfer1.CreatedAt, transfer2.CreatedAt, time.Second)
 }
 
+func TestCreateTransferReversal(t *testing.T) {
+	transfer1 := createRandomTransfer(t)
+
+	// a reversal moves the money in the opposite direction
+	arg := CreateTransferReversalParams{
+		FromAccountID: transfer1.ToAccountID,
+		ToAccountID:   transfer1.FromAccountID,
+		Amount:        transfer1.Amount,
+		ReversalOf:    sql.NullInt64{Int64: transfer1.ID, Valid: true},
+	}
+
+	reversal, err := testQueries.CreateTransferReversal(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, reversal)
+
+	require.Equal(t, arg.FromAccountID, reversal.FromAccountID)
+	require.Equal(t, arg.ToAccountID, reversal.ToAccountID)
+	require.Equal(t, arg.Amount, reversal.Amount)
+	require.Equal(t, arg.ReversalOf, reversal.ReversalOf)
+
+	// GetTransferReversal finds the reversal through the original transfer
+	reversal2, err := testQueries.GetTransferReversal(context.Background(), transfer1.ID)
+	require.NoError(t, err)
+	require.Equal(t, reversal.ID, reversal2.ID)
+
+	// the unique constraint on reversal_of prevents a second reversal
+	_, err = testQueries.CreateTransferReversal(context.Background(), arg)
+	require.Error(t, err)
+}
+
+func TestGetTransferReversalNotFound(t *testing.T) {
+	// a transfer that hasn't been reversed has no reversal
+	transfer := createRandomTransfer(t)
+	require.False(t, transfer.ReversalOf.Valid)
+
+	reversal, err := testQueries.GetTransferReversal(context.Background(), transfer.ID)
+	require.Error(t, err)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+	require.Empty(t, reversal)
+}
+
 func TestUpdateTransfer(t *testing.T) {
 	transfer1 := createRandomTransfer(t)
 
@@ -116,10 +157,9 @@ func TestListTransfers(t *testing.T) {
 	}
 
 	arg1 := ListTransfersParams{
-		FromAccountID: transfer1.FromAccountID, // looking for table entries that have transfer1.FromAccountID as the FromAccountID OR (see below)
-		ToAccountID:   transfer1.FromAccountID, // entries that have transfer1.FromAccountID as the ToAccountID
-		Limit:         5,                       // select five transfer objects
-		Offset:        5,                       // skip the first five transfer objects
+		AccountID: transfer1.FromAccountID, // looking for transfers going in to or out of transfer1.FromAccountID
+		Limit:     5,                       // select five transfer objects
+		Offset:    5,                       // skip the first five transfer objects
 	}
 
 	transfers, err := testQueries.ListTransfers(context.Background(), arg1)
@@ -131,3 +171,71 @@ func TestListTransfers(t *testing.T) {
 		require.True(t, transfer.FromAccountID == transfer1.FromAccountID || transfer.ToAccountID == transfer1.FromAccountID)
 	}
 }
+
+func TestListTransfersFilters(t *testing.T) {
+	transfer1 := createRandomTransfer(t)
+	for i := 0; i < 3; i++ {
+		createMultiTransfers(t, transfer1.ToAccountID, transfer1.FromAccountID)
+	}
+
+	// transfer1.FromAccountID has one outgoing (negative) and three incoming (positive) transfers
+	arg := ListTransfersParams{
+		AccountID: transfer1.FromAccountID,
+		Sign:      sql.NullString{String: AmountSignNegative, Valid: true},
+		Limit:     10,
+	}
+
+	transfers, err := testQueries.ListTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, transfers, 1)
+	require.Equal(t, transfer1.ID, transfers[0].ID)
+
+	arg.Sign = sql.NullString{String: AmountSignPositive, Valid: true}
+	transfers, err = testQueries.ListTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, transfers, 3)
+	for _, transfer := range transfers {
+		require.Equal(t, transfer1.FromAccountID, transfer.ToAccountID)
+	}
+
+	// the end of the time range is exclusive, so nothing created after transfer1 is listed
+	arg.Sign = sql.NullString{}
+	arg.EndTime = sql.NullTime{Time: transfer1.CreatedAt.Add(time.Microsecond), Valid: true}
+	transfers, err = testQueries.ListTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, transfers)
+	require.Equal(t, transfer1.ID, transfers[0].ID)
+
+	// and nothing is created in the future
+	arg.EndTime = sql.NullTime{}
+	arg.StartTime = sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true}
+	transfers, err = testQueries.ListTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Empty(t, transfers)
+}
+
+func TestListTransfersAfter(t *testing.T) {
+	transfer1 := createRandomTransfer(t)
+	for i := 0; i < 3; i++ {
+		createMultiTransfers(t, transfer1.FromAccountID, transfer1.ToAccountID)
+	}
+
+	arg := ListTransfersAfterParams{
+		AccountID: transfer1.FromAccountID,
+		AfterID:   transfer1.ID,
+		Limit:     2,
+	}
+
+	// the three transfers created after transfer1 are split over two pages
+	page1, err := testQueries.ListTransfersAfter(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, page1, 2)
+	require.Greater(t, page1[0].ID, transfer1.ID)
+	require.Greater(t, page1[1].ID, page1[0].ID)
+
+	arg.AfterID = page1[1].ID
+	page2, err := testQueries.ListTransfersAfter(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, page2, 1)
+	require.Greater(t, page2[0].ID, page1[1].ID)
+}
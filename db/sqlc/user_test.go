@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 	"time"
 
@@ -34,6 +35,8 @@ func createRandomUser(t *testing.T) User {
 
 	// confirm that the new user's password_change_at value is the default zero value
 	require.True(t, user.PasswordChangeAt.IsZero())
+	// every new user is a depositor
+	require.Equal(t, util.DepositorRole, user.Role)
 	// postgres db should be auto generating the correct time stamp
 	// require.NotZero() asserts that a value must not be a zero value of its type
 	require.NotZero(t, user.CreatedAt)
@@ -62,3 +65,85 @@ func TestGetUser(t *testing.T) {
 	// require that user2's CreatedAt field has a value within one second of user1's CreatedAt field value
 	require.WithinDuration(t, user1.CreatedAt, user2.CreatedAt, time.Second)
 }
+
+func TestGetUserByEmail(t *testing.T) {
+	user1 := createRandomUser(t)
+
+	user2, err := testQueries.GetUserByEmail(context.Background(), user1.Email)
+	require.NoError(t, err)
+	require.Equal(t, user1.Username, user2.Username)
+	require.Equal(t, user1.Email, user2.Email)
+
+	_, err = testQueries.GetUserByEmail(context.Background(), util.RandomEmail())
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestUpdateUserOnlyFullName(t *testing.T) {
+	oldUser := createRandomUser(t)
+
+	newFullName := util.RandomOwner()
+	updatedUser, err := testQueries.UpdateUser(context.Background(), UpdateUserParams{
+		Username: oldUser.Username,
+		FullName: sql.NullString{String: newFullName, Valid: true},
+	})
+	require.NoError(t, err)
+
+	// the fields which weren't set keep their values
+	require.Equal(t, newFullName, updatedUser.FullName)
+	require.Equal(t, oldUser.Email, updatedUser.Email)
+	require.Equal(t, oldUser.HashedPassword, updatedUser.HashedPassword)
+	require.WithinDuration(t, oldUser.PasswordChangeAt, updatedUser.PasswordChangeAt, time.Second)
+}
+
+func TestUpdateUserOnlyPassword(t *testing.T) {
+	oldUser := createRandomUser(t)
+
+	newHashedPassword, err := util.HashPassword(util.RandomString(6))
+	require.NoError(t, err)
+	passwordChangeAt := time.Now()
+
+	updatedUser, err := testQueries.UpdateUser(context.Background(), UpdateUserParams{
+		Username:         oldUser.Username,
+		HashedPassword:   sql.NullString{String: newHashedPassword, Valid: true},
+		PasswordChangeAt: sql.NullTime{Time: passwordChangeAt, Valid: true},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, newHashedPassword, updatedUser.HashedPassword)
+	require.WithinDuration(t, passwordChangeAt, updatedUser.PasswordChangeAt, time.Second)
+	require.Equal(t, oldUser.FullName, updatedUser.FullName)
+	require.Equal(t, oldUser.Email, updatedUser.Email)
+}
+
+func TestUpdateUserNotFound(t *testing.T) {
+	_, err := testQueries.UpdateUser(context.Background(), UpdateUserParams{
+		Username: util.RandomOwner(),
+		FullName: sql.NullString{String: util.RandomOwner(), Valid: true},
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestUpdateUserPasswordHash(t *testing.T) {
+	oldUser := createRandomUser(t)
+
+	newHashedPassword, err := util.HashPassword(util.RandomString(6))
+	require.NoError(t, err)
+
+	updatedUser, err := testQueries.UpdateUserPasswordHash(context.Background(), UpdateUserPasswordHashParams{
+		Username:          oldUser.Username,
+		OldHashedPassword: oldUser.HashedPassword,
+		NewHashedPassword: newHashedPassword,
+	})
+	require.NoError(t, err)
+	require.Equal(t, newHashedPassword, updatedUser.HashedPassword)
+	// the password itself didn't change
+	require.WithinDuration(t, oldUser.PasswordChangeAt, updatedUser.PasswordChangeAt, time.Second)
+
+	// the old hash no longer matches, so a second rehash from it doesn't overwrite the new hash
+	_, err = testQueries.UpdateUserPasswordHash(context.Background(), UpdateUserPasswordHashParams{
+		Username:          oldUser.Username,
+		OldHashedPassword: oldUser.HashedPassword,
+		NewHashedPassword: oldUser.HashedPassword,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -7,6 +7,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 )
 
 const createUser = `-- name: CreateUser :one
@@ -18,7 +19,7 @@ INSERT INTO users (
 ) VALUES (
   $1, $2, $3, $4
 )
-RETURNING username, hashed_password, full_name, email, password_change_at, created_at
+RETURNING username, hashed_password, full_name, email, password_change_at, created_at, role, is_email_verified
 `
 
 type CreateUserParams struct {
@@ -43,12 +44,14 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Email,
 		&i.PasswordChangeAt,
 		&i.CreatedAt,
+		&i.Role,
+		&i.IsEmailVerified,
 	)
 	return i, err
 }
 
 const getUser = `-- name: GetUser :one
-SELECT username, hashed_password, full_name, email, password_change_at, created_at FROM users
+SELECT username, hashed_password, full_name, email, password_change_at, created_at, role, is_email_verified FROM users
 WHERE username = $1 LIMIT 1
 `
 
@@ -62,6 +65,134 @@ func (q *Queries) GetUser(ctx context.Context, username string) (User, error) {
 		&i.Email,
 		&i.PasswordChangeAt,
 		&i.CreatedAt,
+		&i.Role,
+		&i.IsEmailVerified,
+	)
+	return i, err
+}
+
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT username, hashed_password, full_name, email, password_change_at, created_at, role, is_email_verified FROM users
+WHERE email = $1 LIMIT 1
+`
+
+func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByEmail, email)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangeAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.IsEmailVerified,
+	)
+	return i, err
+}
+
+const markEmailVerified = `-- name: MarkEmailVerified :one
+UPDATE users
+SET is_email_verified = true
+WHERE username = $1 AND email = $2
+RETURNING username, hashed_password, full_name, email, password_change_at, created_at, role, is_email_verified
+`
+
+type MarkEmailVerifiedParams struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+// no row is returned if the email of the user has changed since the verification email was sent
+func (q *Queries) MarkEmailVerified(ctx context.Context, arg MarkEmailVerifiedParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, markEmailVerified, arg.Username, arg.Email)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangeAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.IsEmailVerified,
+	)
+	return i, err
+}
+
+const updateUser = `-- name: UpdateUser :one
+UPDATE users
+SET
+  hashed_password = COALESCE($1, hashed_password),
+  password_change_at = COALESCE($2, password_change_at),
+  full_name = COALESCE($3, full_name),
+  email = COALESCE($4, email),
+  is_email_verified = COALESCE($5, is_email_verified)
+WHERE username = $6
+RETURNING username, hashed_password, full_name, email, password_change_at, created_at, role, is_email_verified
+`
+
+type UpdateUserParams struct {
+	HashedPassword   sql.NullString `json:"hashed_password"`
+	PasswordChangeAt sql.NullTime   `json:"password_change_at"`
+	FullName         sql.NullString `json:"full_name"`
+	Email            sql.NullString `json:"email"`
+	IsEmailVerified  sql.NullBool   `json:"is_email_verified"`
+	Username         string         `json:"username"`
+}
+
+// every field is optional - a NULL argument keeps the current value
+func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUser,
+		arg.HashedPassword,
+		arg.PasswordChangeAt,
+		arg.FullName,
+		arg.Email,
+		arg.IsEmailVerified,
+		arg.Username,
+	)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangeAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.IsEmailVerified,
+	)
+	return i, err
+}
+
+const updateUserPasswordHash = `-- name: UpdateUserPasswordHash :one
+UPDATE users
+SET hashed_password = $1
+WHERE username = $2 AND hashed_password = $3
+RETURNING username, hashed_password, full_name, email, password_change_at, created_at, role, is_email_verified
+`
+
+type UpdateUserPasswordHashParams struct {
+	NewHashedPassword string `json:"new_hashed_password"`
+	Username          string `json:"username"`
+	OldHashedPassword string `json:"old_hashed_password"`
+}
+
+// replaces the hash of the password with a new hash of the same password, e.g. with a stronger algorithm - password_change_at
+// is kept as the password didn't change - no row is returned if the password was changed since the old hash was read
+func (q *Queries) UpdateUserPasswordHash(ctx context.Context, arg UpdateUserPasswordHashParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserPasswordHash, arg.NewHashedPassword, arg.Username, arg.OldHashedPassword)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangeAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.IsEmailVerified,
 	)
 	return i, err
 }
@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"SimpleBankProject/db/util"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomVerifyEmail(t *testing.T, user User) VerifyEmail {
+	arg := CreateVerifyEmailParams{
+		Username:       user.Username,
+		Email:          user.Email,
+		SecretCodeHash: util.HashSecretToken(util.RandomString(32)),
+	}
+
+	verifyEmail, err := testQueries.CreateVerifyEmail(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, verifyEmail.ID)
+	require.Equal(t, arg.Username, verifyEmail.Username)
+	require.Equal(t, arg.Email, verifyEmail.Email)
+	require.Equal(t, arg.SecretCodeHash, verifyEmail.SecretCodeHash)
+	require.False(t, verifyEmail.IsUsed)
+	require.NotZero(t, verifyEmail.CreatedAt)
+	// the code expires 15 minutes after it is created
+	require.WithinDuration(t, verifyEmail.CreatedAt.Add(15*time.Minute), verifyEmail.ExpiredAt, time.Second)
+
+	return verifyEmail
+}
+
+func TestCreateVerifyEmail(t *testing.T) {
+	createRandomVerifyEmail(t, createRandomUser(t))
+}
+
+func TestUpdateVerifyEmail(t *testing.T) {
+	verifyEmail1 := createRandomVerifyEmail(t, createRandomUser(t))
+
+	verifyEmail2, err := testQueries.UpdateVerifyEmail(context.Background(), UpdateVerifyEmailParams{
+		ID:             verifyEmail1.ID,
+		SecretCodeHash: verifyEmail1.SecretCodeHash,
+	})
+	require.NoError(t, err)
+	require.Equal(t, verifyEmail1.ID, verifyEmail2.ID)
+	require.True(t, verifyEmail2.IsUsed)
+
+	// a used code can't be used again
+	_, err = testQueries.UpdateVerifyEmail(context.Background(), UpdateVerifyEmailParams{
+		ID:             verifyEmail1.ID,
+		SecretCodeHash: verifyEmail1.SecretCodeHash,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestMarkEmailVerified(t *testing.T) {
+	user1 := createRandomUser(t)
+
+	user2, err := testQueries.MarkEmailVerified(context.Background(), MarkEmailVerifiedParams{
+		Username: user1.Username,
+		Email:    user1.Email,
+	})
+	require.NoError(t, err)
+	require.True(t, user2.IsEmailVerified)
+
+	// an email which isn't the email of the user is never verified
+	_, err = testQueries.MarkEmailVerified(context.Background(), MarkEmailVerifiedParams{
+		Username: user1.Username,
+		Email:    util.RandomEmail(),
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -0,0 +1,27 @@
+package util
+
+const (
+	// APIKeyPrefix starts every API key, so a leaked key is easy to recognize (e.g. by secret scanners)
+	APIKeyPrefix = "sbk_"
+	// APIKeyLength is the length of the keys created by NewAPIKey
+	APIKeyLength = len(APIKeyPrefix) + SecretTokenLength
+	// the number of characters of a key which are stored in the clear, so the user can tell their keys apart
+	apiKeyDisplayLength = len(APIKeyPrefix) + 8
+)
+
+// NewAPIKey returns a new random API key - like the secret tokens, only its hash (see HashSecretToken) is stored
+func NewAPIKey() (string, error) {
+	secret, err := NewSecretToken()
+	if err != nil {
+		return "", err
+	}
+	return APIKeyPrefix + secret, nil
+}
+
+// APIKeyDisplayPrefix returns the start of the key which is shown in the list of the user's keys
+func APIKeyDisplayPrefix(key string) string {
+	if len(key) < apiKeyDisplayLength {
+		return key
+	}
+	return key[:apiKeyDisplayLength]
+}
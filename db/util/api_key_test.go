@@ -0,0 +1,25 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAPIKey(t *testing.T) {
+	key1, err := NewAPIKey()
+	require.NoError(t, err)
+	require.Len(t, key1, APIKeyLength)
+	require.True(t, strings.HasPrefix(key1, APIKeyPrefix))
+
+	key2, err := NewAPIKey()
+	require.NoError(t, err)
+	require.NotEqual(t, key1, key2)
+
+	// only the start of the key is shown
+	prefix := APIKeyDisplayPrefix(key1)
+	require.True(t, strings.HasPrefix(key1, prefix))
+	require.Less(t, len(prefix), len(key1))
+	require.Equal(t, "sbk", APIKeyDisplayPrefix("sbk"))
+}
@@ -10,13 +10,75 @@ import (
 // in order to get the values and store them here - we use viper's unmarshaling feature
 // viper uses the mapstructure package for unmarshaling values
 type Config struct {
-	DBDriver             string        `mapstructure:"DB_DRIVER"` // mapstructure tags for unmarshaling values
-	DBSource             string        `mapstructure:"DB_SOURCE"`
-	HTTPServerAddress    string        `mapstructure:"HTTP_SERVER_ADDRESS"`
-	GRPCServerAddress    string        `mapstructure:"GRPC_SERVER_ADDRESS"`
-	TokenSymmetricKey    string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
-	AccessTokenDuration  time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
-	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	DBDriver          string `mapstructure:"DB_DRIVER"` // mapstructure tags for unmarshaling values
+	DBSource          string `mapstructure:"DB_SOURCE"`
+	HTTPServerAddress string `mapstructure:"HTTP_SERVER_ADDRESS"`
+	GRPCServerAddress string `mapstructure:"GRPC_SERVER_ADDRESS"`
+	// the token maker which creates and verifies the tokens - "paseto" (the default) and "jwt" use TokenSymmetricKey,
+	// "paseto_public" signs tokens with TokenPrivateKey so other services can verify them with the public keys alone
+	TokenMaker        string `mapstructure:"TOKEN_MAKER"`
+	TokenSymmetricKey string `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+	// the hex encoded 32 byte seed of the Ed25519 key "paseto_public" signs tokens with
+	TokenPrivateKey string `mapstructure:"TOKEN_PRIVATE_KEY"`
+	// comma separated hex encoded public keys of earlier private keys - their tokens are still accepted after a rotation
+	TokenVerificationKeys string        `mapstructure:"TOKEN_VERIFICATION_KEYS"`
+	AccessTokenDuration   time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	RefreshTokenDuration  time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	// how long a server trusts a "not revoked" answer of the token denylist before asking the database again - 0 asks the
+	// database on every request
+	TokenDenylistCacheDuration time.Duration `mapstructure:"TOKEN_DENYLIST_CACHE_DURATION"`
+	// how often the revoked tokens which have expired are deleted from the database
+	TokenDenylistCleanupInterval time.Duration `mapstructure:"TOKEN_DENYLIST_CLEANUP_INTERVAL"`
+	// the SMTP server which sends the emails of the bank - leave the username empty if it doesn't need authentication
+	SMTPAddress        string `mapstructure:"SMTP_ADDRESS"`
+	SMTPUsername       string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword       string `mapstructure:"SMTP_PASSWORD"`
+	EmailSenderName    string `mapstructure:"EMAIL_SENDER_NAME"`
+	EmailSenderAddress string `mapstructure:"EMAIL_SENDER_ADDRESS"`
+	// the verify email route the link in the verification email points to - the email id and secret code are added as
+	// query parameters
+	VerifyEmailURL string `mapstructure:"VERIFY_EMAIL_URL"`
+	// when true, users must verify their email before they can create accounts or transfer money
+	RequireVerifiedEmail bool `mapstructure:"REQUIRE_VERIFIED_EMAIL"`
+	// the page the link in the password reset email points to - the token is added as a query parameter
+	PasswordResetURL string `mapstructure:"PASSWORD_RESET_URL"`
+	// how long the token in a password reset email can be used
+	PasswordResetTokenDuration time.Duration `mapstructure:"PASSWORD_RESET_TOKEN_DURATION"`
+	// the key which encrypts the TOTP secrets of two-factor authentication - must be exactly 32 characters
+	TOTPEncryptionKey string `mapstructure:"TOTP_ENCRYPTION_KEY"`
+	// the name authenticator apps show next to the codes of the bank
+	TOTPIssuer string `mapstructure:"TOTP_ISSUER"`
+	// how long a user with two-factor authentication has to send a TOTP code after their password was checked
+	MFAChallengeDuration time.Duration `mapstructure:"MFA_CHALLENGE_DURATION"`
+	// the wrong codes allowed per MFA challenge token before it is denied and the user has to login with their password
+	// again - 0 disables the limit, the wrong codes still count as failed logins of the user
+	MFAMaxFailures int64 `mapstructure:"MFA_MAX_FAILURES"`
+	// the failed login attempts allowed per username and per client IP before logins are locked out - 0 disables the lockout
+	LoginMaxFailuresPerUser int64 `mapstructure:"LOGIN_MAX_FAILURES_PER_USER"`
+	LoginMaxFailuresPerIP   int64 `mapstructure:"LOGIN_MAX_FAILURES_PER_IP"`
+	// how long the first lockout lasts - every further failed attempt doubles it, up to the maximum
+	LoginLockoutDuration    time.Duration `mapstructure:"LOGIN_LOCKOUT_DURATION"`
+	LoginMaxLockoutDuration time.Duration `mapstructure:"LOGIN_MAX_LOCKOUT_DURATION"`
+	// how far back the failed login attempts are counted
+	LoginFailureWindow time.Duration `mapstructure:"LOGIN_FAILURE_WINDOW"`
+	// the request rate limits of the route groups, written as <requests>/<s|m|h> (e.g. 10/m) - requests are counted per
+	// logged in user, or per client IP if there is none (e.g. the auth routes: create user, login, password reset, ...) -
+	// empty disables the limit
+	RateLimitAuth      string `mapstructure:"RATE_LIMIT_AUTH"`
+	RateLimitTransfers string `mapstructure:"RATE_LIMIT_TRANSFERS"`
+	RateLimitDefault   string `mapstructure:"RATE_LIMIT_DEFAULT"`
+	// the rate limit of every request of a client IP, counted before the access token or API key is checked - it must be
+	// high enough for the logged in users sharing an IP (e.g. behind a NAT) - empty disables the limit
+	RateLimitClientIP string `mapstructure:"RATE_LIMIT_CLIENT_IP"`
+	// the algorithm new passwords are hashed with - "bcrypt" (the default) or "argon2id" - the hashes of a login with an
+	// older algorithm or cost are replaced with a new hash
+	PasswordHashAlgorithm string `mapstructure:"PASSWORD_HASH_ALGORITHM"`
+	// the cost of bcrypt hashes - 0 uses bcrypt.DefaultCost
+	PasswordBcryptCost int `mapstructure:"PASSWORD_BCRYPT_COST"`
+	// the iterations, memory (in KiB) and threads of Argon2id hashes - 0 uses the minimum recommended by OWASP
+	PasswordArgon2Time    uint32 `mapstructure:"PASSWORD_ARGON2_TIME"`
+	PasswordArgon2Memory  uint32 `mapstructure:"PASSWORD_ARGON2_MEMORY"`
+	PasswordArgon2Threads uint8  `mapstructure:"PASSWORD_ARGON2_THREADS"`
 }
 
 // LoadConfig reads configuration from file in the path if it exists or overrides the config values with env vars if provided
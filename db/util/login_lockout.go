@@ -0,0 +1,37 @@
+package util
+
+import "time"
+
+// the reasons a login attempt failed, as recorded in the login_attempts table
+const (
+	LoginFailureUnknownUser   = "unknown_user"
+	LoginFailureWrongPassword = "wrong_password"
+	LoginFailureLockedOut     = "locked_out"
+	LoginFailureWrongMFACode  = "wrong_mfa_code" // a wrong TOTP or recovery code sent with an MFA challenge token
+)
+
+// LoginLockout locks out logins after too many failed attempts - the first lockout lasts Duration and every further failed
+// attempt doubles it, up to MaxDuration
+type LoginLockout struct {
+	MaxFailures int64 // the number of failed attempts allowed before the first lockout - 0 disables the lockout
+	Duration    time.Duration
+	MaxDuration time.Duration
+}
+
+// LockedUntil returns when the lockout after the failed attempts ends - the zero time if there are too few failures
+func (lockout LoginLockout) LockedUntil(failures int64, lastFailedAt time.Time) time.Time {
+	if lockout.MaxFailures <= 0 || failures < lockout.MaxFailures {
+		return time.Time{}
+	}
+
+	duration := lockout.Duration
+	// stop doubling once the maximum is reached, so the duration can't overflow
+	for i := lockout.MaxFailures; i < failures && duration < lockout.MaxDuration; i++ {
+		duration *= 2
+	}
+	if duration > lockout.MaxDuration {
+		duration = lockout.MaxDuration
+	}
+
+	return lastFailedAt.Add(duration)
+}
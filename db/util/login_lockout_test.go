@@ -0,0 +1,34 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginLockout(t *testing.T) {
+	lockout := LoginLockout{
+		MaxFailures: 3,
+		Duration:    time.Minute,
+		MaxDuration: 10 * time.Minute,
+	}
+	lastFailedAt := time.Now()
+
+	// the attempts before the maximum aren't locked out
+	require.True(t, lockout.LockedUntil(0, lastFailedAt).IsZero())
+	require.True(t, lockout.LockedUntil(2, lastFailedAt).IsZero())
+
+	// then every failed attempt doubles the lockout
+	require.Equal(t, lastFailedAt.Add(time.Minute), lockout.LockedUntil(3, lastFailedAt))
+	require.Equal(t, lastFailedAt.Add(2*time.Minute), lockout.LockedUntil(4, lastFailedAt))
+	require.Equal(t, lastFailedAt.Add(8*time.Minute), lockout.LockedUntil(6, lastFailedAt))
+
+	// up to the maximum duration
+	require.Equal(t, lastFailedAt.Add(10*time.Minute), lockout.LockedUntil(7, lastFailedAt))
+	require.Equal(t, lastFailedAt.Add(10*time.Minute), lockout.LockedUntil(1000, lastFailedAt))
+
+	// no maximum number of failures disables the lockout
+	lockout.MaxFailures = 0
+	require.True(t, lockout.LockedUntil(1000, lastFailedAt).IsZero())
+}
@@ -0,0 +1,44 @@
+package util
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// prefix of every page token - lets us change the format later without misreading old tokens
+const pageTokenPrefix = "id:"
+
+// ErrInvalidPageToken is returned by DecodePageToken when the token wasn't created by EncodePageToken
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// EncodePageToken returns the opaque token clients send to get the page after the row with the input id - clients must not
+// rely on what is inside the token
+func EncodePageToken(lastID int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(pageTokenPrefix + strconv.FormatInt(lastID, 10)))
+}
+
+// DecodePageToken returns the id of the last row of the previous page - an empty token means the first page, which
+// starts after id 0
+func DecodePageToken(token string) (int64, error) {
+	if token == "" {
+		return 0, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, ErrInvalidPageToken
+	}
+
+	value := string(data)
+	if !strings.HasPrefix(value, pageTokenPrefix) {
+		return 0, ErrInvalidPageToken
+	}
+
+	lastID, err := strconv.ParseInt(strings.TrimPrefix(value, pageTokenPrefix), 10, 64)
+	if err != nil || lastID < 1 {
+		return 0, ErrInvalidPageToken
+	}
+	return lastID, nil
+}
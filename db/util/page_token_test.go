@@ -0,0 +1,40 @@
+package util
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageToken(t *testing.T) {
+	lastID := RandomInt(1, 1000000)
+
+	token := EncodePageToken(lastID)
+	require.NotEmpty(t, token)
+
+	decodedID, err := DecodePageToken(token)
+	require.NoError(t, err)
+	require.Equal(t, lastID, decodedID)
+
+	// an empty token is the first page
+	decodedID, err = DecodePageToken("")
+	require.NoError(t, err)
+	require.Zero(t, decodedID)
+}
+
+func TestInvalidPageToken(t *testing.T) {
+	tokens := []string{
+		"not base64!",
+		base64.RawURLEncoding.EncodeToString([]byte("123")),      // missing prefix
+		base64.RawURLEncoding.EncodeToString([]byte("id:abc")),   // not a number
+		base64.RawURLEncoding.EncodeToString([]byte("id:0")),     // ids start at 1
+		base64.RawURLEncoding.EncodeToString([]byte("id:-5")),    // negative
+		base64.StdEncoding.EncodeToString([]byte("id:12345678")), // padded encoding
+	}
+
+	for _, token := range tokens {
+		_, err := DecodePageToken(token)
+		require.ErrorIs(t, err, ErrInvalidPageToken, token)
+	}
+}
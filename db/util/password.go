@@ -1,15 +1,116 @@
 package util
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
 
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
-// HashPassword returns the bcrypt hash of the password and an error
+// the algorithms PasswordHasher can hash passwords with - the hashes carry the algorithm and its parameters, so
+// CheckPassword can check hashes of every algorithm and cost, whatever the config is now
+const (
+	PasswordHashBcrypt   = "bcrypt"
+	PasswordHashArgon2id = "argon2id"
+)
+
+// the defaults of the parameters which aren't set in the config - the Argon2id defaults are the minimum recommended by OWASP
+const (
+	defaultArgon2Time    = 2
+	defaultArgon2Memory  = 19 * 1024 // KiB
+	defaultArgon2Threads = 1
+	argon2SaltLength     = 16
+	argon2KeyLength      = 32
+)
+
+// ErrUnknownPasswordHash is returned when a hash wasn't created by any of the supported algorithms
+var ErrUnknownPasswordHash = errors.New("unknown password hash format")
+
+// argon2Params are the parameters of an Argon2id hash, stored in the hash itself
+type argon2Params struct {
+	time    uint32
+	memory  uint32 // KiB
+	threads uint8
+	keyLen  uint32
+}
+
+// PasswordHasher hashes passwords with the algorithm and cost of the config - hashes created with other settings are still
+// accepted by CheckPassword, NeedsRehash tells which ones should be replaced
+type PasswordHasher struct {
+	algorithm  string
+	bcryptCost int
+	argon2     argon2Params
+
+	// the hash CheckPasswordOfUnknownUser compares passwords with - it is created the first time it is needed, as hashing
+	// takes a while
+	unknownUserHash     string
+	unknownUserHashOnce sync.Once
+}
+
+// defaultPasswordHasher is the hasher of HashPassword
+var defaultPasswordHasher = &PasswordHasher{algorithm: PasswordHashBcrypt, bcryptCost: bcrypt.DefaultCost}
+
+// NewPasswordHasher creates a PasswordHasher with the algorithm and cost of the config - bcrypt with the default cost if
+// they aren't set
+func NewPasswordHasher(config Config) (*PasswordHasher, error) {
+	hasher := &PasswordHasher{
+		algorithm:  config.PasswordHashAlgorithm,
+		bcryptCost: config.PasswordBcryptCost,
+		argon2: argon2Params{
+			time:    config.PasswordArgon2Time,
+			memory:  config.PasswordArgon2Memory,
+			threads: config.PasswordArgon2Threads,
+			keyLen:  argon2KeyLength,
+		},
+	}
+
+	if hasher.algorithm == "" {
+		hasher.algorithm = PasswordHashBcrypt
+	}
+	if hasher.algorithm != PasswordHashBcrypt && hasher.algorithm != PasswordHashArgon2id {
+		return nil, fmt.Errorf("unsupported password hash algorithm: %s", hasher.algorithm)
+	}
+
+	if hasher.bcryptCost == 0 {
+		hasher.bcryptCost = bcrypt.DefaultCost
+	}
+	if hasher.bcryptCost < bcrypt.MinCost || hasher.bcryptCost > bcrypt.MaxCost {
+		return nil, fmt.Errorf("invalid bcrypt cost %d: must be between %d and %d", hasher.bcryptCost, bcrypt.MinCost, bcrypt.MaxCost)
+	}
+
+	if hasher.argon2.time == 0 {
+		hasher.argon2.time = defaultArgon2Time
+	}
+	if hasher.argon2.memory == 0 {
+		hasher.argon2.memory = defaultArgon2Memory
+	}
+	if hasher.argon2.threads == 0 {
+		hasher.argon2.threads = defaultArgon2Threads
+	}
+
+	return hasher, nil
+}
+
+// HashPassword returns the bcrypt hash of the password with the default cost - the servers hash passwords with the
+// PasswordHasher of their config instead
 func HashPassword(password string) (string, error) {
-	// GenerateFromPassword requires the password be a slice of bytes, bcrypt.DefaultCost is 10
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	return defaultPasswordHasher.Hash(password)
+}
+
+// Hash returns the hash of the password - it starts with the algorithm and holds its parameters and salt
+func (hasher *PasswordHasher) Hash(password string) (string, error) {
+	if hasher.algorithm == PasswordHashArgon2id {
+		return hashArgon2id(password, hasher.argon2)
+	}
+
+	// GenerateFromPassword requires the password be a slice of bytes
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), hasher.bcryptCost)
 	if err != nil {
 		// return an empty string
 		return "", fmt.Errorf("failed to hash password: %w", err)
@@ -17,8 +118,131 @@ func HashPassword(password string) (string, error) {
 	return string(hashedPassword), nil
 }
 
-// CheckPassword checks the provided password against the hashedPassword to ensure it is correct
+// NeedsRehash returns true if the hash wasn't created with the algorithm and parameters of the hasher - after a successful
+// login, the password should be hashed again and the new hash stored
+func (hasher *PasswordHasher) NeedsRehash(hashedPassword string) bool {
+	switch passwordHashAlgorithm(hashedPassword) {
+	case PasswordHashBcrypt:
+		cost, err := bcrypt.Cost([]byte(hashedPassword))
+		return err != nil || hasher.algorithm != PasswordHashBcrypt || cost != hasher.bcryptCost
+	case PasswordHashArgon2id:
+		params, _, _, err := decodeArgon2id(hashedPassword)
+		return err != nil || hasher.algorithm != PasswordHashArgon2id || params != hasher.argon2
+	default:
+		return true
+	}
+}
+
+// CheckPassword checks the provided password against the hashedPassword to ensure it is correct - the hash may have been
+// created by any of the supported algorithms - a wrong password returns bcrypt.ErrMismatchedHashAndPassword whatever the
+// algorithm - the logins use PasswordHasher.CheckPassword instead
 func CheckPassword(password string, hashedPassword string) error {
-	// CompareHashAndPassword provided by the bcrypt package
-	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+	switch passwordHashAlgorithm(hashedPassword) {
+	case PasswordHashBcrypt:
+		// CompareHashAndPassword provided by the bcrypt package
+		return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+	case PasswordHashArgon2id:
+		return checkArgon2id(password, hashedPassword)
+	default:
+		return ErrUnknownPasswordHash
+	}
+}
+
+// CheckPassword checks the password against the hash like the package-level CheckPassword - the servers check passwords
+// with the hasher of their config, so a hash which none of the algorithms can check (e.g. a corrupted row) takes as long
+// to reject as a wrong password, as CheckPasswordOfUnknownUser does for an unknown user
+func (hasher *PasswordHasher) CheckPassword(password string, hashedPassword string) error {
+	err := CheckPassword(password, hashedPassword)
+	if err == ErrUnknownPasswordHash {
+		// the result is ignored, it is always a mismatch
+		_ = hasher.CheckPasswordOfUnknownUser(password)
+	}
+	return err
+}
+
+// CheckPasswordOfUnknownUser takes as long as checking the password of a user, but always fails - a login with an unknown
+// username must take as long as one with a wrong password, or the response time would tell which usernames exist
+func (hasher *PasswordHasher) CheckPasswordOfUnknownUser(password string) error {
+	hasher.unknownUserHashOnce.Do(func() {
+		// the error is ignored, the comparison below fails with an empty hash as well
+		hasher.unknownUserHash, _ = hasher.Hash(RandomString(32))
+	})
+	if err := CheckPassword(password, hasher.unknownUserHash); err != nil && err != ErrUnknownPasswordHash {
+		return err
+	}
+	return bcrypt.ErrMismatchedHashAndPassword
+}
+
+// passwordHashAlgorithm returns the algorithm which created the hash from its prefix - an empty string if it is unknown
+func passwordHashAlgorithm(hashedPassword string) string {
+	switch {
+	case strings.HasPrefix(hashedPassword, "$2a$"), strings.HasPrefix(hashedPassword, "$2b$"), strings.HasPrefix(hashedPassword, "$2y$"):
+		return PasswordHashBcrypt
+	case strings.HasPrefix(hashedPassword, "$"+PasswordHashArgon2id+"$"):
+		return PasswordHashArgon2id
+	default:
+		return ""
+	}
+}
+
+// hashArgon2id returns the Argon2id hash of the password in the PHC string format:
+// $argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<key>
+func hashArgon2id(password string, params argon2Params) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	key := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.threads, params.keyLen)
+
+	return fmt.Sprintf(
+		"$%s$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		PasswordHashArgon2id,
+		argon2.Version,
+		params.memory,
+		params.time,
+		params.threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// decodeArgon2id splits an Argon2id hash in the PHC string format into its parameters, salt and key
+func decodeArgon2id(hashedPassword string) (params argon2Params, salt []byte, key []byte, err error) {
+	// the leading $ makes the first field empty
+	fields := strings.Split(hashedPassword, "$")
+	if len(fields) != 6 {
+		return params, nil, nil, ErrUnknownPasswordHash
+	}
+
+	var version int
+	if _, err = fmt.Sscanf(fields[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("unsupported argon2 version: %s", fields[2])
+	}
+	if _, err = fmt.Sscanf(fields[3], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads); err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2 parameters: %w", err)
+	}
+	if salt, err = base64.RawStdEncoding.DecodeString(fields[4]); err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2 salt: %w", err)
+	}
+	if key, err = base64.RawStdEncoding.DecodeString(fields[5]); err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2 key: %w", err)
+	}
+	params.keyLen = uint32(len(key))
+
+	return params, salt, key, nil
+}
+
+// checkArgon2id hashes the password with the parameters and salt of the Argon2id hash and compares the keys in constant time
+func checkArgon2id(password string, hashedPassword string) error {
+	params, salt, key, err := decodeArgon2id(hashedPassword)
+	if err != nil {
+		return err
+	}
+
+	otherKey := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.threads, params.keyLen)
+	if subtle.ConstantTimeCompare(key, otherKey) != 1 {
+		return bcrypt.ErrMismatchedHashAndPassword
+	}
+	return nil
 }
@@ -1,6 +1,7 @@
 package util
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -31,3 +32,136 @@ func TestPassword(t *testing.T) {
 	require.NotEmpty(t, hashedPassword2)
 	require.NotEqual(t, hashedPassword1, hashedPassword2)
 }
+
+// newTestPasswordHasher returns a hasher with low costs, so the tests don't take long
+func newTestPasswordHasher(t *testing.T, algorithm string, bcryptCost int, argon2Time uint32) *PasswordHasher {
+	hasher, err := NewPasswordHasher(Config{
+		PasswordHashAlgorithm: algorithm,
+		PasswordBcryptCost:    bcryptCost,
+		PasswordArgon2Time:    argon2Time,
+		PasswordArgon2Memory:  1024,
+	})
+	require.NoError(t, err)
+	return hasher
+}
+
+func TestPasswordHasher(t *testing.T) {
+	testCases := []struct {
+		name   string
+		hasher *PasswordHasher
+		prefix string
+	}{
+		{
+			name:   "Bcrypt",
+			hasher: newTestPasswordHasher(t, PasswordHashBcrypt, bcrypt.MinCost, 0),
+			prefix: "$2a$04$",
+		},
+		{
+			name:   "Argon2id",
+			hasher: newTestPasswordHasher(t, PasswordHashArgon2id, 0, 1),
+			prefix: "$argon2id$v=19$m=1024,t=1,p=1$",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			password := RandomString(6)
+
+			hashedPassword1, err := tc.hasher.Hash(password)
+			require.NoError(t, err)
+			require.True(t, strings.HasPrefix(hashedPassword1, tc.prefix), hashedPassword1)
+			require.False(t, tc.hasher.NeedsRehash(hashedPassword1))
+
+			require.NoError(t, tc.hasher.CheckPassword(password, hashedPassword1))
+			err = tc.hasher.CheckPassword(RandomString(6), hashedPassword1)
+			require.EqualError(t, err, bcrypt.ErrMismatchedHashAndPassword.Error())
+
+			// the salt is random
+			hashedPassword2, err := tc.hasher.Hash(password)
+			require.NoError(t, err)
+			require.NotEqual(t, hashedPassword1, hashedPassword2)
+		})
+	}
+}
+
+func TestPasswordHasherNeedsRehash(t *testing.T) {
+	password := RandomString(6)
+	bcryptHasher := newTestPasswordHasher(t, PasswordHashBcrypt, bcrypt.MinCost, 0)
+	strongerBcryptHasher := newTestPasswordHasher(t, PasswordHashBcrypt, bcrypt.MinCost+1, 0)
+	argon2Hasher := newTestPasswordHasher(t, PasswordHashArgon2id, 0, 1)
+	strongerArgon2Hasher := newTestPasswordHasher(t, PasswordHashArgon2id, 0, 2)
+
+	bcryptHash, err := bcryptHasher.Hash(password)
+	require.NoError(t, err)
+	argon2Hash, err := argon2Hasher.Hash(password)
+	require.NoError(t, err)
+
+	// a higher cost or another algorithm needs a new hash
+	require.True(t, strongerBcryptHasher.NeedsRehash(bcryptHash))
+	require.True(t, argon2Hasher.NeedsRehash(bcryptHash))
+	require.True(t, strongerArgon2Hasher.NeedsRehash(argon2Hash))
+	require.True(t, bcryptHasher.NeedsRehash(argon2Hash))
+	require.True(t, bcryptHasher.NeedsRehash("plain text"))
+
+	// the old hashes are still accepted
+	require.NoError(t, strongerBcryptHasher.CheckPassword(password, bcryptHash))
+	require.NoError(t, argon2Hasher.CheckPassword(password, bcryptHash))
+	require.NoError(t, strongerArgon2Hasher.CheckPassword(password, argon2Hash))
+	require.NoError(t, bcryptHasher.CheckPassword(password, argon2Hash))
+}
+
+func TestCheckPasswordInvalidHash(t *testing.T) {
+	password := RandomString(6)
+
+	err := CheckPassword(password, "")
+	require.ErrorIs(t, err, ErrUnknownPasswordHash)
+	err = CheckPassword(password, password)
+	require.ErrorIs(t, err, ErrUnknownPasswordHash)
+	err = CheckPassword(password, "$argon2id$v=19$m=1024,t=1,p=1$salt")
+	require.ErrorIs(t, err, ErrUnknownPasswordHash)
+	err = CheckPassword(password, "$argon2id$v=16$m=1024,t=1,p=1$c2FsdA$a2V5")
+	require.Error(t, err)
+	err = CheckPassword(password, "$argon2id$v=19$m=1024$c2FsdA$a2V5")
+	require.Error(t, err)
+}
+
+func TestNewPasswordHasher(t *testing.T) {
+	hasher, err := NewPasswordHasher(Config{})
+	require.NoError(t, err)
+	require.Equal(t, PasswordHashBcrypt, hasher.algorithm)
+	require.Equal(t, bcrypt.DefaultCost, hasher.bcryptCost)
+	require.Equal(t, argon2Params{time: 2, memory: 19 * 1024, threads: 1, keyLen: 32}, hasher.argon2)
+
+	_, err = NewPasswordHasher(Config{PasswordHashAlgorithm: "md5"})
+	require.Error(t, err)
+	_, err = NewPasswordHasher(Config{PasswordBcryptCost: bcrypt.MaxCost + 1})
+	require.Error(t, err)
+}
+
+func TestCheckPasswordOfUnknownUser(t *testing.T) {
+	for _, hasher := range []*PasswordHasher{
+		newTestPasswordHasher(t, PasswordHashBcrypt, bcrypt.MinCost, 0),
+		newTestPasswordHasher(t, PasswordHashArgon2id, 0, 1),
+	} {
+		// whatever the password, it is wrong
+		err := hasher.CheckPasswordOfUnknownUser(RandomString(6))
+		require.EqualError(t, err, bcrypt.ErrMismatchedHashAndPassword.Error())
+		err = hasher.CheckPasswordOfUnknownUser("")
+		require.EqualError(t, err, bcrypt.ErrMismatchedHashAndPassword.Error())
+	}
+}
+
+func TestPasswordHasherCheckPasswordInvalidHash(t *testing.T) {
+	password := RandomString(6)
+	hasher := newTestPasswordHasher(t, PasswordHashArgon2id, 0, 1)
+
+	// a hash none of the algorithms can check is rejected, after taking as long as a wrong password
+	err := hasher.CheckPassword(password, "")
+	require.ErrorIs(t, err, ErrUnknownPasswordHash)
+	require.NotEmpty(t, hasher.unknownUserHash)
+
+	err = hasher.CheckPassword(password, password)
+	require.ErrorIs(t, err, ErrUnknownPasswordHash)
+}
@@ -40,7 +40,7 @@ func RandomOwner() string {
 
 // RandomMoney will generate a random amount of money
 func RandomMoney() int64 {
-	return RandomInt(0, 1000) // arbitrary min and max
+	return RandomInt(100, 1000) // arbitrary min and max - the min leaves enough money for the transfer tests in store_test.go
 }
 
 // RandomCurrency will generate a random currency code
@@ -0,0 +1,7 @@
+package util
+
+// list of user roles - every new user is a depositor, admins (bank staff) are promoted directly in the database
+const (
+	DepositorRole = "depositor"
+	AdminRole     = "admin"
+)
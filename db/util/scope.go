@@ -0,0 +1,17 @@
+package util
+
+// list of API key scopes - an API key may only call the routes of its scopes, access tokens may call every route
+const (
+	AccountsReadScope   = "accounts:read"   // read accounts and their entries and transfers
+	AccountsWriteScope  = "accounts:write"  // create and delete accounts, deposit and withdraw money
+	TransfersWriteScope = "transfers:write" // transfer money and reverse transfers
+)
+
+// IsSupportedScope returns true if the scope is supported, false otherwise
+func IsSupportedScope(scope string) bool {
+	switch scope {
+	case AccountsReadScope, AccountsWriteScope, TransfersWriteScope:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package util
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+const (
+	// number of random bytes in a secret token
+	secretTokenBytes = 32
+	// SecretTokenLength is the length of the tokens created by NewSecretToken - 32 bytes are encoded as 43 characters
+	SecretTokenLength = 43
+)
+
+// NewSecretToken returns a random token which can be sent to a user, e.g. in a password reset link - unlike RandomString it
+// uses crypto/rand, so the token can't be guessed
+func NewSecretToken() (string, error) {
+	b := make([]byte, secretTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to create secret token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashSecretToken returns the hex encoded SHA-256 hash of the token - only the hash is stored, so a leaked database doesn't
+// leak usable tokens
+// the tokens are random, so unlike passwords they don't need a slow, salted hash like bcrypt, and the hash can be looked up
+func HashSecretToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
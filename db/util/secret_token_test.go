@@ -0,0 +1,25 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretToken(t *testing.T) {
+	token1, err := NewSecretToken()
+	require.NoError(t, err)
+	require.Len(t, token1, SecretTokenLength)
+
+	// every token is different
+	token2, err := NewSecretToken()
+	require.NoError(t, err)
+	require.NotEqual(t, token1, token2)
+
+	// the hash of a token is always the same, so it can be looked up
+	hash1 := HashSecretToken(token1)
+	require.Len(t, hash1, 64)
+	require.NotEqual(t, token1, hash1)
+	require.Equal(t, hash1, HashSecretToken(token1))
+	require.NotEqual(t, hash1, HashSecretToken(token2))
+}
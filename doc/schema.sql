@@ -7,7 +7,9 @@ CREATE TABLE "accounts" (
   "owner" varchar NOT NULL,
   "balance" bigint NOT NULL,
   "currency" varchar NOT NULL,
-  "created_at" timestamptz NOT NULL DEFAULT 'now()'
+  "created_at" timestamptz NOT NULL DEFAULT 'now()',
+  "overdraft_limit" bigint NOT NULL DEFAULT 0,
+  "is_frozen" boolean NOT NULL DEFAULT false
 );
 
 CREATE TABLE "users" (
@@ -16,7 +18,9 @@ CREATE TABLE "users" (
   "full_name" varchar NOT NULL,
   "email" varchar UNIQUE NOT NULL,
   "password_change_at" timestamptz NOT NULL DEFAULT '0001-01-01 00:00:00+00',
-  "created_at" timestamptz NOT NULL DEFAULT 'now()'
+  "created_at" timestamptz NOT NULL DEFAULT 'now()',
+  "role" varchar NOT NULL DEFAULT 'depositor',
+  "is_email_verified" boolean NOT NULL DEFAULT false
 );
 
 CREATE TABLE "sessions" (
@@ -27,14 +31,19 @@ CREATE TABLE "sessions" (
   "client_ip" varchar NOT NULL,
   "is_blocked" boolean NOT NULL DEFAULT 'false',
   "expires_at" timestamptz NOT NULL,
-  "created_at" timestamptz NOT NULL DEFAULT 'now()'
+  "created_at" timestamptz NOT NULL DEFAULT 'now()',
+  "family_id" uuid NOT NULL,
+  "previous_session_id" uuid,
+  "rotated_at" timestamptz
 );
 
 CREATE TABLE "entries" (
   "id" bigserial PRIMARY KEY,
   "account_id" bigint NOT NULL,
   "amount" bigint NOT NULL,
-  "created_at" timestamptz NOT NULL DEFAULT 'now()'
+  "created_at" timestamptz NOT NULL DEFAULT 'now()',
+  "type" varchar NOT NULL DEFAULT 'transfer',
+  "external_reference" varchar
 );
 
 CREATE TABLE "transfers" (
@@ -42,31 +51,147 @@ CREATE TABLE "transfers" (
   "from_account_id" bigint NOT NULL,
   "to_account_id" bigint NOT NULL,
   "amount" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT 'now()',
+  "reversal_of" bigint UNIQUE
+);
+
+CREATE TABLE "idempotency_keys" (
+  "key" varchar NOT NULL,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "transfer_id" bigint NOT NULL,
+  "result" jsonb NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT 'now()',
+  PRIMARY KEY ("from_account_id", "key")
+);
+
+CREATE TABLE "revoked_tokens" (
+  "id" uuid PRIMARY KEY,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+CREATE TABLE "verify_emails" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "email" varchar NOT NULL,
+  "secret_code_hash" varchar NOT NULL,
+  "is_used" boolean NOT NULL DEFAULT false,
+  "created_at" timestamptz NOT NULL DEFAULT 'now()',
+  "expired_at" timestamptz NOT NULL DEFAULT (now() + interval '15 minutes')
+);
+
+CREATE TABLE "password_resets" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "token_hash" varchar UNIQUE NOT NULL,
+  "is_used" boolean NOT NULL DEFAULT false,
+  "created_at" timestamptz NOT NULL DEFAULT 'now()',
+  "expires_at" timestamptz NOT NULL
+);
+
+CREATE TABLE "user_mfa" (
+  "username" varchar PRIMARY KEY,
+  "encrypted_secret" varchar NOT NULL,
+  "is_enabled" boolean NOT NULL DEFAULT false,
+  "last_used_step" bigint NOT NULL DEFAULT 0,
+  "created_at" timestamptz NOT NULL DEFAULT 'now()',
+  "enabled_at" timestamptz
+);
+
+CREATE TABLE "mfa_recovery_codes" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "code_hash" varchar NOT NULL,
+  "used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+CREATE TABLE "api_keys" (
+  "id" uuid PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "name" varchar NOT NULL,
+  "key_prefix" varchar NOT NULL,
+  "key_hash" varchar UNIQUE NOT NULL,
+  "scopes" varchar[] NOT NULL,
+  "expires_at" timestamptz,
+  "revoked_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+CREATE TABLE "login_attempts" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "client_ip" varchar NOT NULL,
+  "user_agent" varchar NOT NULL,
+  "succeeded" boolean NOT NULL,
+  "failure_reason" varchar NOT NULL DEFAULT '',
   "created_at" timestamptz NOT NULL DEFAULT 'now()'
 );
 
 CREATE INDEX ON "accounts" ("owner");
 
+CREATE INDEX ON "sessions" ("family_id");
+
 CREATE UNIQUE INDEX ON "accounts" ("owner", "currency");
 
 CREATE INDEX ON "entries" ("account_id");
 
+CREATE UNIQUE INDEX ON "entries" ("type", "external_reference");
+
 CREATE INDEX ON "transfers" ("from_account_id");
 
 CREATE INDEX ON "transfers" ("to_account_id");
 
 CREATE INDEX ON "transfers" ("from_account_id", "to_account_id");
 
+CREATE INDEX ON "revoked_tokens" ("expires_at");
+
+CREATE INDEX ON "mfa_recovery_codes" ("username");
+
+CREATE INDEX ON "api_keys" ("username");
+
+CREATE INDEX ON "login_attempts" ("username", "created_at");
+
+CREATE INDEX ON "login_attempts" ("client_ip", "created_at");
+
+COMMENT ON COLUMN "accounts"."overdraft_limit" IS 'how far below zero the balance may go';
+
+COMMENT ON COLUMN "users"."role" IS 'depositor or admin';
+
+COMMENT ON COLUMN "sessions"."family_id" IS 'id of the first session of the login - shared by every rotated session';
+
+COMMENT ON COLUMN "sessions"."rotated_at" IS 'when the refresh token was replaced - using it again blocks the whole family';
+
 COMMENT ON COLUMN "entries"."amount" IS 'can be negative or positive';
 
+COMMENT ON COLUMN "entries"."type" IS 'transfer, deposit, or withdrawal';
+
 COMMENT ON COLUMN "transfers"."amount" IS 'must be positive';
 
 ALTER TABLE "accounts" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
 
 ALTER TABLE "sessions" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
 
+ALTER TABLE "sessions" ADD FOREIGN KEY ("previous_session_id") REFERENCES "sessions" ("id");
+
 ALTER TABLE "entries" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
 
 ALTER TABLE "transfers" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
 
 ALTER TABLE "transfers" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("reversal_of") REFERENCES "transfers" ("id");
+
+ALTER TABLE "idempotency_keys" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "verify_emails" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "password_resets" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "user_mfa" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "mfa_recovery_codes" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "api_keys" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
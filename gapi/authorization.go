@@ -0,0 +1,188 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/token"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// const variables used to find and parse the access token in the metadata of the incoming context
+// the gRPC gateway forwards the HTTP Authorization header to the metadata under the same (lower case) key and, when running
+// as a separate proxy, also under the grpcgateway- prefixed key
+const (
+	authorizationHeader            = "authorization"
+	grpcGatewayAuthorizationHeader = "grpcgateway-authorization"
+	authorizationBearer            = "bearer"
+	authorizationAPIKey            = "apikey"
+)
+
+// apiKeyMethodScopes maps the methods API keys may call to the scope they need - API keys can't call any other method, e.g.
+// they can't create more API keys or change the password of their user - it is the gRPC counterpart of apiKeyRouteScopes in
+// the api package
+var apiKeyMethodScopes = map[string]string{
+	fullMethodName("GetAccount"):      util.AccountsReadScope,
+	fullMethodName("ListAccounts"):    util.AccountsReadScope,
+	fullMethodName("ListEntries"):     util.AccountsReadScope,
+	fullMethodName("ListTransfers"):   util.AccountsReadScope,
+	fullMethodName("CreateAccount"):   util.AccountsWriteScope,
+	fullMethodName("DeleteAccount"):   util.AccountsWriteScope,
+	fullMethodName("Deposit"):         util.AccountsWriteScope,
+	fullMethodName("Withdraw"):        util.AccountsWriteScope,
+	fullMethodName("TransferMoney"):   util.TransfersWriteScope,
+	fullMethodName("ReverseTransfer"): util.TransfersWriteScope,
+}
+
+// authorizeUser returns the payload of the access token or API key sent with the request
+// calls made directly to the gRPC server have already been authenticated by the auth interceptors, which store the payload
+// in the context - calls made through the in-process gateway skip the interceptors, so the access token is verified here
+func (server *Server) authorizeUser(ctx context.Context) (*token.Payload, error) {
+	if payload, ok := authPayloadFromContext(ctx); ok {
+		return payload, nil
+	}
+	// the gateway stores the name of the method it calls in the context
+	method, _ := runtime.RPCMethod(ctx)
+	return server.verifyAuthorization(ctx, method)
+}
+
+// authorizeRole returns the payload of the access token sent with the request if the user has one of the input roles - the
+// returned error is already a gRPC status error - it is the gRPC counterpart of the roleMiddleware in the api package
+func (server *Server) authorizeRole(ctx context.Context, accessibleRoles ...string) (*token.Payload, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if !hasRole(payload, accessibleRoles...) {
+		return nil, status.Errorf(codes.PermissionDenied, "role %s is not allowed to call this method", payload.Role)
+	}
+	return payload, nil
+}
+
+// hasRole returns true if the access token was created for a user with one of the input roles
+func hasRole(payload *token.Payload, roles ...string) bool {
+	for _, role := range roles {
+		if payload.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+// isOwnerOrAdmin returns true if the logged in user owns the resource or is an admin - admins may read and manage the
+// resources of every user, depositors only their own
+func isOwnerOrAdmin(payload *token.Payload, owner string) bool {
+	return payload.Username == owner || hasRole(payload, util.AdminRole)
+}
+
+// requireVerifiedEmail returns a FailedPrecondition status error if the config requires a verified email (see
+// REQUIRE_VERIFIED_EMAIL in app.env) and the user hasn't verified theirs yet - it is the gRPC counterpart of the
+// verifiedEmailMiddleware in the api package
+func (server *Server) requireVerifiedEmail(ctx context.Context, username string) error {
+	if !server.config.RequireVerifiedEmail {
+		return nil
+	}
+
+	user, err := server.store.GetUser(ctx, username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return status.Errorf(codes.NotFound, "user not found: %s", err)
+		}
+		return status.Errorf(codes.Internal, "failed to get user: %s", err)
+	}
+
+	if !user.IsEmailVerified {
+		return status.Errorf(codes.FailedPrecondition, "email must be verified first")
+	}
+	return nil
+}
+
+// verifyAuthorization extracts the access token or API key from the metadata of the incoming context, verifies it and returns
+// its payload - API keys may only call the methods of their scopes (see apiKeyMethodScopes)
+// it is the gRPC counterpart of the authMiddleware in the api package
+func (server *Server) verifyAuthorization(ctx context.Context, method string) (*token.Payload, error) {
+	// metadata.FromIncomingContext returns the metadata in the context if it exists
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("missing metadata")
+	}
+
+	// the metadata is a map with string keys and values which are slices of strings
+	values := md.Get(authorizationHeader)
+	if len(values) == 0 {
+		values = md.Get(grpcGatewayAuthorizationHeader)
+	}
+	// if length is zero, the authorization header is empty
+	if len(values) == 0 {
+		return nil, fmt.Errorf("missing authorization header")
+	}
+
+	// the authorization header should have the prefix Bearer followed by a space and the access token
+	// strings.Fields() splits the authorization header by space
+	authHeader := values[0]
+	fields := strings.Fields(authHeader)
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("invalid authorization header format")
+	}
+
+	// strings.ToLower converts the authorization type to lower case - easier to compare
+	authType := strings.ToLower(fields[0])
+	switch authType {
+	case authorizationBearer:
+		// access token should be the second element of the fields slice
+		return server.verifyAccessToken(ctx, fields[1])
+	case authorizationAPIKey:
+		return server.verifyAPIKey(ctx, fields[1], method)
+	default:
+		return nil, fmt.Errorf("unsupported authorization type: %s", authType)
+	}
+}
+
+// verifyAccessToken verifies the access token and returns its payload
+func (server *Server) verifyAccessToken(ctx context.Context, accessToken string) (*token.Payload, error) {
+	payload, err := server.tokenMaker.VerifyToken(accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("invalid access token: %s", err)
+	}
+	// the token may have been revoked before it expired
+	err = token.CheckDenylist(ctx, server.denylist, payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid access token: %s", err)
+	}
+
+	return payload, nil
+}
+
+// verifyAPIKey looks up the API key and returns the payload of its user if the key has the scope of the method - a key
+// without the scope gets a PermissionDenied status error, which unauthenticatedError passes through
+func (server *Server) verifyAPIKey(ctx context.Context, apiKey string, method string) (*token.Payload, error) {
+	// only the hash of the key is stored
+	key, err := server.store.GetActiveAPIKeyByHash(ctx, util.HashSecretToken(apiKey))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("invalid API key")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get API key: %s", err)
+	}
+
+	// the role is the current role of the user, so demoting a user limits their keys as well
+	payload := token.NewAPIKeyPayload(key.ID, key.Username, key.Role, key.Scopes, key.CreatedAt, key.ExpiresAt.Time)
+
+	scope, ok := apiKeyMethodScopes[method]
+	if !ok {
+		return nil, status.Errorf(codes.PermissionDenied, "API keys are not allowed to call this method")
+	}
+	if !payload.HasScope(scope) {
+		return nil, status.Errorf(codes.PermissionDenied, "API key doesn't have the %s scope", scope)
+	}
+
+	return payload, nil
+}
@@ -13,7 +13,78 @@ func convertUser(user db.User) *pb.User {
 		Username:         user.Username,
 		FullName:         user.FullName,
 		Email:            user.Email,
+		Role:             user.Role,
+		IsEmailVerified:  user.IsEmailVerified,
 		PasswordChangeAt: timestamppb.New(user.PasswordChangeAt),
 		CreatedAt:        timestamppb.New(user.CreatedAt),
 	}
 }
+
+// converting from a db.Account object to a pb.Account object
+func convertAccount(account db.Account) *pb.Account {
+	return &pb.Account{
+		Id:             account.ID,
+		Owner:          account.Owner,
+		Balance:        account.Balance,
+		Currency:       account.Currency,
+		CreatedAt:      timestamppb.New(account.CreatedAt),
+		OverdraftLimit: account.OverdraftLimit,
+		IsFrozen:       account.IsFrozen,
+	}
+}
+
+// converting from a db.Transfer object to a pb.Transfer object
+func convertTransfer(transfer db.Transfer) *pb.Transfer {
+	return &pb.Transfer{
+		Id:            transfer.ID,
+		FromAccountId: transfer.FromAccountID,
+		ToAccountId:   transfer.ToAccountID,
+		Amount:        transfer.Amount,
+		CreatedAt:     timestamppb.New(transfer.CreatedAt),
+		ReversalOf:    transfer.ReversalOf.Int64, // 0 when the transfer isn't a reversal
+	}
+}
+
+// converting from a db.Entry object to a pb.Entry object
+func convertEntry(entry db.Entry) *pb.Entry {
+	return &pb.Entry{
+		Id:                entry.ID,
+		AccountId:         entry.AccountID,
+		Amount:            entry.Amount,
+		CreatedAt:         timestamppb.New(entry.CreatedAt),
+		Type:              entry.Type,
+		ExternalReference: entry.ExternalReference.String, // empty for transfers
+	}
+}
+
+// converting from a db.Session object to a pb.Session object - the refresh token is left out
+func convertSession(session db.Session) *pb.Session {
+	return &pb.Session{
+		Id:        session.ID.String(),
+		Username:  session.Username,
+		UserAgent: session.UserAgent,
+		ClientIp:  session.ClientIp,
+		IsBlocked: session.IsBlocked,
+		ExpiresAt: timestamppb.New(session.ExpiresAt),
+		CreatedAt: timestamppb.New(session.CreatedAt),
+	}
+}
+
+// convertAPIKey converts a db.ApiKey to a pb.APIKey - the hash of the key is left out
+func convertAPIKey(apiKey db.ApiKey) *pb.APIKey {
+	rsp := &pb.APIKey{
+		Id:        apiKey.ID.String(),
+		Username:  apiKey.Username,
+		Name:      apiKey.Name,
+		KeyPrefix: apiKey.KeyPrefix,
+		Scopes:    apiKey.Scopes,
+		CreatedAt: timestamppb.New(apiKey.CreatedAt),
+	}
+	if apiKey.ExpiresAt.Valid {
+		rsp.ExpiresAt = timestamppb.New(apiKey.ExpiresAt.Time)
+	}
+	if apiKey.RevokedAt.Valid {
+		rsp.RevokedAt = timestamppb.New(apiKey.RevokedAt.Time)
+	}
+	return rsp
+}
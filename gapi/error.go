@@ -1,9 +1,12 @@
 package gapi
 
 import (
+	"time"
+
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 // fieldViolation returns the BadRequest_FieldViolation struct with the field name (username, password, full name, etc.) and
@@ -31,3 +34,35 @@ func invalidArgumentError(violations []*errdetails.BadRequest_FieldViolation) er
 	// return a nil CreateUserResponse and the statusDetails error
 	return statusDetails.Err()
 }
+
+// unauthenticatedError wraps the error returned when the access token is missing or invalid with the Unauthenticated code
+// errors which already are status errors (e.g. PermissionDenied for an API key without the scope) are returned as they are
+func unauthenticatedError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.Errorf(codes.Unauthenticated, "unauthorized: %s", err)
+}
+
+// resourceExhaustedError returns the ResourceExhausted code with the time the client should wait before retrying in a
+// RetryInfo detail
+func resourceExhaustedError(message string, retryAfter time.Duration) error {
+	statusExhausted := status.New(codes.ResourceExhausted, message)
+	statusDetails, err := statusExhausted.WithDetails(&errdetails.RetryInfo{
+		RetryDelay: durationpb.New(retryAfter),
+	})
+	if err != nil {
+		return statusExhausted.Err()
+	}
+	return statusDetails.Err()
+}
+
+// retryDelay returns the time to wait before retrying from the RetryInfo detail of a status - 0 if it has none
+func retryDelay(st *status.Status) time.Duration {
+	for _, detail := range st.Details() {
+		if retryInfo, ok := detail.(*errdetails.RetryInfo); ok {
+			return retryInfo.GetRetryDelay().AsDuration()
+		}
+	}
+	return 0
+}
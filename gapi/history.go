@@ -0,0 +1,87 @@
+package gapi
+
+import (
+	"SimpleBankProject/token"
+	"SimpleBankProject/val"
+	"context"
+	"database/sql"
+	"fmt"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// minimum and maximum number of entries or transfers per page - matches the binding tags of accountHistoryQuery in the api
+// package
+const (
+	minHistoryPageSize = 5
+	maxHistoryPageSize = 50
+)
+
+// authorizeAccountOwner confirms the account exists and belongs to the logged in user, unless the user is an admin - the
+// returned error is already a gRPC status error
+func (server *Server) authorizeAccountOwner(ctx context.Context, accountID int64, payload *token.Payload) error {
+	account, err := server.store.GetAccount(ctx, accountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return status.Errorf(codes.NotFound, "account not found: %s", err)
+		}
+		return status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	if !isOwnerOrAdmin(payload, account.Owner) {
+		return status.Errorf(codes.PermissionDenied, "account doesn't belong to the authenticated user")
+	}
+	return nil
+}
+
+// historyFilters converts the optional filters of a history request to the nullable query arguments - a null argument
+// matches every row
+func historyFilters(start *timestamppb.Timestamp, end *timestamppb.Timestamp, sign string) (startTime sql.NullTime, endTime sql.NullTime, signFilter sql.NullString) {
+	if start != nil {
+		startTime = sql.NullTime{Time: start.AsTime(), Valid: true}
+	}
+	if end != nil {
+		endTime = sql.NullTime{Time: end.AsTime(), Valid: true}
+	}
+	if sign != "" {
+		signFilter = sql.NullString{String: sign, Valid: true}
+	}
+	return
+}
+
+// validateHistoryRequest validates the properties shared by ListEntriesRequest and ListTransfersRequest
+func validateHistoryRequest(
+	accountID int64,
+	pageID int32,
+	pageSize int32,
+	pageToken string,
+	start *timestamppb.Timestamp,
+	end *timestamppb.Timestamp,
+	sign string,
+) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(accountID); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+	// page_id is left out (0) for cursor pagination
+	if pageID != 0 {
+		if err := val.ValidatePageID(pageID); err != nil {
+			violations = append(violations, fieldViolation("page_id", err))
+		}
+	}
+	if err := val.ValidatePageSize(pageSize, minHistoryPageSize, maxHistoryPageSize); err != nil {
+		violations = append(violations, fieldViolation("page_size", err))
+	}
+	if err := val.ValidatePageToken(pageToken, pageID); err != nil {
+		violations = append(violations, fieldViolation("page_token", err))
+	}
+	if start != nil && end != nil && !end.AsTime().After(start.AsTime()) {
+		violations = append(violations, fieldViolation("end_time", fmt.Errorf("must be after start_time")))
+	}
+	if err := val.ValidateAmountSign(sign); err != nil {
+		violations = append(violations, fieldViolation("sign", err))
+	}
+	return violations
+}
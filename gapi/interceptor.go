@@ -0,0 +1,101 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+
+	"SimpleBankProject/pb"
+	"SimpleBankProject/token"
+
+	"google.golang.org/grpc"
+)
+
+// authPayloadKey is the key used to store the verified *token.Payload in the context - using an unexported type prevents
+// collisions with keys defined in other packages
+type authPayloadKey struct{}
+
+// publicMethods holds the full method names of the RPCs which can be called without an access token
+// everyone should be able to create a user and login (the second step of a login with two-factor authentication only needs
+// the MFA token sent by the first) - renewing the access token and logging out only need the refresh token,
+// verifying an email only needs the secret code sent to it, a user resetting a forgotten password can't login, and the token
+// public keys are meant to be shared
+var publicMethods = map[string]bool{
+	fullMethodName("CreateUser"):           true,
+	fullMethodName("LoginUser"):            true,
+	fullMethodName("LoginUserMFA"):         true,
+	fullMethodName("RenewAccessToken"):     true,
+	fullMethodName("RevokeToken"):          true,
+	fullMethodName("VerifyEmail"):          true,
+	fullMethodName("RequestPasswordReset"): true,
+	fullMethodName("ResetPassword"):        true,
+	fullMethodName("GetTokenPublicKeys"):   true,
+}
+
+// fullMethodName returns the full gRPC method name (e.g. /pb.SimpleBank/CreateUser) which interceptors receive in
+// grpc.UnaryServerInfo and grpc.StreamServerInfo
+func fullMethodName(method string) string {
+	return fmt.Sprintf("/%s/%s", pb.SimpleBank_ServiceDesc.ServiceName, method)
+}
+
+// authPayloadFromContext returns the payload stored in the context by the auth interceptors
+func authPayloadFromContext(ctx context.Context) (*token.Payload, bool) {
+	payload, ok := ctx.Value(authPayloadKey{}).(*token.Payload)
+	return payload, ok
+}
+
+// authenticate verifies the access token or API key of a call to any method that isn't public and returns a context which
+// holds its payload
+func (server *Server) authenticate(ctx context.Context, method string) (context.Context, error) {
+	if publicMethods[method] {
+		return ctx, nil
+	}
+
+	payload, err := server.verifyAuthorization(ctx, method)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	return context.WithValue(ctx, authPayloadKey{}, payload), nil
+}
+
+// UnaryAuthInterceptor authenticates unary RPCs before they reach the handler - it is the gRPC counterpart of the
+// authMiddleware in the api package
+func (server *Server) UnaryAuthInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	ctx, err := server.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	// forward the context holding the payload to the handler
+	return handler(ctx, req)
+}
+
+// StreamAuthInterceptor authenticates streaming RPCs before they reach the handler
+func (server *Server) StreamAuthInterceptor(
+	srv interface{},
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	ctx, err := server.authenticate(stream.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	// the context of a grpc.ServerStream cannot be replaced, so the stream is wrapped to return the new context
+	return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+}
+
+// authenticatedStream is a grpc.ServerStream whose context holds the access token payload
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context holding the access token payload
+func (stream *authenticatedStream) Context() context.Context {
+	return stream.ctx
+}
@@ -0,0 +1,287 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/token"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// requireStatusCode checks the gRPC status code of the error
+func requireStatusCode(t *testing.T, code codes.Code, err error) {
+	st, ok := status.FromError(err)
+	require.True(t, ok, "not a status error: %v", err)
+	require.Equal(t, code, st.Code(), st.Message())
+}
+
+func TestUnaryAuthInterceptor(t *testing.T) {
+	username := util.RandomOwner()
+
+	testCases := []struct {
+		name       string
+		method     string
+		setupAuth  func(t *testing.T, server *Server) (context.Context, *token.Payload)
+		checkError func(t *testing.T, err error)
+	}{
+		{
+			name:   "OK",
+			method: fullMethodName("GetAccount"),
+			setupAuth: func(t *testing.T, server *Server) (context.Context, *token.Payload) {
+				return newContextWithBearerToken(t, server.tokenMaker, username, util.DepositorRole, time.Minute)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:   "Gateway Authorization",
+			method: fullMethodName("GetAccount"),
+			setupAuth: func(t *testing.T, server *Server) (context.Context, *token.Payload) {
+				accessToken, payload, err := server.tokenMaker.CreateToken(username, util.DepositorRole, time.Minute)
+				require.NoError(t, err)
+				// the gateway forwards the HTTP header under its prefixed key when it runs as a separate proxy
+				md := metadata.Pairs(grpcGatewayAuthorizationHeader, "Bearer "+accessToken)
+				return metadata.NewIncomingContext(context.Background(), md), payload
+			},
+			checkError: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:   "No Metadata",
+			method: fullMethodName("GetAccount"),
+			setupAuth: func(t *testing.T, server *Server) (context.Context, *token.Payload) {
+				return context.Background(), nil
+			},
+			checkError: func(t *testing.T, err error) {
+				requireStatusCode(t, codes.Unauthenticated, err)
+			},
+		},
+		{
+			name:   "No Authorization",
+			method: fullMethodName("GetAccount"),
+			setupAuth: func(t *testing.T, server *Server) (context.Context, *token.Payload) {
+				md := metadata.Pairs(userAgentHeader, "test")
+				return metadata.NewIncomingContext(context.Background(), md), nil
+			},
+			checkError: func(t *testing.T, err error) {
+				requireStatusCode(t, codes.Unauthenticated, err)
+			},
+		},
+		{
+			name:   "Invalid Authorization Format",
+			method: fullMethodName("GetAccount"),
+			setupAuth: func(t *testing.T, server *Server) (context.Context, *token.Payload) {
+				accessToken, _, err := server.tokenMaker.CreateToken(username, util.DepositorRole, time.Minute)
+				require.NoError(t, err)
+				md := metadata.Pairs(authorizationHeader, accessToken)
+				return metadata.NewIncomingContext(context.Background(), md), nil
+			},
+			checkError: func(t *testing.T, err error) {
+				requireStatusCode(t, codes.Unauthenticated, err)
+			},
+		},
+		{
+			name:   "Unsupported Authorization",
+			method: fullMethodName("GetAccount"),
+			setupAuth: func(t *testing.T, server *Server) (context.Context, *token.Payload) {
+				accessToken, _, err := server.tokenMaker.CreateToken(username, util.DepositorRole, time.Minute)
+				require.NoError(t, err)
+				md := metadata.Pairs(authorizationHeader, fmt.Sprintf("basic %s", accessToken))
+				return metadata.NewIncomingContext(context.Background(), md), nil
+			},
+			checkError: func(t *testing.T, err error) {
+				requireStatusCode(t, codes.Unauthenticated, err)
+			},
+		},
+		{
+			name:   "Invalid Token",
+			method: fullMethodName("GetAccount"),
+			setupAuth: func(t *testing.T, server *Server) (context.Context, *token.Payload) {
+				md := metadata.Pairs(authorizationHeader, "bearer invalid")
+				return metadata.NewIncomingContext(context.Background(), md), nil
+			},
+			checkError: func(t *testing.T, err error) {
+				requireStatusCode(t, codes.Unauthenticated, err)
+			},
+		},
+		{
+			name:   "Expired Token",
+			method: fullMethodName("GetAccount"),
+			setupAuth: func(t *testing.T, server *Server) (context.Context, *token.Payload) {
+				ctx, _ := newContextWithBearerToken(t, server.tokenMaker, username, util.DepositorRole, -time.Minute)
+				return ctx, nil
+			},
+			checkError: func(t *testing.T, err error) {
+				requireStatusCode(t, codes.Unauthenticated, err)
+			},
+		},
+		{
+			name:   "Denied Token",
+			method: fullMethodName("GetAccount"),
+			setupAuth: func(t *testing.T, server *Server) (context.Context, *token.Payload) {
+				ctx, payload := newContextWithBearerToken(t, server.tokenMaker, username, util.DepositorRole, time.Minute)
+				require.NoError(t, server.denylist.Deny(context.Background(), payload))
+				return ctx, nil
+			},
+			checkError: func(t *testing.T, err error) {
+				requireStatusCode(t, codes.Unauthenticated, err)
+			},
+		},
+		{
+			name:   "MFA Challenge Token",
+			method: fullMethodName("GetAccount"),
+			setupAuth: func(t *testing.T, server *Server) (context.Context, *token.Payload) {
+				mfaToken, _, err := server.tokenMaker.CreateMFAChallengeToken(username, util.DepositorRole, time.Minute)
+				require.NoError(t, err)
+				md := metadata.Pairs(authorizationHeader, "bearer "+mfaToken)
+				return metadata.NewIncomingContext(context.Background(), md), nil
+			},
+			checkError: func(t *testing.T, err error) {
+				requireStatusCode(t, codes.Unauthenticated, err)
+			},
+		},
+		{
+			// public methods don't need an access token, and no payload is stored for them
+			name:   "Public Method",
+			method: fullMethodName("LoginUser"),
+			setupAuth: func(t *testing.T, server *Server) (context.Context, *token.Payload) {
+				return context.Background(), nil
+			},
+			checkError: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:   "Public Method With Invalid Token",
+			method: fullMethodName("CreateUser"),
+			setupAuth: func(t *testing.T, server *Server) (context.Context, *token.Payload) {
+				md := metadata.Pairs(authorizationHeader, "bearer invalid")
+				return metadata.NewIncomingContext(context.Background(), md), nil
+			},
+			checkError: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestServer(t, nil)
+			ctx, expectedPayload := tc.setupAuth(t, server)
+
+			handlerCalled := false
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				handlerCalled = true
+				// the handler receives the payload of the verified token
+				payload, ok := authPayloadFromContext(ctx)
+				require.Equal(t, expectedPayload != nil, ok)
+				if expectedPayload != nil {
+					require.Equal(t, expectedPayload.ID, payload.ID)
+					require.Equal(t, expectedPayload.Username, payload.Username)
+				}
+				return "response", nil
+			}
+
+			rsp, err := server.UnaryAuthInterceptor(ctx, "request", &grpc.UnaryServerInfo{FullMethod: tc.method}, handler)
+			tc.checkError(t, err)
+			if err == nil {
+				require.True(t, handlerCalled)
+				require.Equal(t, "response", rsp)
+			} else {
+				// a call which isn't authenticated never reaches the handler
+				require.False(t, handlerCalled)
+			}
+		})
+	}
+}
+
+// testServerStream is a grpc.ServerStream which only has a context
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *testServerStream) Context() context.Context {
+	return stream.ctx
+}
+
+func TestStreamAuthInterceptor(t *testing.T) {
+	server := newTestServer(t, nil)
+	info := &grpc.StreamServerInfo{FullMethod: fullMethodName("ListAccounts")}
+
+	ctx, expectedPayload := newContextWithBearerToken(t, server.tokenMaker, util.RandomOwner(), util.DepositorRole, time.Minute)
+	err := server.StreamAuthInterceptor(nil, &testServerStream{ctx: ctx}, info, func(srv interface{}, stream grpc.ServerStream) error {
+		// the wrapped stream returns the context holding the payload
+		payload, ok := authPayloadFromContext(stream.Context())
+		require.True(t, ok)
+		require.Equal(t, expectedPayload.ID, payload.ID)
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = server.StreamAuthInterceptor(nil, &testServerStream{ctx: context.Background()}, info, func(srv interface{}, stream grpc.ServerStream) error {
+		t.Fatal("the handler must not be called without an access token")
+		return nil
+	})
+	requireStatusCode(t, codes.Unauthenticated, err)
+}
+
+func TestAuthorizeRole(t *testing.T) {
+	testCases := []struct {
+		name       string
+		role       string
+		checkError func(t *testing.T, err error)
+	}{
+		{
+			name: "Admin",
+			role: util.AdminRole,
+			checkError: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "Depositor",
+			role: util.DepositorRole,
+			checkError: func(t *testing.T, err error) {
+				requireStatusCode(t, codes.PermissionDenied, err)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestServer(t, nil)
+			ctx, _ := newContextWithBearerToken(t, server.tokenMaker, util.RandomOwner(), tc.role, time.Minute)
+
+			// through the interceptor, which stores the payload in the context
+			_, err := server.UnaryAuthInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: fullMethodName("BlockSession")},
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					return server.authorizeRole(ctx, util.AdminRole)
+				})
+			tc.checkError(t, err)
+
+			// through the in-process gateway, which skips the interceptor
+			_, err = server.authorizeRole(ctx, util.AdminRole)
+			tc.checkError(t, err)
+		})
+	}
+
+	// without an access token the role can't be checked
+	server := newTestServer(t, nil)
+	_, err := server.authorizeRole(context.Background(), util.AdminRole)
+	requireStatusCode(t, codes.Unauthenticated, err)
+}
@@ -0,0 +1,122 @@
+package gapi
+
+import (
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/token"
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// loginClient is the client a login attempt comes from, as it is recorded in the login_attempts table
+type loginClient struct {
+	ClientIP  string
+	UserAgent string
+}
+
+// extractLoginClient returns the client of the login attempt - the port is removed from its IP, so the failures of the IP
+// can be counted
+func (server *Server) extractLoginClient(ctx context.Context) loginClient {
+	return loginClient{
+		ClientIP:  server.extractClientIP(ctx),
+		UserAgent: server.extractMetadata(ctx).UserAgent,
+	}
+}
+
+// loginLockedUntil returns when the lockout of logins as the user or from the client IP ends - the zero time if they aren't
+// locked out - it performs the same checks as loginLockedUntil in the api package
+func (server *Server) loginLockedUntil(ctx context.Context, username string, clientIP string) (time.Time, error) {
+	var lockedUntil time.Time
+	since := time.Now().Add(-server.config.LoginFailureWindow)
+
+	userLockout := util.LoginLockout{
+		MaxFailures: server.config.LoginMaxFailuresPerUser,
+		Duration:    server.config.LoginLockoutDuration,
+		MaxDuration: server.config.LoginMaxLockoutDuration,
+	}
+	if userLockout.MaxFailures > 0 {
+		failures, err := server.store.GetUserLoginFailures(ctx, db.GetUserLoginFailuresParams{
+			Username: username,
+			Since:    since,
+		})
+		if err != nil {
+			return time.Time{}, err
+		}
+		lockedUntil = userLockout.LockedUntil(failures.Failures, failures.LastFailedAt)
+	}
+
+	ipLockout := util.LoginLockout{
+		MaxFailures: server.config.LoginMaxFailuresPerIP,
+		Duration:    server.config.LoginLockoutDuration,
+		MaxDuration: server.config.LoginMaxLockoutDuration,
+	}
+	if ipLockout.MaxFailures > 0 && clientIP != "" {
+		failures, err := server.store.GetClientIPLoginFailures(ctx, db.GetClientIPLoginFailuresParams{
+			ClientIp: clientIP,
+			Since:    since,
+		})
+		if err != nil {
+			return time.Time{}, err
+		}
+		if ipLockedUntil := ipLockout.LockedUntil(failures.Failures, failures.LastFailedAt); ipLockedUntil.After(lockedUntil) {
+			lockedUntil = ipLockedUntil
+		}
+	}
+
+	return lockedUntil, nil
+}
+
+// recordLoginAttempt adds the login attempt to the audit trail in the login_attempts table - an empty failure reason records
+// a successful attempt
+func (server *Server) recordLoginAttempt(ctx context.Context, client loginClient, username string, failureReason string) error {
+	_, err := server.store.CreateLoginAttempt(ctx, db.CreateLoginAttemptParams{
+		Username:      username,
+		ClientIp:      client.ClientIP,
+		UserAgent:     client.UserAgent,
+		Succeeded:     failureReason == "",
+		FailureReason: failureReason,
+	})
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to record login attempt: %s", err)
+	}
+	return nil
+}
+
+// incorrectLoginError records the failed attempt and returns the same Unauthenticated error for an unknown username and a
+// wrong password, so the login can't be used to find out which usernames exist
+func (server *Server) incorrectLoginError(ctx context.Context, client loginClient, username string, failureReason string) error {
+	if err := server.recordLoginAttempt(ctx, client, username, failureReason); err != nil {
+		return err
+	}
+	return status.Errorf(codes.Unauthenticated, "incorrect username or password")
+}
+
+// incorrectMFACodeError records the wrong TOTP or recovery code as a failed attempt and returns an Unauthenticated error -
+// once MFAMaxFailures wrong codes were sent since the token was issued, the token is denied and the user has to login with
+// their password again
+// mfaPayload is the challenge token of LoginUserMFA, or the access token of DisableMFA
+func (server *Server) incorrectMFACodeError(ctx context.Context, client loginClient, mfaPayload *token.Payload) error {
+	if err := server.recordLoginAttempt(ctx, client, mfaPayload.Username, util.LoginFailureWrongMFACode); err != nil {
+		return err
+	}
+
+	if server.config.MFAMaxFailures > 0 {
+		failures, err := server.store.GetMFAChallengeFailures(ctx, db.GetMFAChallengeFailuresParams{
+			Username: mfaPayload.Username,
+			Since:    mfaPayload.IssuedAt,
+		})
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to count MFA failures: %s", err)
+		}
+		if failures >= server.config.MFAMaxFailures {
+			if err := server.denylist.Deny(ctx, mfaPayload); err != nil {
+				return status.Errorf(codes.Internal, "failed to revoke MFA token: %s", err)
+			}
+		}
+	}
+
+	return status.Errorf(codes.Unauthenticated, "%s", errInvalidMFACode)
+}
@@ -0,0 +1,53 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/mail"
+	"SimpleBankProject/token"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func newTestServer(t *testing.T, store db.Store) *Server {
+	config := util.Config{
+		TokenSymmetricKey:   util.RandomString(32),
+		AccessTokenDuration: time.Minute,
+		TOTPEncryptionKey:   util.RandomString(32),
+	}
+
+	server, err := NewServer(config, store, mail.NewMemoryMailer())
+	require.NoError(t, err)
+	// the mock store doesn't expect the queries of the revoked_tokens table, so the tests keep the denylist in memory
+	server.denylist = token.NewMemoryDenylist()
+
+	return server
+}
+
+// newContextWithBearerToken creates an access token and returns an incoming context whose metadata holds it, as a gRPC
+// client would send it
+func newContextWithBearerToken(t *testing.T, tokenMaker token.Maker, username string, role string, duration time.Duration) (context.Context, *token.Payload) {
+	accessToken, payload, err := tokenMaker.CreateToken(username, role, duration)
+	require.NoError(t, err)
+
+	md := metadata.MD{
+		authorizationHeader: []string{fmt.Sprintf("%s %s", authorizationBearer, accessToken)},
+	}
+	return metadata.NewIncomingContext(context.Background(), md), payload
+}
+
+// randomAccount returns an account of the owner with a zero balance, as CreateAccount creates it
+func randomAccount(owner string) db.Account {
+	return db.Account{
+		ID:       util.RandomInt(1, 1000),
+		Owner:    owner,
+		Balance:  0,
+		Currency: util.USD,
+	}
+}
@@ -2,6 +2,8 @@ package gapi
 
 import (
 	"context"
+	"net"
+	"strings"
 
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
@@ -40,10 +42,12 @@ func (server *Server) extractMetadata(ctx context.Context) *Metadata {
 			mtdt.UserAgent = userAgents[0]
 		}
 
-		// grab the Client IP data and, if it is not empty (length greater than 0), the client IP address will be the first
-		// element in the slice - HTTP
+		// grab the Client IP data - HTTP
+		// the gRPC gateway appends the address the HTTP request came from to the X-Forwarded-For header sent by the client,
+		// so only the last address can be trusted, the ones before it can be set by the client to anything
 		if clientIPs := md.Get(xForwardedForHeader); len(clientIPs) > 0 {
-			mtdt.ClientIP = clientIPs[0]
+			forwarded := strings.Split(clientIPs[len(clientIPs)-1], ",")
+			mtdt.ClientIP = strings.TrimSpace(forwarded[len(forwarded)-1])
 		}
 	}
 
@@ -55,3 +59,13 @@ func (server *Server) extractMetadata(ctx context.Context) *Metadata {
 
 	return mtdt
 }
+
+// extractClientIP returns the IP address of the client without the port - the address of a direct gRPC client includes its
+// port, which changes with every connection, so it can't be used to count the requests of a client
+func (server *Server) extractClientIP(ctx context.Context) string {
+	clientIP := server.extractMetadata(ctx).ClientIP
+	if host, _, err := net.SplitHostPort(clientIP); err == nil {
+		return host
+	}
+	return clientIP
+}
@@ -0,0 +1,63 @@
+package gapi
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestExtractClientIP(t *testing.T) {
+	testCases := []struct {
+		name     string
+		buildCtx func() context.Context
+		clientIP string
+	}{
+		{
+			name: "Gateway",
+			buildCtx: func() context.Context {
+				md := metadata.Pairs(xForwardedForHeader, "192.0.2.1")
+				return metadata.NewIncomingContext(context.Background(), md)
+			},
+			clientIP: "192.0.2.1",
+		},
+		{
+			name: "Gateway Spoofed Header",
+			buildCtx: func() context.Context {
+				// the gateway appended the address of the request to the header sent by the client
+				md := metadata.Pairs(xForwardedForHeader, "203.0.113.7, 198.51.100.9, 192.0.2.1")
+				return metadata.NewIncomingContext(context.Background(), md)
+			},
+			clientIP: "192.0.2.1",
+		},
+		{
+			name: "Direct gRPC Client",
+			buildCtx: func() context.Context {
+				// the metadata of a direct gRPC client is set by the client, the peer address isn't
+				md := metadata.Pairs(xForwardedForHeader, "203.0.113.7")
+				ctx := metadata.NewIncomingContext(context.Background(), md)
+				return peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 1234}})
+			},
+			clientIP: "192.0.2.1",
+		},
+		{
+			name: "No Client IP",
+			buildCtx: func() context.Context {
+				return context.Background()
+			},
+			clientIP: "",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestServer(t, nil)
+			require.Equal(t, tc.clientIP, server.extractClientIP(tc.buildCtx()))
+		})
+	}
+}
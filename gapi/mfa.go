@@ -0,0 +1,59 @@
+package gapi
+
+import (
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/totp"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// errInvalidMFACode is returned by checkMFACode when the code is wrong or was already used
+var errInvalidMFACode = errors.New("invalid two-factor authentication code")
+
+// isMFAEnabled returns true if the user has confirmed a TOTP secret
+func (server *Server) isMFAEnabled(ctx context.Context, username string) (bool, error) {
+	userMFA, err := server.store.GetUserMFA(ctx, username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	return userMFA.IsEnabled, nil
+}
+
+// checkMFACode checks a TOTP code of the user, or one of their recovery codes - both can only be used once
+func (server *Server) checkMFACode(ctx context.Context, userMFA db.UserMfa, code string) error {
+	if len(code) != totp.Digits {
+		_, err := server.store.UseMFARecoveryCode(ctx, db.UseMFARecoveryCodeParams{
+			Username: userMFA.Username,
+			CodeHash: util.HashSecretToken(totp.NormalizeRecoveryCode(code)),
+		})
+		if err == sql.ErrNoRows {
+			return errInvalidMFACode
+		}
+		return err
+	}
+
+	secret, err := server.totpCipher.Decrypt(userMFA.EncryptedSecret)
+	if err != nil {
+		return err
+	}
+	step, ok := totp.Validate(secret, code, time.Now())
+	if !ok {
+		return errInvalidMFACode
+	}
+
+	// the code is correct, but it may have been used already
+	_, err = server.store.UseTOTPStep(ctx, db.UseTOTPStepParams{
+		Username: userMFA.Username,
+		Step:     step,
+	})
+	if err == sql.ErrNoRows {
+		return errInvalidMFACode
+	}
+	return err
+}
@@ -0,0 +1,195 @@
+package gapi
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"SimpleBankProject/ratelimit"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// rateLimitMethodGroups maps the methods which don't belong to ratelimit.GroupDefault to their group - it is the gRPC
+// counterpart of rateLimitRouteGroups in the api package
+var rateLimitMethodGroups = map[string]string{
+	fullMethodName("CreateUser"):           ratelimit.GroupAuth,
+	fullMethodName("LoginUser"):            ratelimit.GroupAuth,
+	fullMethodName("LoginUserMFA"):         ratelimit.GroupAuth,
+	fullMethodName("VerifyEmail"):          ratelimit.GroupAuth,
+	fullMethodName("RequestPasswordReset"): ratelimit.GroupAuth,
+	fullMethodName("ResetPassword"):        ratelimit.GroupAuth,
+	fullMethodName("RenewAccessToken"):     ratelimit.GroupAuth,
+	fullMethodName("RevokeToken"):          ratelimit.GroupAuth,
+	fullMethodName("TransferMoney"):        ratelimit.GroupTransfers,
+	fullMethodName("ReverseTransfer"):      ratelimit.GroupTransfers,
+	fullMethodName("Deposit"):              ratelimit.GroupTransfers,
+	fullMethodName("Withdraw"):             ratelimit.GroupTransfers,
+}
+
+// rateLimitGatewayRoutes maps the HTTP routes of the gateway which don't belong to ratelimit.GroupDefault to their group -
+// a route matches its path and every path below it (e.g. /v1/transfers/{id}/reverse)
+var rateLimitGatewayRoutes = []struct {
+	method string
+	path   string
+	group  string
+}{
+	{http.MethodPost, "/v1/create_user", ratelimit.GroupAuth},
+	{http.MethodPost, "/v1/login_user", ratelimit.GroupAuth},
+	{http.MethodGet, "/v1/verify_email", ratelimit.GroupAuth},
+	{http.MethodPost, "/v1/password_reset", ratelimit.GroupAuth},
+	{http.MethodPost, "/v1/tokens/renew_access", ratelimit.GroupAuth},
+	{http.MethodPost, "/v1/tokens/revoke", ratelimit.GroupAuth},
+	{http.MethodPost, "/v1/transfers", ratelimit.GroupTransfers},
+	{http.MethodPost, "/v1/deposits", ratelimit.GroupTransfers},
+	{http.MethodPost, "/v1/withdrawals", ratelimit.GroupTransfers},
+}
+
+// rateLimit takes a token from the bucket of the client in the group - the returned error is already a gRPC status error,
+// ResourceExhausted with the time until the client may try again if it has used up its bucket
+func (server *Server) rateLimit(ctx context.Context, group string, key string) error {
+	limit := server.rateLimits[group]
+	if !limit.Enabled() {
+		return nil
+	}
+
+	result, err := server.limiter.Allow(ctx, key, limit)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to check rate limit: %s", err)
+	}
+	if !result.Allowed {
+		return resourceExhaustedError("too many requests, try again later", result.RetryAfter)
+	}
+	return nil
+}
+
+// rateLimitMethod rate limits a call to the method - calls are counted per user if the auth interceptors stored the payload
+// of the caller in the context, per client IP otherwise
+func (server *Server) rateLimitMethod(ctx context.Context, method string) error {
+	group, ok := rateLimitMethodGroups[method]
+	if !ok {
+		group = ratelimit.GroupDefault
+	}
+
+	key := ratelimit.ClientIPKey(group, server.extractClientIP(ctx))
+	if payload, ok := authPayloadFromContext(ctx); ok {
+		key = ratelimit.UserKey(group, payload.Username)
+	}
+
+	return server.rateLimit(ctx, group, key)
+}
+
+// UnaryRateLimitInterceptor rate limits unary RPCs - it must be chained after UnaryAuthInterceptor, so the calls of
+// logged in users are counted per user - it is the gRPC counterpart of the rateLimitMiddleware in the api package
+func (server *Server) UnaryRateLimitInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	if err := server.rateLimitMethod(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// UnaryClientIPRateLimitInterceptor limits every unary RPC of a client IP with the ratelimit.GroupClientIP bucket - it must
+// be chained before UnaryAuthInterceptor, which rejects a bad access token or API key before UnaryRateLimitInterceptor
+// counts it - it is the gRPC counterpart of the clientIPRateLimitMiddleware in the api package
+func (server *Server) UnaryClientIPRateLimitInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	key := ratelimit.ClientIPKey(ratelimit.GroupClientIP, server.extractClientIP(ctx))
+	if err := server.rateLimit(ctx, ratelimit.GroupClientIP, key); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// StreamClientIPRateLimitInterceptor is the streaming counterpart of UnaryClientIPRateLimitInterceptor
+func (server *Server) StreamClientIPRateLimitInterceptor(
+	srv interface{},
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	key := ratelimit.ClientIPKey(ratelimit.GroupClientIP, server.extractClientIP(stream.Context()))
+	if err := server.rateLimit(stream.Context(), ratelimit.GroupClientIP, key); err != nil {
+		return err
+	}
+	return handler(srv, stream)
+}
+
+// StreamRateLimitInterceptor rate limits streaming RPCs - a stream counts as one call however many messages it sends
+func (server *Server) StreamRateLimitInterceptor(
+	srv interface{},
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	if err := server.rateLimitMethod(stream.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, stream)
+}
+
+// RateLimitGateway rate limits the HTTP requests of the gateway before the mux handles them - the in-process gateway calls
+// the handlers directly, skipping the interceptors
+// every request is first counted per client IP in the ratelimit.GroupClientIP bucket, as the client IP interceptors do -
+// then requests with a valid access token are counted per user, the others per client IP - the token isn't checked against
+// the denylist and API keys aren't looked up, that is left to the handler, so a revoked token is counted per user but still
+// rejected - a client which has used up its bucket gets 429 Too Many Requests with a Retry-After header
+func (server *Server) RateLimitGateway(mux *runtime.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		group := ratelimit.GroupDefault
+		for _, route := range rateLimitGatewayRoutes {
+			if r.Method == route.method && (r.URL.Path == route.path || strings.HasPrefix(r.URL.Path, route.path+"/")) {
+				group = route.group
+				break
+			}
+		}
+
+		key := ratelimit.ClientIPKey(group, gatewayClientIP(r))
+		fields := strings.Fields(r.Header.Get("Authorization"))
+		if len(fields) == 2 && strings.ToLower(fields[0]) == authorizationBearer {
+			if payload, err := server.tokenMaker.VerifyToken(fields[1]); err == nil {
+				key = ratelimit.UserKey(group, payload.Username)
+			}
+		}
+
+		clientIPKey := ratelimit.ClientIPKey(ratelimit.GroupClientIP, gatewayClientIP(r))
+		err := server.rateLimit(r.Context(), ratelimit.GroupClientIP, clientIPKey)
+		if err == nil {
+			err = server.rateLimit(r.Context(), group, key)
+		}
+		if err != nil {
+			if st, ok := status.FromError(err); ok && st.Code() == codes.ResourceExhausted {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryDelay(st).Seconds()))))
+			}
+			// send the error in the same format as the errors of the handlers
+			_, outbound := runtime.MarshalerForRequest(mux, r)
+			runtime.HTTPError(r.Context(), mux, outbound, w, r, err)
+			return
+		}
+
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// gatewayClientIP returns the IP address the HTTP request came from - unlike the X-Forwarded-For header, it can't be set by
+// the client to get a new bucket
+func gatewayClientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
@@ -0,0 +1,85 @@
+package gapi
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"SimpleBankProject/ratelimit"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// newRateLimitedTestServer returns a test server with the input limits
+func newRateLimitedTestServer(t *testing.T, limits ratelimit.Limits) *Server {
+	server := newTestServer(t, nil)
+	server.limiter = ratelimit.NewMemoryLimiter()
+	server.rateLimits = limits
+	return server
+}
+
+// newContextWithClientIP returns an incoming context of a direct gRPC client at the IP with a bad access token
+func newContextWithClientIP(clientIP string) context.Context {
+	md := metadata.Pairs(authorizationHeader, authorizationBearer+" invalid-token")
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+	return peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(clientIP), Port: 1234}})
+}
+
+func TestUnaryClientIPRateLimitInterceptor(t *testing.T) {
+	server := newRateLimitedTestServer(t, ratelimit.Limits{
+		ratelimit.GroupClientIP: {Rate: 1.0 / 60, Burst: 2},
+	})
+	info := &grpc.UnaryServerInfo{FullMethod: fullMethodName("GetAccount")}
+	// the auth interceptor is chained after the client IP interceptor, as in main.go
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return server.UnaryAuthInterceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, nil
+		})
+	}
+
+	// calls with a bad access token are counted per client IP before they are rejected
+	for i := 0; i < 2; i++ {
+		_, err := server.UnaryClientIPRateLimitInterceptor(newContextWithClientIP("192.0.2.1"), nil, info, handler)
+		requireStatusCode(t, codes.Unauthenticated, err)
+	}
+	_, err := server.UnaryClientIPRateLimitInterceptor(newContextWithClientIP("192.0.2.1"), nil, info, handler)
+	requireStatusCode(t, codes.ResourceExhausted, err)
+
+	// other clients have their own bucket
+	_, err = server.UnaryClientIPRateLimitInterceptor(newContextWithClientIP("192.0.2.2"), nil, info, handler)
+	requireStatusCode(t, codes.Unauthenticated, err)
+}
+
+func TestRateLimitGatewayClientIP(t *testing.T) {
+	server := newRateLimitedTestServer(t, ratelimit.Limits{
+		ratelimit.GroupClientIP: {Rate: 1.0 / 60, Burst: 2},
+	})
+	handler := server.RateLimitGateway(runtime.NewServeMux())
+
+	sendRequest := func(clientIP string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, "/v1/accounts/1", nil)
+		request.RemoteAddr = clientIP + ":1234"
+		request.Header.Set("Authorization", "Bearer invalid-token")
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// the mux has no handlers registered, so the requests which get through the limit aren't found
+	for i := 0; i < 2; i++ {
+		require.Equal(t, http.StatusNotFound, sendRequest("192.0.2.1").Code)
+	}
+	recorder := sendRequest("192.0.2.1")
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	require.Equal(t, "60", recorder.Header().Get("Retry-After"))
+
+	// other clients have their own bucket
+	require.Equal(t, http.StatusNotFound, sendRequest("192.0.2.2").Code)
+}
@@ -0,0 +1,51 @@
+package gapi
+
+import (
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/pb"
+	"SimpleBankProject/val"
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// BlockSession stops a session from renewing access tokens (e.g. when its refresh token was stolen)
+func (server *Server) BlockSession(ctx context.Context, req *pb.BlockSessionRequest) (*pb.BlockSessionResponse, error) {
+	// only admins may block the sessions of other users
+	if _, err := server.authorizeRole(ctx, util.AdminRole); err != nil {
+		return nil, err
+	}
+
+	// validate that the BlockSessionRequest properties meet the criteria defined in validator.go
+	violations := validateBlockSessionRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	// the session id has already been validated, so it can be parsed
+	session, err := server.blockAndDenySession(ctx, uuid.MustParse(req.GetSessionId()))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "session not found: %s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to block session: %s", err)
+	}
+
+	rsp := &pb.BlockSessionResponse{
+		Session: convertSession(session),
+	}
+
+	return rsp, nil
+}
+
+// validateBlockSessionRequest will validate each property of the BlockSessionRequest object
+func validateBlockSessionRequest(req *pb.BlockSessionRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateSessionID(req.GetSessionId()); err != nil {
+		violations = append(violations, fieldViolation("session_id", err))
+	}
+	return violations
+}
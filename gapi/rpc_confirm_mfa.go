@@ -0,0 +1,86 @@
+package gapi
+
+import (
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/pb"
+	"SimpleBankProject/totp"
+	"SimpleBankProject/val"
+	"context"
+	"database/sql"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ConfirmMFA enables two-factor authentication for the logged in user once they send a code of the secret created by
+// EnrollMFA, which proves their authenticator app has the secret - the recovery codes are returned
+func (server *Server) ConfirmMFA(ctx context.Context, req *pb.ConfirmMFARequest) (*pb.ConfirmMFAResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	// validate that the ConfirmMFARequest properties meet the criteria defined in validator.go
+	violations := validateConfirmMFARequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	userMFA, err := server.store.GetUserMFA(ctx, authPayload.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "two-factor authentication enrollment not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get two-factor authentication: %s", err)
+	}
+	if userMFA.IsEnabled {
+		return nil, status.Errorf(codes.AlreadyExists, "two-factor authentication is already enabled")
+	}
+
+	secret, err := server.totpCipher.Decrypt(userMFA.EncryptedSecret)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to decrypt secret: %s", err)
+	}
+	step, ok := totp.Validate(secret, req.GetCode(), time.Now())
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "%s", errInvalidMFACode)
+	}
+
+	recoveryCodes, err := totp.GenerateRecoveryCodes(totp.RecoveryCodeCount)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate recovery codes: %s", err)
+	}
+	codeHashes := make([]string, len(recoveryCodes))
+	for i, code := range recoveryCodes {
+		codeHashes[i] = util.HashSecretToken(totp.NormalizeRecoveryCode(code))
+	}
+
+	_, err = server.store.EnableMFATX(ctx, db.EnableMFATxParams{
+		Username:           authPayload.Username,
+		Step:               step,
+		RecoveryCodeHashes: codeHashes,
+	})
+	if err != nil {
+		// another request confirmed the secret first
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.AlreadyExists, "two-factor authentication is already enabled")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to enable two-factor authentication: %s", err)
+	}
+
+	rsp := &pb.ConfirmMFAResponse{
+		RecoveryCodes: recoveryCodes,
+	}
+	return rsp, nil
+}
+
+// validateConfirmMFARequest will validate each property of the ConfirmMFARequest object
+func validateConfirmMFARequest(req *pb.ConfirmMFARequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateTOTPCode(req.GetCode()); err != nil {
+		violations = append(violations, fieldViolation("code", err))
+	}
+	return violations
+}
@@ -0,0 +1,68 @@
+package gapi
+
+import (
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/pb"
+	"SimpleBankProject/val"
+	"context"
+
+	"github.com/lib/pq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CreateAccount(ctx context.Context, req *pb.CreateAccountRequest) (*pb.CreateAccountResponse, error) {
+	// the owner is only allowed to be the logged in user - this info is in the payload of the access token
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	// validate that the CreateAccountRequest properties meet the criteria defined in validator.go
+	violations := validateCreateAccountRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	if err := server.requireVerifiedEmail(ctx, authPayload.Username); err != nil {
+		return nil, err
+	}
+
+	// if no violations, create account with a balance of zero
+	arg := db.CreateAccountParams{
+		Owner:    authPayload.Username,
+		Currency: req.GetCurrency(),
+		Balance:  0,
+	}
+
+	account, err := server.store.CreateAccount(ctx, arg)
+	if err != nil {
+		// try to convert err to type pq.Error
+		// this is to provide a better error in the event someone attempts to create an account without a user or a second
+		// account with a duplicate currency (users can only have one account per currency)
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "unique_violation":
+				return nil, status.Errorf(codes.AlreadyExists, "account already exists: %s", pqErr.Error())
+			case "foreign_key_violation":
+				return nil, status.Errorf(codes.FailedPrecondition, "owner does not exist: %s", pqErr.Error())
+			}
+		}
+		return nil, status.Errorf(codes.Internal, "failed to create account: %s", err)
+	}
+
+	rsp := &pb.CreateAccountResponse{
+		Account: convertAccount(account),
+	}
+
+	return rsp, nil
+}
+
+// validateCreateAccountRequest will validate each property of the CreateAccountRequest object
+func validateCreateAccountRequest(req *pb.CreateAccountRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateCurrency(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+	}
+	return violations
+}
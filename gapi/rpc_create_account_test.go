@@ -0,0 +1,164 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "SimpleBankProject/db/mock"
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/pb"
+	"SimpleBankProject/token"
+
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestCreateAccountAPI(t *testing.T) {
+	user := db.User{Username: util.RandomOwner(), Role: util.DepositorRole, IsEmailVerified: true}
+	account := randomAccount(user.Username)
+
+	testCases := []struct {
+		name                 string
+		req                  *pb.CreateAccountRequest
+		requireVerifiedEmail bool
+		buildStubs           func(store *mockdb.MockStore)
+		buildContext         func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse        func(t *testing.T, rsp *pb.CreateAccountResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.CreateAccountRequest{Currency: account.Currency},
+			buildStubs: func(store *mockdb.MockStore) {
+				// the owner is the logged in user
+				arg := db.CreateAccountParams{
+					Owner:    user.Username,
+					Currency: account.Currency,
+					Balance:  0,
+				}
+				store.EXPECT().CreateAccount(gomock.Any(), gomock.Eq(arg)).Times(1).Return(account, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				ctx, _ := newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+				return ctx
+			},
+			checkResponse: func(t *testing.T, rsp *pb.CreateAccountResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, account.ID, rsp.GetAccount().GetId())
+				require.Equal(t, user.Username, rsp.GetAccount().GetOwner())
+				require.Equal(t, account.Currency, rsp.GetAccount().GetCurrency())
+			},
+		},
+		{
+			name: "No Authorization",
+			req:  &pb.CreateAccountRequest{Currency: account.Currency},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			checkResponse: func(t *testing.T, rsp *pb.CreateAccountResponse, err error) {
+				requireStatusCode(t, codes.Unauthenticated, err)
+			},
+		},
+		{
+			name: "Invalid Currency",
+			req:  &pb.CreateAccountRequest{Currency: "XYZ"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				ctx, _ := newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+				return ctx
+			},
+			checkResponse: func(t *testing.T, rsp *pb.CreateAccountResponse, err error) {
+				requireStatusCode(t, codes.InvalidArgument, err)
+			},
+		},
+		{
+			name:                 "Email Not Verified",
+			req:                  &pb.CreateAccountRequest{Currency: account.Currency},
+			requireVerifiedEmail: true,
+			buildStubs: func(store *mockdb.MockStore) {
+				unverifiedUser := user
+				unverifiedUser.IsEmailVerified = false
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(unverifiedUser, nil)
+				store.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				ctx, _ := newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+				return ctx
+			},
+			checkResponse: func(t *testing.T, rsp *pb.CreateAccountResponse, err error) {
+				requireStatusCode(t, codes.FailedPrecondition, err)
+			},
+		},
+		{
+			// the user already has an account with the currency
+			name: "Duplicate Currency",
+			req:  &pb.CreateAccountRequest{Currency: account.Currency},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{}, &pq.Error{Code: "23505"})
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				ctx, _ := newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+				return ctx
+			},
+			checkResponse: func(t *testing.T, rsp *pb.CreateAccountResponse, err error) {
+				requireStatusCode(t, codes.AlreadyExists, err)
+			},
+		},
+		{
+			// the user was deleted after the access token was created
+			name: "Owner Not Found",
+			req:  &pb.CreateAccountRequest{Currency: account.Currency},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{}, &pq.Error{Code: "23503"})
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				ctx, _ := newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+				return ctx
+			},
+			checkResponse: func(t *testing.T, rsp *pb.CreateAccountResponse, err error) {
+				requireStatusCode(t, codes.FailedPrecondition, err)
+			},
+		},
+		{
+			name: "Internal Error",
+			req:  &pb.CreateAccountRequest{Currency: account.Currency},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{}, sql.ErrConnDone)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				ctx, _ := newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+				return ctx
+			},
+			checkResponse: func(t *testing.T, rsp *pb.CreateAccountResponse, err error) {
+				requireStatusCode(t, codes.Internal, err)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			server.config.RequireVerifiedEmail = tc.requireVerifiedEmail
+
+			rsp, err := server.CreateAccount(tc.buildContext(t, server.tokenMaker), tc.req)
+			tc.checkResponse(t, rsp, err)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package gapi
+
+import (
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/pb"
+	"SimpleBankProject/val"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CreateAPIKey creates an API key for the logged in user, limited to the scopes in the request - the key is only sent back
+// here, only its hash is stored
+func (server *Server) CreateAPIKey(ctx context.Context, req *pb.CreateAPIKeyRequest) (*pb.CreateAPIKeyResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	// validate that the CreateAPIKeyRequest properties meet the criteria defined in validator.go
+	violations := validateCreateAPIKeyRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	key, err := util.NewAPIKey()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create API key: %s", err)
+	}
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create API key id: %s", err)
+	}
+
+	arg := db.CreateAPIKeyParams{
+		ID:        id,
+		Username:  authPayload.Username,
+		Name:      req.GetName(),
+		KeyPrefix: util.APIKeyDisplayPrefix(key),
+		KeyHash:   util.HashSecretToken(key),
+		Scopes:    req.GetScopes(),
+	}
+	if req.ExpiresAt != nil {
+		arg.ExpiresAt = sql.NullTime{Time: req.GetExpiresAt().AsTime(), Valid: true}
+	}
+
+	apiKey, err := server.store.CreateAPIKey(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create API key: %s", err)
+	}
+
+	rsp := &pb.CreateAPIKeyResponse{
+		ApiKey: convertAPIKey(apiKey),
+		Key:    key,
+	}
+
+	return rsp, nil
+}
+
+// validateCreateAPIKeyRequest will validate each property of the CreateAPIKeyRequest object
+func validateCreateAPIKeyRequest(req *pb.CreateAPIKeyRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateAPIKeyName(req.GetName()); err != nil {
+		violations = append(violations, fieldViolation("name", err))
+	}
+	if err := val.ValidateScopes(req.GetScopes()); err != nil {
+		violations = append(violations, fieldViolation("scopes", err))
+	}
+	if req.ExpiresAt != nil && !req.GetExpiresAt().AsTime().After(time.Now()) {
+		violations = append(violations, fieldViolation("expires_at", fmt.Errorf("must be in the future")))
+	}
+	return violations
+}
@@ -23,20 +23,29 @@ func (server *Server) CreateUser(ctx context.Context, req *pb.CreateUserRequest)
 	}
 
 	// GetPassword offers a check for nil values which is better than simply grabbing the password from req.Password.
-	hashedPassword, err := util.HashPassword(req.GetPassword())
+	hashedPassword, err := server.hasher.Hash(req.GetPassword())
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to hash password: %s", err)
 	}
 
-	// if no err, begin user creation
-	arg := db.CreateUserParams{
-		Username:       req.GetUsername(),
-		HashedPassword: hashedPassword,
-		FullName:       req.GetFullName(),
-		Email:          req.GetEmail(),
+	// the code is sent in the verification email - only its hash is stored
+	secretCode, err := util.NewSecretToken()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create verification code: %s", err)
+	}
+
+	// if no err, begin user creation - the verification email is sent once the user has been created
+	arg := db.CreateUserTxParams{
+		CreateUserParams: db.CreateUserParams{
+			Username:       req.GetUsername(),
+			HashedPassword: hashedPassword,
+			FullName:       req.GetFullName(),
+			Email:          req.GetEmail(),
+		},
+		SecretCodeHash: util.HashSecretToken(secretCode),
 	}
 
-	user, err := server.store.CreateUser(ctx, arg)
+	result, err := server.store.CreateUserTX(ctx, arg)
 	if err != nil {
 		// try to convert err to type pq.Error
 		// this is to provide a better error in the event that someone attempts to create a user with a
@@ -50,8 +59,10 @@ func (server *Server) CreateUser(ctx context.Context, req *pb.CreateUserRequest)
 		return nil, status.Errorf(codes.Internal, "failed to create user: %s", err)
 	}
 
+	server.sendVerifyEmail(ctx, result.User, result.VerifyEmail, secretCode)
+
 	rsp := &pb.CreateUserResponse{
-		User: convertUser(user),
+		User: convertUser(result.User),
 	}
 
 	return rsp, nil
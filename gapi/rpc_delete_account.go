@@ -0,0 +1,58 @@
+package gapi
+
+import (
+	"SimpleBankProject/pb"
+	"SimpleBankProject/val"
+	"context"
+	"database/sql"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) DeleteAccount(ctx context.Context, req *pb.DeleteAccountRequest) (*pb.DeleteAccountResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	// validate that the DeleteAccountRequest properties meet the criteria defined in validator.go
+	violations := validateDeleteAccountRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	// test if the account actually exists - without this, deleting an account that doesn't exist would succeed
+	account, err := server.store.GetAccount(ctx, req.GetId())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "account not found: %s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	// the logged in user is only allowed to delete an account they own
+	if account.Owner != authPayload.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "account doesn't belong to the authenticated user")
+	}
+
+	err = server.store.DeleteAccount(ctx, req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete account: %s", err)
+	}
+
+	rsp := &pb.DeleteAccountResponse{
+		Id: req.GetId(),
+	}
+
+	return rsp, nil
+}
+
+// validateDeleteAccountRequest will validate each property of the DeleteAccountRequest object
+func validateDeleteAccountRequest(req *pb.DeleteAccountRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetId()); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+	return violations
+}
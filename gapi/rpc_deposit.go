@@ -0,0 +1,75 @@
+package gapi
+
+import (
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/pb"
+	"SimpleBankProject/val"
+	"context"
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Deposit adds money from outside the bank to an account - only the back office (admins) can book deposits, as the money
+// arrives outside the bank and a customer could otherwise credit their own account with any amount
+func (server *Server) Deposit(ctx context.Context, req *pb.DepositRequest) (*pb.DepositResponse, error) {
+	_, err := server.authorizeRole(ctx, util.AdminRole)
+	if err != nil {
+		return nil, err
+	}
+
+	// validate that the DepositRequest properties meet the criteria defined in validator.go
+	violations := validateDepositRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	// check if AccountId exists and has the correct currency for the deposit - it can belong to any user
+	_, err = server.validAccount(ctx, req.GetAccountId(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := server.store.DepositTX(ctx, db.DepositTxParams{
+		AccountID:         req.GetAccountId(),
+		Amount:            req.GetAmount(),
+		ExternalReference: req.GetExternalReference(),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrDuplicateExternalReference) {
+			return nil, status.Errorf(codes.AlreadyExists, "cannot deposit money: %s", err)
+		}
+		// an admin froze the account after validAccount checked it
+		if errors.Is(err, db.ErrAccountFrozen) {
+			return nil, status.Errorf(codes.FailedPrecondition, "cannot deposit money: %s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to deposit money: %s", err)
+	}
+
+	rsp := &pb.DepositResponse{
+		Account: convertAccount(result.Account),
+		Entry:   convertEntry(result.Entry),
+	}
+
+	return rsp, nil
+}
+
+// validateDepositRequest will validate each property of the DepositRequest object
+func validateDepositRequest(req *pb.DepositRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+	if err := val.ValidateAmount(req.GetAmount()); err != nil {
+		violations = append(violations, fieldViolation("amount", err))
+	}
+	if err := val.ValidateCurrency(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+	}
+	if err := val.ValidateExternalReference(req.GetExternalReference()); err != nil {
+		violations = append(violations, fieldViolation("external_reference", err))
+	}
+	return violations
+}
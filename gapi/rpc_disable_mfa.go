@@ -0,0 +1,80 @@
+package gapi
+
+import (
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/pb"
+	"SimpleBankProject/val"
+	"context"
+	"database/sql"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DisableMFA turns two-factor authentication off for the logged in user - a stolen access token alone isn't enough, a TOTP
+// code or a recovery code is needed as well
+func (server *Server) DisableMFA(ctx context.Context, req *pb.DisableMFARequest) (*pb.DisableMFAResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	// validate that the DisableMFARequest properties meet the criteria defined in validator.go
+	violations := validateDisableMFARequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	userMFA, err := server.store.GetUserMFA(ctx, authPayload.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "two-factor authentication enrollment not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get two-factor authentication: %s", err)
+	}
+
+	// an enrollment which was never confirmed can be dropped without a code
+	if userMFA.IsEnabled {
+		// the codes are guarded like in LoginUserMFA, so a stolen access token can't be used to guess them - the lockout is
+		// shared with the login, and the access token is denied after MFAMaxFailures wrong codes
+		client := server.extractLoginClient(ctx)
+		lockedUntil, err := server.loginLockedUntil(ctx, authPayload.Username, client.ClientIP)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to check login attempts: %s", err)
+		}
+		if time.Now().Before(lockedUntil) {
+			if err := server.recordLoginAttempt(ctx, client, authPayload.Username, util.LoginFailureLockedOut); err != nil {
+				return nil, err
+			}
+			return nil, resourceExhaustedError("too many failed login attempts, try again later", time.Until(lockedUntil))
+		}
+
+		err = server.checkMFACode(ctx, userMFA, req.GetCode())
+		if err != nil {
+			if err == errInvalidMFACode {
+				return nil, server.incorrectMFACodeError(ctx, client, authPayload)
+			}
+			return nil, status.Errorf(codes.Internal, "failed to check code: %s", err)
+		}
+	}
+
+	err = server.store.DisableMFATX(ctx, authPayload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to disable two-factor authentication: %s", err)
+	}
+
+	rsp := &pb.DisableMFAResponse{
+		MfaEnabled: false,
+	}
+	return rsp, nil
+}
+
+// validateDisableMFARequest will validate each property of the DisableMFARequest object
+func validateDisableMFARequest(req *pb.DisableMFARequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateMFACode(req.GetCode()); err != nil {
+		violations = append(violations, fieldViolation("code", err))
+	}
+	return violations
+}
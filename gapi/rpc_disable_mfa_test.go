@@ -0,0 +1,114 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "SimpleBankProject/db/mock"
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/pb"
+	"SimpleBankProject/totp"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+// randomUserMFA returns an enabled two-factor authentication of the user and its TOTP secret
+func randomUserMFA(t *testing.T, server *Server, username string) (db.UserMfa, string) {
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	encryptedSecret, err := server.totpCipher.Encrypt(secret)
+	require.NoError(t, err)
+	return db.UserMfa{Username: username, EncryptedSecret: encryptedSecret, IsEnabled: true}, secret
+}
+
+func TestDisableMFAMaxFailures(t *testing.T) {
+	username := util.RandomOwner()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+	server.config.MFAMaxFailures = 3
+	userMFA, secret := randomUserMFA(t, server, username)
+
+	ctx, accessPayload := newContextWithBearerToken(t, server.tokenMaker, username, util.DepositorRole, time.Minute)
+
+	// every wrong code is recorded, and the failures since the access token was issued are counted
+	failures := int64(0)
+	store.EXPECT().GetUserMFA(gomock.Any(), gomock.Eq(username)).Times(3).Return(userMFA, nil)
+	store.EXPECT().UseMFARecoveryCode(gomock.Any(), gomock.Any()).Times(3).Return(db.MfaRecoveryCode{}, sql.ErrNoRows)
+	store.EXPECT().
+		CreateLoginAttempt(gomock.Any(), gomock.Any()).
+		Times(3).
+		DoAndReturn(func(_ context.Context, arg db.CreateLoginAttemptParams) (db.LoginAttempt, error) {
+			require.Equal(t, username, arg.Username)
+			require.False(t, arg.Succeeded)
+			require.Equal(t, util.LoginFailureWrongMFACode, arg.FailureReason)
+			failures++
+			return db.LoginAttempt{}, nil
+		})
+	store.EXPECT().
+		GetMFAChallengeFailures(gomock.Any(), gomock.Any()).
+		Times(3).
+		DoAndReturn(func(_ context.Context, arg db.GetMFAChallengeFailuresParams) (int64, error) {
+			require.Equal(t, username, arg.Username)
+			require.WithinDuration(t, accessPayload.IssuedAt, arg.Since, time.Second)
+			return failures, nil
+		})
+	store.EXPECT().DisableMFATX(gomock.Any(), gomock.Any()).Times(0)
+
+	for i := 0; i < 3; i++ {
+		_, err := server.DisableMFA(ctx, &pb.DisableMFARequest{Code: "abcde-fghij"})
+		requireStatusCode(t, codes.Unauthenticated, err)
+	}
+
+	// the access token was denied by the last wrong code, so even a correct code can't be sent with it
+	code, err := totp.GenerateCode(secret, time.Now())
+	require.NoError(t, err)
+	_, err = server.DisableMFA(ctx, &pb.DisableMFARequest{Code: code})
+	requireStatusCode(t, codes.Unauthenticated, err)
+}
+
+func TestDisableMFALockout(t *testing.T) {
+	username := util.RandomOwner()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+	server.config.LoginMaxFailuresPerUser = 3
+	server.config.LoginLockoutDuration = time.Minute
+	server.config.LoginMaxLockoutDuration = time.Hour
+	server.config.LoginFailureWindow = 24 * time.Hour
+	userMFA, secret := randomUserMFA(t, server, username)
+
+	// the user is locked out by wrong passwords or codes, so the code isn't checked even if it is correct
+	store.EXPECT().GetUserMFA(gomock.Any(), gomock.Eq(username)).Times(1).Return(userMFA, nil)
+	store.EXPECT().
+		GetUserLoginFailures(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.GetUserLoginFailuresRow{Failures: 3, LastFailedAt: time.Now()}, nil)
+	store.EXPECT().UseTOTPStep(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().
+		CreateLoginAttempt(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CreateLoginAttemptParams) (db.LoginAttempt, error) {
+			require.Equal(t, util.LoginFailureLockedOut, arg.FailureReason)
+			return db.LoginAttempt{}, nil
+		})
+	store.EXPECT().DisableMFATX(gomock.Any(), gomock.Any()).Times(0)
+
+	code, err := totp.GenerateCode(secret, time.Now())
+	require.NoError(t, err)
+
+	ctx, _ := newContextWithBearerToken(t, server.tokenMaker, username, util.DepositorRole, time.Minute)
+	_, err = server.DisableMFA(ctx, &pb.DisableMFARequest{Code: code})
+	requireStatusCode(t, codes.ResourceExhausted, err)
+}
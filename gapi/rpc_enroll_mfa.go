@@ -0,0 +1,48 @@
+package gapi
+
+import (
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/pb"
+	"SimpleBankProject/totp"
+	"context"
+	"database/sql"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// EnrollMFA creates a new TOTP secret for the logged in user - two-factor authentication is only enabled once the user sends
+// a code of the secret to ConfirmMFA, so a user who never finishes the enrollment isn't locked out
+func (server *Server) EnrollMFA(ctx context.Context, req *pb.EnrollMFARequest) (*pb.EnrollMFAResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate secret: %s", err)
+	}
+	encryptedSecret, err := server.totpCipher.Encrypt(secret)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to encrypt secret: %s", err)
+	}
+
+	_, err = server.store.CreateUserMFA(ctx, db.CreateUserMFAParams{
+		Username:        authPayload.Username,
+		EncryptedSecret: encryptedSecret,
+	})
+	if err != nil {
+		// the secret isn't replaced once it is enabled, it must be disabled first
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.AlreadyExists, "two-factor authentication is already enabled")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to enroll: %s", err)
+	}
+
+	rsp := &pb.EnrollMFAResponse{
+		Secret: secret,
+		KeyUri: totp.KeyURI(server.config.TOTPIssuer, authPayload.Username, secret),
+	}
+	return rsp, nil
+}
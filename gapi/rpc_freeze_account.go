@@ -0,0 +1,53 @@
+package gapi
+
+import (
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/pb"
+	"SimpleBankProject/val"
+	"context"
+	"database/sql"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// FreezeAccount freezes or unfreezes an account - no money can move in to or out of a frozen account
+func (server *Server) FreezeAccount(ctx context.Context, req *pb.FreezeAccountRequest) (*pb.FreezeAccountResponse, error) {
+	// only admins may freeze accounts
+	if _, err := server.authorizeRole(ctx, util.AdminRole); err != nil {
+		return nil, err
+	}
+
+	// validate that the FreezeAccountRequest properties meet the criteria defined in validator.go
+	violations := validateFreezeAccountRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	account, err := server.store.UpdateAccountFrozen(ctx, db.UpdateAccountFrozenParams{
+		ID:       req.GetId(),
+		IsFrozen: req.GetFrozen(),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "account not found: %s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to freeze account: %s", err)
+	}
+
+	rsp := &pb.FreezeAccountResponse{
+		Account: convertAccount(account),
+	}
+
+	return rsp, nil
+}
+
+// validateFreezeAccountRequest will validate each property of the FreezeAccountRequest object
+func validateFreezeAccountRequest(req *pb.FreezeAccountRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetId()); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+	return violations
+}
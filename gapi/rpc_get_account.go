@@ -0,0 +1,54 @@
+package gapi
+
+import (
+	"SimpleBankProject/pb"
+	"SimpleBankProject/val"
+	"context"
+	"database/sql"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) GetAccount(ctx context.Context, req *pb.GetAccountRequest) (*pb.GetAccountResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	// validate that the GetAccountRequest properties meet the criteria defined in validator.go
+	violations := validateGetAccountRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	account, err := server.store.GetAccount(ctx, req.GetId())
+	if err != nil {
+		// there is no account with that id
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "account not found: %s", err)
+		}
+		// an internal error with querying data from the database
+		return nil, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	// we can only return the account data if the account owner matches the logged in user or the user is an admin
+	if !isOwnerOrAdmin(authPayload, account.Owner) {
+		return nil, status.Errorf(codes.PermissionDenied, "account doesn't belong to the authenticated user")
+	}
+
+	rsp := &pb.GetAccountResponse{
+		Account: convertAccount(account),
+	}
+
+	return rsp, nil
+}
+
+// validateGetAccountRequest will validate each property of the GetAccountRequest object
+func validateGetAccountRequest(req *pb.GetAccountRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetId()); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+	return violations
+}
@@ -0,0 +1,149 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "SimpleBankProject/db/mock"
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/pb"
+	"SimpleBankProject/token"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestGetAccountAPI(t *testing.T) {
+	username := util.RandomOwner()
+	account := randomAccount(username)
+
+	testCases := []struct {
+		name          string
+		accountID     int64
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, rsp *pb.GetAccountResponse, err error)
+	}{
+		{
+			name:      "OK",
+			accountID: account.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				ctx, _ := newContextWithBearerToken(t, tokenMaker, username, util.DepositorRole, time.Minute)
+				return ctx
+			},
+			checkResponse: func(t *testing.T, rsp *pb.GetAccountResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, account.ID, rsp.GetAccount().GetId())
+				require.Equal(t, account.Owner, rsp.GetAccount().GetOwner())
+			},
+		},
+		{
+			// admins may read the accounts of every user
+			name:      "Admin",
+			accountID: account.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				ctx, _ := newContextWithBearerToken(t, tokenMaker, util.RandomOwner(), util.AdminRole, time.Minute)
+				return ctx
+			},
+			checkResponse: func(t *testing.T, rsp *pb.GetAccountResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, account.ID, rsp.GetAccount().GetId())
+			},
+		},
+		{
+			name:      "Unauthorized User",
+			accountID: account.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				ctx, _ := newContextWithBearerToken(t, tokenMaker, util.RandomOwner(), util.DepositorRole, time.Minute)
+				return ctx
+			},
+			checkResponse: func(t *testing.T, rsp *pb.GetAccountResponse, err error) {
+				requireStatusCode(t, codes.PermissionDenied, err)
+			},
+		},
+		{
+			name:      "No Authorization",
+			accountID: account.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			checkResponse: func(t *testing.T, rsp *pb.GetAccountResponse, err error) {
+				requireStatusCode(t, codes.Unauthenticated, err)
+			},
+		},
+		{
+			name:      "Not Found",
+			accountID: account.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, sql.ErrNoRows)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				ctx, _ := newContextWithBearerToken(t, tokenMaker, username, util.DepositorRole, time.Minute)
+				return ctx
+			},
+			checkResponse: func(t *testing.T, rsp *pb.GetAccountResponse, err error) {
+				requireStatusCode(t, codes.NotFound, err)
+			},
+		},
+		{
+			name:      "Internal Error",
+			accountID: account.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, sql.ErrConnDone)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				ctx, _ := newContextWithBearerToken(t, tokenMaker, username, util.DepositorRole, time.Minute)
+				return ctx
+			},
+			checkResponse: func(t *testing.T, rsp *pb.GetAccountResponse, err error) {
+				requireStatusCode(t, codes.Internal, err)
+			},
+		},
+		{
+			name:      "Invalid ID",
+			accountID: 0,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				ctx, _ := newContextWithBearerToken(t, tokenMaker, username, util.DepositorRole, time.Minute)
+				return ctx
+			},
+			checkResponse: func(t *testing.T, rsp *pb.GetAccountResponse, err error) {
+				requireStatusCode(t, codes.InvalidArgument, err)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			rsp, err := server.GetAccount(tc.buildContext(t, server.tokenMaker), &pb.GetAccountRequest{Id: tc.accountID})
+			tc.checkResponse(t, rsp, err)
+		})
+	}
+}
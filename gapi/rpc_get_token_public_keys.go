@@ -0,0 +1,32 @@
+package gapi
+
+import (
+	"SimpleBankProject/pb"
+	"SimpleBankProject/token"
+	"context"
+	"encoding/hex"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetTokenPublicKeys returns the public keys which verify the signature of the tokens, so other services can check tokens
+// offline - only the "paseto_public" token maker has public keys, the symmetric makers can't share their key
+func (server *Server) GetTokenPublicKeys(ctx context.Context, req *pb.GetTokenPublicKeysRequest) (*pb.GetTokenPublicKeysResponse, error) {
+	maker, ok := server.tokenMaker.(token.PublicKeyMaker)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "token maker has no public keys")
+	}
+
+	rsp := &pb.GetTokenPublicKeysResponse{
+		Version: token.PublicKeyVersion,
+	}
+	for _, key := range maker.PublicKeys() {
+		rsp.Keys = append(rsp.Keys, &pb.TokenPublicKey{
+			KeyId:     key.KeyID,
+			PublicKey: hex.EncodeToString(key.PublicKey),
+		})
+	}
+
+	return rsp, nil
+}
@@ -0,0 +1,106 @@
+package gapi
+
+import (
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/pb"
+	"SimpleBankProject/val"
+	"context"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// minimum and maximum number of accounts per page - matches the binding tags of listAccountRequest in the api package
+const (
+	minAccountsPageSize = 5
+	maxAccountsPageSize = 10
+)
+
+func (server *Server) ListAccounts(ctx context.Context, req *pb.ListAccountsRequest) (*pb.ListAccountsResponse, error) {
+	// the logged in user is only allowed to see their accounts - the username is in the payload of the access token
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	// validate that the ListAccountsRequest properties meet the criteria defined in validator.go
+	violations := validateListAccountsRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	// without page_id the accounts are listed with cursor pagination
+	if req.GetPageId() == 0 {
+		return server.listAccountsAfter(ctx, authPayload.Username, req)
+	}
+
+	arg := db.ListAccountsParams{
+		Owner: authPayload.Username,
+		Limit: req.GetPageSize(),
+		// what page your on times the number of entries on a page equals where to begin for the next set of accounts
+		Offset: (req.GetPageId() - 1) * req.GetPageSize(),
+	}
+
+	accounts, err := server.store.ListAccounts(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list accounts: %s", err)
+	}
+
+	rsp := &pb.ListAccountsResponse{
+		Accounts: make([]*pb.Account, 0, len(accounts)),
+	}
+	for _, account := range accounts {
+		rsp.Accounts = append(rsp.Accounts, convertAccount(account))
+	}
+
+	return rsp, nil
+}
+
+// listAccountsAfter lists the page of accounts after the one in the page token - unlike an offset, the cursor doesn't skip or
+// repeat accounts when accounts are created or deleted between two pages
+func (server *Server) listAccountsAfter(ctx context.Context, owner string, req *pb.ListAccountsRequest) (*pb.ListAccountsResponse, error) {
+	// the page token has already been validated
+	afterID, _ := util.DecodePageToken(req.GetPageToken())
+
+	// one extra account is fetched to find out if there is another page
+	accounts, err := server.store.ListAccountsAfter(ctx, db.ListAccountsAfterParams{
+		Owner:   owner,
+		AfterID: afterID,
+		Limit:   req.GetPageSize() + 1,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list accounts: %s", err)
+	}
+
+	rsp := &pb.ListAccountsResponse{}
+	if len(accounts) > int(req.GetPageSize()) {
+		accounts = accounts[:req.GetPageSize()]
+		rsp.NextPageToken = util.EncodePageToken(accounts[len(accounts)-1].ID)
+	}
+
+	rsp.Accounts = make([]*pb.Account, 0, len(accounts))
+	for _, account := range accounts {
+		rsp.Accounts = append(rsp.Accounts, convertAccount(account))
+	}
+
+	return rsp, nil
+}
+
+// validateListAccountsRequest will validate each property of the ListAccountsRequest object
+func validateListAccountsRequest(req *pb.ListAccountsRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	// page_id is left out (0) for cursor pagination
+	if req.GetPageId() != 0 {
+		if err := val.ValidatePageID(req.GetPageId()); err != nil {
+			violations = append(violations, fieldViolation("page_id", err))
+		}
+	}
+	if err := val.ValidatePageSize(req.GetPageSize(), minAccountsPageSize, maxAccountsPageSize); err != nil {
+		violations = append(violations, fieldViolation("page_size", err))
+	}
+	if err := val.ValidatePageToken(req.GetPageToken(), req.GetPageId()); err != nil {
+		violations = append(violations, fieldViolation("page_token", err))
+	}
+	return violations
+}
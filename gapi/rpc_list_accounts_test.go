@@ -0,0 +1,140 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "SimpleBankProject/db/mock"
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/pb"
+	"SimpleBankProject/token"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestListAccountsAPI(t *testing.T) {
+	username := util.RandomOwner()
+
+	n := 5
+	// one account more than a page
+	accounts := make([]db.Account, n+1)
+	for i := range accounts {
+		accounts[i] = randomAccount(username)
+		accounts[i].ID = int64(i + 1)
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.ListAccountsRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, rsp *pb.ListAccountsResponse, err error)
+	}{
+		{
+			name: "OK Page",
+			req:  &pb.ListAccountsRequest{PageId: 2, PageSize: int32(n)},
+			buildStubs: func(store *mockdb.MockStore) {
+				// only the accounts of the logged in user are listed
+				arg := db.ListAccountsParams{
+					Owner:  username,
+					Limit:  int32(n),
+					Offset: int32(n),
+				}
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Eq(arg)).Times(1).Return(accounts[:n], nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				ctx, _ := newContextWithBearerToken(t, tokenMaker, username, util.DepositorRole, time.Minute)
+				return ctx
+			},
+			checkResponse: func(t *testing.T, rsp *pb.ListAccountsResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, rsp.GetAccounts(), n)
+				require.Empty(t, rsp.GetNextPageToken())
+			},
+		},
+		{
+			name: "OK Cursor",
+			req:  &pb.ListAccountsRequest{PageSize: int32(n)},
+			buildStubs: func(store *mockdb.MockStore) {
+				// one extra account tells there is another page
+				arg := db.ListAccountsAfterParams{
+					Owner:   username,
+					AfterID: 0,
+					Limit:   int32(n + 1),
+				}
+				store.EXPECT().ListAccountsAfter(gomock.Any(), gomock.Eq(arg)).Times(1).Return(accounts, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				ctx, _ := newContextWithBearerToken(t, tokenMaker, username, util.DepositorRole, time.Minute)
+				return ctx
+			},
+			checkResponse: func(t *testing.T, rsp *pb.ListAccountsResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, rsp.GetAccounts(), n)
+				require.Equal(t, util.EncodePageToken(accounts[n-1].ID), rsp.GetNextPageToken())
+			},
+		},
+		{
+			name: "No Authorization",
+			req:  &pb.ListAccountsRequest{PageId: 1, PageSize: int32(n)},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			checkResponse: func(t *testing.T, rsp *pb.ListAccountsResponse, err error) {
+				requireStatusCode(t, codes.Unauthenticated, err)
+			},
+		},
+		{
+			name: "Internal Error",
+			req:  &pb.ListAccountsRequest{PageId: 1, PageSize: int32(n)},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Times(1).Return([]db.Account{}, sql.ErrConnDone)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				ctx, _ := newContextWithBearerToken(t, tokenMaker, username, util.DepositorRole, time.Minute)
+				return ctx
+			},
+			checkResponse: func(t *testing.T, rsp *pb.ListAccountsResponse, err error) {
+				requireStatusCode(t, codes.Internal, err)
+			},
+		},
+		{
+			name: "Invalid Page Size",
+			req:  &pb.ListAccountsRequest{PageId: 1, PageSize: 100},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				ctx, _ := newContextWithBearerToken(t, tokenMaker, username, util.DepositorRole, time.Minute)
+				return ctx
+			},
+			checkResponse: func(t *testing.T, rsp *pb.ListAccountsResponse, err error) {
+				requireStatusCode(t, codes.InvalidArgument, err)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			rsp, err := server.ListAccounts(tc.buildContext(t, server.tokenMaker), tc.req)
+			tc.checkResponse(t, rsp, err)
+		})
+	}
+}
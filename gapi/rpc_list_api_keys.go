@@ -0,0 +1,29 @@
+package gapi
+
+import (
+	"SimpleBankProject/pb"
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListAPIKeys lists the API keys of the logged in user, including the revoked and expired ones
+func (server *Server) ListAPIKeys(ctx context.Context, req *pb.ListAPIKeysRequest) (*pb.ListAPIKeysResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	apiKeys, err := server.store.ListAPIKeysByUser(ctx, authPayload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list API keys: %s", err)
+	}
+
+	rsp := &pb.ListAPIKeysResponse{}
+	for _, apiKey := range apiKeys {
+		rsp.ApiKeys = append(rsp.ApiKeys, convertAPIKey(apiKey))
+	}
+
+	return rsp, nil
+}
@@ -0,0 +1,110 @@
+package gapi
+
+import (
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/pb"
+	"context"
+	"database/sql"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListEntries(ctx context.Context, req *pb.ListEntriesRequest) (*pb.ListEntriesResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	// validate that the ListEntriesRequest properties meet the criteria defined in validator.go
+	violations := validateListEntriesRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	// only the owner of the account or an admin may see its history
+	if err := server.authorizeAccountOwner(ctx, req.GetAccountId(), authPayload); err != nil {
+		return nil, err
+	}
+
+	startTime, endTime, sign := historyFilters(req.GetStartTime(), req.GetEndTime(), req.GetSign())
+	if req.GetPageId() == 0 {
+		return server.listEntriesAfter(ctx, req, startTime, endTime, sign)
+	}
+
+	arg := db.ListEntriesParams{
+		AccountID: req.GetAccountId(),
+		StartTime: startTime,
+		EndTime:   endTime,
+		Sign:      sign,
+		Limit:     req.GetPageSize(),
+		Offset:    (req.GetPageId() - 1) * req.GetPageSize(),
+	}
+
+	entries, err := server.store.ListEntries(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list entries: %s", err)
+	}
+
+	rsp := &pb.ListEntriesResponse{
+		Entries: make([]*pb.Entry, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		rsp.Entries = append(rsp.Entries, convertEntry(entry))
+	}
+
+	return rsp, nil
+}
+
+// listEntriesAfter lists the page of entries after the one in the page token - the cursor pagination of ListEntries
+func (server *Server) listEntriesAfter(
+	ctx context.Context,
+	req *pb.ListEntriesRequest,
+	startTime sql.NullTime,
+	endTime sql.NullTime,
+	sign sql.NullString,
+) (*pb.ListEntriesResponse, error) {
+	// the page token has already been validated
+	afterID, _ := util.DecodePageToken(req.GetPageToken())
+
+	// one extra entry is fetched to find out if there is another page
+	entries, err := server.store.ListEntriesAfter(ctx, db.ListEntriesAfterParams{
+		AccountID: req.GetAccountId(),
+		AfterID:   afterID,
+		StartTime: startTime,
+		EndTime:   endTime,
+		Sign:      sign,
+		Limit:     req.GetPageSize() + 1,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list entries: %s", err)
+	}
+
+	rsp := &pb.ListEntriesResponse{}
+	if len(entries) > int(req.GetPageSize()) {
+		entries = entries[:req.GetPageSize()]
+		rsp.NextPageToken = util.EncodePageToken(entries[len(entries)-1].ID)
+	}
+
+	rsp.Entries = make([]*pb.Entry, 0, len(entries))
+	for _, entry := range entries {
+		rsp.Entries = append(rsp.Entries, convertEntry(entry))
+	}
+
+	return rsp, nil
+}
+
+// validateListEntriesRequest will validate each property of the ListEntriesRequest object
+func validateListEntriesRequest(req *pb.ListEntriesRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	return validateHistoryRequest(
+		req.GetAccountId(),
+		req.GetPageId(),
+		req.GetPageSize(),
+		req.GetPageToken(),
+		req.GetStartTime(),
+		req.GetEndTime(),
+		req.GetSign(),
+	)
+}
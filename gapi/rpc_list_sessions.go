@@ -0,0 +1,32 @@
+package gapi
+
+import (
+	"SimpleBankProject/pb"
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListSessions lists the active sessions of the logged in user - one for every device or client the user has logged in
+// from, with its user agent and client IP so the user can spot sessions they don't recognize
+func (server *Server) ListSessions(ctx context.Context, req *pb.ListSessionsRequest) (*pb.ListSessionsResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	sessions, err := server.store.ListSessionsByUser(ctx, authPayload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list sessions: %s", err)
+	}
+
+	rsp := &pb.ListSessionsResponse{
+		Sessions: make([]*pb.Session, 0, len(sessions)),
+	}
+	for _, session := range sessions {
+		rsp.Sessions = append(rsp.Sessions, convertSession(session))
+	}
+
+	return rsp, nil
+}
@@ -0,0 +1,110 @@
+package gapi
+
+import (
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/pb"
+	"context"
+	"database/sql"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListTransfers(ctx context.Context, req *pb.ListTransfersRequest) (*pb.ListTransfersResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	// validate that the ListTransfersRequest properties meet the criteria defined in validator.go
+	violations := validateListTransfersRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	// only the owner of the account or an admin may see its history
+	if err := server.authorizeAccountOwner(ctx, req.GetAccountId(), authPayload); err != nil {
+		return nil, err
+	}
+
+	startTime, endTime, sign := historyFilters(req.GetStartTime(), req.GetEndTime(), req.GetSign())
+	if req.GetPageId() == 0 {
+		return server.listTransfersAfter(ctx, req, startTime, endTime, sign)
+	}
+
+	arg := db.ListTransfersParams{
+		AccountID: req.GetAccountId(),
+		StartTime: startTime,
+		EndTime:   endTime,
+		Sign:      sign,
+		Limit:     req.GetPageSize(),
+		Offset:    (req.GetPageId() - 1) * req.GetPageSize(),
+	}
+
+	transfers, err := server.store.ListTransfers(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list transfers: %s", err)
+	}
+
+	rsp := &pb.ListTransfersResponse{
+		Transfers: make([]*pb.Transfer, 0, len(transfers)),
+	}
+	for _, transfer := range transfers {
+		rsp.Transfers = append(rsp.Transfers, convertTransfer(transfer))
+	}
+
+	return rsp, nil
+}
+
+// listTransfersAfter lists the page of transfers after the one in the page token - the cursor pagination of ListTransfers
+func (server *Server) listTransfersAfter(
+	ctx context.Context,
+	req *pb.ListTransfersRequest,
+	startTime sql.NullTime,
+	endTime sql.NullTime,
+	sign sql.NullString,
+) (*pb.ListTransfersResponse, error) {
+	// the page token has already been validated
+	afterID, _ := util.DecodePageToken(req.GetPageToken())
+
+	// one extra transfer is fetched to find out if there is another page
+	transfers, err := server.store.ListTransfersAfter(ctx, db.ListTransfersAfterParams{
+		AccountID: req.GetAccountId(),
+		AfterID:   afterID,
+		StartTime: startTime,
+		EndTime:   endTime,
+		Sign:      sign,
+		Limit:     req.GetPageSize() + 1,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list transfers: %s", err)
+	}
+
+	rsp := &pb.ListTransfersResponse{}
+	if len(transfers) > int(req.GetPageSize()) {
+		transfers = transfers[:req.GetPageSize()]
+		rsp.NextPageToken = util.EncodePageToken(transfers[len(transfers)-1].ID)
+	}
+
+	rsp.Transfers = make([]*pb.Transfer, 0, len(transfers))
+	for _, transfer := range transfers {
+		rsp.Transfers = append(rsp.Transfers, convertTransfer(transfer))
+	}
+
+	return rsp, nil
+}
+
+// validateListTransfersRequest will validate each property of the ListTransfersRequest object
+func validateListTransfersRequest(req *pb.ListTransfersRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	return validateHistoryRequest(
+		req.GetAccountId(),
+		req.GetPageId(),
+		req.GetPageSize(),
+		req.GetPageToken(),
+		req.GetStartTime(),
+		req.GetEndTime(),
+		req.GetSign(),
+	)
+}
@@ -7,6 +7,8 @@ import (
 	"SimpleBankProject/val"
 	"context"
 	"database/sql"
+	"log"
+	"time"
 
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
@@ -23,33 +25,117 @@ func (server *Server) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (
 		return nil, invalidArgumentError(violations)
 	}
 
+	client := server.extractLoginClient(ctx)
+
+	// too many failed attempts to login as the user or from the client IP lock out the login for a while
+	lockedUntil, err := server.loginLockedUntil(ctx, req.GetUsername(), client.ClientIP)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get failed login attempts: %s", err)
+	}
+	if time.Now().Before(lockedUntil) {
+		if err := server.recordLoginAttempt(ctx, client, req.GetUsername(), util.LoginFailureLockedOut); err != nil {
+			return nil, err
+		}
+		return nil, resourceExhaustedError("too many failed login attempts, try again later", time.Until(lockedUntil))
+	}
+
 	// get user requested if it exists - GetUsername checks for nil which is better than just using req.Username
 	user, err := server.store.GetUser(ctx, req.GetUsername())
 	if err != nil {
 		// two reasons err may not be nil
-		// first, the user doesn't exist
+		// first, the user doesn't exist - the password is still checked, so the response takes as long and looks the same
+		// as for a wrong password
 		if err == sql.ErrNoRows {
-			return nil, status.Errorf(codes.NotFound, "failed to find user: %s", err)
+			server.hasher.CheckPasswordOfUnknownUser(req.GetPassword())
+			return nil, server.incorrectLoginError(ctx, client, req.GetUsername(), util.LoginFailureUnknownUser)
 		}
 		// second, internal issue with the GetUser api call
 		return nil, status.Errorf(codes.Internal, "failed to get user: %s", err)
 	}
 
 	// check if the password provided is correct - GetPassword() checks for nil
-	err = util.CheckPassword(req.GetPassword(), user.HashedPassword)
+	err = server.hasher.CheckPassword(req.GetPassword(), user.HashedPassword)
 	if err != nil {
 		// if err isn't nil, the password provided was incorrect
-		return nil, status.Errorf(codes.PermissionDenied, "password provided is incorrect: %s", err)
+		return nil, server.incorrectLoginError(ctx, client, req.GetUsername(), util.LoginFailureWrongPassword)
+	}
+
+	// a hash created with an outdated algorithm or cost is replaced while the password is at hand
+	server.upgradePasswordHash(ctx, user, req.GetPassword())
+
+	// users with two-factor authentication get a challenge token instead, which LoginUserMFA exchanges for the tokens
+	// together with a TOTP code - their login only succeeds once the code is checked, so the correct password doesn't reset
+	// the failed attempts, wrong codes included
+	mfaEnabled, err := server.isMFAEnabled(ctx, user.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get two-factor authentication: %s", err)
+	}
+	if mfaEnabled {
+		mfaToken, mfaPayload, err := server.tokenMaker.CreateMFAChallengeToken(user.Username, user.Role, server.config.MFAChallengeDuration)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to create MFA token: %s", err)
+		}
+		rsp := &pb.LoginUserResponse{
+			MfaChallenge: &pb.MFAChallenge{
+				MfaToken:          mfaToken,
+				MfaTokenExpiresAt: timestamppb.New(mfaPayload.ExpiredAt),
+			},
+		}
+		return rsp, nil
+	}
+
+	// a successful login resets the failed attempts of the user
+	err = server.recordLoginAttempt(ctx, client, user.Username, "")
+	if err != nil {
+		return nil, err
+	}
+
+	login, err := server.createLogin(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &pb.LoginUserResponse{
+		Login: login,
+	}
+	return rsp, nil
+}
+
+// upgradePasswordHash replaces the hash of the user's password if it wasn't created with the algorithm and cost of the
+// config - it is the gRPC counterpart of upgradePasswordHash in the api package
+func (server *Server) upgradePasswordHash(ctx context.Context, user db.User, password string) {
+	if !server.hasher.NeedsRehash(user.HashedPassword) {
+		return
 	}
 
-	// user exists and password provided is correct, create access token
-	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, server.config.AccessTokenDuration)
+	hashedPassword, err := server.hasher.Hash(password)
+	if err != nil {
+		log.Printf("cannot rehash password of user %s: %s", user.Username, err)
+		return
+	}
+	// no row is updated if the password was changed since the user was read - the new password is already hashed
+	_, err = server.store.UpdateUserPasswordHash(ctx, db.UpdateUserPasswordHashParams{
+		Username:          user.Username,
+		OldHashedPassword: user.HashedPassword,
+		NewHashedPassword: hashedPassword,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		log.Printf("cannot store rehashed password of user %s: %s", user.Username, err)
+	}
+}
+
+// createLogin creates the access token, refresh token and session of a user who has proven who they are - with their
+// password, and with a TOTP code if they enabled two-factor authentication - the returned error is already a gRPC status
+// error
+func (server *Server) createLogin(ctx context.Context, user db.User) (*pb.Login, error) {
+	// create access token
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, server.config.AccessTokenDuration)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to create access token: %s", err)
 	}
 
 	// create refresh token with a longer valid duration than the access token - will use to create session
-	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.Username, server.config.RefreshTokenDuration)
+	refreshToken, refreshPayload, err := server.tokenMaker.CreateRefreshToken(user.Username, user.Role, server.config.RefreshTokenDuration)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to create refresh token: %s", err)
 	}
@@ -66,23 +152,22 @@ func (server *Server) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (
 		ClientIp:     mtdt.ClientIP,
 		IsBlocked:    false,
 		ExpiresAt:    refreshPayload.ExpiredAt,
+		FamilyID:     refreshPayload.ID, // a login starts a new session family, every refresh adds a session to it
 	})
 
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to create session: %s", err)
 	}
 
-	rsp := &pb.LoginUserResponse{
-		Login: &pb.Login{
-			SessionId:             session.ID.String(),
-			AccessToken:           accessToken,
-			AccessTokenExpiresAt:  timestamppb.New(accessPayload.ExpiredAt),
-			RefreshToken:          refreshToken,
-			RefreshTokenExpiresAt: timestamppb.New(refreshPayload.ExpiredAt),
-			User:                  convertUser(user),
-		},
+	login := &pb.Login{
+		SessionId:             session.ID.String(),
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  timestamppb.New(accessPayload.ExpiredAt),
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: timestamppb.New(refreshPayload.ExpiredAt),
+		User:                  convertUser(user),
 	}
-	return rsp, nil
+	return login, nil
 }
 
 // validateLoginUserRequest will validate each property of the LoginUserRequest object - it will return a slice of errors
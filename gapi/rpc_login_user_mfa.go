@@ -0,0 +1,109 @@
+package gapi
+
+import (
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/pb"
+	"SimpleBankProject/token"
+	"SimpleBankProject/val"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// LoginUserMFA is the second step of the login of a user with two-factor authentication - it exchanges the challenge token
+// sent by LoginUser and a TOTP code for the access token, refresh token and session
+func (server *Server) LoginUserMFA(ctx context.Context, req *pb.LoginUserMFARequest) (*pb.LoginUserMFAResponse, error) {
+	// validate that the LoginUserMFARequest properties meet the criteria defined in validator.go
+	violations := validateLoginUserMFARequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	mfaPayload, err := server.tokenMaker.VerifyMFAChallengeToken(req.GetMfaToken())
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+	// every challenge token can only be used once - it is denied below once the login succeeds
+	err = token.CheckDenylist(ctx, server.denylist, mfaPayload)
+	if err != nil {
+		if errors.Is(err, token.ErrRevokedToken) {
+			return nil, unauthenticatedError(err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to check MFA token: %s", err)
+	}
+
+	// wrong codes count as failed logins of the user, so the lockout stops the guessing of codes as well
+	client := server.extractLoginClient(ctx)
+	lockedUntil, err := server.loginLockedUntil(ctx, mfaPayload.Username, client.ClientIP)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to check login attempts: %s", err)
+	}
+	if time.Now().Before(lockedUntil) {
+		if err := server.recordLoginAttempt(ctx, client, mfaPayload.Username, util.LoginFailureLockedOut); err != nil {
+			return nil, err
+		}
+		return nil, resourceExhaustedError("too many failed login attempts, try again later", time.Until(lockedUntil))
+	}
+
+	userMFA, err := server.store.GetUserMFA(ctx, mfaPayload.Username)
+	if err != nil {
+		// two-factor authentication was disabled after the challenge token was created
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.Unauthenticated, "two-factor authentication is not enabled")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get two-factor authentication: %s", err)
+	}
+	if !userMFA.IsEnabled {
+		return nil, status.Errorf(codes.Unauthenticated, "two-factor authentication is not enabled")
+	}
+
+	err = server.checkMFACode(ctx, userMFA, req.GetCode())
+	if err != nil {
+		if err == errInvalidMFACode {
+			return nil, server.incorrectMFACodeError(ctx, client, mfaPayload)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to check code: %s", err)
+	}
+
+	// the login succeeded, which resets the failed attempts of the user
+	err = server.recordLoginAttempt(ctx, client, mfaPayload.Username, "")
+	if err != nil {
+		return nil, err
+	}
+
+	err = server.denylist.Deny(ctx, mfaPayload)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to revoke MFA token: %s", err)
+	}
+
+	user, err := server.store.GetUser(ctx, mfaPayload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user: %s", err)
+	}
+
+	login, err := server.createLogin(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &pb.LoginUserMFAResponse{
+		Login: login,
+	}
+	return rsp, nil
+}
+
+// validateLoginUserMFARequest will validate each property of the LoginUserMFARequest object
+func validateLoginUserMFARequest(req *pb.LoginUserMFARequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateToken(req.GetMfaToken()); err != nil {
+		violations = append(violations, fieldViolation("mfa_token", err))
+	}
+	if err := val.ValidateMFACode(req.GetCode()); err != nil {
+		violations = append(violations, fieldViolation("code", err))
+	}
+	return violations
+}
@@ -0,0 +1,74 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "SimpleBankProject/db/mock"
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/pb"
+	"SimpleBankProject/totp"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestLoginUserMFAMaxFailures(t *testing.T) {
+	username := util.RandomOwner()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+	server.config.MFAMaxFailures = 3
+
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	encryptedSecret, err := server.totpCipher.Encrypt(secret)
+	require.NoError(t, err)
+	userMFA := db.UserMfa{Username: username, EncryptedSecret: encryptedSecret, IsEnabled: true}
+
+	mfaToken, mfaPayload, err := server.tokenMaker.CreateMFAChallengeToken(username, util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+
+	// every wrong code is recorded, and the failures since the challenge token was issued are counted
+	failures := int64(0)
+	store.EXPECT().GetUserMFA(gomock.Any(), gomock.Eq(username)).Times(3).Return(userMFA, nil)
+	store.EXPECT().UseMFARecoveryCode(gomock.Any(), gomock.Any()).Times(3).Return(db.MfaRecoveryCode{}, sql.ErrNoRows)
+	store.EXPECT().
+		CreateLoginAttempt(gomock.Any(), gomock.Any()).
+		Times(3).
+		DoAndReturn(func(_ context.Context, arg db.CreateLoginAttemptParams) (db.LoginAttempt, error) {
+			require.Equal(t, username, arg.Username)
+			require.False(t, arg.Succeeded)
+			require.Equal(t, util.LoginFailureWrongMFACode, arg.FailureReason)
+			failures++
+			return db.LoginAttempt{}, nil
+		})
+	store.EXPECT().
+		GetMFAChallengeFailures(gomock.Any(), gomock.Any()).
+		Times(3).
+		DoAndReturn(func(_ context.Context, arg db.GetMFAChallengeFailuresParams) (int64, error) {
+			require.Equal(t, username, arg.Username)
+			require.WithinDuration(t, mfaPayload.IssuedAt, arg.Since, time.Second)
+			return failures, nil
+		})
+	store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+
+	req := &pb.LoginUserMFARequest{MfaToken: mfaToken, Code: "abcde-fghij"}
+	for i := 0; i < 3; i++ {
+		_, err = server.LoginUserMFA(context.Background(), req)
+		requireStatusCode(t, codes.Unauthenticated, err)
+	}
+
+	// the challenge token was denied by the last wrong code, so even a correct code has to wait for a new login
+	code, err := totp.GenerateCode(secret, time.Now())
+	require.NoError(t, err)
+	_, err = server.LoginUserMFA(context.Background(), &pb.LoginUserMFARequest{MfaToken: mfaToken, Code: code})
+	requireStatusCode(t, codes.Unauthenticated, err)
+}
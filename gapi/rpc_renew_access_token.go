@@ -0,0 +1,135 @@
+package gapi
+
+import (
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/pb"
+	"SimpleBankProject/val"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// RenewAccessToken creates a new access token from a refresh token and rotates the refresh token - it performs the same session
+// checks as renewAccessToken in the api package, and each failed check returns its own status code so clients can tell them
+// apart:
+//   - blocked session: PermissionDenied - the user logged out or an admin blocked the session
+//   - username mismatch: Unauthenticated - the refresh token wasn't issued for the user of the session
+//   - refresh token mismatch: InvalidArgument - the refresh token isn't the one the session was created with
+//   - expired session: FailedPrecondition - the session can't be renewed any more, the user must login again
+func (server *Server) RenewAccessToken(ctx context.Context, req *pb.RenewAccessTokenRequest) (*pb.RenewAccessTokenResponse, error) {
+	// validate that the RenewAccessTokenRequest properties meet the criteria defined in validator.go
+	violations := validateRenewAccessTokenRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	// verify the refresh token is still valid
+	refreshPayload, err := server.tokenMaker.VerifyRefreshToken(req.GetRefreshToken())
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	// if refresh token is still valid, get session
+	session, err := server.store.GetSession(ctx, refreshPayload.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "session not found: %s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get session: %s", err)
+	}
+
+	if session.IsBlocked {
+		return nil, status.Errorf(codes.PermissionDenied, "blocked session")
+	}
+	if session.Username != refreshPayload.Username {
+		return nil, status.Errorf(codes.Unauthenticated, "incorrect session user")
+	}
+	if session.RefreshToken != req.GetRefreshToken() {
+		return nil, status.Errorf(codes.InvalidArgument, "mismatched session token")
+	}
+
+	// presenting a rotated refresh token again means it may have been stolen
+	if session.RotatedAt.Valid {
+		return nil, server.blockSessionFamily(ctx, session)
+	}
+
+	// reconfirming the session isn't expired - in rare cases, we may want to force the session to expire early
+	if time.Now().After(session.ExpiresAt) {
+		return nil, status.Errorf(codes.FailedPrecondition, "expired session")
+	}
+
+	// the role may have changed since login, so the new tokens carry the current role of the user
+	user, err := server.store.GetUser(ctx, refreshPayload.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.Unauthenticated, "user not found: %s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get user: %s", err)
+	}
+
+	// create new access token
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(
+		user.Username,
+		user.Role,
+		server.config.AccessTokenDuration,
+	)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create access token: %s", err)
+	}
+
+	// create the new refresh token - it expires with the session it replaces, so rotating doesn't extend the login
+	refreshToken, newRefreshPayload, err := server.tokenMaker.CreateRefreshToken(
+		user.Username,
+		user.Role,
+		time.Until(session.ExpiresAt),
+	)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create refresh token: %s", err)
+	}
+
+	// pass context for metadata extraction - allows us to populate UserAgent and ClientIP in the new session
+	mtdt := server.extractMetadata(ctx)
+
+	result, err := server.store.RotateSessionTX(ctx, db.RotateSessionTxParams{
+		SessionID: session.ID,
+		NewSession: db.CreateSessionParams{
+			ID:           newRefreshPayload.ID,
+			Username:     session.Username,
+			RefreshToken: refreshToken,
+			UserAgent:    mtdt.UserAgent,
+			ClientIp:     mtdt.ClientIP,
+			IsBlocked:    false,
+			ExpiresAt:    newRefreshPayload.ExpiredAt,
+		},
+	})
+	if err != nil {
+		// another request rotated the session since we read it - the refresh token was used twice
+		if errors.Is(err, db.ErrSessionAlreadyRotated) {
+			return nil, server.blockSessionFamily(ctx, session)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to rotate session: %s", err)
+	}
+
+	rsp := &pb.RenewAccessTokenResponse{
+		SessionId:             result.NewSession.ID.String(),
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  timestamppb.New(accessPayload.ExpiredAt),
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: timestamppb.New(newRefreshPayload.ExpiredAt),
+	}
+	return rsp, nil
+}
+
+// validateRenewAccessTokenRequest will validate each property of the RenewAccessTokenRequest object
+func validateRenewAccessTokenRequest(req *pb.RenewAccessTokenRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateToken(req.GetRefreshToken()); err != nil {
+		violations = append(violations, fieldViolation("refresh_token", err))
+	}
+	return violations
+}
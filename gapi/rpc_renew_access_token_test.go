@@ -0,0 +1,340 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "SimpleBankProject/db/mock"
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/pb"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestRenewAccessTokenAPI(t *testing.T) {
+	username := util.RandomOwner()
+
+	testCases := []struct {
+		name string
+		// request builds the request from the refresh token created for the session
+		request       func(refreshToken string) *pb.RenewAccessTokenRequest
+		updateSession func(session *db.Session)
+		buildStubs    func(store *mockdb.MockStore, session db.Session)
+		checkResponse func(t *testing.T, rsp *pb.RenewAccessTokenResponse, err error, refreshToken string)
+	}{
+		{
+			name: "OK",
+			request: func(refreshToken string) *pb.RenewAccessTokenRequest {
+				return &pb.RenewAccessTokenRequest{RefreshToken: refreshToken}
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(session.Username)).Times(1).Return(db.User{Username: username, Role: util.DepositorRole}, nil)
+				store.EXPECT().
+					RotateSessionTX(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.RotateSessionTxParams) (db.RotateSessionTxResult, error) {
+						require.Equal(t, session.ID, arg.SessionID)
+						require.Equal(t, session.Username, arg.NewSession.Username)
+						// the new refresh token expires with the session it replaces
+						require.WithinDuration(t, session.ExpiresAt, arg.NewSession.ExpiresAt, time.Second)
+
+						rotatedSession := session
+						rotatedSession.RotatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+						newSession := db.Session{
+							ID:                arg.NewSession.ID,
+							Username:          arg.NewSession.Username,
+							RefreshToken:      arg.NewSession.RefreshToken,
+							ExpiresAt:         arg.NewSession.ExpiresAt,
+							FamilyID:          session.FamilyID,
+							PreviousSessionID: uuid.NullUUID{UUID: session.ID, Valid: true},
+						}
+						return db.RotateSessionTxResult{RotatedSession: rotatedSession, NewSession: newSession}, nil
+					})
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.RenewAccessTokenResponse, err error, refreshToken string) {
+				require.NoError(t, err)
+				require.NotEmpty(t, rsp.GetAccessToken())
+				// the refresh token is rotated
+				require.NotEmpty(t, rsp.GetRefreshToken())
+				require.NotEqual(t, refreshToken, rsp.GetRefreshToken())
+			},
+		},
+		{
+			// a rotated refresh token is presented again - every session of the login is blocked
+			name: "Reused Refresh Token",
+			request: func(refreshToken string) *pb.RenewAccessTokenRequest {
+				return &pb.RenewAccessTokenRequest{RefreshToken: refreshToken}
+			},
+			updateSession: func(session *db.Session) {
+				session.RotatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().RotateSessionTX(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Eq(session.FamilyID)).Times(1).Return(int64(2), nil)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.RenewAccessTokenResponse, err error, refreshToken string) {
+				requireStatusCode(t, codes.Unauthenticated, err)
+			},
+		},
+		{
+			// another request rotated the session between GetSession and RotateSessionTX
+			name: "Concurrent Reuse",
+			request: func(refreshToken string) *pb.RenewAccessTokenRequest {
+				return &pb.RenewAccessTokenRequest{RefreshToken: refreshToken}
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(session.Username)).Times(1).Return(db.User{Username: username, Role: util.DepositorRole}, nil)
+				store.EXPECT().RotateSessionTX(gomock.Any(), gomock.Any()).Times(1).Return(db.RotateSessionTxResult{}, db.ErrSessionAlreadyRotated)
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Eq(session.FamilyID)).Times(1).Return(int64(2), nil)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.RenewAccessTokenResponse, err error, refreshToken string) {
+				requireStatusCode(t, codes.Unauthenticated, err)
+			},
+		},
+		{
+			name: "Blocked Session",
+			request: func(refreshToken string) *pb.RenewAccessTokenRequest {
+				return &pb.RenewAccessTokenRequest{RefreshToken: refreshToken}
+			},
+			updateSession: func(session *db.Session) {
+				session.IsBlocked = true
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().RotateSessionTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.RenewAccessTokenResponse, err error, refreshToken string) {
+				requireStatusCode(t, codes.PermissionDenied, err)
+			},
+		},
+		{
+			name: "Incorrect Session User",
+			request: func(refreshToken string) *pb.RenewAccessTokenRequest {
+				return &pb.RenewAccessTokenRequest{RefreshToken: refreshToken}
+			},
+			updateSession: func(session *db.Session) {
+				session.Username = util.RandomOwner()
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().RotateSessionTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.RenewAccessTokenResponse, err error, refreshToken string) {
+				requireStatusCode(t, codes.Unauthenticated, err)
+			},
+		},
+		{
+			name: "Mismatched Session Token",
+			request: func(refreshToken string) *pb.RenewAccessTokenRequest {
+				return &pb.RenewAccessTokenRequest{RefreshToken: refreshToken}
+			},
+			updateSession: func(session *db.Session) {
+				session.RefreshToken = util.RandomString(32)
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().RotateSessionTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.RenewAccessTokenResponse, err error, refreshToken string) {
+				requireStatusCode(t, codes.InvalidArgument, err)
+			},
+		},
+		{
+			// the session was expired early, before its refresh token
+			name: "Expired Session",
+			request: func(refreshToken string) *pb.RenewAccessTokenRequest {
+				return &pb.RenewAccessTokenRequest{RefreshToken: refreshToken}
+			},
+			updateSession: func(session *db.Session) {
+				session.ExpiresAt = time.Now().Add(-time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().RotateSessionTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.RenewAccessTokenResponse, err error, refreshToken string) {
+				requireStatusCode(t, codes.FailedPrecondition, err)
+			},
+		},
+		{
+			name: "Session Not Found",
+			request: func(refreshToken string) *pb.RenewAccessTokenRequest {
+				return &pb.RenewAccessTokenRequest{RefreshToken: refreshToken}
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(db.Session{}, sql.ErrNoRows)
+				store.EXPECT().RotateSessionTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.RenewAccessTokenResponse, err error, refreshToken string) {
+				requireStatusCode(t, codes.NotFound, err)
+			},
+		},
+		{
+			// the user was deleted after logging in
+			name: "User Not Found",
+			request: func(refreshToken string) *pb.RenewAccessTokenRequest {
+				return &pb.RenewAccessTokenRequest{RefreshToken: refreshToken}
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(session.Username)).Times(1).Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().RotateSessionTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.RenewAccessTokenResponse, err error, refreshToken string) {
+				requireStatusCode(t, codes.Unauthenticated, err)
+			},
+		},
+		{
+			name: "Internal Error",
+			request: func(refreshToken string) *pb.RenewAccessTokenRequest {
+				return &pb.RenewAccessTokenRequest{RefreshToken: refreshToken}
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(session.Username)).Times(1).Return(db.User{Username: username, Role: util.DepositorRole}, nil)
+				store.EXPECT().RotateSessionTX(gomock.Any(), gomock.Any()).Times(1).Return(db.RotateSessionTxResult{}, sql.ErrConnDone)
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.RenewAccessTokenResponse, err error, refreshToken string) {
+				requireStatusCode(t, codes.Internal, err)
+			},
+		},
+		{
+			name: "Invalid Token",
+			request: func(refreshToken string) *pb.RenewAccessTokenRequest {
+				return &pb.RenewAccessTokenRequest{RefreshToken: "invalid"}
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().RotateSessionTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.RenewAccessTokenResponse, err error, refreshToken string) {
+				requireStatusCode(t, codes.Unauthenticated, err)
+			},
+		},
+		{
+			name: "Missing Token",
+			request: func(refreshToken string) *pb.RenewAccessTokenRequest {
+				return &pb.RenewAccessTokenRequest{}
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().RotateSessionTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.RenewAccessTokenResponse, err error, refreshToken string) {
+				requireStatusCode(t, codes.InvalidArgument, err)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := newTestServer(t, store)
+
+			// the refresh token must be created by the token maker of the test server
+			refreshToken, refreshPayload, err := server.tokenMaker.CreateRefreshToken(username, util.DepositorRole, time.Hour)
+			require.NoError(t, err)
+
+			session := db.Session{
+				ID:           refreshPayload.ID,
+				FamilyID:     refreshPayload.ID,
+				Username:     username,
+				RefreshToken: refreshToken,
+				UserAgent:    "test-agent",
+				ClientIp:     "127.0.0.1",
+				ExpiresAt:    refreshPayload.ExpiredAt,
+				CreatedAt:    refreshPayload.IssuedAt,
+			}
+			if tc.updateSession != nil {
+				tc.updateSession(&session)
+			}
+			tc.buildStubs(store, session)
+
+			rsp, err := server.RenewAccessToken(context.Background(), tc.request(refreshToken))
+			tc.checkResponse(t, rsp, err, refreshToken)
+		})
+	}
+}
+
+// TestRenewAccessTokenCurrentRole - tests that the renewed tokens carry the current role of the user, not the role the
+// user had when logging in
+func TestRenewAccessTokenCurrentRole(t *testing.T) {
+	username := util.RandomOwner()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+
+	// the user was a depositor at login and has been made an admin since
+	refreshToken, refreshPayload, err := server.tokenMaker.CreateRefreshToken(username, util.DepositorRole, time.Hour)
+	require.NoError(t, err)
+
+	session := db.Session{
+		ID:           refreshPayload.ID,
+		FamilyID:     refreshPayload.ID,
+		Username:     username,
+		RefreshToken: refreshToken,
+		ExpiresAt:    refreshPayload.ExpiredAt,
+		CreatedAt:    refreshPayload.IssuedAt,
+	}
+
+	store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(username)).Times(1).Return(db.User{Username: username, Role: util.AdminRole}, nil)
+	store.EXPECT().
+		RotateSessionTX(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.RotateSessionTxParams) (db.RotateSessionTxResult, error) {
+			newSession := db.Session{
+				ID:           arg.NewSession.ID,
+				Username:     arg.NewSession.Username,
+				RefreshToken: arg.NewSession.RefreshToken,
+				ExpiresAt:    arg.NewSession.ExpiresAt,
+			}
+			return db.RotateSessionTxResult{RotatedSession: session, NewSession: newSession}, nil
+		})
+
+	rsp, err := server.RenewAccessToken(context.Background(), &pb.RenewAccessTokenRequest{RefreshToken: refreshToken})
+	require.NoError(t, err)
+
+	accessPayload, err := server.tokenMaker.VerifyToken(rsp.GetAccessToken())
+	require.NoError(t, err)
+	require.Equal(t, util.AdminRole, accessPayload.Role)
+
+	newRefreshPayload, err := server.tokenMaker.VerifyRefreshToken(rsp.GetRefreshToken())
+	require.NoError(t, err)
+	require.Equal(t, util.AdminRole, newRefreshPayload.Role)
+}
+
+// TestRenewAccessTokenWithAccessToken - tests that an access token can't be used to renew the session it was created with
+func TestRenewAccessTokenWithAccessToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(0)
+	server := newTestServer(t, store)
+
+	accessToken, _, err := server.tokenMaker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+
+	_, err = server.RenewAccessToken(context.Background(), &pb.RenewAccessTokenRequest{RefreshToken: accessToken})
+	requireStatusCode(t, codes.Unauthenticated, err)
+}
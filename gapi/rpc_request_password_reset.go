@@ -0,0 +1,78 @@
+package gapi
+
+import (
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/mail"
+	"SimpleBankProject/pb"
+	"SimpleBankProject/val"
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// the same answer is sent whether the email belongs to a user or not, so the RPC can't be used to find out who has an
+// account at the bank
+const passwordResetSentMessage = "if the email belongs to a user, a password reset link has been sent to it"
+
+// RequestPasswordReset emails a single use link to reset the password of the user with the email - only the hash of the token
+// in the link is stored, and it is one of the publicMethods as the user can't login
+func (server *Server) RequestPasswordReset(ctx context.Context, req *pb.RequestPasswordResetRequest) (*pb.RequestPasswordResetResponse, error) {
+	// validate that the RequestPasswordResetRequest properties meet the criteria defined in validator.go
+	violations := validateRequestPasswordResetRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	resetToken, err := util.NewSecretToken()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create password reset token: %s", err)
+	}
+
+	result, err := server.store.CreatePasswordResetTX(ctx, db.CreatePasswordResetTxParams{
+		Email:     req.GetEmail(),
+		TokenHash: util.HashSecretToken(resetToken),
+		ExpiresAt: time.Now().Add(server.config.PasswordResetTokenDuration),
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, status.Errorf(codes.Internal, "failed to create password reset: %s", err)
+	}
+	if err == nil {
+		server.sendPasswordResetEmail(ctx, result.User, resetToken)
+	}
+
+	rsp := &pb.RequestPasswordResetResponse{
+		Message: passwordResetSentMessage,
+	}
+
+	return rsp, nil
+}
+
+// sendPasswordResetEmail sends the token to the user - it links to the password reset page in the config
+// it is called once CreatePasswordResetTX has committed - the response is the same whether the email could be sent or not, so a
+// failed email is only logged and the user can request another one
+func (server *Server) sendPasswordResetEmail(ctx context.Context, user db.User, resetToken string) {
+	msg := mail.PasswordResetMessage(
+		user.Email,
+		user.FullName,
+		server.config.PasswordResetURL,
+		resetToken,
+		server.config.PasswordResetTokenDuration,
+	)
+	if err := server.mailer.SendEmail(ctx, msg); err != nil {
+		log.Printf("cannot send password reset email to user %s: %s", user.Username, err)
+	}
+}
+
+// validateRequestPasswordResetRequest will validate each property of the RequestPasswordResetRequest object
+func validateRequestPasswordResetRequest(req *pb.RequestPasswordResetRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateEmail(req.GetEmail()); err != nil {
+		violations = append(violations, fieldViolation("email", err))
+	}
+	return violations
+}
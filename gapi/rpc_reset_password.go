@@ -0,0 +1,58 @@
+package gapi
+
+import (
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/pb"
+	"SimpleBankProject/val"
+	"context"
+	"database/sql"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ResetPassword sets the new password of the user the password reset token was sent to - every session of the user is
+// blocked, so they have to login again with the new password
+func (server *Server) ResetPassword(ctx context.Context, req *pb.ResetPasswordRequest) (*pb.ResetPasswordResponse, error) {
+	// validate that the ResetPasswordRequest properties meet the criteria defined in validator.go
+	violations := validateResetPasswordRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	hashedPassword, err := server.hasher.Hash(req.GetPassword())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to hash password: %s", err)
+	}
+
+	result, err := server.store.ResetPasswordTX(ctx, db.ResetPasswordTxParams{
+		TokenHash:      util.HashSecretToken(req.GetToken()),
+		HashedPassword: hashedPassword,
+	})
+	if err != nil {
+		// the token is wrong, was already used or has expired
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "invalid or expired password reset token")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to reset password: %s", err)
+	}
+
+	rsp := &pb.ResetPasswordResponse{
+		User: convertUser(result.User),
+	}
+
+	return rsp, nil
+}
+
+// validateResetPasswordRequest will validate each property of the ResetPasswordRequest object
+func validateResetPasswordRequest(req *pb.ResetPasswordRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateSecretToken(req.GetToken()); err != nil {
+		violations = append(violations, fieldViolation("token", err))
+	}
+	if err := val.ValidatePassword(req.GetPassword()); err != nil {
+		violations = append(violations, fieldViolation("password", err))
+	}
+	return violations
+}
@@ -0,0 +1,78 @@
+package gapi
+
+import (
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/pb"
+	"SimpleBankProject/val"
+	"context"
+	"database/sql"
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ReverseTransfer(ctx context.Context, req *pb.ReverseTransferRequest) (*pb.ReverseTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	// validate that the ReverseTransferRequest properties meet the criteria defined in validator.go
+	violations := validateReverseTransferRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	transfer, err := server.store.GetTransfer(ctx, req.GetId())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "transfer not found: %s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get transfer: %s", err)
+	}
+
+	// only the owner of the account that received the money may send it back - admins may reverse any transfer
+	toAccount, err := server.store.GetAccount(ctx, transfer.ToAccountID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	if !isOwnerOrAdmin(authPayload, toAccount.Owner) {
+		return nil, status.Errorf(codes.PermissionDenied, "transfer wasn't received by an account of the authenticated user")
+	}
+
+	result, err := server.store.ReverseTransferTX(ctx, db.ReverseTransferTxParams{
+		TransferID: transfer.ID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrTransferAlreadyReversed):
+			return nil, status.Errorf(codes.AlreadyExists, "cannot reverse transfer: %s", err)
+		// the transfer is a reversal, the receiving account no longer holds the money, or an admin froze one of the accounts
+		case errors.Is(err, db.ErrCannotReverseReversal), errors.Is(err, db.ErrInsufficientFunds), errors.Is(err, db.ErrAccountFrozen):
+			return nil, status.Errorf(codes.FailedPrecondition, "cannot reverse transfer: %s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to reverse transfer: %s", err)
+	}
+
+	rsp := &pb.ReverseTransferResponse{
+		OriginalTransfer: convertTransfer(result.OriginalTransfer),
+		Transfer:         convertTransfer(result.Reversal.Transfer),
+		FromAccount:      convertAccount(result.Reversal.FromAccount),
+		ToAccount:        convertAccount(result.Reversal.ToAccount),
+		FromEntry:        convertEntry(result.Reversal.FromEntry),
+		ToEntry:          convertEntry(result.Reversal.ToEntry),
+	}
+
+	return rsp, nil
+}
+
+// validateReverseTransferRequest will validate each property of the ReverseTransferRequest object
+func validateReverseTransferRequest(req *pb.ReverseTransferRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetId()); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+	return violations
+}
@@ -0,0 +1,61 @@
+package gapi
+
+import (
+	"SimpleBankProject/pb"
+	"SimpleBankProject/val"
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RevokeAPIKey revokes one of the API keys of the logged in user - it stops working immediately
+func (server *Server) RevokeAPIKey(ctx context.Context, req *pb.RevokeAPIKeyRequest) (*pb.RevokeAPIKeyResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	// validate that the RevokeAPIKeyRequest properties meet the criteria defined in validator.go
+	violations := validateRevokeAPIKeyRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	// the API key id has already been validated, so it can be parsed
+	apiKey, err := server.store.GetAPIKey(ctx, uuid.MustParse(req.GetApiKeyId()))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "API key not found: %s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get API key: %s", err)
+	}
+
+	// users may only revoke their own API keys - admins may revoke any API key
+	if !isOwnerOrAdmin(authPayload, apiKey.Username) {
+		return nil, status.Errorf(codes.PermissionDenied, "API key doesn't belong to the authenticated user")
+	}
+
+	apiKey, err = server.store.RevokeAPIKey(ctx, apiKey.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to revoke API key: %s", err)
+	}
+
+	rsp := &pb.RevokeAPIKeyResponse{
+		ApiKey: convertAPIKey(apiKey),
+	}
+
+	return rsp, nil
+}
+
+// validateRevokeAPIKeyRequest will validate each property of the RevokeAPIKeyRequest object
+func validateRevokeAPIKeyRequest(req *pb.RevokeAPIKeyRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	// API key ids are uuids like session ids
+	if err := val.ValidateSessionID(req.GetApiKeyId()); err != nil {
+		violations = append(violations, fieldViolation("api_key_id", err))
+	}
+	return violations
+}
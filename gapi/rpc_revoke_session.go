@@ -0,0 +1,81 @@
+package gapi
+
+import (
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/pb"
+	"SimpleBankProject/token"
+	"SimpleBankProject/val"
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RevokeSession blocks one of the sessions of the logged in user, e.g. a session on a lost device - its refresh token can no
+// longer renew access tokens
+func (server *Server) RevokeSession(ctx context.Context, req *pb.RevokeSessionRequest) (*pb.RevokeSessionResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	// validate that the RevokeSessionRequest properties meet the criteria defined in validator.go
+	violations := validateRevokeSessionRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	// the session id has already been validated, so it can be parsed
+	session, err := server.store.GetSession(ctx, uuid.MustParse(req.GetSessionId()))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "session not found: %s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get session: %s", err)
+	}
+
+	// users may only revoke their own sessions - admins may revoke any session
+	if !isOwnerOrAdmin(authPayload, session.Username) {
+		return nil, status.Errorf(codes.PermissionDenied, "session doesn't belong to the authenticated user")
+	}
+
+	session, err = server.blockAndDenySession(ctx, session.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to block session: %s", err)
+	}
+
+	rsp := &pb.RevokeSessionResponse{
+		Session: convertSession(session),
+	}
+
+	return rsp, nil
+}
+
+// blockAndDenySession blocks the session, so its refresh token can no longer renew access tokens, and adds the refresh
+// token to the denylist - the session ID is the ID of its refresh token - refresh tokens created before they had a purpose
+// pass VerifyToken, and would otherwise keep working as access tokens until they expire
+// sql.ErrNoRows is returned as is if the session doesn't exist
+func (server *Server) blockAndDenySession(ctx context.Context, sessionID uuid.UUID) (db.Session, error) {
+	session, err := server.store.BlockSession(ctx, sessionID)
+	if err != nil {
+		return session, err
+	}
+
+	err = server.denylist.Deny(ctx, &token.Payload{
+		ID:        session.ID,
+		Username:  session.Username,
+		ExpiredAt: session.ExpiresAt,
+	})
+	return session, err
+}
+
+// validateRevokeSessionRequest will validate each property of the RevokeSessionRequest object
+func validateRevokeSessionRequest(req *pb.RevokeSessionRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateSessionID(req.GetSessionId()); err != nil {
+		violations = append(violations, fieldViolation("session_id", err))
+	}
+	return violations
+}
@@ -0,0 +1,101 @@
+package gapi
+
+import (
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/pb"
+	"SimpleBankProject/token"
+	"SimpleBankProject/val"
+	"context"
+	"database/sql"
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RevokeToken logs the user out by blocking the session of the refresh token - holding the refresh token is proof enough,
+// so it is one of the publicMethods which don't need an access token
+func (server *Server) RevokeToken(ctx context.Context, req *pb.RevokeTokenRequest) (*pb.RevokeTokenResponse, error) {
+	// validate that the RevokeTokenRequest properties meet the criteria defined in validator.go
+	violations := validateRevokeTokenRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	refreshPayload, err := server.tokenMaker.VerifyRefreshToken(req.GetRefreshToken())
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	session, err := server.store.GetSession(ctx, refreshPayload.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "session not found: %s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get session: %s", err)
+	}
+
+	// the refresh token must be the one the session was created with
+	if session.Username != refreshPayload.Username {
+		return nil, status.Errorf(codes.Unauthenticated, "incorrect session user")
+	}
+	if session.RefreshToken != req.GetRefreshToken() {
+		return nil, status.Errorf(codes.Unauthenticated, "mismatched session token")
+	}
+	// presenting a rotated refresh token again means it may have been stolen
+	if session.RotatedAt.Valid {
+		return nil, server.blockSessionFamily(ctx, session)
+	}
+
+	// the optional access token is denied so it stops working right away - one which has already expired doesn't need to be
+	var accessPayload *token.Payload
+	if req.GetAccessToken() != "" {
+		accessPayload, err = server.tokenMaker.VerifyToken(req.GetAccessToken())
+		if err != nil && !errors.Is(err, token.ErrExpiredToken) {
+			return nil, unauthenticatedError(err)
+		}
+		// a user may only revoke their own access token
+		if accessPayload != nil && accessPayload.Username != refreshPayload.Username {
+			return nil, status.Errorf(codes.Unauthenticated, "access token doesn't belong to the session user")
+		}
+	}
+
+	// revoking a session twice is not an error - the user is logged out either way
+	session, err = server.blockAndDenySession(ctx, session.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to block session: %s", err)
+	}
+
+	if accessPayload != nil {
+		err = server.denylist.Deny(ctx, accessPayload)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to revoke access token: %s", err)
+		}
+	}
+
+	rsp := &pb.RevokeTokenResponse{
+		Session: convertSession(session),
+	}
+
+	return rsp, nil
+}
+
+// blockSessionFamily blocks every session created from the same login as the input session, which revokes their refresh
+// tokens as well, used when a refresh token which was already rotated is presented again - the returned error is the status
+// error to send the client
+func (server *Server) blockSessionFamily(ctx context.Context, session db.Session) error {
+	_, err := server.store.BlockSessionFamily(ctx, session.FamilyID)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to block session family: %s", err)
+	}
+	return status.Errorf(codes.Unauthenticated, "refresh token has already been used, all sessions of this login have been blocked")
+}
+
+// validateRevokeTokenRequest will validate each property of the RevokeTokenRequest object
+func validateRevokeTokenRequest(req *pb.RevokeTokenRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateToken(req.GetRefreshToken()); err != nil {
+		violations = append(violations, fieldViolation("refresh_token", err))
+	}
+	return violations
+}
@@ -0,0 +1,130 @@
+package gapi
+
+import (
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/pb"
+	"SimpleBankProject/val"
+	"context"
+	"database/sql"
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) TransferMoney(ctx context.Context, req *pb.TransferMoneyRequest) (*pb.TransferMoneyResponse, error) {
+	// only the owner of the from account is allowed to transfer money out of it
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	// validate that the TransferMoneyRequest properties meet the criteria defined in validator.go
+	violations := validateTransferMoneyRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	if err := server.requireVerifiedEmail(ctx, authPayload.Username); err != nil {
+		return nil, err
+	}
+
+	// check if FromAccountId exists and has the correct currency for the transfer
+	fromAccount, err := server.validAccount(ctx, req.GetFromAccountId(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	if fromAccount.Owner != authPayload.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "from account does not belong to authenticated user")
+	}
+
+	// check if ToAccountId exists and has the correct currency for the transfer - we do not care who owns the to account
+	_, err = server.validAccount(ctx, req.GetToAccountId(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	arg := db.TransferTxParams{
+		FromAccountID:  req.GetFromAccountId(),
+		ToAccountID:    req.GetToAccountId(),
+		Amount:         req.GetAmount(),
+		IdempotencyKey: req.GetIdempotencyKey(),
+	}
+
+	result, err := server.store.TransferTX(ctx, arg)
+	if err != nil {
+		// the from account doesn't have enough money to cover the transfer - nothing was transferred
+		if errors.Is(err, db.ErrInsufficientFunds) {
+			return nil, status.Errorf(codes.FailedPrecondition, "cannot transfer money: %s", err)
+		}
+		// the idempotency key was already used for a different transfer - FailedPrecondition matches the 422 of the HTTP
+		// API, the request itself is valid
+		if errors.Is(err, db.ErrIdempotencyKeyReused) {
+			return nil, status.Errorf(codes.FailedPrecondition, "cannot transfer money: %s", err)
+		}
+		// an admin froze one of the accounts after validAccount checked it
+		if errors.Is(err, db.ErrAccountFrozen) {
+			return nil, status.Errorf(codes.FailedPrecondition, "cannot transfer money: %s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to transfer money: %s", err)
+	}
+
+	rsp := &pb.TransferMoneyResponse{
+		Transfer:    convertTransfer(result.Transfer),
+		FromAccount: convertAccount(result.FromAccount),
+		ToAccount:   convertAccount(result.ToAccount),
+		FromEntry:   convertEntry(result.FromEntry),
+		ToEntry:     convertEntry(result.ToEntry),
+	}
+
+	return rsp, nil
+}
+
+// validAccount confirms the account exists, isn't frozen and that its currency matches the input currency - the returned error is
+// already a gRPC status error
+func (server *Server) validAccount(ctx context.Context, accountID int64, currency string) (db.Account, error) {
+	account, err := server.store.GetAccount(ctx, accountID)
+	if err != nil {
+		// account doesn't exist
+		if err == sql.ErrNoRows {
+			return account, status.Errorf(codes.NotFound, "account [%d] not found: %s", accountID, err)
+		}
+		// internal error
+		return account, status.Errorf(codes.Internal, "failed to get account [%d]: %s", accountID, err)
+	}
+
+	if account.Currency != currency {
+		return account, status.Errorf(codes.InvalidArgument, "account [%d] currency mismatch: %s vs %s", accountID, account.Currency, currency)
+	}
+
+	// no money can move in to or out of an account frozen by an admin
+	if account.IsFrozen {
+		return account, status.Errorf(codes.FailedPrecondition, "account [%d] is frozen", accountID)
+	}
+	return account, nil
+}
+
+// validateTransferMoneyRequest will validate each property of the TransferMoneyRequest object
+func validateTransferMoneyRequest(req *pb.TransferMoneyRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetFromAccountId()); err != nil {
+		violations = append(violations, fieldViolation("from_account_id", err))
+	}
+	if err := val.ValidateID(req.GetToAccountId()); err != nil {
+		violations = append(violations, fieldViolation("to_account_id", err))
+	}
+	if err := val.ValidateAmount(req.GetAmount()); err != nil {
+		violations = append(violations, fieldViolation("amount", err))
+	}
+	if err := val.ValidateCurrency(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+	}
+	// the idempotency key is optional
+	if req.GetIdempotencyKey() != "" {
+		if err := val.ValidateIdempotencyKey(req.GetIdempotencyKey()); err != nil {
+			violations = append(violations, fieldViolation("idempotency_key", err))
+		}
+	}
+	return violations
+}
@@ -0,0 +1,323 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "SimpleBankProject/db/mock"
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/pb"
+	"SimpleBankProject/token"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestTransferMoneyAPI(t *testing.T) {
+	amount := int64(10)
+
+	user1 := util.RandomOwner()
+	user2 := util.RandomOwner()
+	user3 := util.RandomOwner()
+
+	account1 := randomAccount(user1)
+	account2 := randomAccount(user2)
+	account3 := randomAccount(user3)
+	account2.ID = account1.ID + 1
+	account3.ID = account1.ID + 2
+	account3.Currency = util.EUR
+
+	testCases := []struct {
+		name          string
+		req           *pb.TransferMoneyRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, rsp *pb.TransferMoneyResponse, err error)
+	}{
+		{
+			name: "OK",
+			req: &pb.TransferMoneyRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        amount,
+				Currency:      util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				arg := db.TransferTxParams{
+					FromAccountID: account1.ID,
+					ToAccountID:   account2.ID,
+					Amount:        amount,
+				}
+				result := db.TransferTxResult{
+					Transfer:    db.Transfer{ID: 1, FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: amount},
+					FromAccount: account1,
+					ToAccount:   account2,
+				}
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				ctx, _ := newContextWithBearerToken(t, tokenMaker, user1, util.DepositorRole, time.Minute)
+				return ctx
+			},
+			checkResponse: func(t *testing.T, rsp *pb.TransferMoneyResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, account1.ID, rsp.GetTransfer().GetFromAccountId())
+				require.Equal(t, account2.ID, rsp.GetTransfer().GetToAccountId())
+				require.Equal(t, amount, rsp.GetTransfer().GetAmount())
+			},
+		},
+		{
+			// only the owner of the from account may transfer money out of it
+			name: "Unauthorized User",
+			req: &pb.TransferMoneyRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        amount,
+				Currency:      util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(0)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				ctx, _ := newContextWithBearerToken(t, tokenMaker, user2, util.DepositorRole, time.Minute)
+				return ctx
+			},
+			checkResponse: func(t *testing.T, rsp *pb.TransferMoneyResponse, err error) {
+				requireStatusCode(t, codes.PermissionDenied, err)
+			},
+		},
+		{
+			name: "No Authorization",
+			req: &pb.TransferMoneyRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        amount,
+				Currency:      util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			checkResponse: func(t *testing.T, rsp *pb.TransferMoneyResponse, err error) {
+				requireStatusCode(t, codes.Unauthenticated, err)
+			},
+		},
+		{
+			name: "From Account Not Found",
+			req: &pb.TransferMoneyRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        amount,
+				Currency:      util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(db.Account{}, sql.ErrNoRows)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(0)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				ctx, _ := newContextWithBearerToken(t, tokenMaker, user1, util.DepositorRole, time.Minute)
+				return ctx
+			},
+			checkResponse: func(t *testing.T, rsp *pb.TransferMoneyResponse, err error) {
+				requireStatusCode(t, codes.NotFound, err)
+			},
+		},
+		{
+			name: "To Account Not Found",
+			req: &pb.TransferMoneyRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        amount,
+				Currency:      util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(db.Account{}, sql.ErrNoRows)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				ctx, _ := newContextWithBearerToken(t, tokenMaker, user1, util.DepositorRole, time.Minute)
+				return ctx
+			},
+			checkResponse: func(t *testing.T, rsp *pb.TransferMoneyResponse, err error) {
+				requireStatusCode(t, codes.NotFound, err)
+			},
+		},
+		{
+			name: "To Account Currency Mismatch",
+			req: &pb.TransferMoneyRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account3.ID,
+				Amount:        amount,
+				Currency:      util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account3.ID)).Times(1).Return(account3, nil)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				ctx, _ := newContextWithBearerToken(t, tokenMaker, user1, util.DepositorRole, time.Minute)
+				return ctx
+			},
+			checkResponse: func(t *testing.T, rsp *pb.TransferMoneyResponse, err error) {
+				requireStatusCode(t, codes.InvalidArgument, err)
+			},
+		},
+		{
+			name: "Get Account Internal Error",
+			req: &pb.TransferMoneyRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        amount,
+				Currency:      util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(db.Account{}, sql.ErrConnDone)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				ctx, _ := newContextWithBearerToken(t, tokenMaker, user1, util.DepositorRole, time.Minute)
+				return ctx
+			},
+			checkResponse: func(t *testing.T, rsp *pb.TransferMoneyResponse, err error) {
+				requireStatusCode(t, codes.Internal, err)
+			},
+		},
+		{
+			name: "Insufficient Funds",
+			req: &pb.TransferMoneyRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        amount,
+				Currency:      util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, db.ErrInsufficientFunds)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				ctx, _ := newContextWithBearerToken(t, tokenMaker, user1, util.DepositorRole, time.Minute)
+				return ctx
+			},
+			checkResponse: func(t *testing.T, rsp *pb.TransferMoneyResponse, err error) {
+				requireStatusCode(t, codes.FailedPrecondition, err)
+			},
+		},
+		{
+			// an admin froze an account after validAccount read it - TransferTX checks again with the accounts locked
+			name: "Frozen During Transfer",
+			req: &pb.TransferMoneyRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        amount,
+				Currency:      util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, db.ErrAccountFrozen)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				ctx, _ := newContextWithBearerToken(t, tokenMaker, user1, util.DepositorRole, time.Minute)
+				return ctx
+			},
+			checkResponse: func(t *testing.T, rsp *pb.TransferMoneyResponse, err error) {
+				requireStatusCode(t, codes.FailedPrecondition, err)
+			},
+		},
+		{
+			// the key was already used for a transfer out of the account with a different payload
+			name: "Idempotency Key Reused",
+			req: &pb.TransferMoneyRequest{
+				FromAccountId:  account1.ID,
+				ToAccountId:    account2.ID,
+				Amount:         amount,
+				Currency:       util.USD,
+				IdempotencyKey: "transfer-1",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, db.ErrIdempotencyKeyReused)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				ctx, _ := newContextWithBearerToken(t, tokenMaker, user1, util.DepositorRole, time.Minute)
+				return ctx
+			},
+			checkResponse: func(t *testing.T, rsp *pb.TransferMoneyResponse, err error) {
+				requireStatusCode(t, codes.FailedPrecondition, err)
+			},
+		},
+		{
+			name: "Transfer Internal Error",
+			req: &pb.TransferMoneyRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        amount,
+				Currency:      util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, sql.ErrTxDone)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				ctx, _ := newContextWithBearerToken(t, tokenMaker, user1, util.DepositorRole, time.Minute)
+				return ctx
+			},
+			checkResponse: func(t *testing.T, rsp *pb.TransferMoneyResponse, err error) {
+				requireStatusCode(t, codes.Internal, err)
+			},
+		},
+		{
+			name: "Negative Amount",
+			req: &pb.TransferMoneyRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        -amount,
+				Currency:      util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				ctx, _ := newContextWithBearerToken(t, tokenMaker, user1, util.DepositorRole, time.Minute)
+				return ctx
+			},
+			checkResponse: func(t *testing.T, rsp *pb.TransferMoneyResponse, err error) {
+				requireStatusCode(t, codes.InvalidArgument, err)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			rsp, err := server.TransferMoney(tc.buildContext(t, server.tokenMaker), tc.req)
+			tc.checkResponse(t, rsp, err)
+		})
+	}
+}
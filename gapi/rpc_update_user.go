@@ -0,0 +1,117 @@
+package gapi
+
+import (
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/pb"
+	"SimpleBankProject/val"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UpdateUser changes the full name, email and/or password of a user - users may only update themselves, admins may update
+// anyone - a password change blocks every session of the user, so they have to login again with the new password
+func (server *Server) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	// validate that the UpdateUserRequest properties meet the criteria defined in validator.go
+	violations := validateUpdateUserRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	if !isOwnerOrAdmin(authPayload, req.GetUsername()) {
+		return nil, status.Errorf(codes.PermissionDenied, "cannot update other user's info")
+	}
+
+	// an empty field keeps its current value - a new email must be verified again
+	arg := db.UpdateUserTxParams{
+		UpdateUserParams: db.UpdateUserParams{
+			Username: req.GetUsername(),
+			FullName: sql.NullString{
+				String: req.GetFullName(),
+				Valid:  req.GetFullName() != "",
+			},
+			Email: sql.NullString{
+				String: req.GetEmail(),
+				Valid:  req.GetEmail() != "",
+			},
+		},
+	}
+
+	// the code is only sent if the email changes - only its hash is stored
+	var secretCode string
+	if arg.Email.Valid {
+		var err error
+		secretCode, err = util.NewSecretToken()
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to create verification code: %s", err)
+		}
+		arg.SecretCodeHash = util.HashSecretToken(secretCode)
+	}
+
+	if req.GetPassword() != "" {
+		hashedPassword, err := server.hasher.Hash(req.GetPassword())
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to hash password: %s", err)
+		}
+		arg.HashedPassword = sql.NullString{String: hashedPassword, Valid: true}
+		arg.PasswordChangeAt = sql.NullTime{Time: time.Now(), Valid: true}
+	}
+
+	result, err := server.store.UpdateUserTX(ctx, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "user not found: %s", err)
+		}
+		// the email is already used by another user
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+			return nil, status.Errorf(codes.AlreadyExists, "email already exists: %s", pqErr.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "failed to update user: %s", err)
+	}
+
+	// the new email must be verified again
+	if result.VerifyEmail.ID != 0 {
+		server.sendVerifyEmail(ctx, result.User, result.VerifyEmail, secretCode)
+	}
+
+	rsp := &pb.UpdateUserResponse{
+		User: convertUser(result.User),
+	}
+
+	return rsp, nil
+}
+
+// validateUpdateUserRequest will validate each property of the UpdateUserRequest object - the optional properties are only
+// validated when they are set
+func validateUpdateUserRequest(req *pb.UpdateUserRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateUsername(req.GetUsername()); err != nil {
+		violations = append(violations, fieldViolation("username", err))
+	}
+	if req.GetFullName() != "" {
+		if err := val.ValidateFullName(req.GetFullName()); err != nil {
+			violations = append(violations, fieldViolation("full_name", err))
+		}
+	}
+	if req.GetEmail() != "" {
+		if err := val.ValidateEmail(req.GetEmail()); err != nil {
+			violations = append(violations, fieldViolation("email", err))
+		}
+	}
+	if req.GetPassword() != "" {
+		if err := val.ValidatePassword(req.GetPassword()); err != nil {
+			violations = append(violations, fieldViolation("password", err))
+		}
+	}
+	return violations
+}
@@ -0,0 +1,72 @@
+package gapi
+
+import (
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/db/util"
+	"SimpleBankProject/mail"
+	"SimpleBankProject/pb"
+	"SimpleBankProject/val"
+	"context"
+	"database/sql"
+	"log"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// VerifyEmail marks the email of the user as verified - the secret code sent to the email is proof enough, so it is one of
+// the publicMethods which don't need an access token
+func (server *Server) VerifyEmail(ctx context.Context, req *pb.VerifyEmailRequest) (*pb.VerifyEmailResponse, error) {
+	// validate that the VerifyEmailRequest properties meet the criteria defined in validator.go
+	violations := validateVerifyEmailRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	result, err := server.store.VerifyEmailTX(ctx, db.VerifyEmailTxParams{
+		EmailID:        req.GetEmailId(),
+		SecretCodeHash: util.HashSecretToken(req.GetSecretCode()),
+	})
+	if err != nil {
+		// the code is wrong, was already used or has expired
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "invalid or expired verification code")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to verify email: %s", err)
+	}
+
+	rsp := &pb.VerifyEmailResponse{
+		User: convertUser(result.User),
+	}
+
+	return rsp, nil
+}
+
+// sendVerifyEmail sends the verification code to the user - it links to the verify email route in the config
+// verifyEmail only holds the hash of the code, so the code itself is passed in as well
+// it is called once the user transaction has committed, so the email is sent once however often the transaction was retried -
+// the user exists either way, so a failed email is only logged
+func (server *Server) sendVerifyEmail(ctx context.Context, user db.User, verifyEmail db.VerifyEmail, secretCode string) {
+	msg := mail.VerifyEmailMessage(
+		verifyEmail.Email,
+		user.FullName,
+		server.config.VerifyEmailURL,
+		verifyEmail.ID,
+		secretCode,
+	)
+	if err := server.mailer.SendEmail(ctx, msg); err != nil {
+		log.Printf("cannot send verification email to user %s: %s", user.Username, err)
+	}
+}
+
+// validateVerifyEmailRequest will validate each property of the VerifyEmailRequest object
+func validateVerifyEmailRequest(req *pb.VerifyEmailRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateEmailID(req.GetEmailId()); err != nil {
+		violations = append(violations, fieldViolation("email_id", err))
+	}
+	if err := val.ValidateSecretCode(req.GetSecretCode()); err != nil {
+		violations = append(violations, fieldViolation("secret_code", err))
+	}
+	return violations
+}
@@ -0,0 +1,81 @@
+package gapi
+
+import (
+	db "SimpleBankProject/db/sqlc"
+	"SimpleBankProject/pb"
+	"SimpleBankProject/val"
+	"context"
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) Withdraw(ctx context.Context, req *pb.WithdrawRequest) (*pb.WithdrawResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	// validate that the WithdrawRequest properties meet the criteria defined in validator.go
+	violations := validateWithdrawRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	// check if AccountId exists and has the correct currency for the withdrawal
+	account, err := server.validAccount(ctx, req.GetAccountId(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	// the logged in user can only withdraw from an account they own
+	if account.Owner != authPayload.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "account doesn't belong to the authenticated user")
+	}
+
+	result, err := server.store.WithdrawTX(ctx, db.WithdrawTxParams{
+		AccountID:         req.GetAccountId(),
+		Amount:            req.GetAmount(),
+		ExternalReference: req.GetExternalReference(),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrDuplicateExternalReference) {
+			return nil, status.Errorf(codes.AlreadyExists, "cannot withdraw money: %s", err)
+		}
+		// an admin froze the account after validAccount checked it
+		if errors.Is(err, db.ErrAccountFrozen) {
+			return nil, status.Errorf(codes.FailedPrecondition, "cannot withdraw money: %s", err)
+		}
+		// the account doesn't have enough money - nothing was withdrawn
+		if errors.Is(err, db.ErrInsufficientFunds) {
+			return nil, status.Errorf(codes.FailedPrecondition, "cannot withdraw money: %s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to withdraw money: %s", err)
+	}
+
+	rsp := &pb.WithdrawResponse{
+		Account: convertAccount(result.Account),
+		Entry:   convertEntry(result.Entry),
+	}
+
+	return rsp, nil
+}
+
+// validateWithdrawRequest will validate each property of the WithdrawRequest object
+func validateWithdrawRequest(req *pb.WithdrawRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+	if err := val.ValidateAmount(req.GetAmount()); err != nil {
+		violations = append(violations, fieldViolation("amount", err))
+	}
+	if err := val.ValidateCurrency(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+	}
+	if err := val.ValidateExternalReference(req.GetExternalReference()); err != nil {
+		violations = append(violations, fieldViolation("external_reference", err))
+	}
+	return violations
+}
@@ -5,8 +5,11 @@ import (
 
 	db "SimpleBankProject/db/sqlc"
 	"SimpleBankProject/db/util"
+	"SimpleBankProject/mail"
 	"SimpleBankProject/pb"
+	"SimpleBankProject/ratelimit"
 	"SimpleBankProject/token"
+	"SimpleBankProject/totp"
 )
 
 // Define server struct - serves gRPC requests for banking service
@@ -18,21 +21,45 @@ type Server struct {
 	config     util.Config
 	store      db.Store
 	tokenMaker token.Maker
+	denylist   token.Denylist       // access tokens revoked before they expired
+	mailer     mail.Mailer          // sends the verification and password reset emails
+	totpCipher *totp.SecretCipher   // encrypts the TOTP secrets of two-factor authentication
+	hasher     *util.PasswordHasher // hashes passwords with the algorithm of the config
+	limiter    ratelimit.Limiter    // counts the calls of each client against the rate limits
+	rateLimits ratelimit.Limits     // the rate limit of each group of methods
 }
 
 // NewServer creates a new gRPC server - Server object must implement CreateUser and LoginUser to implement
 // the SimpleBankServer interface
-func NewServer(config util.Config, store db.Store) (*Server, error) {
-	// initialize tokenMaker, symmetric key will come from the environment variable
-	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricKey)
+func NewServer(config util.Config, store db.Store, mailer mail.Mailer) (*Server, error) {
+	// initialize tokenMaker, the config selects the maker and its keys (see token.NewMaker)
+	tokenMaker, err := token.NewMaker(config)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create token maker: %w", err)
 	}
+	totpCipher, err := totp.NewSecretCipher(config.TOTPEncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create TOTP secret cipher: %w", err)
+	}
+	hasher, err := util.NewPasswordHasher(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create password hasher: %w", err)
+	}
+	rateLimits, err := ratelimit.NewLimits(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse rate limits: %w", err)
+	}
 	// Server struct, store property, initialized to store which we pass in
 	server := &Server{
 		config:     config,
 		store:      store,
 		tokenMaker: tokenMaker,
+		denylist:   db.NewDenylist(store, config.TokenDenylistCacheDuration),
+		mailer:     mailer,
+		totpCipher: totpCipher,
+		hasher:     hasher,
+		limiter:    ratelimit.NewMemoryLimiter(),
+		rateLimits: rateLimits,
 	}
 
 	return server, nil
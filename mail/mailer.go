@@ -0,0 +1,22 @@
+package mail
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNoRecipients is returned by a Mailer when the message has no recipients
+var ErrNoRecipients = errors.New("email has no recipients")
+
+// Message is an email sent by the bank - the body is plain text
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Mailer sends emails - the servers only depend on this interface, so the SMTP server can be swapped for an in-memory
+// mailer in tests or for another provider
+type Mailer interface {
+	SendEmail(ctx context.Context, msg Message) error
+}
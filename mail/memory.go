@@ -0,0 +1,37 @@
+package mail
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryMailer keeps the emails it is asked to send in memory instead of sending them - it is used in tests to check which
+// emails the servers send
+type MemoryMailer struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+// NewMemoryMailer creates a MemoryMailer without any emails
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+// SendEmail stores the message
+func (mailer *MemoryMailer) SendEmail(ctx context.Context, msg Message) error {
+	if len(msg.To) == 0 {
+		return ErrNoRecipients
+	}
+
+	mailer.mu.Lock()
+	defer mailer.mu.Unlock()
+	mailer.messages = append(mailer.messages, msg)
+	return nil
+}
+
+// Messages returns the emails sent so far, oldest first
+func (mailer *MemoryMailer) Messages() []Message {
+	mailer.mu.Lock()
+	defer mailer.mu.Unlock()
+	return append([]Message(nil), mailer.messages...)
+}
@@ -0,0 +1,35 @@
+package mail
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryMailer(t *testing.T) {
+	mailer := NewMemoryMailer()
+	require.Empty(t, mailer.Messages())
+
+	msg := Message{
+		To:      []string{"user@example.com"},
+		Subject: "subject",
+		Body:    "body",
+	}
+	require.NoError(t, mailer.SendEmail(context.Background(), msg))
+
+	messages := mailer.Messages()
+	require.Equal(t, []Message{msg}, messages)
+
+	// the returned slice is a copy, changing it doesn't change the emails of the mailer
+	messages[0].Subject = "changed"
+	require.Equal(t, msg, mailer.Messages()[0])
+}
+
+func TestMemoryMailerNoRecipients(t *testing.T) {
+	mailer := NewMemoryMailer()
+
+	err := mailer.SendEmail(context.Background(), Message{Subject: "subject"})
+	require.ErrorIs(t, err, ErrNoRecipients)
+	require.Empty(t, mailer.Messages())
+}
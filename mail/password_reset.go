@@ -0,0 +1,25 @@
+package mail
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// PasswordResetMessage returns the email which lets a user choose a new password by following a link to resetURL with the
+// token of the password reset - the token can be used until it expires after duration
+func PasswordResetMessage(to, fullName, resetURL, token string, duration time.Duration) Message {
+	query := url.Values{}
+	query.Set("token", token)
+	link := fmt.Sprintf("%s?%s", resetURL, query.Encode())
+
+	return Message{
+		To:      []string{to},
+		Subject: "Reset your Simple Bank password",
+		Body: fmt.Sprintf(
+			"Hello %s,\n\nWe received a request to reset your password. Choose a new password by opening this link:\n%s\n\n"+
+				"The link expires in %.0f minutes. If you didn't ask to reset your password, you can ignore this email.\n",
+			fullName, link, duration.Minutes(),
+		),
+	}
+}
@@ -0,0 +1,18 @@
+package mail
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordResetMessage(t *testing.T) {
+	msg := PasswordResetMessage("user@example.com", "Garrett", "http://localhost:8080/reset_password", "abc-123", 15*time.Minute)
+
+	require.Equal(t, []string{"user@example.com"}, msg.To)
+	require.NotEmpty(t, msg.Subject)
+	require.Contains(t, msg.Body, "Hello Garrett")
+	require.Contains(t, msg.Body, "http://localhost:8080/reset_password?token=abc-123")
+	require.Contains(t, msg.Body, "15 minutes")
+}
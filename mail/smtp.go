@@ -0,0 +1,66 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTPMailer sends emails through an SMTP server
+type SMTPMailer struct {
+	address     string // host:port of the SMTP server
+	auth        smtp.Auth
+	fromName    string
+	fromAddress string
+}
+
+// NewSMTPMailer creates an SMTPMailer which sends emails from fromName <fromAddress> - when username is empty, the SMTP server
+// is used without authentication (e.g. a local mail catcher during development)
+func NewSMTPMailer(address, username, password, fromName, fromAddress string) (*SMTPMailer, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP address: %w", err)
+	}
+
+	mailer := &SMTPMailer{
+		address:     address,
+		fromName:    fromName,
+		fromAddress: fromAddress,
+	}
+	if username != "" {
+		mailer.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return mailer, nil
+}
+
+// SendEmail sends the message to all of its recipients - smtp.SendMail doesn't take a context, so the context is only checked
+// before the email is sent
+func (mailer *SMTPMailer) SendEmail(ctx context.Context, msg Message) error {
+	if len(msg.To) == 0 {
+		return ErrNoRecipients
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	from := fmt.Sprintf("%s <%s>", mailer.fromName, mailer.fromAddress)
+	return smtp.SendMail(mailer.address, mailer.auth, mailer.fromAddress, msg.To, buildMessage(from, msg, time.Now()))
+}
+
+// buildMessage formats the message as an RFC 5322 email with a plain text body
+func buildMessage(from string, msg Message, date time.Time) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(msg.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"UTF-8\"\r\n")
+	b.WriteString("\r\n")
+	// SMTP requires CRLF line endings in the body as well
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(msg.Body, "\r\n", "\n"), "\n", "\r\n"))
+	return []byte(b.String())
+}
@@ -0,0 +1,58 @@
+package mail
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSMTPMailer(t *testing.T) {
+	mailer, err := NewSMTPMailer("localhost:1025", "", "", "Simple Bank", "no-reply@simplebank.local")
+	require.NoError(t, err)
+	// no username, no authentication
+	require.Nil(t, mailer.auth)
+
+	mailer, err = NewSMTPMailer("smtp.example.com:587", "user", "secret", "Simple Bank", "no-reply@simplebank.local")
+	require.NoError(t, err)
+	require.NotNil(t, mailer.auth)
+
+	// the port is missing
+	_, err = NewSMTPMailer("localhost", "", "", "Simple Bank", "no-reply@simplebank.local")
+	require.Error(t, err)
+}
+
+func TestSMTPMailerSendEmail(t *testing.T) {
+	mailer, err := NewSMTPMailer("localhost:1025", "", "", "Simple Bank", "no-reply@simplebank.local")
+	require.NoError(t, err)
+
+	err = mailer.SendEmail(context.Background(), Message{Subject: "subject"})
+	require.ErrorIs(t, err, ErrNoRecipients)
+
+	// a cancelled context stops the email before the SMTP server is contacted
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = mailer.SendEmail(ctx, Message{To: []string{"user@example.com"}})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestBuildMessage(t *testing.T) {
+	date := time.Date(2022, time.July, 1, 12, 0, 0, 0, time.UTC)
+	msg := Message{
+		To:      []string{"a@example.com", "b@example.com"},
+		Subject: "Hello",
+		Body:    "line 1\nline 2\r\n",
+	}
+
+	got := string(buildMessage("Simple Bank <no-reply@simplebank.local>", msg, date))
+	want := "From: Simple Bank <no-reply@simplebank.local>\r\n" +
+		"To: a@example.com, b@example.com\r\n" +
+		"Subject: Hello\r\n" +
+		"Date: Fri, 01 Jul 2022 12:00:00 +0000\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=\"UTF-8\"\r\n" +
+		"\r\n" +
+		"line 1\r\nline 2\r\n"
+	require.Equal(t, want, got)
+}
@@ -0,0 +1,24 @@
+package mail
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// VerifyEmailMessage returns the email which asks a user to confirm their email address by following a link to verifyURL
+// with the id and secret code of the verification
+func VerifyEmailMessage(to, fullName, verifyURL string, emailID int64, secretCode string) Message {
+	query := url.Values{}
+	query.Set("email_id", fmt.Sprint(emailID))
+	query.Set("secret_code", secretCode)
+	link := fmt.Sprintf("%s?%s", verifyURL, query.Encode())
+
+	return Message{
+		To:      []string{to},
+		Subject: "Welcome to Simple Bank",
+		Body: fmt.Sprintf(
+			"Hello %s,\n\nPlease verify your email address by opening this link:\n%s\n\nThe link expires in 15 minutes.\n",
+			fullName, link,
+		),
+	}
+}
@@ -0,0 +1,16 @@
+package mail
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyEmailMessage(t *testing.T) {
+	msg := VerifyEmailMessage("user@example.com", "Garrett", "http://localhost:8080/v1/verify_email", 7, "abc")
+
+	require.Equal(t, []string{"user@example.com"}, msg.To)
+	require.NotEmpty(t, msg.Subject)
+	require.Contains(t, msg.Body, "Hello Garrett")
+	require.Contains(t, msg.Body, "http://localhost:8080/v1/verify_email?email_id=7&secret_code=abc")
+}
@@ -6,12 +6,14 @@ import (
 	"log"
 	"net"
 	"net/http"
+	"time"
 
 	"SimpleBankProject/api"
 	db "SimpleBankProject/db/sqlc"
 	"SimpleBankProject/db/util"
 	_ "SimpleBankProject/doc/statik"
 	"SimpleBankProject/gapi"
+	"SimpleBankProject/mail"
 	"SimpleBankProject/pb"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
@@ -37,26 +39,76 @@ func main() {
 
 	// create store
 	store := db.NewStore(conn)
-	// uncomment runGinServer(config, store) if working with standard HTTP API
-	// runGinServer(config, store)
+
+	// the mailer sends the verification emails through the SMTP server in the config
+	mailer, err := mail.NewSMTPMailer(
+		config.SMTPAddress,
+		config.SMTPUsername,
+		config.SMTPPassword,
+		config.EmailSenderName,
+		config.EmailSenderAddress,
+	)
+	if err != nil {
+		log.Fatal("cannot create mailer:", err)
+	}
+
+	// uncomment runGinServer(config, store, mailer) if working with standard HTTP API
+	// runGinServer(config, store, mailer)
+
+	// revoked tokens only need to be kept until they expire
+	go runDenylistCleanup(config, store)
 
 	// we need to run the gRPC server and the gateway server in two different go routines
 	// otherwise they will block each other
-	go runGatewayServer(config, store)
+	go runGatewayServer(config, store, mailer)
 	// start the gRPC server
-	runGrpcServer(config, store)
+	runGrpcServer(config, store, mailer)
 
 }
 
-func runGrpcServer(config util.Config, store db.Store) {
+// runDenylistCleanup deletes the revoked tokens which have expired every TokenDenylistCleanupInterval
+func runDenylistCleanup(config util.Config, store db.Store) {
+	if config.TokenDenylistCleanupInterval <= 0 {
+		return
+	}
+
+	denylist := db.NewTokenDenylist(store)
+	ticker := time.NewTicker(config.TokenDenylistCleanupInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		deleted, err := denylist.DeleteExpired(context.Background())
+		if err != nil {
+			log.Printf("cannot delete expired revoked tokens: %s", err)
+			continue
+		}
+		log.Printf("deleted %d expired revoked tokens", deleted)
+	}
+}
+
+func runGrpcServer(config util.Config, store db.Store, mailer mail.Mailer) {
 	// create our implementation of the Simple Bank server
-	server, err := gapi.NewServer(config, store)
+	server, err := gapi.NewServer(config, store, mailer)
 	if err != nil {
 		log.Fatal("cannot create server:", err)
 	}
 
 	// create a new gRPC server from auto-generated code - has no services registered
-	grpcServer := grpc.NewServer()
+	// the auth interceptors verify the access token of every call before it reaches a handler (see gapi/interceptor.go)
+	// the rate limit interceptors run after them, so the calls of logged in users are counted per user (see gapi/rate_limit.go)
+	// the client IP rate limit interceptors run first, so calls with a bad access token or API key are limited too
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			server.UnaryClientIPRateLimitInterceptor,
+			server.UnaryAuthInterceptor,
+			server.UnaryRateLimitInterceptor,
+		),
+		grpc.ChainStreamInterceptor(
+			server.StreamClientIPRateLimitInterceptor,
+			server.StreamAuthInterceptor,
+			server.StreamRateLimitInterceptor,
+		),
+	)
 
 	// register the new gRPC server
 	pb.RegisterSimpleBankServer(grpcServer, server)
@@ -79,9 +131,9 @@ func runGrpcServer(config util.Config, store db.Store) {
 }
 
 // setup gRPC gateway server using in-process translation method (limited to unary gRPC)
-func runGatewayServer(config util.Config, store db.Store) {
+func runGatewayServer(config util.Config, store db.Store, mailer mail.Mailer) {
 	// create our implementation of the Simple Bank server
-	server, err := gapi.NewServer(config, store)
+	server, err := gapi.NewServer(config, store, mailer)
 	if err != nil {
 		log.Fatal("cannot create server:", err)
 	}
@@ -118,7 +170,8 @@ func runGatewayServer(config util.Config, store db.Store) {
 	// to convert the HTTP requests to gRPC format, the HTTP requests must be routed to the gRPC mux (grpcMux)
 	// Handle() registers the handler for the given pattern (e.g "/" which covers all HTTP server routes and therefore
 	// all handlers) - in other words, the HTTP serve mux now points to the grpcMux handlers
-	mux.Handle("/", grpcMux)
+	// the in-process gateway skips the gRPC interceptors, so its requests are rate limited before they reach the grpcMux
+	mux.Handle("/", server.RateLimitGateway(grpcMux))
 
 	// optional - using Swagger UI in order to visually document our API
 	// create file server
@@ -151,9 +204,9 @@ func runGatewayServer(config util.Config, store db.Store) {
 	}
 }
 
-func runGinServer(config util.Config, store db.Store) {
+func runGinServer(config util.Config, store db.Store, mailer mail.Mailer) {
 	// create server
-	server, err := api.NewServer(config, store)
+	server, err := api.NewServer(config, store, mailer)
 	if err != nil {
 		log.Fatal("cannot create server:", err)
 	}
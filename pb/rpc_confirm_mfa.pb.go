@@ -0,0 +1,216 @@
+// tells protobuf compiler which version we are using
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.0
+// 	protoc        v3.21.3
+// source: rpc_confirm_mfa.proto
+
+// a way of grouping proto files together
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// define what fields the ConfirmMFARequest object will hold - a code of the secret created by EnrollMFA
+type ConfirmMFARequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// type, name of field and field number
+	// field number will uniquely define the field when serializing or deserializing the message in binary format
+	Code string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+}
+
+func (x *ConfirmMFARequest) Reset() {
+	*x = ConfirmMFARequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_confirm_mfa_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConfirmMFARequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmMFARequest) ProtoMessage() {}
+
+func (x *ConfirmMFARequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_confirm_mfa_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmMFARequest.ProtoReflect.Descriptor instead.
+func (*ConfirmMFARequest) Descriptor() ([]byte, []int) {
+	return file_rpc_confirm_mfa_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ConfirmMFARequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+// define what fields the ConfirmMFAResponse object will hold
+type ConfirmMFAResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// shown once - only their hashes are stored
+	RecoveryCodes []string `protobuf:"bytes,1,rep,name=recovery_codes,json=recoveryCodes,proto3" json:"recovery_codes,omitempty"`
+}
+
+func (x *ConfirmMFAResponse) Reset() {
+	*x = ConfirmMFAResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_confirm_mfa_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConfirmMFAResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmMFAResponse) ProtoMessage() {}
+
+func (x *ConfirmMFAResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_confirm_mfa_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmMFAResponse.ProtoReflect.Descriptor instead.
+func (*ConfirmMFAResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_confirm_mfa_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ConfirmMFAResponse) GetRecoveryCodes() []string {
+	if x != nil {
+		return x.RecoveryCodes
+	}
+	return nil
+}
+
+var File_rpc_confirm_mfa_proto protoreflect.FileDescriptor
+
+var file_rpc_confirm_mfa_proto_rawDesc = []byte{
+	0x0a, 0x15, 0x72, 0x70, 0x63, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x5f, 0x6d, 0x66,
+	0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02, 0x70, 0x62, 0x22, 0x27, 0x0a, 0x11, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x4d, 0x46, 0x41, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x63, 0x6f, 0x64, 0x65, 0x22, 0x3b, 0x0a, 0x12, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x4d,
+	0x46, 0x41, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x72, 0x65,
+	0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x0d, 0x72, 0x65, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x43, 0x6f, 0x64, 0x65,
+	0x73, 0x42, 0x16, 0x5a, 0x14, 0x53, 0x69, 0x6d, 0x70, 0x6c, 0x65, 0x42, 0x61, 0x6e, 0x6b, 0x50,
+	0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
+}
+
+var (
+	file_rpc_confirm_mfa_proto_rawDescOnce sync.Once
+	file_rpc_confirm_mfa_proto_rawDescData = file_rpc_confirm_mfa_proto_rawDesc
+)
+
+func file_rpc_confirm_mfa_proto_rawDescGZIP() []byte {
+	file_rpc_confirm_mfa_proto_rawDescOnce.Do(func() {
+		file_rpc_confirm_mfa_proto_rawDescData = protoimpl.X.CompressGZIP(file_rpc_confirm_mfa_proto_rawDescData)
+	})
+	return file_rpc_confirm_mfa_proto_rawDescData
+}
+
+var file_rpc_confirm_mfa_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_confirm_mfa_proto_goTypes = []interface{}{
+	(*ConfirmMFARequest)(nil),  // 0: pb.ConfirmMFARequest
+	(*ConfirmMFAResponse)(nil), // 1: pb.ConfirmMFAResponse
+}
+var file_rpc_confirm_mfa_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_rpc_confirm_mfa_proto_init() }
+func file_rpc_confirm_mfa_proto_init() {
+	if File_rpc_confirm_mfa_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_rpc_confirm_mfa_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConfirmMFARequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_confirm_mfa_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConfirmMFAResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_rpc_confirm_mfa_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_confirm_mfa_proto_goTypes,
+		DependencyIndexes: file_rpc_confirm_mfa_proto_depIdxs,
+		MessageInfos:      file_rpc_confirm_mfa_proto_msgTypes,
+	}.Build()
+	File_rpc_confirm_mfa_proto = out.File
+	file_rpc_confirm_mfa_proto_rawDesc = nil
+	file_rpc_confirm_mfa_proto_goTypes = nil
+	file_rpc_confirm_mfa_proto_depIdxs = nil
+}
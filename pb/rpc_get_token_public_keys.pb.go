@@ -0,0 +1,290 @@
+// tells protobuf compiler which version we are using
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.0
+// 	protoc        v3.21.3
+// source: rpc_get_token_public_keys.proto
+
+// a way of grouping proto files together
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// define what fields the GetTokenPublicKeysRequest object will hold - no access token is needed
+type GetTokenPublicKeysRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetTokenPublicKeysRequest) Reset() {
+	*x = GetTokenPublicKeysRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_get_token_public_keys_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetTokenPublicKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTokenPublicKeysRequest) ProtoMessage() {}
+
+func (x *GetTokenPublicKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_get_token_public_keys_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTokenPublicKeysRequest.ProtoReflect.Descriptor instead.
+func (*GetTokenPublicKeysRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_get_token_public_keys_proto_rawDescGZIP(), []int{0}
+}
+
+// a key which verifies the signature of the tokens - it can't create tokens
+type TokenPublicKey struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// type, name of field and field number
+	// field number will uniquely define the field when serializing or deserializing the message in binary format
+	KeyId     string `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`             // the kid in the footer of the tokens signed by the matching private key
+	PublicKey string `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"` // hex encoded Ed25519 public key
+}
+
+func (x *TokenPublicKey) Reset() {
+	*x = TokenPublicKey{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_get_token_public_keys_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TokenPublicKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenPublicKey) ProtoMessage() {}
+
+func (x *TokenPublicKey) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_get_token_public_keys_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenPublicKey.ProtoReflect.Descriptor instead.
+func (*TokenPublicKey) Descriptor() ([]byte, []int) {
+	return file_rpc_get_token_public_keys_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TokenPublicKey) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *TokenPublicKey) GetPublicKey() string {
+	if x != nil {
+		return x.PublicKey
+	}
+	return ""
+}
+
+// define what the GetTokenPublicKeysResponse object will hold
+type GetTokenPublicKeysResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version string            `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"` // the PASETO version and purpose of the tokens - v2.public
+	Keys    []*TokenPublicKey `protobuf:"bytes,2,rep,name=keys,proto3" json:"keys,omitempty"`       // the key of the current private key first
+}
+
+func (x *GetTokenPublicKeysResponse) Reset() {
+	*x = GetTokenPublicKeysResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_get_token_public_keys_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetTokenPublicKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTokenPublicKeysResponse) ProtoMessage() {}
+
+func (x *GetTokenPublicKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_get_token_public_keys_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTokenPublicKeysResponse.ProtoReflect.Descriptor instead.
+func (*GetTokenPublicKeysResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_get_token_public_keys_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetTokenPublicKeysResponse) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *GetTokenPublicKeysResponse) GetKeys() []*TokenPublicKey {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+var File_rpc_get_token_public_keys_proto protoreflect.FileDescriptor
+
+var file_rpc_get_token_public_keys_proto_rawDesc = []byte{
+	0x0a, 0x1f, 0x72, 0x70, 0x63, 0x5f, 0x67, 0x65, 0x74, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x5f,
+	0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f, 0x6b, 0x65, 0x79, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x02, 0x70, 0x62, 0x22, 0x1b, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x54, 0x6f, 0x6b, 0x65,
+	0x6e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x22, 0x46, 0x0a, 0x0e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x50, 0x75, 0x62, 0x6c, 0x69,
+	0x63, 0x4b, 0x65, 0x79, 0x12, 0x15, 0x0a, 0x06, 0x6b, 0x65, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6b, 0x65, 0x79, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x70,
+	0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x22, 0x5e, 0x0a, 0x1a, 0x47, 0x65,
+	0x74, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x04, 0x6b, 0x65, 0x79, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x12, 0x2e, 0x70, 0x62, 0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x50, 0x75, 0x62, 0x6c, 0x69,
+	0x63, 0x4b, 0x65, 0x79, 0x52, 0x04, 0x6b, 0x65, 0x79, 0x73, 0x42, 0x16, 0x5a, 0x14, 0x53, 0x69,
+	0x6d, 0x70, 0x6c, 0x65, 0x42, 0x61, 0x6e, 0x6b, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x2f,
+	0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_rpc_get_token_public_keys_proto_rawDescOnce sync.Once
+	file_rpc_get_token_public_keys_proto_rawDescData = file_rpc_get_token_public_keys_proto_rawDesc
+)
+
+func file_rpc_get_token_public_keys_proto_rawDescGZIP() []byte {
+	file_rpc_get_token_public_keys_proto_rawDescOnce.Do(func() {
+		file_rpc_get_token_public_keys_proto_rawDescData = protoimpl.X.CompressGZIP(file_rpc_get_token_public_keys_proto_rawDescData)
+	})
+	return file_rpc_get_token_public_keys_proto_rawDescData
+}
+
+var file_rpc_get_token_public_keys_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_rpc_get_token_public_keys_proto_goTypes = []interface{}{
+	(*GetTokenPublicKeysRequest)(nil),  // 0: pb.GetTokenPublicKeysRequest
+	(*TokenPublicKey)(nil),             // 1: pb.TokenPublicKey
+	(*GetTokenPublicKeysResponse)(nil), // 2: pb.GetTokenPublicKeysResponse
+}
+var file_rpc_get_token_public_keys_proto_depIdxs = []int32{
+	1, // 0: pb.GetTokenPublicKeysResponse.keys:type_name -> pb.TokenPublicKey
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_rpc_get_token_public_keys_proto_init() }
+func file_rpc_get_token_public_keys_proto_init() {
+	if File_rpc_get_token_public_keys_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_rpc_get_token_public_keys_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetTokenPublicKeysRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_get_token_public_keys_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TokenPublicKey); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_get_token_public_keys_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetTokenPublicKeysResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_rpc_get_token_public_keys_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_get_token_public_keys_proto_goTypes,
+		DependencyIndexes: file_rpc_get_token_public_keys_proto_depIdxs,
+		MessageInfos:      file_rpc_get_token_public_keys_proto_msgTypes,
+	}.Build()
+	File_rpc_get_token_public_keys_proto = out.File
+	file_rpc_get_token_public_keys_proto_rawDesc = nil
+	file_rpc_get_token_public_keys_proto_goTypes = nil
+	file_rpc_get_token_public_keys_proto_depIdxs = nil
+}
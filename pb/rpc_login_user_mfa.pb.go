@@ -0,0 +1,230 @@
+// tells protobuf compiler which version we are using
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.0
+// 	protoc        v3.21.3
+// source: rpc_login_user_mfa.proto
+
+// a way of grouping proto files together
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// define what fields the LoginUserMFARequest object will hold - the second step of the login of a user with two-factor
+// authentication
+type LoginUserMFARequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// type, name of field and field number
+	// field number will uniquely define the field when serializing or deserializing the message in binary format
+	MfaToken string `protobuf:"bytes,1,opt,name=mfa_token,json=mfaToken,proto3" json:"mfa_token,omitempty"` // the challenge token sent by LoginUser
+	Code     string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`                         // a TOTP code or a recovery code
+}
+
+func (x *LoginUserMFARequest) Reset() {
+	*x = LoginUserMFARequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_login_user_mfa_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoginUserMFARequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginUserMFARequest) ProtoMessage() {}
+
+func (x *LoginUserMFARequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_login_user_mfa_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginUserMFARequest.ProtoReflect.Descriptor instead.
+func (*LoginUserMFARequest) Descriptor() ([]byte, []int) {
+	return file_rpc_login_user_mfa_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LoginUserMFARequest) GetMfaToken() string {
+	if x != nil {
+		return x.MfaToken
+	}
+	return ""
+}
+
+func (x *LoginUserMFARequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+// define what fields the LoginUserMFAResponse object will hold
+type LoginUserMFAResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// an object of type Login defined in login.proto - imported above
+	Login *Login `protobuf:"bytes,1,opt,name=login,proto3" json:"login,omitempty"`
+}
+
+func (x *LoginUserMFAResponse) Reset() {
+	*x = LoginUserMFAResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_login_user_mfa_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoginUserMFAResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginUserMFAResponse) ProtoMessage() {}
+
+func (x *LoginUserMFAResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_login_user_mfa_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginUserMFAResponse.ProtoReflect.Descriptor instead.
+func (*LoginUserMFAResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_login_user_mfa_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *LoginUserMFAResponse) GetLogin() *Login {
+	if x != nil {
+		return x.Login
+	}
+	return nil
+}
+
+var File_rpc_login_user_mfa_proto protoreflect.FileDescriptor
+
+var file_rpc_login_user_mfa_proto_rawDesc = []byte{
+	0x0a, 0x18, 0x72, 0x70, 0x63, 0x5f, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x5f, 0x75, 0x73, 0x65, 0x72,
+	0x5f, 0x6d, 0x66, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02, 0x70, 0x62, 0x1a, 0x0b,
+	0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x46, 0x0a, 0x13, 0x4c,
+	0x6f, 0x67, 0x69, 0x6e, 0x55, 0x73, 0x65, 0x72, 0x4d, 0x46, 0x41, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x66, 0x61, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6d, 0x66, 0x61, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12,
+	0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63,
+	0x6f, 0x64, 0x65, 0x22, 0x37, 0x0a, 0x14, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x55, 0x73, 0x65, 0x72,
+	0x4d, 0x46, 0x41, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a, 0x05, 0x6c,
+	0x6f, 0x67, 0x69, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x70, 0x62, 0x2e,
+	0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x52, 0x05, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x42, 0x16, 0x5a, 0x14,
+	0x53, 0x69, 0x6d, 0x70, 0x6c, 0x65, 0x42, 0x61, 0x6e, 0x6b, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63,
+	0x74, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_rpc_login_user_mfa_proto_rawDescOnce sync.Once
+	file_rpc_login_user_mfa_proto_rawDescData = file_rpc_login_user_mfa_proto_rawDesc
+)
+
+func file_rpc_login_user_mfa_proto_rawDescGZIP() []byte {
+	file_rpc_login_user_mfa_proto_rawDescOnce.Do(func() {
+		file_rpc_login_user_mfa_proto_rawDescData = protoimpl.X.CompressGZIP(file_rpc_login_user_mfa_proto_rawDescData)
+	})
+	return file_rpc_login_user_mfa_proto_rawDescData
+}
+
+var file_rpc_login_user_mfa_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_login_user_mfa_proto_goTypes = []interface{}{
+	(*LoginUserMFARequest)(nil),  // 0: pb.LoginUserMFARequest
+	(*LoginUserMFAResponse)(nil), // 1: pb.LoginUserMFAResponse
+	(*Login)(nil),                // 2: pb.Login
+}
+var file_rpc_login_user_mfa_proto_depIdxs = []int32{
+	2, // 0: pb.LoginUserMFAResponse.login:type_name -> pb.Login
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_rpc_login_user_mfa_proto_init() }
+func file_rpc_login_user_mfa_proto_init() {
+	if File_rpc_login_user_mfa_proto != nil {
+		return
+	}
+	file_login_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_rpc_login_user_mfa_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoginUserMFARequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_login_user_mfa_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoginUserMFAResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_rpc_login_user_mfa_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_login_user_mfa_proto_goTypes,
+		DependencyIndexes: file_rpc_login_user_mfa_proto_depIdxs,
+		MessageInfos:      file_rpc_login_user_mfa_proto_msgTypes,
+	}.Build()
+	File_rpc_login_user_mfa_proto = out.File
+	file_rpc_login_user_mfa_proto_rawDesc = nil
+	file_rpc_login_user_mfa_proto_goTypes = nil
+	file_rpc_login_user_mfa_proto_depIdxs = nil
+}
@@ -34,57 +34,528 @@ var file_service_simple_bank_proto_rawDesc = []byte{
 	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x15, 0x72,
 	0x70, 0x63, 0x5f, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x70,
 	0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x14, 0x72, 0x70, 0x63, 0x5f, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x5f,
-	0x75, 0x73, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x63, 0x2d, 0x67, 0x65, 0x6e, 0x2d, 0x6f, 0x70, 0x65, 0x6e, 0x61, 0x70, 0x69, 0x76, 0x32,
-	0x2f, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2f, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x32, 0xb6, 0x02, 0x0a, 0x0a, 0x53,
-	0x69, 0x6d, 0x70, 0x6c, 0x65, 0x42, 0x61, 0x6e, 0x6b, 0x12, 0x83, 0x01, 0x0a, 0x0a, 0x43, 0x72,
-	0x65, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x12, 0x15, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x72,
-	0x65, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x16, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x46, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x14, 0x22,
-	0x0f, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x5f, 0x75, 0x73, 0x65, 0x72,
-	0x3a, 0x01, 0x2a, 0x92, 0x41, 0x29, 0x12, 0x0f, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x20, 0x4e,
-	0x65, 0x77, 0x20, 0x55, 0x73, 0x65, 0x72, 0x1a, 0x16, 0x41, 0x50, 0x49, 0x20, 0x74, 0x6f, 0x20,
-	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x20, 0x4e, 0x65, 0x77, 0x20, 0x55, 0x73, 0x65, 0x72, 0x12,
-	0xa1, 0x01, 0x0a, 0x09, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x55, 0x73, 0x65, 0x72, 0x12, 0x14, 0x2e,
-	0x70, 0x62, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x70, 0x62, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x55, 0x73,
-	0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x67, 0x82, 0xd3, 0xe4, 0x93,
-	0x02, 0x13, 0x22, 0x0e, 0x2f, 0x76, 0x31, 0x2f, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x5f, 0x75, 0x73,
-	0x65, 0x72, 0x3a, 0x01, 0x2a, 0x92, 0x41, 0x4b, 0x12, 0x0a, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x20,
-	0x55, 0x73, 0x65, 0x72, 0x1a, 0x3d, 0x41, 0x50, 0x49, 0x20, 0x74, 0x6f, 0x20, 0x4c, 0x6f, 0x67,
-	0x69, 0x6e, 0x20, 0x55, 0x73, 0x65, 0x72, 0x20, 0x61, 0x6e, 0x64, 0x20, 0x47, 0x65, 0x74, 0x20,
-	0x42, 0x6f, 0x74, 0x68, 0x20, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x20, 0x54, 0x6f, 0x6b, 0x65,
-	0x6e, 0x20, 0x61, 0x6e, 0x64, 0x20, 0x52, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x20, 0x54, 0x6f,
-	0x6b, 0x65, 0x6e, 0x42, 0x84, 0x01, 0x5a, 0x14, 0x53, 0x69, 0x6d, 0x70, 0x6c, 0x65, 0x42, 0x61,
-	0x6e, 0x6b, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x2f, 0x70, 0x62, 0x92, 0x41, 0x6b, 0x12,
-	0x69, 0x0a, 0x0f, 0x53, 0x69, 0x6d, 0x70, 0x6c, 0x65, 0x20, 0x42, 0x61, 0x6e, 0x6b, 0x20, 0x41,
-	0x50, 0x49, 0x22, 0x51, 0x0a, 0x0e, 0x47, 0x61, 0x72, 0x72, 0x65, 0x74, 0x74, 0x20, 0x54, 0x61,
-	0x79, 0x6c, 0x6f, 0x72, 0x12, 0x28, 0x68, 0x74, 0x74, 0x70, 0x73, 0x3a, 0x2f, 0x2f, 0x67, 0x69,
-	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x74, 0x61, 0x79, 0x6c, 0x6f, 0x72,
-	0x33, 0x31, 0x34, 0x2f, 0x73, 0x69, 0x6d, 0x70, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x1a, 0x15,
-	0x67, 0x74, 0x61, 0x79, 0x6c, 0x6f, 0x72, 0x33, 0x31, 0x34, 0x40, 0x69, 0x63, 0x6c, 0x6f, 0x75,
-	0x64, 0x2e, 0x63, 0x6f, 0x6d, 0x32, 0x03, 0x31, 0x2e, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x33,
+	0x75, 0x73, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x18, 0x72, 0x70, 0x63, 0x5f,
+	0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x5f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x15, 0x72, 0x70, 0x63, 0x5f, 0x67, 0x65, 0x74, 0x5f, 0x61, 0x63,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x17, 0x72, 0x70, 0x63,
+	0x5f, 0x6c, 0x69, 0x73, 0x74, 0x5f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x18, 0x72, 0x70, 0x63, 0x5f, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x5f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x18,
+	0x72, 0x70, 0x63, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x5f, 0x6d, 0x6f, 0x6e,
+	0x65, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1a, 0x72, 0x70, 0x63, 0x5f, 0x72, 0x65,
+	0x76, 0x65, 0x72, 0x73, 0x65, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x11, 0x72, 0x70, 0x63, 0x5f, 0x64, 0x65, 0x70, 0x6f, 0x73, 0x69,
+	0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x12, 0x72, 0x70, 0x63, 0x5f, 0x77, 0x69, 0x74,
+	0x68, 0x64, 0x72, 0x61, 0x77, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x16, 0x72, 0x70, 0x63,
+	0x5f, 0x6c, 0x69, 0x73, 0x74, 0x5f, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x1a, 0x18, 0x72, 0x70, 0x63, 0x5f, 0x6c, 0x69, 0x73, 0x74, 0x5f, 0x74, 0x72,
+	0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x18, 0x72,
+	0x70, 0x63, 0x5f, 0x66, 0x72, 0x65, 0x65, 0x7a, 0x65, 0x5f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x17, 0x72, 0x70, 0x63, 0x5f, 0x62, 0x6c, 0x6f,
+	0x63, 0x6b, 0x5f, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x1a, 0x16, 0x72, 0x70, 0x63, 0x5f, 0x72, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x5f, 0x74, 0x6f, 0x6b,
+	0x65, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x17, 0x72, 0x70, 0x63, 0x5f, 0x6c, 0x69,
+	0x73, 0x74, 0x5f, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x1a, 0x18, 0x72, 0x70, 0x63, 0x5f, 0x72, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x5f, 0x73, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1c, 0x72, 0x70, 0x63,
+	0x5f, 0x72, 0x65, 0x6e, 0x65, 0x77, 0x5f, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x5f, 0x74, 0x6f,
+	0x6b, 0x65, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x15, 0x72, 0x70, 0x63, 0x5f, 0x75,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x1a, 0x16, 0x72, 0x70, 0x63, 0x5f, 0x76, 0x65, 0x72, 0x69, 0x66, 0x79, 0x5f, 0x65, 0x6d, 0x61,
+	0x69, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x20, 0x72, 0x70, 0x63, 0x5f, 0x72, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x5f, 0x72,
+	0x65, 0x73, 0x65, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x18, 0x72, 0x70, 0x63, 0x5f,
+	0x72, 0x65, 0x73, 0x65, 0x74, 0x5f, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x18, 0x72, 0x70, 0x63, 0x5f, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x5f,
+	0x75, 0x73, 0x65, 0x72, 0x5f, 0x6d, 0x66, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x14,
+	0x72, 0x70, 0x63, 0x5f, 0x65, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x5f, 0x6d, 0x66, 0x61, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x15, 0x72, 0x70, 0x63, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72,
+	0x6d, 0x5f, 0x6d, 0x66, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x15, 0x72, 0x70, 0x63,
+	0x5f, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x6d, 0x66, 0x61, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x1a, 0x1f, 0x72, 0x70, 0x63, 0x5f, 0x67, 0x65, 0x74, 0x5f, 0x74, 0x6f, 0x6b, 0x65,
+	0x6e, 0x5f, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f, 0x6b, 0x65, 0x79, 0x73, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x1a, 0x18, 0x72, 0x70, 0x63, 0x5f, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x5f,
+	0x61, 0x70, 0x69, 0x5f, 0x6b, 0x65, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x17, 0x72,
+	0x70, 0x63, 0x5f, 0x6c, 0x69, 0x73, 0x74, 0x5f, 0x61, 0x70, 0x69, 0x5f, 0x6b, 0x65, 0x79, 0x73,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x18, 0x72, 0x70, 0x63, 0x5f, 0x72, 0x65, 0x76, 0x6f,
+	0x6b, 0x65, 0x5f, 0x61, 0x70, 0x69, 0x5f, 0x6b, 0x65, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x2d, 0x67, 0x65, 0x6e, 0x2d, 0x6f, 0x70, 0x65,
+	0x6e, 0x61, 0x70, 0x69, 0x76, 0x32, 0x2f, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2f, 0x61,
+	0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x32, 0xe6, 0x29, 0x0a, 0x0a, 0x53, 0x69, 0x6d, 0x70, 0x6c, 0x65, 0x42, 0x61, 0x6e, 0x6b, 0x12,
+	0x83, 0x01, 0x0a, 0x0a, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x12, 0x15,
+	0x2e, 0x70, 0x62, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x46, 0x82,
+	0xd3, 0xe4, 0x93, 0x02, 0x14, 0x22, 0x0f, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x3a, 0x01, 0x2a, 0x92, 0x41, 0x29, 0x12, 0x0f, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x20, 0x4e, 0x65, 0x77, 0x20, 0x55, 0x73, 0x65, 0x72, 0x1a, 0x16, 0x41,
+	0x50, 0x49, 0x20, 0x74, 0x6f, 0x20, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x20, 0x4e, 0x65, 0x77,
+	0x20, 0x55, 0x73, 0x65, 0x72, 0x12, 0xa1, 0x01, 0x0a, 0x09, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x55,
+	0x73, 0x65, 0x72, 0x12, 0x14, 0x2e, 0x70, 0x62, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x55, 0x73,
+	0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x70, 0x62, 0x2e, 0x4c,
+	0x6f, 0x67, 0x69, 0x6e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x67, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x13, 0x22, 0x0e, 0x2f, 0x76, 0x31, 0x2f, 0x6c, 0x6f,
+	0x67, 0x69, 0x6e, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x3a, 0x01, 0x2a, 0x92, 0x41, 0x4b, 0x12, 0x0a,
+	0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x20, 0x55, 0x73, 0x65, 0x72, 0x1a, 0x3d, 0x41, 0x50, 0x49, 0x20,
+	0x74, 0x6f, 0x20, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x20, 0x55, 0x73, 0x65, 0x72, 0x20, 0x61, 0x6e,
+	0x64, 0x20, 0x47, 0x65, 0x74, 0x20, 0x42, 0x6f, 0x74, 0x68, 0x20, 0x41, 0x63, 0x63, 0x65, 0x73,
+	0x73, 0x20, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x20, 0x61, 0x6e, 0x64, 0x20, 0x52, 0x65, 0x66, 0x72,
+	0x65, 0x73, 0x68, 0x20, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0xa6, 0x01, 0x0a, 0x0d, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x18, 0x2e, 0x70, 0x62,
+	0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x60, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x11, 0x22, 0x0c, 0x2f, 0x76, 0x31, 0x2f, 0x61, 0x63,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x3a, 0x01, 0x2a, 0x92, 0x41, 0x46, 0x12, 0x12, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x20, 0x4e, 0x65, 0x77, 0x20, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x1a, 0x30, 0x41, 0x50, 0x49, 0x20, 0x74, 0x6f, 0x20, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x20,
+	0x4e, 0x65, 0x77, 0x20, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x20, 0x66, 0x6f, 0x72, 0x20,
+	0x74, 0x68, 0x65, 0x20, 0x4c, 0x6f, 0x67, 0x67, 0x65, 0x64, 0x20, 0x49, 0x6e, 0x20, 0x55, 0x73,
+	0x65, 0x72, 0x12, 0x99, 0x01, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x12, 0x15, 0x2e, 0x70, 0x62, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x70, 0x62, 0x2e, 0x47, 0x65,
+	0x74, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x5c, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x13, 0x12, 0x11, 0x2f, 0x76, 0x31, 0x2f, 0x61, 0x63,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x2f, 0x7b, 0x69, 0x64, 0x7d, 0x92, 0x41, 0x40, 0x12, 0x0b,
+	0x47, 0x65, 0x74, 0x20, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x1a, 0x31, 0x41, 0x50, 0x49,
+	0x20, 0x74, 0x6f, 0x20, 0x47, 0x65, 0x74, 0x20, 0x61, 0x6e, 0x20, 0x41, 0x63, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x20, 0x4f, 0x77, 0x6e, 0x65, 0x64, 0x20, 0x62, 0x79, 0x20, 0x74, 0x68, 0x65, 0x20,
+	0x4c, 0x6f, 0x67, 0x67, 0x65, 0x64, 0x20, 0x49, 0x6e, 0x20, 0x55, 0x73, 0x65, 0x72, 0x12, 0x9f,
+	0x01, 0x0a, 0x0c, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x12,
+	0x17, 0x2e, 0x70, 0x62, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x70, 0x62, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x5c, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0e, 0x12, 0x0c, 0x2f, 0x76, 0x31, 0x2f,
+	0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x92, 0x41, 0x45, 0x12, 0x0d, 0x4c, 0x69, 0x73,
+	0x74, 0x20, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x1a, 0x34, 0x41, 0x50, 0x49, 0x20,
+	0x74, 0x6f, 0x20, 0x4c, 0x69, 0x73, 0x74, 0x20, 0x74, 0x68, 0x65, 0x20, 0x41, 0x63, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x73, 0x20, 0x4f, 0x77, 0x6e, 0x65, 0x64, 0x20, 0x62, 0x79, 0x20, 0x74, 0x68,
+	0x65, 0x20, 0x4c, 0x6f, 0x67, 0x67, 0x65, 0x64, 0x20, 0x49, 0x6e, 0x20, 0x55, 0x73, 0x65, 0x72,
+	0x12, 0xa8, 0x01, 0x0a, 0x0d, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x12, 0x18, 0x2e, 0x70, 0x62, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x41, 0x63,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x70,
+	0x62, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x62, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x13, 0x2a,
+	0x11, 0x2f, 0x76, 0x31, 0x2f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x2f, 0x7b, 0x69,
+	0x64, 0x7d, 0x92, 0x41, 0x46, 0x12, 0x0e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x20, 0x41, 0x63,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x1a, 0x34, 0x41, 0x50, 0x49, 0x20, 0x74, 0x6f, 0x20, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x20, 0x61, 0x6e, 0x20, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x20,
+	0x4f, 0x77, 0x6e, 0x65, 0x64, 0x20, 0x62, 0x79, 0x20, 0x74, 0x68, 0x65, 0x20, 0x4c, 0x6f, 0x67,
+	0x67, 0x65, 0x64, 0x20, 0x49, 0x6e, 0x20, 0x55, 0x73, 0x65, 0x72, 0x12, 0xc8, 0x01, 0x0a, 0x0d,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x4d, 0x6f, 0x6e, 0x65, 0x79, 0x12, 0x18, 0x2e,
+	0x70, 0x62, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x4d, 0x6f, 0x6e, 0x65, 0x79,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x70, 0x62, 0x2e, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x66, 0x65, 0x72, 0x4d, 0x6f, 0x6e, 0x65, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x81, 0x01, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x12, 0x22, 0x0d, 0x2f, 0x76, 0x31,
+	0x2f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x73, 0x3a, 0x01, 0x2a, 0x92, 0x41, 0x66,
+	0x12, 0x0e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x20, 0x4d, 0x6f, 0x6e, 0x65, 0x79,
+	0x1a, 0x54, 0x41, 0x50, 0x49, 0x20, 0x74, 0x6f, 0x20, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65,
+	0x72, 0x20, 0x4d, 0x6f, 0x6e, 0x65, 0x79, 0x20, 0x66, 0x72, 0x6f, 0x6d, 0x20, 0x61, 0x6e, 0x20,
+	0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x20, 0x4f, 0x77, 0x6e, 0x65, 0x64, 0x20, 0x62, 0x79,
+	0x20, 0x74, 0x68, 0x65, 0x20, 0x4c, 0x6f, 0x67, 0x67, 0x65, 0x64, 0x20, 0x49, 0x6e, 0x20, 0x55,
+	0x73, 0x65, 0x72, 0x20, 0x74, 0x6f, 0x20, 0x41, 0x6e, 0x6f, 0x74, 0x68, 0x65, 0x72, 0x20, 0x41,
+	0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0xd5, 0x01, 0x0a, 0x0f, 0x52, 0x65, 0x76, 0x65, 0x72,
+	0x73, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x12, 0x1a, 0x2e, 0x70, 0x62, 0x2e,
+	0x52, 0x65, 0x76, 0x65, 0x72, 0x73, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x76, 0x65,
+	0x72, 0x73, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x88, 0x01, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1f, 0x22, 0x1a, 0x2f, 0x76,
+	0x31, 0x2f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x73, 0x2f, 0x7b, 0x69, 0x64, 0x7d,
+	0x2f, 0x72, 0x65, 0x76, 0x65, 0x72, 0x73, 0x65, 0x3a, 0x01, 0x2a, 0x92, 0x41, 0x60, 0x12, 0x10,
+	0x52, 0x65, 0x76, 0x65, 0x72, 0x73, 0x65, 0x20, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72,
+	0x1a, 0x4c, 0x41, 0x50, 0x49, 0x20, 0x74, 0x6f, 0x20, 0x52, 0x65, 0x76, 0x65, 0x72, 0x73, 0x65,
+	0x20, 0x61, 0x20, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x20, 0x52, 0x65, 0x63, 0x65,
+	0x69, 0x76, 0x65, 0x64, 0x20, 0x62, 0x79, 0x20, 0x61, 0x6e, 0x20, 0x41, 0x63, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x20, 0x4f, 0x77, 0x6e, 0x65, 0x64, 0x20, 0x62, 0x79, 0x20, 0x74, 0x68, 0x65, 0x20,
+	0x4c, 0x6f, 0x67, 0x67, 0x65, 0x64, 0x20, 0x49, 0x6e, 0x20, 0x55, 0x73, 0x65, 0x72, 0x12, 0xa2,
+	0x01, 0x0a, 0x07, 0x44, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x12, 0x12, 0x2e, 0x70, 0x62, 0x2e,
+	0x44, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13,
+	0x2e, 0x70, 0x62, 0x2e, 0x44, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x6e, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x11, 0x22, 0x0c, 0x2f, 0x76, 0x31,
+	0x2f, 0x64, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x73, 0x3a, 0x01, 0x2a, 0x92, 0x41, 0x54, 0x12,
+	0x0d, 0x44, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x20, 0x4d, 0x6f, 0x6e, 0x65, 0x79, 0x1a, 0x43,
+	0x41, 0x50, 0x49, 0x20, 0x66, 0x6f, 0x72, 0x20, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x73, 0x20, 0x74,
+	0x6f, 0x20, 0x44, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x20, 0x4d, 0x6f, 0x6e, 0x65, 0x79, 0x20,
+	0x66, 0x72, 0x6f, 0x6d, 0x20, 0x4f, 0x75, 0x74, 0x73, 0x69, 0x64, 0x65, 0x20, 0x74, 0x68, 0x65,
+	0x20, 0x42, 0x61, 0x6e, 0x6b, 0x20, 0x74, 0x6f, 0x20, 0x61, 0x6e, 0x20, 0x41, 0x63, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x12, 0xbc, 0x01, 0x0a, 0x08, 0x57, 0x69, 0x74, 0x68, 0x64, 0x72, 0x61, 0x77,
+	0x12, 0x13, 0x2e, 0x70, 0x62, 0x2e, 0x57, 0x69, 0x74, 0x68, 0x64, 0x72, 0x61, 0x77, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x70, 0x62, 0x2e, 0x57, 0x69, 0x74, 0x68, 0x64,
+	0x72, 0x61, 0x77, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x84, 0x01, 0x82, 0xd3,
+	0xe4, 0x93, 0x02, 0x14, 0x22, 0x0f, 0x2f, 0x76, 0x31, 0x2f, 0x77, 0x69, 0x74, 0x68, 0x64, 0x72,
+	0x61, 0x77, 0x61, 0x6c, 0x73, 0x3a, 0x01, 0x2a, 0x92, 0x41, 0x67, 0x12, 0x0e, 0x57, 0x69, 0x74,
+	0x68, 0x64, 0x72, 0x61, 0x77, 0x20, 0x4d, 0x6f, 0x6e, 0x65, 0x79, 0x1a, 0x55, 0x41, 0x50, 0x49,
+	0x20, 0x74, 0x6f, 0x20, 0x57, 0x69, 0x74, 0x68, 0x64, 0x72, 0x61, 0x77, 0x20, 0x4d, 0x6f, 0x6e,
+	0x65, 0x79, 0x20, 0x66, 0x72, 0x6f, 0x6d, 0x20, 0x61, 0x6e, 0x20, 0x41, 0x63, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x20, 0x4f, 0x77, 0x6e, 0x65, 0x64, 0x20, 0x62, 0x79, 0x20, 0x74, 0x68, 0x65, 0x20,
+	0x4c, 0x6f, 0x67, 0x67, 0x65, 0x64, 0x20, 0x49, 0x6e, 0x20, 0x55, 0x73, 0x65, 0x72, 0x20, 0x74,
+	0x6f, 0x20, 0x4f, 0x75, 0x74, 0x73, 0x69, 0x64, 0x65, 0x20, 0x74, 0x68, 0x65, 0x20, 0x42, 0x61,
+	0x6e, 0x6b, 0x12, 0xbd, 0x01, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x69,
+	0x65, 0x73, 0x12, 0x16, 0x2e, 0x70, 0x62, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x45, 0x6e, 0x74, 0x72,
+	0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x62, 0x2e,
+	0x4c, 0x69, 0x73, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x7d, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x23, 0x12, 0x21, 0x2f, 0x76, 0x31,
+	0x2f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x2f, 0x7b, 0x61, 0x63, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x5f, 0x69, 0x64, 0x7d, 0x2f, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x92, 0x41,
+	0x51, 0x12, 0x0c, 0x4c, 0x69, 0x73, 0x74, 0x20, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x1a,
+	0x41, 0x41, 0x50, 0x49, 0x20, 0x74, 0x6f, 0x20, 0x4c, 0x69, 0x73, 0x74, 0x20, 0x74, 0x68, 0x65,
+	0x20, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x20, 0x6f, 0x66, 0x20, 0x61, 0x6e, 0x20, 0x41,
+	0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x20, 0x4f, 0x77, 0x6e, 0x65, 0x64, 0x20, 0x62, 0x79, 0x20,
+	0x74, 0x68, 0x65, 0x20, 0x4c, 0x6f, 0x67, 0x67, 0x65, 0x64, 0x20, 0x49, 0x6e, 0x20, 0x55, 0x73,
+	0x65, 0x72, 0x12, 0xde, 0x01, 0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x66, 0x65, 0x72, 0x73, 0x12, 0x18, 0x2e, 0x70, 0x62, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19,
+	0x2e, 0x70, 0x62, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x97, 0x01, 0x82, 0xd3, 0xe4, 0x93,
+	0x02, 0x25, 0x12, 0x23, 0x2f, 0x76, 0x31, 0x2f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73,
+	0x2f, 0x7b, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x7d, 0x2f, 0x74, 0x72,
+	0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x73, 0x92, 0x41, 0x69, 0x12, 0x0e, 0x4c, 0x69, 0x73, 0x74,
+	0x20, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x73, 0x1a, 0x57, 0x41, 0x50, 0x49, 0x20,
+	0x74, 0x6f, 0x20, 0x4c, 0x69, 0x73, 0x74, 0x20, 0x74, 0x68, 0x65, 0x20, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x66, 0x65, 0x72, 0x73, 0x20, 0x47, 0x6f, 0x69, 0x6e, 0x67, 0x20, 0x49, 0x6e, 0x20, 0x74,
+	0x6f, 0x20, 0x61, 0x6e, 0x64, 0x20, 0x4f, 0x75, 0x74, 0x20, 0x6f, 0x66, 0x20, 0x61, 0x6e, 0x20,
+	0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x20, 0x4f, 0x77, 0x6e, 0x65, 0x64, 0x20, 0x62, 0x79,
+	0x20, 0x74, 0x68, 0x65, 0x20, 0x4c, 0x6f, 0x67, 0x67, 0x65, 0x64, 0x20, 0x49, 0x6e, 0x20, 0x55,
+	0x73, 0x65, 0x72, 0x12, 0xb3, 0x01, 0x0a, 0x0d, 0x46, 0x72, 0x65, 0x65, 0x7a, 0x65, 0x41, 0x63,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x18, 0x2e, 0x70, 0x62, 0x2e, 0x46, 0x72, 0x65, 0x65, 0x7a,
+	0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x19, 0x2e, 0x70, 0x62, 0x2e, 0x46, 0x72, 0x65, 0x65, 0x7a, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x6d, 0x82, 0xd3, 0xe4, 0x93,
+	0x02, 0x23, 0x22, 0x1e, 0x2f, 0x76, 0x31, 0x2f, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2f, 0x61, 0x63,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x2f, 0x7b, 0x69, 0x64, 0x7d, 0x2f, 0x66, 0x72, 0x65, 0x65,
+	0x7a, 0x65, 0x3a, 0x01, 0x2a, 0x92, 0x41, 0x41, 0x12, 0x0e, 0x46, 0x72, 0x65, 0x65, 0x7a, 0x65,
+	0x20, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x1a, 0x2f, 0x41, 0x50, 0x49, 0x20, 0x66, 0x6f,
+	0x72, 0x20, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x73, 0x20, 0x74, 0x6f, 0x20, 0x46, 0x72, 0x65, 0x65,
+	0x7a, 0x65, 0x20, 0x6f, 0x72, 0x20, 0x55, 0x6e, 0x66, 0x72, 0x65, 0x65, 0x7a, 0x65, 0x20, 0x61,
+	0x6e, 0x20, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0xe0, 0x01, 0x0a, 0x0c, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x17, 0x2e, 0x70, 0x62, 0x2e,
+	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x70, 0x62, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x53, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x9c, 0x01,
+	0x82, 0xd3, 0xe4, 0x93, 0x02, 0x2a, 0x22, 0x25, 0x2f, 0x76, 0x31, 0x2f, 0x61, 0x64, 0x6d, 0x69,
+	0x6e, 0x2f, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x2f, 0x7b, 0x73, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x7d, 0x2f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x3a, 0x01, 0x2a,
+	0x92, 0x41, 0x69, 0x12, 0x0d, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x20, 0x53, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x1a, 0x58, 0x41, 0x50, 0x49, 0x20, 0x66, 0x6f, 0x72, 0x20, 0x41, 0x64, 0x6d, 0x69,
+	0x6e, 0x73, 0x20, 0x74, 0x6f, 0x20, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x20, 0x61, 0x20, 0x53, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x20, 0x73, 0x6f, 0x20, 0x69, 0x74, 0x73, 0x20, 0x52, 0x65, 0x66,
+	0x72, 0x65, 0x73, 0x68, 0x20, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x20, 0x43, 0x61, 0x6e, 0x20, 0x4e,
+	0x6f, 0x20, 0x4c, 0x6f, 0x6e, 0x67, 0x65, 0x72, 0x20, 0x52, 0x65, 0x6e, 0x65, 0x77, 0x20, 0x41,
+	0x63, 0x63, 0x65, 0x73, 0x73, 0x20, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x12, 0xa8, 0x01, 0x0a,
+	0x0b, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x16, 0x2e, 0x70,
+	0x62, 0x2e, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65,
+	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x68, 0x82,
+	0xd3, 0xe4, 0x93, 0x02, 0x16, 0x22, 0x11, 0x2f, 0x76, 0x31, 0x2f, 0x74, 0x6f, 0x6b, 0x65, 0x6e,
+	0x73, 0x2f, 0x72, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x3a, 0x01, 0x2a, 0x92, 0x41, 0x49, 0x12, 0x0c,
+	0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x20, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x1a, 0x39, 0x41, 0x50,
+	0x49, 0x20, 0x74, 0x6f, 0x20, 0x4c, 0x6f, 0x67, 0x20, 0x4f, 0x75, 0x74, 0x20, 0x62, 0x79, 0x20,
+	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x69, 0x6e, 0x67, 0x20, 0x74, 0x68, 0x65, 0x20, 0x53, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x20, 0x6f, 0x66, 0x20, 0x61, 0x20, 0x52, 0x65, 0x66, 0x72, 0x65, 0x73,
+	0x68, 0x20, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0xa0, 0x01, 0x0a, 0x0c, 0x4c, 0x69, 0x73, 0x74,
+	0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x18, 0x2e, 0x70, 0x62, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x5d, 0x82, 0xd3, 0xe4,
+	0x93, 0x02, 0x0e, 0x12, 0x0c, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x73, 0x92, 0x41, 0x46, 0x12, 0x0d, 0x4c, 0x69, 0x73, 0x74, 0x20, 0x53, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x73, 0x1a, 0x35, 0x41, 0x50, 0x49, 0x20, 0x74, 0x6f, 0x20, 0x4c, 0x69, 0x73, 0x74,
+	0x20, 0x74, 0x68, 0x65, 0x20, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x20, 0x53, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x73, 0x20, 0x6f, 0x66, 0x20, 0x74, 0x68, 0x65, 0x20, 0x4c, 0x6f, 0x67, 0x67,
+	0x65, 0x64, 0x20, 0x49, 0x6e, 0x20, 0x55, 0x73, 0x65, 0x72, 0x12, 0xb3, 0x01, 0x0a, 0x0d, 0x52,
+	0x65, 0x76, 0x6f, 0x6b, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x2e, 0x70,
+	0x62, 0x2e, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x76, 0x6f,
+	0x6b, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x6d, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1b, 0x2a, 0x19, 0x2f, 0x76, 0x31, 0x2f, 0x73,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x2f, 0x7b, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x5f, 0x69, 0x64, 0x7d, 0x92, 0x41, 0x49, 0x12, 0x0e, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x20,
+	0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x1a, 0x37, 0x41, 0x50, 0x49, 0x20, 0x74, 0x6f, 0x20,
+	0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x20, 0x4f, 0x6e, 0x65, 0x20, 0x6f, 0x66, 0x20, 0x74, 0x68,
+	0x65, 0x20, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x20, 0x6f, 0x66, 0x20, 0x74, 0x68,
+	0x65, 0x20, 0x4c, 0x6f, 0x67, 0x67, 0x65, 0x64, 0x20, 0x49, 0x6e, 0x20, 0x55, 0x73, 0x65, 0x72,
+	0x12, 0xc7, 0x01, 0x0a, 0x10, 0x52, 0x65, 0x6e, 0x65, 0x77, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73,
+	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1b, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x6e, 0x65, 0x77,
+	0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x6e, 0x65, 0x77, 0x41, 0x63, 0x63,
+	0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x78, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1c, 0x22, 0x17, 0x2f, 0x76, 0x31, 0x2f, 0x74, 0x6f,
+	0x6b, 0x65, 0x6e, 0x73, 0x2f, 0x72, 0x65, 0x6e, 0x65, 0x77, 0x5f, 0x61, 0x63, 0x63, 0x65, 0x73,
+	0x73, 0x3a, 0x01, 0x2a, 0x92, 0x41, 0x53, 0x12, 0x12, 0x52, 0x65, 0x6e, 0x65, 0x77, 0x20, 0x41,
+	0x63, 0x63, 0x65, 0x73, 0x73, 0x20, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x1a, 0x3d, 0x41, 0x50, 0x49,
+	0x20, 0x74, 0x6f, 0x20, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x20, 0x61, 0x20, 0x4e, 0x65, 0x77,
+	0x20, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x20, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x20, 0x61, 0x6e,
+	0x64, 0x20, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x20, 0x74, 0x68, 0x65, 0x20, 0x52, 0x65, 0x66,
+	0x72, 0x65, 0x73, 0x68, 0x20, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0xa6, 0x01, 0x0a, 0x0a, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x12, 0x15, 0x2e, 0x70, 0x62, 0x2e, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x16, 0x2e, 0x70, 0x62, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x69, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x19,
+	0x32, 0x14, 0x2f, 0x76, 0x31, 0x2f, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2f, 0x7b, 0x75, 0x73, 0x65,
+	0x72, 0x6e, 0x61, 0x6d, 0x65, 0x7d, 0x3a, 0x01, 0x2a, 0x92, 0x41, 0x47, 0x12, 0x0b, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x20, 0x55, 0x73, 0x65, 0x72, 0x1a, 0x38, 0x41, 0x50, 0x49, 0x20, 0x74,
+	0x6f, 0x20, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x20, 0x74, 0x68, 0x65, 0x20, 0x46, 0x75, 0x6c,
+	0x6c, 0x20, 0x4e, 0x61, 0x6d, 0x65, 0x2c, 0x20, 0x45, 0x6d, 0x61, 0x69, 0x6c, 0x20, 0x6f, 0x72,
+	0x20, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x20, 0x6f, 0x66, 0x20, 0x61, 0x20, 0x55,
+	0x73, 0x65, 0x72, 0x12, 0x94, 0x01, 0x0a, 0x0b, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x45, 0x6d,
+	0x61, 0x69, 0x6c, 0x12, 0x16, 0x2e, 0x70, 0x62, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x45,
+	0x6d, 0x61, 0x69, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x62,
+	0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x45, 0x6d, 0x61, 0x69, 0x6c, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x54, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x12, 0x12, 0x10, 0x2f, 0x76,
+	0x31, 0x2f, 0x76, 0x65, 0x72, 0x69, 0x66, 0x79, 0x5f, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x92, 0x41,
+	0x39, 0x12, 0x0c, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x20, 0x45, 0x6d, 0x61, 0x69, 0x6c, 0x1a,
+	0x29, 0x41, 0x50, 0x49, 0x20, 0x74, 0x6f, 0x20, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x20, 0x74,
+	0x68, 0x65, 0x20, 0x45, 0x6d, 0x61, 0x69, 0x6c, 0x20, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x20, 0x6f, 0x66, 0x20, 0x61, 0x20, 0x55, 0x73, 0x65, 0x72, 0x12, 0xc1, 0x01, 0x0a, 0x14, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x52, 0x65,
+	0x73, 0x65, 0x74, 0x12, 0x1f, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x73, 0x65, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x73, 0x65, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x66, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x17, 0x22, 0x12,
+	0x2f, 0x76, 0x31, 0x2f, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x5f, 0x72, 0x65, 0x73,
+	0x65, 0x74, 0x3a, 0x01, 0x2a, 0x92, 0x41, 0x46, 0x12, 0x16, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x20, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x20, 0x52, 0x65, 0x73, 0x65, 0x74,
+	0x1a, 0x2c, 0x41, 0x50, 0x49, 0x20, 0x74, 0x6f, 0x20, 0x45, 0x6d, 0x61, 0x69, 0x6c, 0x20, 0x61,
+	0x20, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x20, 0x52, 0x65, 0x73, 0x65, 0x74, 0x20,
+	0x4c, 0x69, 0x6e, 0x6b, 0x20, 0x74, 0x6f, 0x20, 0x61, 0x20, 0x55, 0x73, 0x65, 0x72, 0x12, 0xc1,
+	0x01, 0x0a, 0x0d, 0x52, 0x65, 0x73, 0x65, 0x74, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64,
+	0x12, 0x18, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x65, 0x74, 0x50, 0x61, 0x73, 0x73, 0x77,
+	0x6f, 0x72, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x70, 0x62, 0x2e,
+	0x52, 0x65, 0x73, 0x65, 0x74, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x7b, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1f, 0x22, 0x1a, 0x2f,
+	0x76, 0x31, 0x2f, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x5f, 0x72, 0x65, 0x73, 0x65,
+	0x74, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x3a, 0x01, 0x2a, 0x92, 0x41, 0x53, 0x12,
+	0x0e, 0x52, 0x65, 0x73, 0x65, 0x74, 0x20, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x1a,
+	0x41, 0x41, 0x50, 0x49, 0x20, 0x74, 0x6f, 0x20, 0x53, 0x65, 0x74, 0x20, 0x61, 0x20, 0x4e, 0x65,
+	0x77, 0x20, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x20, 0x77, 0x69, 0x74, 0x68, 0x20,
+	0x74, 0x68, 0x65, 0x20, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x20, 0x6f, 0x66, 0x20, 0x61, 0x20, 0x50,
+	0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x20, 0x52, 0x65, 0x73, 0x65, 0x74, 0x20, 0x4c, 0x69,
+	0x6e, 0x6b, 0x12, 0xb5, 0x01, 0x0a, 0x0c, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x55, 0x73, 0x65, 0x72,
+	0x4d, 0x46, 0x41, 0x12, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x55, 0x73,
+	0x65, 0x72, 0x4d, 0x46, 0x41, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x70,
+	0x62, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x55, 0x73, 0x65, 0x72, 0x4d, 0x46, 0x41, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x72, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x17, 0x22, 0x12,
+	0x2f, 0x76, 0x31, 0x2f, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x2f, 0x6d,
+	0x66, 0x61, 0x3a, 0x01, 0x2a, 0x92, 0x41, 0x52, 0x12, 0x0e, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x20,
+	0x55, 0x73, 0x65, 0x72, 0x20, 0x4d, 0x46, 0x41, 0x1a, 0x40, 0x41, 0x50, 0x49, 0x20, 0x74, 0x6f,
+	0x20, 0x46, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x20, 0x74, 0x68, 0x65, 0x20, 0x4c, 0x6f, 0x67, 0x69,
+	0x6e, 0x20, 0x6f, 0x66, 0x20, 0x61, 0x20, 0x55, 0x73, 0x65, 0x72, 0x20, 0x77, 0x69, 0x74, 0x68,
+	0x20, 0x54, 0x77, 0x6f, 0x2d, 0x46, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x20, 0x41, 0x75, 0x74, 0x68,
+	0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x9d, 0x01, 0x0a, 0x09, 0x45,
+	0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x4d, 0x46, 0x41, 0x12, 0x14, 0x2e, 0x70, 0x62, 0x2e, 0x45, 0x6e,
+	0x72, 0x6f, 0x6c, 0x6c, 0x4d, 0x46, 0x41, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15,
+	0x2e, 0x70, 0x62, 0x2e, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x4d, 0x46, 0x41, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x63, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x13, 0x22, 0x0e, 0x2f,
+	0x76, 0x31, 0x2f, 0x6d, 0x66, 0x61, 0x2f, 0x65, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x3a, 0x01, 0x2a,
+	0x92, 0x41, 0x47, 0x12, 0x0a, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x20, 0x4d, 0x46, 0x41, 0x1a,
+	0x39, 0x41, 0x50, 0x49, 0x20, 0x74, 0x6f, 0x20, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x20, 0x61,
+	0x20, 0x54, 0x4f, 0x54, 0x50, 0x20, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x20, 0x66, 0x6f, 0x72,
+	0x20, 0x54, 0x77, 0x6f, 0x2d, 0x46, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x20, 0x41, 0x75, 0x74, 0x68,
+	0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0xa1, 0x01, 0x0a, 0x0a, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x4d, 0x46, 0x41, 0x12, 0x15, 0x2e, 0x70, 0x62, 0x2e, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x4d, 0x46, 0x41, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x16, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x4d, 0x46, 0x41,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x64, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x14,
+	0x22, 0x0f, 0x2f, 0x76, 0x31, 0x2f, 0x6d, 0x66, 0x61, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72,
+	0x6d, 0x3a, 0x01, 0x2a, 0x92, 0x41, 0x47, 0x12, 0x0b, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d,
+	0x20, 0x4d, 0x46, 0x41, 0x1a, 0x38, 0x41, 0x50, 0x49, 0x20, 0x74, 0x6f, 0x20, 0x45, 0x6e, 0x61,
+	0x62, 0x6c, 0x65, 0x20, 0x54, 0x77, 0x6f, 0x2d, 0x46, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x20, 0x41,
+	0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x20, 0x77, 0x69,
+	0x74, 0x68, 0x20, 0x61, 0x20, 0x54, 0x4f, 0x54, 0x50, 0x20, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x91,
+	0x01, 0x0a, 0x0a, 0x44, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x4d, 0x46, 0x41, 0x12, 0x15, 0x2e,
+	0x70, 0x62, 0x2e, 0x44, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x4d, 0x46, 0x41, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x70, 0x62, 0x2e, 0x44, 0x69, 0x73, 0x61, 0x62, 0x6c,
+	0x65, 0x4d, 0x46, 0x41, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x54, 0x82, 0xd3,
+	0xe4, 0x93, 0x02, 0x14, 0x22, 0x0f, 0x2f, 0x76, 0x31, 0x2f, 0x6d, 0x66, 0x61, 0x2f, 0x64, 0x69,
+	0x73, 0x61, 0x62, 0x6c, 0x65, 0x3a, 0x01, 0x2a, 0x92, 0x41, 0x37, 0x12, 0x0b, 0x44, 0x69, 0x73,
+	0x61, 0x62, 0x6c, 0x65, 0x20, 0x4d, 0x46, 0x41, 0x1a, 0x28, 0x41, 0x50, 0x49, 0x20, 0x74, 0x6f,
+	0x20, 0x44, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x20, 0x54, 0x77, 0x6f, 0x2d, 0x46, 0x61, 0x63,
+	0x74, 0x6f, 0x72, 0x20, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0xc1, 0x01, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x50,
+	0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x1d, 0x2e, 0x70, 0x62, 0x2e, 0x47,
+	0x65, 0x74, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x70, 0x62, 0x2e, 0x47, 0x65,
+	0x74, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x6c, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x18,
+	0x12, 0x16, 0x2f, 0x76, 0x31, 0x2f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x2f, 0x70, 0x75, 0x62,
+	0x6c, 0x69, 0x63, 0x5f, 0x6b, 0x65, 0x79, 0x73, 0x92, 0x41, 0x4b, 0x12, 0x15, 0x47, 0x65, 0x74,
+	0x20, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x20, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x20, 0x6b, 0x65,
+	0x79, 0x73, 0x1a, 0x32, 0x41, 0x50, 0x49, 0x20, 0x74, 0x6f, 0x20, 0x47, 0x65, 0x74, 0x20, 0x74,
+	0x68, 0x65, 0x20, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x20, 0x4b, 0x65, 0x79, 0x73, 0x20, 0x77,
+	0x68, 0x69, 0x63, 0x68, 0x20, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x20, 0x74, 0x68, 0x65, 0x20,
+	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x12, 0xa4, 0x01, 0x0a, 0x0c, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x41, 0x50, 0x49, 0x4b, 0x65, 0x79, 0x12, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x41, 0x50, 0x49, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x18, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x41, 0x50, 0x49, 0x4b,
+	0x65, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x61, 0x82, 0xd3, 0xe4, 0x93,
+	0x02, 0x11, 0x22, 0x0c, 0x2f, 0x76, 0x31, 0x2f, 0x61, 0x70, 0x69, 0x5f, 0x6b, 0x65, 0x79, 0x73,
+	0x3a, 0x01, 0x2a, 0x92, 0x41, 0x47, 0x12, 0x0e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x20, 0x41,
+	0x50, 0x49, 0x20, 0x6b, 0x65, 0x79, 0x1a, 0x35, 0x41, 0x50, 0x49, 0x20, 0x74, 0x6f, 0x20, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x20, 0x61, 0x6e, 0x20, 0x41, 0x50, 0x49, 0x20, 0x4b, 0x65, 0x79,
+	0x20, 0x66, 0x6f, 0x72, 0x20, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2d, 0x74, 0x6f, 0x2d, 0x53,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x20, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x96, 0x01,
+	0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x50, 0x49, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x16, 0x2e,
+	0x70, 0x62, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x50, 0x49, 0x4b, 0x65, 0x79, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x41,
+	0x50, 0x49, 0x4b, 0x65, 0x79, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x56,
+	0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0e, 0x12, 0x0c, 0x2f, 0x76, 0x31, 0x2f, 0x61, 0x70, 0x69, 0x5f,
+	0x6b, 0x65, 0x79, 0x73, 0x92, 0x41, 0x3f, 0x12, 0x0d, 0x4c, 0x69, 0x73, 0x74, 0x20, 0x41, 0x50,
+	0x49, 0x20, 0x6b, 0x65, 0x79, 0x73, 0x1a, 0x2e, 0x41, 0x50, 0x49, 0x20, 0x74, 0x6f, 0x20, 0x4c,
+	0x69, 0x73, 0x74, 0x20, 0x74, 0x68, 0x65, 0x20, 0x41, 0x50, 0x49, 0x20, 0x4b, 0x65, 0x79, 0x73,
+	0x20, 0x6f, 0x66, 0x20, 0x74, 0x68, 0x65, 0x20, 0x4c, 0x6f, 0x67, 0x67, 0x65, 0x64, 0x20, 0x49,
+	0x6e, 0x20, 0x55, 0x73, 0x65, 0x72, 0x12, 0xb0, 0x01, 0x0a, 0x0c, 0x52, 0x65, 0x76, 0x6f, 0x6b,
+	0x65, 0x41, 0x50, 0x49, 0x4b, 0x65, 0x79, 0x12, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x76,
+	0x6f, 0x6b, 0x65, 0x41, 0x50, 0x49, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x18, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x41, 0x50, 0x49, 0x4b,
+	0x65, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x6d, 0x82, 0xd3, 0xe4, 0x93,
+	0x02, 0x1b, 0x2a, 0x19, 0x2f, 0x76, 0x31, 0x2f, 0x61, 0x70, 0x69, 0x5f, 0x6b, 0x65, 0x79, 0x73,
+	0x2f, 0x7b, 0x61, 0x70, 0x69, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x69, 0x64, 0x7d, 0x92, 0x41, 0x49,
+	0x12, 0x0e, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x20, 0x41, 0x50, 0x49, 0x20, 0x6b, 0x65, 0x79,
+	0x1a, 0x37, 0x41, 0x50, 0x49, 0x20, 0x74, 0x6f, 0x20, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x20,
+	0x4f, 0x6e, 0x65, 0x20, 0x6f, 0x66, 0x20, 0x74, 0x68, 0x65, 0x20, 0x41, 0x50, 0x49, 0x20, 0x4b,
+	0x65, 0x79, 0x73, 0x20, 0x6f, 0x66, 0x20, 0x74, 0x68, 0x65, 0x20, 0x4c, 0x6f, 0x67, 0x67, 0x65,
+	0x64, 0x20, 0x49, 0x6e, 0x20, 0x55, 0x73, 0x65, 0x72, 0x42, 0x84, 0x01, 0x5a, 0x14, 0x53, 0x69,
+	0x6d, 0x70, 0x6c, 0x65, 0x42, 0x61, 0x6e, 0x6b, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x2f,
+	0x70, 0x62, 0x92, 0x41, 0x6b, 0x12, 0x69, 0x0a, 0x0f, 0x53, 0x69, 0x6d, 0x70, 0x6c, 0x65, 0x20,
+	0x42, 0x61, 0x6e, 0x6b, 0x20, 0x41, 0x50, 0x49, 0x22, 0x51, 0x0a, 0x0e, 0x47, 0x61, 0x72, 0x72,
+	0x65, 0x74, 0x74, 0x20, 0x54, 0x61, 0x79, 0x6c, 0x6f, 0x72, 0x12, 0x28, 0x68, 0x74, 0x74, 0x70,
+	0x73, 0x3a, 0x2f, 0x2f, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67,
+	0x74, 0x61, 0x79, 0x6c, 0x6f, 0x72, 0x33, 0x31, 0x34, 0x2f, 0x73, 0x69, 0x6d, 0x70, 0x6c, 0x65,
+	0x62, 0x61, 0x6e, 0x6b, 0x1a, 0x15, 0x67, 0x74, 0x61, 0x79, 0x6c, 0x6f, 0x72, 0x33, 0x31, 0x34,
+	0x40, 0x69, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x63, 0x6f, 0x6d, 0x32, 0x03, 0x31, 0x2e, 0x31,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var file_service_simple_bank_proto_goTypes = []interface{}{
-	(*CreateUserRequest)(nil),  // 0: pb.CreateUserRequest
-	(*LoginUserRequest)(nil),   // 1: pb.LoginUserRequest
-	(*CreateUserResponse)(nil), // 2: pb.CreateUserResponse
-	(*LoginUserResponse)(nil),  // 3: pb.LoginUserResponse
+	(*CreateUserRequest)(nil),            // 0: pb.CreateUserRequest
+	(*LoginUserRequest)(nil),             // 1: pb.LoginUserRequest
+	(*CreateAccountRequest)(nil),         // 2: pb.CreateAccountRequest
+	(*GetAccountRequest)(nil),            // 3: pb.GetAccountRequest
+	(*ListAccountsRequest)(nil),          // 4: pb.ListAccountsRequest
+	(*DeleteAccountRequest)(nil),         // 5: pb.DeleteAccountRequest
+	(*TransferMoneyRequest)(nil),         // 6: pb.TransferMoneyRequest
+	(*ReverseTransferRequest)(nil),       // 7: pb.ReverseTransferRequest
+	(*DepositRequest)(nil),               // 8: pb.DepositRequest
+	(*WithdrawRequest)(nil),              // 9: pb.WithdrawRequest
+	(*ListEntriesRequest)(nil),           // 10: pb.ListEntriesRequest
+	(*ListTransfersRequest)(nil),         // 11: pb.ListTransfersRequest
+	(*FreezeAccountRequest)(nil),         // 12: pb.FreezeAccountRequest
+	(*BlockSessionRequest)(nil),          // 13: pb.BlockSessionRequest
+	(*RevokeTokenRequest)(nil),           // 14: pb.RevokeTokenRequest
+	(*ListSessionsRequest)(nil),          // 15: pb.ListSessionsRequest
+	(*RevokeSessionRequest)(nil),         // 16: pb.RevokeSessionRequest
+	(*RenewAccessTokenRequest)(nil),      // 17: pb.RenewAccessTokenRequest
+	(*UpdateUserRequest)(nil),            // 18: pb.UpdateUserRequest
+	(*VerifyEmailRequest)(nil),           // 19: pb.VerifyEmailRequest
+	(*RequestPasswordResetRequest)(nil),  // 20: pb.RequestPasswordResetRequest
+	(*ResetPasswordRequest)(nil),         // 21: pb.ResetPasswordRequest
+	(*LoginUserMFARequest)(nil),          // 22: pb.LoginUserMFARequest
+	(*EnrollMFARequest)(nil),             // 23: pb.EnrollMFARequest
+	(*ConfirmMFARequest)(nil),            // 24: pb.ConfirmMFARequest
+	(*DisableMFARequest)(nil),            // 25: pb.DisableMFARequest
+	(*GetTokenPublicKeysRequest)(nil),    // 26: pb.GetTokenPublicKeysRequest
+	(*CreateAPIKeyRequest)(nil),          // 27: pb.CreateAPIKeyRequest
+	(*ListAPIKeysRequest)(nil),           // 28: pb.ListAPIKeysRequest
+	(*RevokeAPIKeyRequest)(nil),          // 29: pb.RevokeAPIKeyRequest
+	(*CreateUserResponse)(nil),           // 30: pb.CreateUserResponse
+	(*LoginUserResponse)(nil),            // 31: pb.LoginUserResponse
+	(*CreateAccountResponse)(nil),        // 32: pb.CreateAccountResponse
+	(*GetAccountResponse)(nil),           // 33: pb.GetAccountResponse
+	(*ListAccountsResponse)(nil),         // 34: pb.ListAccountsResponse
+	(*DeleteAccountResponse)(nil),        // 35: pb.DeleteAccountResponse
+	(*TransferMoneyResponse)(nil),        // 36: pb.TransferMoneyResponse
+	(*ReverseTransferResponse)(nil),      // 37: pb.ReverseTransferResponse
+	(*DepositResponse)(nil),              // 38: pb.DepositResponse
+	(*WithdrawResponse)(nil),             // 39: pb.WithdrawResponse
+	(*ListEntriesResponse)(nil),          // 40: pb.ListEntriesResponse
+	(*ListTransfersResponse)(nil),        // 41: pb.ListTransfersResponse
+	(*FreezeAccountResponse)(nil),        // 42: pb.FreezeAccountResponse
+	(*BlockSessionResponse)(nil),         // 43: pb.BlockSessionResponse
+	(*RevokeTokenResponse)(nil),          // 44: pb.RevokeTokenResponse
+	(*ListSessionsResponse)(nil),         // 45: pb.ListSessionsResponse
+	(*RevokeSessionResponse)(nil),        // 46: pb.RevokeSessionResponse
+	(*RenewAccessTokenResponse)(nil),     // 47: pb.RenewAccessTokenResponse
+	(*UpdateUserResponse)(nil),           // 48: pb.UpdateUserResponse
+	(*VerifyEmailResponse)(nil),          // 49: pb.VerifyEmailResponse
+	(*RequestPasswordResetResponse)(nil), // 50: pb.RequestPasswordResetResponse
+	(*ResetPasswordResponse)(nil),        // 51: pb.ResetPasswordResponse
+	(*LoginUserMFAResponse)(nil),         // 52: pb.LoginUserMFAResponse
+	(*EnrollMFAResponse)(nil),            // 53: pb.EnrollMFAResponse
+	(*ConfirmMFAResponse)(nil),           // 54: pb.ConfirmMFAResponse
+	(*DisableMFAResponse)(nil),           // 55: pb.DisableMFAResponse
+	(*GetTokenPublicKeysResponse)(nil),   // 56: pb.GetTokenPublicKeysResponse
+	(*CreateAPIKeyResponse)(nil),         // 57: pb.CreateAPIKeyResponse
+	(*ListAPIKeysResponse)(nil),          // 58: pb.ListAPIKeysResponse
+	(*RevokeAPIKeyResponse)(nil),         // 59: pb.RevokeAPIKeyResponse
 }
 var file_service_simple_bank_proto_depIdxs = []int32{
-	0, // 0: pb.SimpleBank.CreateUser:input_type -> pb.CreateUserRequest
-	1, // 1: pb.SimpleBank.LoginUser:input_type -> pb.LoginUserRequest
-	2, // 2: pb.SimpleBank.CreateUser:output_type -> pb.CreateUserResponse
-	3, // 3: pb.SimpleBank.LoginUser:output_type -> pb.LoginUserResponse
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: pb.SimpleBank.CreateUser:input_type -> pb.CreateUserRequest
+	1,  // 1: pb.SimpleBank.LoginUser:input_type -> pb.LoginUserRequest
+	2,  // 2: pb.SimpleBank.CreateAccount:input_type -> pb.CreateAccountRequest
+	3,  // 3: pb.SimpleBank.GetAccount:input_type -> pb.GetAccountRequest
+	4,  // 4: pb.SimpleBank.ListAccounts:input_type -> pb.ListAccountsRequest
+	5,  // 5: pb.SimpleBank.DeleteAccount:input_type -> pb.DeleteAccountRequest
+	6,  // 6: pb.SimpleBank.TransferMoney:input_type -> pb.TransferMoneyRequest
+	7,  // 7: pb.SimpleBank.ReverseTransfer:input_type -> pb.ReverseTransferRequest
+	8,  // 8: pb.SimpleBank.Deposit:input_type -> pb.DepositRequest
+	9,  // 9: pb.SimpleBank.Withdraw:input_type -> pb.WithdrawRequest
+	10, // 10: pb.SimpleBank.ListEntries:input_type -> pb.ListEntriesRequest
+	11, // 11: pb.SimpleBank.ListTransfers:input_type -> pb.ListTransfersRequest
+	12, // 12: pb.SimpleBank.FreezeAccount:input_type -> pb.FreezeAccountRequest
+	13, // 13: pb.SimpleBank.BlockSession:input_type -> pb.BlockSessionRequest
+	14, // 14: pb.SimpleBank.RevokeToken:input_type -> pb.RevokeTokenRequest
+	15, // 15: pb.SimpleBank.ListSessions:input_type -> pb.ListSessionsRequest
+	16, // 16: pb.SimpleBank.RevokeSession:input_type -> pb.RevokeSessionRequest
+	17, // 17: pb.SimpleBank.RenewAccessToken:input_type -> pb.RenewAccessTokenRequest
+	18, // 18: pb.SimpleBank.UpdateUser:input_type -> pb.UpdateUserRequest
+	19, // 19: pb.SimpleBank.VerifyEmail:input_type -> pb.VerifyEmailRequest
+	20, // 20: pb.SimpleBank.RequestPasswordReset:input_type -> pb.RequestPasswordResetRequest
+	21, // 21: pb.SimpleBank.ResetPassword:input_type -> pb.ResetPasswordRequest
+	22, // 22: pb.SimpleBank.LoginUserMFA:input_type -> pb.LoginUserMFARequest
+	23, // 23: pb.SimpleBank.EnrollMFA:input_type -> pb.EnrollMFARequest
+	24, // 24: pb.SimpleBank.ConfirmMFA:input_type -> pb.ConfirmMFARequest
+	25, // 25: pb.SimpleBank.DisableMFA:input_type -> pb.DisableMFARequest
+	26, // 26: pb.SimpleBank.GetTokenPublicKeys:input_type -> pb.GetTokenPublicKeysRequest
+	27, // 27: pb.SimpleBank.CreateAPIKey:input_type -> pb.CreateAPIKeyRequest
+	28, // 28: pb.SimpleBank.ListAPIKeys:input_type -> pb.ListAPIKeysRequest
+	29, // 29: pb.SimpleBank.RevokeAPIKey:input_type -> pb.RevokeAPIKeyRequest
+	30, // 30: pb.SimpleBank.CreateUser:output_type -> pb.CreateUserResponse
+	31, // 31: pb.SimpleBank.LoginUser:output_type -> pb.LoginUserResponse
+	32, // 32: pb.SimpleBank.CreateAccount:output_type -> pb.CreateAccountResponse
+	33, // 33: pb.SimpleBank.GetAccount:output_type -> pb.GetAccountResponse
+	34, // 34: pb.SimpleBank.ListAccounts:output_type -> pb.ListAccountsResponse
+	35, // 35: pb.SimpleBank.DeleteAccount:output_type -> pb.DeleteAccountResponse
+	36, // 36: pb.SimpleBank.TransferMoney:output_type -> pb.TransferMoneyResponse
+	37, // 37: pb.SimpleBank.ReverseTransfer:output_type -> pb.ReverseTransferResponse
+	38, // 38: pb.SimpleBank.Deposit:output_type -> pb.DepositResponse
+	39, // 39: pb.SimpleBank.Withdraw:output_type -> pb.WithdrawResponse
+	40, // 40: pb.SimpleBank.ListEntries:output_type -> pb.ListEntriesResponse
+	41, // 41: pb.SimpleBank.ListTransfers:output_type -> pb.ListTransfersResponse
+	42, // 42: pb.SimpleBank.FreezeAccount:output_type -> pb.FreezeAccountResponse
+	43, // 43: pb.SimpleBank.BlockSession:output_type -> pb.BlockSessionResponse
+	44, // 44: pb.SimpleBank.RevokeToken:output_type -> pb.RevokeTokenResponse
+	45, // 45: pb.SimpleBank.ListSessions:output_type -> pb.ListSessionsResponse
+	46, // 46: pb.SimpleBank.RevokeSession:output_type -> pb.RevokeSessionResponse
+	47, // 47: pb.SimpleBank.RenewAccessToken:output_type -> pb.RenewAccessTokenResponse
+	48, // 48: pb.SimpleBank.UpdateUser:output_type -> pb.UpdateUserResponse
+	49, // 49: pb.SimpleBank.VerifyEmail:output_type -> pb.VerifyEmailResponse
+	50, // 50: pb.SimpleBank.RequestPasswordReset:output_type -> pb.RequestPasswordResetResponse
+	51, // 51: pb.SimpleBank.ResetPassword:output_type -> pb.ResetPasswordResponse
+	52, // 52: pb.SimpleBank.LoginUserMFA:output_type -> pb.LoginUserMFAResponse
+	53, // 53: pb.SimpleBank.EnrollMFA:output_type -> pb.EnrollMFAResponse
+	54, // 54: pb.SimpleBank.ConfirmMFA:output_type -> pb.ConfirmMFAResponse
+	55, // 55: pb.SimpleBank.DisableMFA:output_type -> pb.DisableMFAResponse
+	56, // 56: pb.SimpleBank.GetTokenPublicKeys:output_type -> pb.GetTokenPublicKeysResponse
+	57, // 57: pb.SimpleBank.CreateAPIKey:output_type -> pb.CreateAPIKeyResponse
+	58, // 58: pb.SimpleBank.ListAPIKeys:output_type -> pb.ListAPIKeysResponse
+	59, // 59: pb.SimpleBank.RevokeAPIKey:output_type -> pb.RevokeAPIKeyResponse
+	30, // [30:60] is the sub-list for method output_type
+	0,  // [0:30] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
 }
 
 func init() { file_service_simple_bank_proto_init() }
@@ -94,6 +565,34 @@ func file_service_simple_bank_proto_init() {
 	}
 	file_rpc_create_user_proto_init()
 	file_rpc_login_user_proto_init()
+	file_rpc_create_account_proto_init()
+	file_rpc_get_account_proto_init()
+	file_rpc_list_accounts_proto_init()
+	file_rpc_delete_account_proto_init()
+	file_rpc_transfer_money_proto_init()
+	file_rpc_reverse_transfer_proto_init()
+	file_rpc_deposit_proto_init()
+	file_rpc_withdraw_proto_init()
+	file_rpc_list_entries_proto_init()
+	file_rpc_list_transfers_proto_init()
+	file_rpc_freeze_account_proto_init()
+	file_rpc_block_session_proto_init()
+	file_rpc_revoke_token_proto_init()
+	file_rpc_list_sessions_proto_init()
+	file_rpc_revoke_session_proto_init()
+	file_rpc_renew_access_token_proto_init()
+	file_rpc_update_user_proto_init()
+	file_rpc_verify_email_proto_init()
+	file_rpc_request_password_reset_proto_init()
+	file_rpc_reset_password_proto_init()
+	file_rpc_login_user_mfa_proto_init()
+	file_rpc_enroll_mfa_proto_init()
+	file_rpc_confirm_mfa_proto_init()
+	file_rpc_disable_mfa_proto_init()
+	file_rpc_get_token_public_keys_proto_init()
+	file_rpc_create_api_key_proto_init()
+	file_rpc_list_api_keys_proto_init()
+	file_rpc_revoke_api_key_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
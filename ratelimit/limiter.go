@@ -0,0 +1,114 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"SimpleBankProject/db/util"
+)
+
+// the route groups which get their own limit - every route belongs to exactly one group
+const (
+	// GroupAuth holds the routes used before a user is logged in (create user, login, password reset, ...) - they are
+	// limited per client IP
+	GroupAuth = "auth"
+	// GroupTransfers holds the routes which move money (transfers, deposits, withdrawals)
+	GroupTransfers = "transfers"
+	// GroupDefault holds every other route
+	GroupDefault = "default"
+	// GroupClientIP isn't a route group - its limit counts every request of a client IP before it is authenticated, so a
+	// client sending bad access tokens or API keys is limited too, while the route groups only count the logged in user
+	GroupClientIP = "client_ip"
+)
+
+// Limit is the size and refill rate of a token bucket - a client may send Burst requests at once, after that a request
+// every 1/Rate seconds
+type Limit struct {
+	Rate  float64 // tokens added to the bucket per second
+	Burst int     // the tokens the bucket holds when it is full
+}
+
+// Enabled returns false for the zero Limit, which doesn't limit requests
+func (limit Limit) Enabled() bool {
+	return limit.Rate > 0 && limit.Burst > 0
+}
+
+// ParseLimit parses a limit written as <requests>/<unit> (e.g. "10/s", "100/m", "1000/h") - the bucket holds the requests
+// of one unit and refills over the unit - an empty string or "0" disables the limit
+func ParseLimit(limit string) (Limit, error) {
+	limit = strings.TrimSpace(limit)
+	if limit == "" || limit == "0" {
+		return Limit{}, nil
+	}
+
+	requests, unit, ok := strings.Cut(limit, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: must be <requests>/<s|m|h>", limit)
+	}
+	burst, err := strconv.Atoi(requests)
+	if err != nil || burst <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: requests must be a positive number", limit)
+	}
+
+	var period time.Duration
+	switch unit {
+	case "s":
+		period = time.Second
+	case "m":
+		period = time.Minute
+	case "h":
+		period = time.Hour
+	default:
+		return Limit{}, fmt.Errorf("invalid rate limit %q: unit must be s, m or h", limit)
+	}
+
+	return Limit{Rate: float64(burst) / period.Seconds(), Burst: burst}, nil
+}
+
+// Limits holds the limit of every route group
+type Limits map[string]Limit
+
+// NewLimits parses the limits of the route groups in the config
+func NewLimits(config util.Config) (Limits, error) {
+	limits := Limits{}
+	for group, limit := range map[string]string{
+		GroupAuth:      config.RateLimitAuth,
+		GroupTransfers: config.RateLimitTransfers,
+		GroupDefault:   config.RateLimitDefault,
+		GroupClientIP:  config.RateLimitClientIP,
+	} {
+		parsed, err := ParseLimit(limit)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", group, err)
+		}
+		limits[group] = parsed
+	}
+	return limits, nil
+}
+
+// Result is the answer of a Limiter to a request
+type Result struct {
+	Allowed    bool
+	Remaining  int           // the requests the client may still send right away
+	RetryAfter time.Duration // how long a client which isn't allowed must wait for the next request
+}
+
+// Limiter counts the requests of clients with a token bucket per key - implementations must be safe for concurrent use
+// MemoryLimiter keeps the buckets in memory, a shared backend (e.g. Redis) would let several servers share the limits
+type Limiter interface {
+	// Allow takes a token from the bucket of the key and returns whether there was one
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// UserKey returns the key of the bucket of a logged in user in the route group
+func UserKey(group string, username string) string {
+	return group + ":user:" + username
+}
+
+// ClientIPKey returns the key of the bucket of a client which isn't logged in in the route group
+func ClientIPKey(group string, clientIP string) string {
+	return group + ":ip:" + clientIP
+}
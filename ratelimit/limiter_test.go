@@ -0,0 +1,59 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"SimpleBankProject/db/util"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLimit(t *testing.T) {
+	testCases := []struct {
+		limit    string
+		expected Limit
+		valid    bool
+	}{
+		{limit: "10/s", expected: Limit{Rate: 10, Burst: 10}, valid: true},
+		{limit: "120/m", expected: Limit{Rate: 2, Burst: 120}, valid: true},
+		{limit: "3600/h", expected: Limit{Rate: 1, Burst: 3600}, valid: true},
+		{limit: " 60/m ", expected: Limit{Rate: 1, Burst: 60}, valid: true},
+		{limit: "", expected: Limit{}, valid: true},
+		{limit: "0", expected: Limit{}, valid: true},
+		{limit: "10", valid: false},
+		{limit: "10/d", valid: false},
+		{limit: "-1/s", valid: false},
+		{limit: "0/s", valid: false},
+		{limit: "ten/s", valid: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.limit, func(t *testing.T) {
+			limit, err := ParseLimit(tc.limit)
+			if !tc.valid {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, limit)
+			require.Equal(t, tc.limit != "" && tc.limit != "0", limit.Enabled())
+		})
+	}
+}
+
+func TestNewLimits(t *testing.T) {
+	limits, err := NewLimits(util.Config{
+		RateLimitAuth:      "5/m",
+		RateLimitTransfers: "1/s",
+		RateLimitClientIP:  "100/s",
+	})
+	require.NoError(t, err)
+	require.Equal(t, Limit{Rate: 5 / time.Minute.Seconds(), Burst: 5}, limits[GroupAuth])
+	require.Equal(t, Limit{Rate: 1, Burst: 1}, limits[GroupTransfers])
+	require.False(t, limits[GroupDefault].Enabled())
+	require.Equal(t, Limit{Rate: 100, Burst: 100}, limits[GroupClientIP])
+
+	_, err = NewLimits(util.Config{RateLimitDefault: "fast"})
+	require.Error(t, err)
+}
@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often MemoryLimiter removes the buckets which have refilled - a full bucket is the same as no bucket
+const sweepInterval = time.Minute
+
+// bucket is the token bucket of one key
+type bucket struct {
+	tokens    float64   // the tokens left at updatedAt
+	updatedAt time.Time // when the tokens were last refilled
+	limit     Limit
+}
+
+// refill adds the tokens earned since the bucket was last updated
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.updatedAt).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(float64(b.limit.Burst), b.tokens+elapsed*b.limit.Rate)
+		b.updatedAt = now
+	}
+}
+
+// MemoryLimiter keeps the token buckets in memory - the limits are counted per process, so servers running side by side
+// each allow the full rate
+type MemoryLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time // replaced in tests
+}
+
+// NewMemoryLimiter creates a MemoryLimiter without any buckets
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Allow takes a token from the bucket of the key - a key without a bucket starts with a full one
+func (limiter *MemoryLimiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	if !limit.Enabled() {
+		return Result{Allowed: true, Remaining: math.MaxInt}, nil
+	}
+
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	now := limiter.now()
+	limiter.sweep(now)
+
+	b, ok := limiter.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updatedAt: now}
+		limiter.buckets[key] = b
+	}
+	// a changed config applies to the existing buckets as well
+	b.limit = limit
+	b.refill(now)
+
+	if b.tokens < 1 {
+		retryAfter := time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+		return Result{Allowed: false, RetryAfter: retryAfter}, nil
+	}
+
+	b.tokens--
+	return Result{Allowed: true, Remaining: int(b.tokens)}, nil
+}
+
+// sweep removes the buckets which have refilled since they were last used, so clients which stopped sending requests
+// don't keep using memory - the caller must hold the lock
+func (limiter *MemoryLimiter) sweep(now time.Time) {
+	if now.Sub(limiter.lastSweep) < sweepInterval {
+		return
+	}
+	limiter.lastSweep = now
+
+	for key, b := range limiter.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.limit.Burst) {
+			delete(limiter.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestLimiter returns a MemoryLimiter whose clock only moves when the returned function is called
+func newTestLimiter() (*MemoryLimiter, func(time.Duration)) {
+	limiter := NewMemoryLimiter()
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+	return limiter, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestMemoryLimiter(t *testing.T) {
+	limiter, advance := newTestLimiter()
+	limit := Limit{Rate: 1, Burst: 3}
+	ctx := context.Background()
+
+	// a new client may send the whole burst at once
+	for i := 2; i >= 0; i-- {
+		result, err := limiter.Allow(ctx, "key", limit)
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+		require.Equal(t, i, result.Remaining)
+	}
+
+	result, err := limiter.Allow(ctx, "key", limit)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Equal(t, time.Second, result.RetryAfter)
+
+	// other keys have their own bucket
+	result, err = limiter.Allow(ctx, "other", limit)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+
+	// the bucket refills at the rate of the limit
+	advance(500 * time.Millisecond)
+	result, err = limiter.Allow(ctx, "key", limit)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Equal(t, 500*time.Millisecond, result.RetryAfter)
+
+	advance(500 * time.Millisecond)
+	result, err = limiter.Allow(ctx, "key", limit)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+
+	// the bucket never holds more than the burst
+	advance(time.Hour)
+	for i := 0; i < 3; i++ {
+		result, err = limiter.Allow(ctx, "key", limit)
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+	}
+	result, err = limiter.Allow(ctx, "key", limit)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+}
+
+func TestMemoryLimiterDisabled(t *testing.T) {
+	limiter, _ := newTestLimiter()
+
+	for i := 0; i < 100; i++ {
+		result, err := limiter.Allow(context.Background(), "key", Limit{})
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+	}
+	require.Empty(t, limiter.buckets)
+}
+
+func TestMemoryLimiterSweep(t *testing.T) {
+	limiter, advance := newTestLimiter()
+	limit := Limit{Rate: 1, Burst: 10}
+	ctx := context.Background()
+
+	_, err := limiter.Allow(ctx, "idle", limit)
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		_, err = limiter.Allow(ctx, "busy", Limit{Rate: 0.01, Burst: 10})
+		require.NoError(t, err)
+	}
+	require.Len(t, limiter.buckets, 2)
+
+	// the idle bucket has refilled by the next sweep, the busy one hasn't
+	advance(sweepInterval)
+	_, err = limiter.Allow(ctx, "new", limit)
+	require.NoError(t, err)
+	require.Contains(t, limiter.buckets, "busy")
+	require.Contains(t, limiter.buckets, "new")
+	require.NotContains(t, limiter.buckets, "idle")
+}
@@ -0,0 +1,165 @@
+package token
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Denylist holds the tokens which were revoked before they expired - tokens are stateless, so VerifyToken still accepts a
+// revoked token and every place that authenticates a request must also check the denylist
+type Denylist interface {
+	// Deny revokes the token of the payload until it expires
+	Deny(ctx context.Context, payload *Payload) error
+	// IsDenied returns true if the token of the payload has been revoked
+	IsDenied(ctx context.Context, payload *Payload) (bool, error)
+}
+
+// CheckDenylist returns ErrRevokedToken if the token of the payload has been revoked
+func CheckDenylist(ctx context.Context, denylist Denylist, payload *Payload) error {
+	denied, err := denylist.IsDenied(ctx, payload)
+	if err != nil {
+		return err
+	}
+	if denied {
+		return ErrRevokedToken
+	}
+	return nil
+}
+
+// memoryPruneInterval is how often MemoryDenylist removes the tokens which have expired
+const memoryPruneInterval = time.Minute
+
+// MemoryDenylist is a Denylist held in the memory of a single process - it is used as the cache of CachedDenylist and in
+// tests, revoked tokens are lost when the process stops
+type MemoryDenylist struct {
+	mu        sync.Mutex
+	expiresAt map[uuid.UUID]time.Time // payload id -> when the token expires
+	lastPrune time.Time
+}
+
+// NewMemoryDenylist creates an empty MemoryDenylist
+func NewMemoryDenylist() *MemoryDenylist {
+	return &MemoryDenylist{
+		expiresAt: make(map[uuid.UUID]time.Time),
+		lastPrune: time.Now(),
+	}
+}
+
+// Deny revokes the token of the payload until it expires
+func (denylist *MemoryDenylist) Deny(ctx context.Context, payload *Payload) error {
+	denylist.mu.Lock()
+	defer denylist.mu.Unlock()
+
+	denylist.expiresAt[payload.ID] = payload.ExpiredAt
+	denylist.prune()
+	return nil
+}
+
+// IsDenied returns true if the token of the payload has been revoked
+func (denylist *MemoryDenylist) IsDenied(ctx context.Context, payload *Payload) (bool, error) {
+	denylist.mu.Lock()
+	defer denylist.mu.Unlock()
+
+	_, ok := denylist.expiresAt[payload.ID]
+	return ok, nil
+}
+
+// prune removes the tokens which have expired - the token maker already rejects them - it must be called with the lock held
+func (denylist *MemoryDenylist) prune() {
+	now := time.Now()
+	if now.Sub(denylist.lastPrune) < memoryPruneInterval {
+		return
+	}
+
+	for id, expiresAt := range denylist.expiresAt {
+		if now.After(expiresAt) {
+			delete(denylist.expiresAt, id)
+		}
+	}
+	denylist.lastPrune = now
+}
+
+// CachedDenylist puts an in-process cache in front of another (shared) Denylist to save a round trip per request
+// tokens revoked through this CachedDenylist are denied right away, while tokens revoked through another process are denied
+// once the cached "not revoked" answer is older than the ttl
+type CachedDenylist struct {
+	next   Denylist
+	ttl    time.Duration
+	denied *MemoryDenylist
+
+	mu        sync.Mutex
+	allowed   map[uuid.UUID]time.Time // payload id -> when the "not revoked" answer of next must be checked again
+	lastPrune time.Time
+}
+
+// NewCachedDenylist creates a CachedDenylist which caches the answers of next for ttl
+func NewCachedDenylist(next Denylist, ttl time.Duration) *CachedDenylist {
+	return &CachedDenylist{
+		next:      next,
+		ttl:       ttl,
+		denied:    NewMemoryDenylist(),
+		allowed:   make(map[uuid.UUID]time.Time),
+		lastPrune: time.Now(),
+	}
+}
+
+// Deny revokes the token of the payload in the shared denylist and the cache
+func (denylist *CachedDenylist) Deny(ctx context.Context, payload *Payload) error {
+	if err := denylist.next.Deny(ctx, payload); err != nil {
+		return err
+	}
+
+	denylist.mu.Lock()
+	delete(denylist.allowed, payload.ID)
+	denylist.mu.Unlock()
+
+	return denylist.denied.Deny(ctx, payload)
+}
+
+// IsDenied returns the cached answer if there is one and asks the shared denylist otherwise
+func (denylist *CachedDenylist) IsDenied(ctx context.Context, payload *Payload) (bool, error) {
+	// a revoked token stays revoked, so a cached denial never goes stale
+	if denied, _ := denylist.denied.IsDenied(ctx, payload); denied {
+		return true, nil
+	}
+
+	now := time.Now()
+	denylist.mu.Lock()
+	recheckAt, ok := denylist.allowed[payload.ID]
+	denylist.mu.Unlock()
+	if ok && now.Before(recheckAt) {
+		return false, nil
+	}
+
+	denied, err := denylist.next.IsDenied(ctx, payload)
+	if err != nil {
+		return false, err
+	}
+
+	if denied {
+		return true, denylist.denied.Deny(ctx, payload)
+	}
+
+	denylist.mu.Lock()
+	denylist.allowed[payload.ID] = now.Add(denylist.ttl)
+	denylist.pruneAllowed(now)
+	denylist.mu.Unlock()
+	return false, nil
+}
+
+// pruneAllowed removes the cached answers which must be checked again - it must be called with the lock held
+func (denylist *CachedDenylist) pruneAllowed(now time.Time) {
+	if now.Sub(denylist.lastPrune) < memoryPruneInterval {
+		return
+	}
+
+	for id, recheckAt := range denylist.allowed {
+		if now.After(recheckAt) {
+			delete(denylist.allowed, id)
+		}
+	}
+	denylist.lastPrune = now
+}
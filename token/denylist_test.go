@@ -0,0 +1,121 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"SimpleBankProject/db/util"
+
+	"github.com/stretchr/testify/require"
+)
+
+// countingDenylist is the shared denylist behind a CachedDenylist in the tests - it counts the questions it is asked
+type countingDenylist struct {
+	*MemoryDenylist
+	calls int
+	err   error
+}
+
+func (denylist *countingDenylist) IsDenied(ctx context.Context, payload *Payload) (bool, error) {
+	denylist.calls++
+	if denylist.err != nil {
+		return false, denylist.err
+	}
+	return denylist.MemoryDenylist.IsDenied(ctx, payload)
+}
+
+func randomPayload(t *testing.T) *Payload {
+	payload, err := NewPayload(util.RandomOwner(), util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+	return payload
+}
+
+func TestMemoryDenylist(t *testing.T) {
+	denylist := NewMemoryDenylist()
+	payload1 := randomPayload(t)
+	payload2 := randomPayload(t)
+
+	require.NoError(t, CheckDenylist(context.Background(), denylist, payload1))
+
+	require.NoError(t, denylist.Deny(context.Background(), payload1))
+	// denying a token twice is not an error
+	require.NoError(t, denylist.Deny(context.Background(), payload1))
+
+	err := CheckDenylist(context.Background(), denylist, payload1)
+	require.ErrorIs(t, err, ErrRevokedToken)
+	// other tokens of the same user are not affected
+	require.NoError(t, CheckDenylist(context.Background(), denylist, payload2))
+}
+
+func TestMemoryDenylistPrune(t *testing.T) {
+	denylist := NewMemoryDenylist()
+	expired := randomPayload(t)
+	expired.ExpiredAt = time.Now().Add(-time.Minute)
+	require.NoError(t, denylist.Deny(context.Background(), expired))
+
+	// the next Deny after the prune interval removes the expired token
+	denylist.lastPrune = time.Now().Add(-memoryPruneInterval)
+	require.NoError(t, denylist.Deny(context.Background(), randomPayload(t)))
+
+	denied, err := denylist.IsDenied(context.Background(), expired)
+	require.NoError(t, err)
+	require.False(t, denied)
+	require.Len(t, denylist.expiresAt, 1)
+}
+
+func TestCachedDenylist(t *testing.T) {
+	next := &countingDenylist{MemoryDenylist: NewMemoryDenylist()}
+	denylist := NewCachedDenylist(next, time.Minute)
+	payload := randomPayload(t)
+
+	// the "not revoked" answer is cached
+	require.NoError(t, CheckDenylist(context.Background(), denylist, payload))
+	require.NoError(t, CheckDenylist(context.Background(), denylist, payload))
+	require.Equal(t, 1, next.calls)
+
+	// a token revoked through the cache is denied right away and stored in the shared denylist
+	require.NoError(t, denylist.Deny(context.Background(), payload))
+	require.ErrorIs(t, CheckDenylist(context.Background(), denylist, payload), ErrRevokedToken)
+	require.Equal(t, 1, next.calls)
+
+	denied, err := next.MemoryDenylist.IsDenied(context.Background(), payload)
+	require.NoError(t, err)
+	require.True(t, denied)
+}
+
+func TestCachedDenylistRevokedElsewhere(t *testing.T) {
+	next := &countingDenylist{MemoryDenylist: NewMemoryDenylist()}
+	denylist := NewCachedDenylist(next, time.Minute)
+	payload := randomPayload(t)
+
+	require.NoError(t, CheckDenylist(context.Background(), denylist, payload))
+
+	// another process revokes the token - the cached answer is used until it is older than the ttl
+	require.NoError(t, next.Deny(context.Background(), payload))
+	require.NoError(t, CheckDenylist(context.Background(), denylist, payload))
+
+	denylist.allowed[payload.ID] = time.Now().Add(-time.Second)
+	require.ErrorIs(t, CheckDenylist(context.Background(), denylist, payload), ErrRevokedToken)
+	require.Equal(t, 2, next.calls)
+
+	// the denial is cached for good
+	require.ErrorIs(t, CheckDenylist(context.Background(), denylist, payload), ErrRevokedToken)
+	require.Equal(t, 2, next.calls)
+}
+
+func TestCachedDenylistError(t *testing.T) {
+	next := &countingDenylist{MemoryDenylist: NewMemoryDenylist(), err: errors.New("connection refused")}
+	denylist := NewCachedDenylist(next, time.Minute)
+	payload := randomPayload(t)
+
+	// errors are returned and never cached
+	err := CheckDenylist(context.Background(), denylist, payload)
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrRevokedToken)
+
+	next.err = nil
+	require.NoError(t, CheckDenylist(context.Background(), denylist, payload))
+	require.Equal(t, 2, next.calls)
+}
@@ -27,12 +27,43 @@ func NewJWTMaker(secretKey string) (Maker, error) {
 	return &JWTMaker{secretKey}, nil
 }
 
-// CreateToken method will create a token for a specific username and duration
-func (maker *JWTMaker) CreateToken(username string, duration time.Duration) (string, *Payload, error) {
-	payload, err := NewPayload(username, duration)
+// CreateToken method will create a token for a specific username, role and duration
+func (maker *JWTMaker) CreateToken(username string, role string, duration time.Duration) (string, *Payload, error) {
+	return maker.createToken(username, role, "", duration)
+}
+
+// VerifyToken method will confirm the provided token is valid or not
+func (maker *JWTMaker) VerifyToken(token string) (*Payload, error) {
+	return maker.verifyToken(token, "")
+}
+
+// CreateRefreshToken creates a token which can only be verified by VerifyRefreshToken
+func (maker *JWTMaker) CreateRefreshToken(username string, role string, duration time.Duration) (string, *Payload, error) {
+	return maker.createToken(username, role, PurposeRefresh, duration)
+}
+
+// VerifyRefreshToken checks if the token is a valid refresh token
+func (maker *JWTMaker) VerifyRefreshToken(token string) (*Payload, error) {
+	return maker.verifyToken(token, PurposeRefresh)
+}
+
+// CreateMFAChallengeToken creates a token which can only be verified by VerifyMFAChallengeToken
+func (maker *JWTMaker) CreateMFAChallengeToken(username string, role string, duration time.Duration) (string, *Payload, error) {
+	return maker.createToken(username, role, PurposeMFAChallenge, duration)
+}
+
+// VerifyMFAChallengeToken method will confirm the provided token is a valid MFA challenge token
+func (maker *JWTMaker) VerifyMFAChallengeToken(token string) (*Payload, error) {
+	return maker.verifyToken(token, PurposeMFAChallenge)
+}
+
+// createToken method will create a token for a specific username, role, purpose and duration
+func (maker *JWTMaker) createToken(username string, role string, purpose string, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, duration)
 	if err != nil {
 		return "", payload, err
 	}
+	payload.Purpose = purpose
 
 	// creating a new JWT
 	// jwt.NewWithClaims takes in the signing method and claims, which is our payload
@@ -42,8 +73,8 @@ func (maker *JWTMaker) CreateToken(username string, duration time.Duration) (str
 	return signedJWTToken, payload, err
 }
 
-// VerifyToken method will confirm the provided token is valid or not
-func (maker *JWTMaker) VerifyToken(token string) (*Payload, error) {
+// verifyToken method will confirm the provided token is valid and was created for the purpose
+func (maker *JWTMaker) verifyToken(token string, purpose string) (*Payload, error) {
 	// need to verify header to make sure the signing algorithm matches what is normally used to sign tokens
 	// if it matches, you return the key so JWT can verify the token
 	keyFunc := func(token *jwt.Token) (interface{}, error) {
@@ -82,5 +113,10 @@ func (maker *JWTMaker) VerifyToken(token string) (*Payload, error) {
 		// something must be wrong with the token - hence invalid token
 		return nil, ErrInvalidToken
 	}
+
+	err = payload.checkPurpose(purpose)
+	if err != nil {
+		return nil, err
+	}
 	return payload, nil
 }
@@ -1,13 +1,50 @@
 package token
 
-import "time"
+import (
+	"fmt"
+	"time"
+
+	"SimpleBankProject/db/util"
+)
+
+// the token makers util.Config.TokenMaker can select
+const (
+	PasetoMakerType       = "paseto"        // PasetoMaker - the default
+	PasetoPublicMakerType = "paseto_public" // PasetoPublicMaker
+	JWTMakerType          = "jwt"           // JWTMaker
+)
 
 // Maker is an interface to manage the creation and verification of tokens
 // we will implement both a JWT struct and a PASETO struct to implement this interface and easily switch between the two
 type Maker interface {
-	// CreateToken creates a new token for a specific username and duration
-	CreateToken(username string, duration time.Duration) (string, *Payload, error)
+	// CreateToken creates a new token for a specific username, role and duration
+	CreateToken(username string, role string, duration time.Duration) (string, *Payload, error)
 	// VerifyToken will confirm if the token is valid or not
 	// if valid, VerifyToken will return the payload data of the token
 	VerifyToken(token string) (*Payload, error)
+	// CreateRefreshToken creates the token a session is renewed with - it can only be exchanged for a new access token,
+	// VerifyToken rejects it, so a refresh token can't be used as a bearer token
+	CreateRefreshToken(username string, role string, duration time.Duration) (string, *Payload, error)
+	// VerifyRefreshToken returns the payload of a token created by CreateRefreshToken, any other token is invalid
+	VerifyRefreshToken(token string) (*Payload, error)
+	// CreateMFAChallengeToken creates the token a user with two-factor authentication receives after their password was
+	// checked - it can only be exchanged for an access token together with a TOTP code, VerifyToken rejects it
+	CreateMFAChallengeToken(username string, role string, duration time.Duration) (string, *Payload, error)
+	// VerifyMFAChallengeToken returns the payload of a token created by CreateMFAChallengeToken, any other token is invalid
+	VerifyMFAChallengeToken(token string) (*Payload, error)
+}
+
+// NewMaker creates the token maker selected by the config - the symmetric makers use TokenSymmetricKey, PasetoPublicMaker uses
+// TokenPrivateKey and TokenVerificationKeys
+func NewMaker(config util.Config) (Maker, error) {
+	switch config.TokenMaker {
+	case PasetoMakerType, "":
+		return NewPasetoMaker(config.TokenSymmetricKey)
+	case PasetoPublicMakerType:
+		return NewPasetoPublicMaker(config.TokenPrivateKey, ParseVerificationKeys(config.TokenVerificationKeys))
+	case JWTMakerType:
+		return NewJWTMaker(config.TokenSymmetricKey)
+	default:
+		return nil, fmt.Errorf("unsupported token maker: %s", config.TokenMaker)
+	}
 }
@@ -0,0 +1,41 @@
+package token
+
+import (
+	"testing"
+
+	"SimpleBankProject/db/util"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMaker(t *testing.T) {
+	privateKey, _ := randomKey(t)
+	config := util.Config{
+		TokenSymmetricKey: util.RandomString(32),
+		TokenPrivateKey:   privateKey,
+	}
+
+	// the PASETO maker is the default
+	maker, err := NewMaker(config)
+	require.NoError(t, err)
+	require.IsType(t, &PasetoMaker{}, maker)
+
+	config.TokenMaker = PasetoMakerType
+	maker, err = NewMaker(config)
+	require.NoError(t, err)
+	require.IsType(t, &PasetoMaker{}, maker)
+
+	config.TokenMaker = PasetoPublicMakerType
+	maker, err = NewMaker(config)
+	require.NoError(t, err)
+	require.IsType(t, &PasetoPublicMaker{}, maker)
+
+	config.TokenMaker = JWTMakerType
+	maker, err = NewMaker(config)
+	require.NoError(t, err)
+	require.IsType(t, &JWTMaker{}, maker)
+
+	config.TokenMaker = "unknown"
+	_, err = NewMaker(config)
+	require.Error(t, err)
+}
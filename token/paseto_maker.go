@@ -32,20 +32,51 @@ func NewPasetoMaker(symmetricKey string) (Maker, error) {
 	return maker, nil
 }
 
-// CreateToken creates a new token for a specific username and duration
-func (maker *PasetoMaker) CreateToken(username string, duration time.Duration) (string, *Payload, error) {
-	payload, err := NewPayload(username, duration)
+// CreateToken creates a new token for a specific username, role and duration
+func (maker *PasetoMaker) CreateToken(username string, role string, duration time.Duration) (string, *Payload, error) {
+	return maker.createToken(username, role, "", duration)
+}
+
+// VerifyToken checks if the token is valid or not
+func (maker *PasetoMaker) VerifyToken(token string) (*Payload, error) {
+	return maker.verifyToken(token, "")
+}
+
+// CreateRefreshToken creates a token which can only be verified by VerifyRefreshToken
+func (maker *PasetoMaker) CreateRefreshToken(username string, role string, duration time.Duration) (string, *Payload, error) {
+	return maker.createToken(username, role, PurposeRefresh, duration)
+}
+
+// VerifyRefreshToken checks if the token is a valid refresh token
+func (maker *PasetoMaker) VerifyRefreshToken(token string) (*Payload, error) {
+	return maker.verifyToken(token, PurposeRefresh)
+}
+
+// CreateMFAChallengeToken creates a token which can only be verified by VerifyMFAChallengeToken
+func (maker *PasetoMaker) CreateMFAChallengeToken(username string, role string, duration time.Duration) (string, *Payload, error) {
+	return maker.createToken(username, role, PurposeMFAChallenge, duration)
+}
+
+// VerifyMFAChallengeToken checks if the token is a valid MFA challenge token
+func (maker *PasetoMaker) VerifyMFAChallengeToken(token string) (*Payload, error) {
+	return maker.verifyToken(token, PurposeMFAChallenge)
+}
+
+// createToken creates a new token for a specific username, role, purpose and duration
+func (maker *PasetoMaker) createToken(username string, role string, purpose string, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, duration)
 	if err != nil {
 		return "", payload, err
 	}
+	payload.Purpose = purpose
 
 	// encrypt the payload using the symmetricKey, the payload, and an optional footer (we use nil)
 	token, err := maker.paseto.Encrypt(maker.symmetricKey, payload, nil)
 	return token, payload, err
 }
 
-// VerifyToken checks if the token is valid or not
-func (maker *PasetoMaker) VerifyToken(token string) (*Payload, error) {
+// verifyToken checks if the token is valid and was created for the purpose
+func (maker *PasetoMaker) verifyToken(token string, purpose string) (*Payload, error) {
 	payload := &Payload{}
 
 	err := maker.paseto.Decrypt(token, maker.symmetricKey, payload, nil)
@@ -59,5 +90,10 @@ func (maker *PasetoMaker) VerifyToken(token string) (*Payload, error) {
 		return nil, err
 	}
 
+	err = payload.checkPurpose(purpose)
+	if err != nil {
+		return nil, err
+	}
+
 	return payload, nil
 }
@@ -16,6 +16,7 @@ func TestPasetoMaker(t *testing.T) {
 
 	// create a random username for the token
 	username := util.RandomOwner()
+	role := util.DepositorRole
 	// duration will be one minute
 	duration := time.Minute
 
@@ -23,7 +24,7 @@ func TestPasetoMaker(t *testing.T) {
 	expiredAt := issuedAt.Add(duration)
 
 	// create token
-	token, payload, err := maker.CreateToken(username, duration)
+	token, payload, err := maker.CreateToken(username, role, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotEmpty(t, payload)
@@ -34,6 +35,7 @@ func TestPasetoMaker(t *testing.T) {
 	require.NotEmpty(t, payload)
 	require.NotZero(t, payload.ID)
 	require.Equal(t, username, payload.Username)
+	require.Equal(t, role, payload.Role)
 	// both issuedAt and expiredAt should be within one second of the times reported in the payload
 	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
 	require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
@@ -44,7 +46,7 @@ func TestExpiredPasetoToken(t *testing.T) {
 	require.NoError(t, err)
 
 	// we create an expired token using a negative duration with CreateToken method
-	token, payload, err := maker.CreateToken(util.RandomOwner(), -time.Minute)
+	token, payload, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, -time.Minute)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotEmpty(t, payload)
@@ -55,3 +57,63 @@ func TestExpiredPasetoToken(t *testing.T) {
 	require.EqualError(t, err, ErrExpiredToken.Error())
 	require.Nil(t, payload)
 }
+
+func TestPasetoMFAChallengeToken(t *testing.T) {
+	maker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	username := util.RandomOwner()
+	challengeToken, payload, err := maker.CreateMFAChallengeToken(username, util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, PurposeMFAChallenge, payload.Purpose)
+
+	payload, err = maker.VerifyMFAChallengeToken(challengeToken)
+	require.NoError(t, err)
+	require.Equal(t, username, payload.Username)
+	require.Equal(t, PurposeMFAChallenge, payload.Purpose)
+
+	// the challenge token can't be used as an access token
+	payload, err = maker.VerifyToken(challengeToken)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+
+	// and an access token can't be used as a challenge token
+	accessToken, _, err := maker.CreateToken(username, util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+	payload, err = maker.VerifyMFAChallengeToken(accessToken)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestPasetoRefreshToken(t *testing.T) {
+	maker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	username := util.RandomOwner()
+	refreshToken, payload, err := maker.CreateRefreshToken(username, util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, PurposeRefresh, payload.Purpose)
+
+	payload, err = maker.VerifyRefreshToken(refreshToken)
+	require.NoError(t, err)
+	require.Equal(t, username, payload.Username)
+	require.Equal(t, PurposeRefresh, payload.Purpose)
+
+	// the refresh token can't be used as an access token
+	payload, err = maker.VerifyToken(refreshToken)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+
+	// and neither an access token nor a challenge token can be used as a refresh token
+	accessToken, _, err := maker.CreateToken(username, util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+	payload, err = maker.VerifyRefreshToken(accessToken)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+
+	challengeToken, _, err := maker.CreateMFAChallengeToken(username, util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+	payload, err = maker.VerifyRefreshToken(challengeToken)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
@@ -0,0 +1,185 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/o1egl/paseto"
+)
+
+// PublicKeyVersion is the PASETO version and purpose of the tokens signed by PasetoPublicMaker
+const PublicKeyVersion = "v2.public"
+
+// PublicKey is a key which verifies the tokens of a PasetoPublicMaker - it is safe to share, it can't sign tokens
+type PublicKey struct {
+	KeyID     string            // the key id in the footer of the tokens signed by the matching private key
+	PublicKey ed25519.PublicKey // the Ed25519 public key
+}
+
+// PublicKeyMaker is a Maker which signs tokens with a private key - other services can verify its tokens offline with the
+// public keys, without being able to create tokens themselves
+type PublicKeyMaker interface {
+	Maker
+	// PublicKeys returns every key the maker accepts tokens from - the key of the private key first
+	PublicKeys() []PublicKey
+}
+
+// publicFooter is the footer of the tokens signed by PasetoPublicMaker - it isn't encrypted, but it is signed together with
+// the payload, so it can't be changed
+type publicFooter struct {
+	KeyID string `json:"kid"`
+}
+
+// PasetoPublicMaker is a PASETO token maker which signs tokens with an Ed25519 private key (v2.public) - unlike PasetoMaker,
+// the payload isn't encrypted, so tokens must not carry secrets
+type PasetoPublicMaker struct {
+	paseto     *paseto.V2
+	keyID      string // the key id of the private key, sent in the footer of every token
+	privateKey ed25519.PrivateKey
+	// the keys tokens can be verified with by key id - keeping the public keys of earlier private keys allows rotating the
+	// private key without invalidating the tokens it already signed
+	publicKeys map[string]ed25519.PublicKey
+	// the public keys in the order PublicKeys returns them
+	keys []PublicKey
+}
+
+// NewPasetoPublicMaker creates a new PasetoPublicMaker - privateKey is the hex encoded 32 byte seed of the Ed25519 key tokens
+// are signed with, verificationKeys are the hex encoded public keys of earlier private keys whose tokens are still accepted
+func NewPasetoPublicMaker(privateKey string, verificationKeys []string) (Maker, error) {
+	seed, err := hex.DecodeString(privateKey)
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("invalid private key: must be %d hex encoded bytes", ed25519.SeedSize)
+	}
+
+	maker := &PasetoPublicMaker{
+		paseto:     paseto.NewV2(),
+		privateKey: ed25519.NewKeyFromSeed(seed),
+		publicKeys: make(map[string]ed25519.PublicKey),
+	}
+	maker.keyID = maker.addPublicKey(maker.privateKey.Public().(ed25519.PublicKey))
+
+	for _, verificationKey := range verificationKeys {
+		publicKey, err := hex.DecodeString(verificationKey)
+		if err != nil || len(publicKey) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid verification key %q: must be %d hex encoded bytes", verificationKey, ed25519.PublicKeySize)
+		}
+		maker.addPublicKey(publicKey)
+	}
+
+	return maker, nil
+}
+
+// addPublicKey lets the maker verify the tokens of the key and returns its key id - adding a key twice does nothing
+func (maker *PasetoPublicMaker) addPublicKey(publicKey ed25519.PublicKey) string {
+	keyID := KeyID(publicKey)
+	if _, ok := maker.publicKeys[keyID]; !ok {
+		maker.publicKeys[keyID] = publicKey
+		maker.keys = append(maker.keys, PublicKey{KeyID: keyID, PublicKey: publicKey})
+	}
+	return keyID
+}
+
+// KeyID returns the key id of an Ed25519 public key - the first 8 bytes of its SHA-256 hash, so every service derives the
+// same id from the key without it being configured
+func KeyID(publicKey ed25519.PublicKey) string {
+	hash := sha256.Sum256(publicKey)
+	return hex.EncodeToString(hash[:8])
+}
+
+// ParseVerificationKeys splits a comma separated list of hex encoded public keys, as TOKEN_VERIFICATION_KEYS holds them
+func ParseVerificationKeys(keys string) []string {
+	var verificationKeys []string
+	for _, key := range strings.Split(keys, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			verificationKeys = append(verificationKeys, key)
+		}
+	}
+	return verificationKeys
+}
+
+// PublicKeys returns every key the maker accepts tokens from - the key of the private key first
+func (maker *PasetoPublicMaker) PublicKeys() []PublicKey {
+	keys := make([]PublicKey, len(maker.keys))
+	copy(keys, maker.keys)
+	return keys
+}
+
+// CreateToken creates a new token for a specific username, role and duration
+func (maker *PasetoPublicMaker) CreateToken(username string, role string, duration time.Duration) (string, *Payload, error) {
+	return maker.createToken(username, role, "", duration)
+}
+
+// VerifyToken checks if the token is valid or not
+func (maker *PasetoPublicMaker) VerifyToken(token string) (*Payload, error) {
+	return maker.verifyToken(token, "")
+}
+
+// CreateRefreshToken creates a token which can only be verified by VerifyRefreshToken
+func (maker *PasetoPublicMaker) CreateRefreshToken(username string, role string, duration time.Duration) (string, *Payload, error) {
+	return maker.createToken(username, role, PurposeRefresh, duration)
+}
+
+// VerifyRefreshToken checks if the token is a valid refresh token
+func (maker *PasetoPublicMaker) VerifyRefreshToken(token string) (*Payload, error) {
+	return maker.verifyToken(token, PurposeRefresh)
+}
+
+// CreateMFAChallengeToken creates a token which can only be verified by VerifyMFAChallengeToken
+func (maker *PasetoPublicMaker) CreateMFAChallengeToken(username string, role string, duration time.Duration) (string, *Payload, error) {
+	return maker.createToken(username, role, PurposeMFAChallenge, duration)
+}
+
+// VerifyMFAChallengeToken checks if the token is a valid MFA challenge token
+func (maker *PasetoPublicMaker) VerifyMFAChallengeToken(token string) (*Payload, error) {
+	return maker.verifyToken(token, PurposeMFAChallenge)
+}
+
+// createToken creates a new token for a specific username, role, purpose and duration
+func (maker *PasetoPublicMaker) createToken(username string, role string, purpose string, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, duration)
+	if err != nil {
+		return "", payload, err
+	}
+	payload.Purpose = purpose
+
+	// sign the payload with the private key - the footer tells the verifier which public key to use
+	token, err := maker.paseto.Sign(maker.privateKey, payload, publicFooter{KeyID: maker.keyID})
+	return token, payload, err
+}
+
+// verifyToken checks if the token is valid and was created for the purpose
+func (maker *PasetoPublicMaker) verifyToken(token string, purpose string) (*Payload, error) {
+	// the footer is read before the signature is checked to find the public key - Verify checks it wasn't changed
+	footer := publicFooter{}
+	err := paseto.ParseFooter(token, &footer)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	publicKey, ok := maker.publicKeys[footer.KeyID]
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	payload := &Payload{}
+	err = maker.paseto.Verify(token, publicKey, payload, nil)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	// check if payload is valid
+	err = payload.Valid()
+	if err != nil {
+		return nil, err
+	}
+
+	err = payload.checkPurpose(purpose)
+	if err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
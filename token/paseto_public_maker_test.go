@@ -0,0 +1,215 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"SimpleBankProject/db/util"
+
+	"github.com/stretchr/testify/require"
+)
+
+// randomKey generates an Ed25519 key and returns the hex encoded seed and public key, as the config holds them
+func randomKey(t *testing.T) (string, string) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return hex.EncodeToString(privateKey.Seed()), hex.EncodeToString(publicKey)
+}
+
+func TestPasetoPublicMaker(t *testing.T) {
+	privateKey, _ := randomKey(t)
+	maker, err := NewPasetoPublicMaker(privateKey, nil)
+	require.NoError(t, err)
+
+	username := util.RandomOwner()
+	role := util.DepositorRole
+	duration := time.Minute
+
+	issuedAt := time.Now()
+	expiredAt := issuedAt.Add(duration)
+
+	token, payload, err := maker.CreateToken(username, role, duration)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	require.NotEmpty(t, payload)
+	require.True(t, strings.HasPrefix(token, PublicKeyVersion+"."))
+
+	payload, err = maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.NotZero(t, payload.ID)
+	require.Equal(t, username, payload.Username)
+	require.Equal(t, role, payload.Role)
+	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
+	require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
+}
+
+func TestExpiredPasetoPublicToken(t *testing.T) {
+	privateKey, _ := randomKey(t)
+	maker, err := NewPasetoPublicMaker(privateKey, nil)
+	require.NoError(t, err)
+
+	token, _, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, -time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.EqualError(t, err, ErrExpiredToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestPasetoPublicMFAChallengeToken(t *testing.T) {
+	privateKey, _ := randomKey(t)
+	maker, err := NewPasetoPublicMaker(privateKey, nil)
+	require.NoError(t, err)
+
+	username := util.RandomOwner()
+	challengeToken, _, err := maker.CreateMFAChallengeToken(username, util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyMFAChallengeToken(challengeToken)
+	require.NoError(t, err)
+	require.Equal(t, username, payload.Username)
+
+	// the challenge token can't be used as an access token
+	payload, err = maker.VerifyToken(challengeToken)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestPasetoPublicRefreshToken(t *testing.T) {
+	privateKey, _ := randomKey(t)
+	maker, err := NewPasetoPublicMaker(privateKey, nil)
+	require.NoError(t, err)
+
+	username := util.RandomOwner()
+	refreshToken, payload, err := maker.CreateRefreshToken(username, util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, PurposeRefresh, payload.Purpose)
+
+	payload, err = maker.VerifyRefreshToken(refreshToken)
+	require.NoError(t, err)
+	require.Equal(t, username, payload.Username)
+	require.Equal(t, PurposeRefresh, payload.Purpose)
+
+	// the refresh token can't be used as an access token
+	payload, err = maker.VerifyToken(refreshToken)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+
+	// and neither an access token nor a challenge token can be used as a refresh token
+	accessToken, _, err := maker.CreateToken(username, util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+	payload, err = maker.VerifyRefreshToken(accessToken)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+
+	challengeToken, _, err := maker.CreateMFAChallengeToken(username, util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+	payload, err = maker.VerifyRefreshToken(challengeToken)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestPasetoPublicMakerKeyRotation(t *testing.T) {
+	oldPrivateKey, oldPublicKey := randomKey(t)
+	oldMaker, err := NewPasetoPublicMaker(oldPrivateKey, nil)
+	require.NoError(t, err)
+	oldToken, _, err := oldMaker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+
+	// the new private key signs the tokens, the old public key still verifies the tokens signed before the rotation
+	newPrivateKey, newPublicKey := randomKey(t)
+	newMaker, err := NewPasetoPublicMaker(newPrivateKey, []string{oldPublicKey})
+	require.NoError(t, err)
+
+	_, err = newMaker.VerifyToken(oldToken)
+	require.NoError(t, err)
+
+	newToken, _, err := newMaker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+	_, err = newMaker.VerifyToken(newToken)
+	require.NoError(t, err)
+
+	// the old maker doesn't know the new key
+	_, err = oldMaker.VerifyToken(newToken)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+
+	// the key of the private key comes first
+	publicKeys := newMaker.(PublicKeyMaker).PublicKeys()
+	require.Len(t, publicKeys, 2)
+	require.Equal(t, newPublicKey, hex.EncodeToString(publicKeys[0].PublicKey))
+	require.Equal(t, KeyID(publicKeys[0].PublicKey), publicKeys[0].KeyID)
+	require.Equal(t, oldPublicKey, hex.EncodeToString(publicKeys[1].PublicKey))
+}
+
+func TestPasetoPublicMakerWrongKey(t *testing.T) {
+	privateKey, publicKey := randomKey(t)
+	maker, err := NewPasetoPublicMaker(privateKey, nil)
+	require.NoError(t, err)
+	token, _, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+
+	// a different private key can't sign tokens with the key id of the maker's key
+	otherPrivateKey, _ := randomKey(t)
+	seed, err := hex.DecodeString(otherPrivateKey)
+	require.NoError(t, err)
+	forger := &PasetoPublicMaker{
+		paseto:     maker.(*PasetoPublicMaker).paseto,
+		keyID:      KeyID(mustDecodeHex(t, publicKey)),
+		privateKey: ed25519.NewKeyFromSeed(seed),
+	}
+	forgedToken, _, err := forger.CreateToken(util.RandomOwner(), util.AdminRole, time.Minute)
+	require.NoError(t, err)
+	_, err = maker.VerifyToken(forgedToken)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+
+	// the footer is signed, so the key id can't be changed to another key the maker accepts
+	otherMaker, err := NewPasetoPublicMaker(otherPrivateKey, nil)
+	require.NoError(t, err)
+	otherToken, _, err := otherMaker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+	otherPublicKey := hex.EncodeToString(otherMaker.(PublicKeyMaker).PublicKeys()[0].PublicKey)
+	bothMaker, err := NewPasetoPublicMaker(privateKey, []string{otherPublicKey})
+	require.NoError(t, err)
+
+	parts := strings.Split(token, ".")
+	require.Len(t, parts, 4)
+	parts[3] = strings.Split(otherToken, ".")[3]
+	_, err = bothMaker.VerifyToken(strings.Join(parts, "."))
+	require.EqualError(t, err, ErrInvalidToken.Error())
+
+	// symmetric tokens aren't accepted
+	symmetricMaker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+	symmetricToken, _, err := symmetricMaker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+	_, err = maker.VerifyToken(symmetricToken)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+}
+
+func TestNewPasetoPublicMakerInvalidKeys(t *testing.T) {
+	privateKey, publicKey := randomKey(t)
+
+	_, err := NewPasetoPublicMaker("", nil)
+	require.Error(t, err)
+	_, err = NewPasetoPublicMaker(privateKey[:10], nil)
+	require.Error(t, err)
+	_, err = NewPasetoPublicMaker(privateKey, []string{publicKey[:10]})
+	require.Error(t, err)
+	_, err = NewPasetoPublicMaker(privateKey, []string{"not hex"})
+	require.Error(t, err)
+}
+
+func TestParseVerificationKeys(t *testing.T) {
+	require.Empty(t, ParseVerificationKeys(""))
+	require.Equal(t, []string{"a", "b"}, ParseVerificationKeys(" a, ,b,"))
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
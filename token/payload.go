@@ -11,18 +11,31 @@ import (
 var (
 	ErrInvalidToken = errors.New("token is invalid")
 	ErrExpiredToken = errors.New("token has expired")
+	ErrRevokedToken = errors.New("token has been revoked")
+)
+
+// the purposes of the tokens which can't be used as access tokens - access tokens have no purpose, so the access tokens
+// created before purposes were added keep working
+const (
+	PurposeMFAChallenge = "mfa_challenge" // tokens created by CreateMFAChallengeToken
+	PurposeRefresh      = "refresh"       // tokens created by CreateRefreshToken
 )
 
 // Payload will contain the payload data of the token
 type Payload struct {
-	ID        uuid.UUID `json:"id"` // can use ID to invalidate tokens in the future if found to be leaked
-	Username  string    `json:"username"`
+	ID       uuid.UUID `json:"id"` // used to revoke the token before it expires (see Denylist)
+	Username string    `json:"username"`
+	Role     string    `json:"role"`              // the role of the user when the token was created - util.DepositorRole or util.AdminRole
+	Purpose  string    `json:"purpose,omitempty"` // what the token can be used for - empty for access tokens
+	// the scopes of the API key the payload was created for (see NewAPIKeyPayload) - empty for tokens, which aren't limited
+	// to scopes
+	Scopes    []string  `json:"scopes,omitempty"`
 	IssuedAt  time.Time `json:"issued_at"`  // when the token was created
 	ExpiredAt time.Time `json:"expired_at"` //when the token will expire
 }
 
-// NewPayload creates a new token payload with a specific username/duration
-func NewPayload(username string, duration time.Duration) (*Payload, error) {
+// NewPayload creates a new token payload with a specific username/role/duration
+func NewPayload(username string, role string, duration time.Duration) (*Payload, error) {
 	// create a new ID
 	tokenID, err := uuid.NewRandom()
 	if err != nil {
@@ -33,6 +46,7 @@ func NewPayload(username string, duration time.Duration) (*Payload, error) {
 	payload := &Payload{
 		ID:        tokenID,
 		Username:  username,
+		Role:      role,
 		IssuedAt:  time.Now(),
 		ExpiredAt: time.Now().Add(duration),
 	}
@@ -40,6 +54,44 @@ func NewPayload(username string, duration time.Duration) (*Payload, error) {
 	return payload, nil
 }
 
+// NewAPIKeyPayload creates the payload of a request authenticated with an API key - it is never turned into a token, the
+// handlers read it like the payload of an access token
+// a key which never expires has a zero ExpiredAt
+func NewAPIKeyPayload(id uuid.UUID, username string, role string, scopes []string, issuedAt time.Time, expiredAt time.Time) *Payload {
+	return &Payload{
+		ID:        id,
+		Username:  username,
+		Role:      role,
+		Scopes:    scopes,
+		IssuedAt:  issuedAt,
+		ExpiredAt: expiredAt,
+	}
+}
+
+// IsAPIKey returns true if the payload was created for an API key rather than an access token
+func (payload *Payload) IsAPIKey() bool {
+	return len(payload.Scopes) > 0
+}
+
+// HasScope returns true if the payload was created for an API key with the scope
+func (payload *Payload) HasScope(scope string) bool {
+	for _, s := range payload.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPurpose returns ErrInvalidToken if the token was created for another purpose than the input purpose - it stops an MFA
+// challenge token from being used as an access token and the other way round
+func (payload *Payload) checkPurpose(purpose string) error {
+	if payload.Purpose != purpose {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
 // Valid method checks if the token payload is valid or not
 // *Payload needs a Valid method in order to implement jwt.Claims interface
 func (payload *Payload) Valid() error {
@@ -0,0 +1,70 @@
+package totp
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// KeySize is the length of the key of a SecretCipher - AES-256 needs 32 bytes
+const KeySize = 32
+
+// ErrInvalidCiphertext is returned by Decrypt when the secret wasn't encrypted with the key of the SecretCipher
+var ErrInvalidCiphertext = errors.New("invalid encrypted secret")
+
+// SecretCipher encrypts the TOTP secrets before they are stored - unlike passwords, the servers need the secret itself to
+// check a code, so it can't be hashed
+type SecretCipher struct {
+	aead cipher.AEAD
+}
+
+// NewSecretCipher creates a SecretCipher which encrypts with AES-256-GCM
+func NewSecretCipher(key string) (*SecretCipher, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("invalid key size: must be exactly %d characters", KeySize)
+	}
+
+	block, err := aes.NewCipher([]byte(key))
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretCipher{aead: aead}, nil
+}
+
+// Encrypt returns the base64 encoded nonce and ciphertext of the secret
+func (c *SecretCipher) Encrypt(secret string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	// the ciphertext is appended to the nonce, so both are stored together
+	sealed := c.aead.Seal(nonce, nonce, []byte(secret), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt returns the secret encrypted by Encrypt
+func (c *SecretCipher) Decrypt(encrypted string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+
+	nonceSize := c.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return "", ErrInvalidCiphertext
+	}
+
+	secret, err := c.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+	return string(secret), nil
+}
@@ -0,0 +1,46 @@
+package totp
+
+import (
+	"testing"
+
+	"SimpleBankProject/db/util"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretCipher(t *testing.T) {
+	c, err := NewSecretCipher(util.RandomString(KeySize))
+	require.NoError(t, err)
+
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+
+	encrypted1, err := c.Encrypt(secret)
+	require.NoError(t, err)
+	require.NotContains(t, encrypted1, secret)
+
+	// every encryption uses a new nonce
+	encrypted2, err := c.Encrypt(secret)
+	require.NoError(t, err)
+	require.NotEqual(t, encrypted1, encrypted2)
+
+	decrypted, err := c.Decrypt(encrypted1)
+	require.NoError(t, err)
+	require.Equal(t, secret, decrypted)
+
+	// a secret encrypted with another key can't be decrypted
+	other, err := NewSecretCipher(util.RandomString(KeySize))
+	require.NoError(t, err)
+	_, err = other.Decrypt(encrypted1)
+	require.ErrorIs(t, err, ErrInvalidCiphertext)
+
+	_, err = c.Decrypt("not base64!")
+	require.ErrorIs(t, err, ErrInvalidCiphertext)
+	_, err = c.Decrypt("")
+	require.ErrorIs(t, err, ErrInvalidCiphertext)
+}
+
+func TestNewSecretCipherInvalidKey(t *testing.T) {
+	_, err := NewSecretCipher(util.RandomString(KeySize - 1))
+	require.Error(t, err)
+}
@@ -0,0 +1,42 @@
+package totp
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strings"
+)
+
+const (
+	// RecoveryCodeCount is how many recovery codes a user gets when they enable two-factor authentication
+	RecoveryCodeCount = 10
+	// the characters of a recovery code - lower case base32 without the easily confused 0, 1, 8 and 9
+	recoveryAlphabet = "abcdefghijklmnopqrstuvwxyz234567"
+	// a recovery code is shown as two groups of 5 characters, e.g. abcde-fgh23
+	recoveryGroupSize = 5
+)
+
+// GenerateRecoveryCodes returns n random recovery codes - each can be used once instead of a TOTP code when the user has
+// lost their authenticator app
+func GenerateRecoveryCodes(n int) ([]string, error) {
+	codes := make([]string, n)
+	for i := range codes {
+		b := make([]byte, 2*recoveryGroupSize)
+		if _, err := rand.Read(b); err != nil {
+			return nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		for j := range b {
+			// 256 is a multiple of 32, so every character is equally likely
+			b[j] = recoveryAlphabet[int(b[j])%len(recoveryAlphabet)]
+		}
+		codes[i] = string(b[:recoveryGroupSize]) + "-" + string(b[recoveryGroupSize:])
+	}
+	return codes, nil
+}
+
+// NormalizeRecoveryCode removes what a user may add or change when typing a recovery code (dashes, spaces, upper case), so
+// it can be hashed and compared with the stored codes
+func NormalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
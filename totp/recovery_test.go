@@ -0,0 +1,27 @@
+package totp
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(RecoveryCodeCount)
+	require.NoError(t, err)
+	require.Len(t, codes, RecoveryCodeCount)
+
+	format := regexp.MustCompile(`^[a-z2-7]{5}-[a-z2-7]{5}$`)
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		require.Regexp(t, format, code)
+		require.False(t, seen[code])
+		seen[code] = true
+	}
+}
+
+func TestNormalizeRecoveryCode(t *testing.T) {
+	require.Equal(t, "abcdefgh23", NormalizeRecoveryCode("abcde-fgh23"))
+	require.Equal(t, "abcdefgh23", NormalizeRecoveryCode(" ABCDE FGH23 "))
+}
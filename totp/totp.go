@@ -0,0 +1,111 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// Period is how long a code is valid - authenticator apps use 30 seconds by default
+	Period = 30 * time.Second
+	// Digits is the number of digits of a code
+	Digits = 6
+	// secretSize is the number of random bytes in a secret - RFC 4226 recommends 160 bits
+	secretSize = 20
+	// skew is how many periods before and after the current one are accepted, so a code typed in just as it changes and
+	// a clock which is a little off still work
+	skew = 1
+)
+
+// base32 without padding, as expected by authenticator apps
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// codeModulus is 10^Digits - the truncated value of a code is reduced to its last Digits digits
+var codeModulus = func() uint32 {
+	modulus := uint32(1)
+	for i := 0; i < Digits; i++ {
+		modulus *= 10
+	}
+	return modulus
+}()
+
+// GenerateSecret returns a random base32 encoded secret - the user adds it to their authenticator app
+func GenerateSecret() (string, error) {
+	b := make([]byte, secretSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate secret: %w", err)
+	}
+	return encoding.EncodeToString(b), nil
+}
+
+// KeyURI returns the otpauth:// URI of the secret - authenticator apps add the secret by scanning it as a QR code
+func KeyURI(issuer, accountName, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(Digits))
+	query.Set("period", fmt.Sprint(int(Period.Seconds())))
+
+	label := url.PathEscape(issuer + ":" + accountName)
+	return fmt.Sprintf("otpauth://totp/%s?%s", label, query.Encode())
+}
+
+// Step returns the number of periods since the Unix epoch at time t - the counter of RFC 6238
+func Step(t time.Time) int64 {
+	return t.Unix() / int64(Period.Seconds())
+}
+
+// GenerateCode returns the code of the secret at time t
+func GenerateCode(secret string, t time.Time) (string, error) {
+	return generateCode(secret, Step(t))
+}
+
+// Validate checks the code against the codes of the secret around time t - it returns the step of the matching code, which
+// must be stored to stop the same code from being used twice (see RFC 6238 section 5.2)
+func Validate(secret, code string, t time.Time) (int64, bool) {
+	if len(code) != Digits {
+		return 0, false
+	}
+
+	current := Step(t)
+	for step := current - skew; step <= current+skew; step++ {
+		expected, err := generateCode(secret, step)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// generateCode implements HOTP (RFC 4226) with HMAC-SHA1 for the counter step
+func generateCode(secret string, step int64) (string, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", fmt.Errorf("invalid secret: %w", err)
+	}
+
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+
+	// dynamic truncation - the last 4 bits pick the 4 bytes which make up the code
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", Digits, value%codeModulus), nil
+}
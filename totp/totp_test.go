@@ -0,0 +1,82 @@
+package totp
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// the secret of the test vectors in RFC 6238 appendix B - the ASCII string "12345678901234567890"
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerateCodeRFC6238(t *testing.T) {
+	// the last 6 digits of the 8 digit SHA1 codes of the RFC
+	testCases := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tc := range testCases {
+		code, err := GenerateCode(rfcSecret, time.Unix(tc.unix, 0))
+		require.NoError(t, err)
+		require.Equal(t, tc.code, code, "time %d", tc.unix)
+	}
+}
+
+func TestCodeModulus(t *testing.T) {
+	// the codes have Digits digits, so changing Digits changes the length of the codes
+	require.Equal(t, uint32(math.Pow10(Digits)), codeModulus)
+
+	code, err := GenerateCode(rfcSecret, time.Unix(59, 0))
+	require.NoError(t, err)
+	require.Len(t, code, Digits)
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+	require.Len(t, secret, 32)
+
+	now := time.Now()
+	code, err := GenerateCode(secret, now)
+	require.NoError(t, err)
+
+	step, ok := Validate(secret, code, now)
+	require.True(t, ok)
+	require.Equal(t, Step(now), step)
+
+	// the code of the previous period is still accepted, and the step tells which period it belongs to
+	step, ok = Validate(secret, code, now.Add(Period))
+	require.True(t, ok)
+	require.Equal(t, Step(now), step)
+
+	// but not once it is two periods old
+	_, ok = Validate(secret, code, now.Add(2*Period))
+	require.False(t, ok)
+
+	// a wrong or malformed code
+	_, ok = Validate(secret, "000000", time.Unix(59, 0))
+	require.False(t, ok)
+	_, ok = Validate(secret, "12345", now)
+	require.False(t, ok)
+}
+
+func TestKeyURI(t *testing.T) {
+	uri := KeyURI("Simple Bank", "garrett", rfcSecret)
+
+	require.True(t, strings.HasPrefix(uri, "otpauth://totp/Simple%20Bank:garrett?"))
+	require.Contains(t, uri, "secret="+rfcSecret)
+	require.Contains(t, uri, "issuer=Simple+Bank")
+	require.Contains(t, uri, "digits=6")
+	require.Contains(t, uri, "period=30")
+}
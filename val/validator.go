@@ -4,6 +4,10 @@ import (
 	"fmt"
 	"net/mail"
 	"regexp"
+
+	"SimpleBankProject/db/util"
+
+	"github.com/google/uuid"
 )
 
 var (
@@ -69,3 +73,153 @@ func ValidateEmail(email string) error {
 	}
 	return nil
 }
+
+// ValidateCurrency validates that the input currency is one of the currencies supported by the bank
+func ValidateCurrency(currency string) error {
+	// IsSupportedCurrency defined in currency.go of the util package
+	if !util.IsSupportedCurrency(currency) {
+		return fmt.Errorf("unsupported currency: %s", currency)
+	}
+	return nil
+}
+
+// ValidateID validates that the input id is a positive number (ids generated by postgres start at 1)
+func ValidateID(id int64) error {
+	if id < 1 {
+		return fmt.Errorf("must be a positive integer")
+	}
+	return nil
+}
+
+// ValidateAmount validates that the input amount of money is greater than zero
+func ValidateAmount(amount int64) error {
+	if amount <= 0 {
+		return fmt.Errorf("must be greater than 0")
+	}
+	return nil
+}
+
+// ValidatePageID validates that the input page id is a positive number
+func ValidatePageID(pageID int32) error {
+	if pageID < 1 {
+		return fmt.Errorf("must be a positive integer")
+	}
+	return nil
+}
+
+// ValidatePageToken validates that the input page token was created by util.EncodePageToken - a page token selects the page
+// of cursor pagination, so it can't be combined with a page id
+func ValidatePageToken(pageToken string, pageID int32) error {
+	if pageToken == "" {
+		return nil
+	}
+	if pageID != 0 {
+		return fmt.Errorf("can't be combined with page_id")
+	}
+	if _, err := util.DecodePageToken(pageToken); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ValidatePageSize validates that the input page size is within the minimum and maximum number of items per page
+func ValidatePageSize(pageSize int32, minSize int32, maxSize int32) error {
+	if pageSize < minSize || pageSize > maxSize {
+		return fmt.Errorf("must be from %d-%d", minSize, maxSize)
+	}
+	return nil
+}
+
+// ValidateAmountSign validates the optional sign filter of the history endpoints
+func ValidateAmountSign(sign string) error {
+	switch sign {
+	case "", "positive", "negative":
+		return nil
+	}
+	return fmt.Errorf("must be positive or negative")
+}
+
+// ValidateIdempotencyKey validates that the idempotency key fits in the idempotency_keys table
+func ValidateIdempotencyKey(key string) error {
+	return ValidateString(key, 1, 255)
+}
+
+// ValidateExternalReference validates that the external reference of a deposit or withdrawal is within the character limits
+func ValidateExternalReference(reference string) error {
+	return ValidateString(reference, 1, 255)
+}
+
+// ValidateSessionID validates that the input session id is a uuid
+func ValidateSessionID(sessionID string) error {
+	if _, err := uuid.Parse(sessionID); err != nil {
+		return fmt.Errorf("must be a valid uuid")
+	}
+	return nil
+}
+
+// ValidateToken validates that a token (e.g. a refresh token) was sent - the token maker verifies its contents
+func ValidateToken(token string) error {
+	if token == "" {
+		return fmt.Errorf("must not be empty")
+	}
+	return nil
+}
+
+// ValidateEmailID validates the id of the verification email (ids generated by postgres start at 1)
+func ValidateEmailID(emailID int64) error {
+	return ValidateID(emailID)
+}
+
+// ValidateSecretCode validates that the secret code of an email verification link has the length of the codes the servers
+// send - they are created by util.NewSecretToken
+func ValidateSecretCode(secretCode string) error {
+	return ValidateSecretToken(secretCode)
+}
+
+// ValidateSecretToken validates that the token has the length of the tokens created by util.NewSecretToken (e.g. the token
+// of a password reset link)
+func ValidateSecretToken(token string) error {
+	return ValidateString(token, util.SecretTokenLength, util.SecretTokenLength)
+}
+
+// ValidateTOTPCode validates that the code has the 6 digits of a TOTP code
+func ValidateTOTPCode(code string) error {
+	if err := ValidateString(code, 6, 6); err != nil {
+		return err
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return fmt.Errorf("must contain only digits")
+		}
+	}
+	return nil
+}
+
+// ValidateMFACode validates a code of two-factor authentication - a TOTP code or a recovery code
+func ValidateMFACode(code string) error {
+	return ValidateString(code, 6, 20)
+}
+
+// ValidateAPIKeyName validates the name the user gave an API key
+func ValidateAPIKeyName(name string) error {
+	return ValidateString(name, 1, 100)
+}
+
+// ValidateScopes validates that at least one scope is requested, each one is supported and none is repeated
+func ValidateScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return fmt.Errorf("must contain at least one scope")
+	}
+	seen := make(map[string]bool, len(scopes))
+	for _, scope := range scopes {
+		// IsSupportedScope defined in scope.go of the util package
+		if !util.IsSupportedScope(scope) {
+			return fmt.Errorf("unsupported scope: %s", scope)
+		}
+		if seen[scope] {
+			return fmt.Errorf("repeated scope: %s", scope)
+		}
+		seen[scope] = true
+	}
+	return nil
+}